@@ -0,0 +1,33 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package hashing
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/bits"
+	"testing"
+)
+
+func TestMix64(t *testing.T) {
+	assert.Equal(t, uint64(0), Mix64(0))
+	assert.Equal(t, uint64(0x5692161d100b05e5), Mix64(1))
+
+	// neighbours differ in about half of the bits.
+	for i := uint64(1); i < 100; i++ {
+		assert.InDelta(t, 32, bits.OnesCount64(Mix64(i)^Mix64(i+1)), 16)
+	}
+}
@@ -0,0 +1,261 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xbytes
+
+import (
+	"errors"
+	"io"
+	"net"
+)
+
+const chainBlockSize = 32 << 10
+
+// ErrChainRange is returned when slicing a Chain out of its range.
+var ErrChainRange = errors.New("xbytes: chain slice out of range")
+
+// A Chain is a byte buffer made of a list of segments, a rope.
+//
+// Unlike bytes.Buffer, appending and prepending never move the existing data,
+// Append and Prepend keep a reference to the given slices without copying them,
+// and WriteTo hands all the segments to a single writev on a net.Conn.
+// The zero value is an empty Chain ready to use.
+type Chain struct {
+	segs [][]byte
+	len  int
+	// block is the buffer filled by Write and ReadFrom, up to used.
+	block []byte
+	used  int
+	// tailOwned reports whether the last segment ends at block[used].
+	tailOwned bool
+}
+
+// NewChain returns a Chain of segs, without copying them.
+func NewChain(segs ...[]byte) *Chain {
+	c := &Chain{}
+	for _, seg := range segs {
+		c.Append(seg)
+	}
+
+	return c
+}
+
+// Append adds b at the end of c without copying it, b must not be modified afterwards.
+func (c *Chain) Append(b []byte) {
+	if len(b) == 0 {
+		return
+	}
+
+	c.segs = append(c.segs, b[:len(b):len(b)])
+	c.len += len(b)
+	c.tailOwned = false
+}
+
+// Prepend adds b at the start of c without copying it, b must not be modified afterwards.
+func (c *Chain) Prepend(b []byte) {
+	if len(b) == 0 {
+		return
+	}
+
+	if len(c.segs) == 0 {
+		c.tailOwned = false
+	}
+	c.segs = append(c.segs, nil)
+	copy(c.segs[1:], c.segs)
+	c.segs[0] = b[:len(b):len(b)]
+	c.len += len(b)
+}
+
+// Write appends a copy of p, it implements io.Writer.
+func (c *Chain) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := copy(c.spare(), p)
+		c.commit(n)
+		p = p[n:]
+		written += n
+	}
+
+	return written, nil
+}
+
+// WriteString appends a copy of s.
+func (c *Chain) WriteString(s string) (int, error) {
+	written := 0
+	for len(s) > 0 {
+		n := copy(c.spare(), s)
+		c.commit(n)
+		s = s[n:]
+		written += n
+	}
+
+	return written, nil
+}
+
+// ReadFrom reads r until EOF directly into the segments of c, it implements io.ReaderFrom.
+func (c *Chain) ReadFrom(r io.Reader) (int64, error) {
+	var total int64
+	for {
+		n, err := r.Read(c.spare())
+		if n > 0 {
+			c.commit(n)
+			total += int64(n)
+		}
+		if err == io.EOF {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+	}
+}
+
+// Read reads and consumes the start of c, it implements io.Reader.
+func (c *Chain) Read(p []byte) (int, error) {
+	if c.len == 0 {
+		if len(p) == 0 {
+			return 0, nil
+		}
+		return 0, io.EOF
+	}
+
+	n := 0
+	for _, seg := range c.segs {
+		if n == len(p) {
+			break
+		}
+		n += copy(p[n:], seg)
+	}
+	c.Discard(n)
+
+	return n, nil
+}
+
+// WriteTo writes and consumes c, it implements io.WriterTo.
+// It uses writev when w is a net.Conn.
+func (c *Chain) WriteTo(w io.Writer) (int64, error) {
+	bufs := make(net.Buffers, len(c.segs))
+	copy(bufs, c.segs)
+	n, err := bufs.WriteTo(w)
+	c.Discard(int(n))
+
+	return n, err
+}
+
+// Discard consumes the first n bytes of c, or all of them if c is shorter.
+// It returns the number of bytes discarded.
+func (c *Chain) Discard(n int) int {
+	if n >= c.len {
+		n = c.len
+		c.Reset()
+		return n
+	}
+
+	left, i := n, 0
+	for ; left > 0 && left >= len(c.segs[i]); i++ {
+		left -= len(c.segs[i])
+	}
+	clear(c.segs[:i])
+	c.segs = c.segs[i:]
+	if left > 0 {
+		c.segs[0] = c.segs[0][left:]
+	}
+	c.len -= n
+
+	return n
+}
+
+// Slice returns a Chain of the bytes [from, to) of c, sharing its memory.
+func (c *Chain) Slice(from, to int) (*Chain, error) {
+	if from < 0 || to > c.len || from > to {
+		return nil, ErrChainRange
+	}
+
+	s := &Chain{}
+	pos := 0
+	for _, seg := range c.segs {
+		end := pos + len(seg)
+		if end > from && pos < to {
+			s.Append(seg[max(from-pos, 0):min(to-pos, len(seg))])
+		}
+		if end >= to {
+			break
+		}
+		pos = end
+	}
+
+	return s, nil
+}
+
+// Bytes returns a copy of the content of c in a single slice.
+func (c *Chain) Bytes() []byte {
+	b := make([]byte, 0, c.len)
+	for _, seg := range c.segs {
+		b = append(b, seg...)
+	}
+
+	return b
+}
+
+// String returns the content of c as a string.
+func (c *Chain) String() string {
+	return string(c.Bytes())
+}
+
+// Segments returns the segments of c, they must not be modified.
+func (c *Chain) Segments() [][]byte {
+	return c.segs
+}
+
+// Len returns the number of bytes of c.
+func (c *Chain) Len() int {
+	return c.len
+}
+
+// Reset empties c.
+func (c *Chain) Reset() {
+	clear(c.segs)
+	c.segs = c.segs[:0]
+	c.len = 0
+	// the block may still be shared with slices.
+	c.block, c.used, c.tailOwned = nil, 0, false
+}
+
+// spare returns the free space of the current block, allocating a new one if it is full.
+func (c *Chain) spare() []byte {
+	if c.used == len(c.block) {
+		c.block = make([]byte, chainBlockSize)
+		c.used = 0
+		c.tailOwned = false
+	}
+
+	return c.block[c.used:]
+}
+
+// commit adds the next n bytes of the block, written by spare's caller, to c.
+func (c *Chain) commit(n int) {
+	end := c.used + n
+	if c.tailOwned {
+		last := len(c.segs) - 1
+		start := c.used - len(c.segs[last])
+		c.segs[last] = c.block[start:end:end]
+	} else {
+		c.segs = append(c.segs, c.block[c.used:end:end])
+		c.tailOwned = true
+	}
+	c.used = end
+	c.len += n
+}
@@ -0,0 +1,246 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xbytes
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestChain_AppendPrepend(t *testing.T) {
+	world := []byte("world")
+	c := NewChain([]byte("hello"), nil, []byte(" "))
+	c.Append(world)
+	c.Prepend([]byte(">> "))
+	c.Prepend(nil)
+	assert.Equal(t, ">> hello world", c.String())
+	assert.Equal(t, 14, c.Len())
+	assert.Len(t, c.Segments(), 4)
+
+	// zero-copy
+	world[0] = 'W'
+	assert.Equal(t, ">> hello World", c.String())
+
+	var empty Chain
+	empty.Prepend([]byte("a"))
+	_, _ = empty.WriteString("b")
+	assert.Equal(t, "ab", empty.String())
+	assert.Len(t, empty.Segments(), 2)
+}
+
+func TestChain_Write(t *testing.T) {
+	var c Chain
+	p := []byte("abc")
+	_, _ = c.Write(p)
+	_, _ = c.WriteString("def")
+	p[0] = 'x'
+	assert.Equal(t, "abcdef", c.String())
+	// consecutive writes share a segment.
+	assert.Len(t, c.Segments(), 1)
+
+	c.Append([]byte("-"))
+	_, _ = c.WriteString("ghi")
+	assert.Len(t, c.Segments(), 3)
+
+	large := strings.Repeat("z", chainBlockSize+10)
+	n, err := c.WriteString(large)
+	assert.NoError(t, err)
+	assert.Equal(t, len(large), n)
+	n, err = c.Write([]byte(large))
+	assert.NoError(t, err)
+	assert.Equal(t, len(large), n)
+	assert.Equal(t, "abcdef-ghi"+large+large, c.String())
+}
+
+func TestChain_ReadFrom(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 10000)
+	var c Chain
+	c.Append([]byte("head:"))
+	n, err := c.ReadFrom(iotest.OneByteReader(bytes.NewReader(data[:100])))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), n)
+	assert.Len(t, c.Segments(), 2)
+
+	n, err = c.ReadFrom(bytes.NewReader(data[100:]))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(data)-100), n)
+	assert.Equal(t, append([]byte("head:"), data...), c.Bytes())
+
+	cause := errors.New("broken")
+	n, err = c.ReadFrom(iotest.ErrReader(cause))
+	assert.ErrorIs(t, err, cause)
+	assert.Zero(t, n)
+}
+
+func TestChain_Read(t *testing.T) {
+	c := NewChain([]byte("ab"), []byte("cde"), []byte("f"))
+	p := make([]byte, 4)
+	n, err := c.Read(p)
+	assert.NoError(t, err)
+	assert.Equal(t, "abcd", string(p[:n]))
+	assert.Equal(t, "ef", c.String())
+
+	n, err = c.Read(nil)
+	assert.NoError(t, err)
+	assert.Zero(t, n)
+
+	data, err := io.ReadAll(c)
+	assert.NoError(t, err)
+	assert.Equal(t, "ef", string(data))
+	assert.Zero(t, c.Len())
+	n, err = c.Read(nil)
+	assert.NoError(t, err)
+	assert.Zero(t, n)
+
+	// reading the start of an owned tail keeps appending to it.
+	_, _ = c.WriteString("123")
+	c.Discard(1)
+	_, _ = c.WriteString("45")
+	assert.Equal(t, "2345", c.String())
+	assert.Len(t, c.Segments(), 1)
+}
+
+func TestChain_Discard(t *testing.T) {
+	c := NewChain([]byte("ab"), []byte("cde"), []byte("f"))
+	assert.Equal(t, 2, c.Discard(2))
+	assert.Equal(t, "cdef", c.String())
+	assert.Equal(t, 1, c.Discard(1))
+	assert.Equal(t, "def", c.String())
+	assert.Equal(t, 3, c.Discard(10))
+	assert.Zero(t, c.Len())
+	assert.Empty(t, c.Segments())
+}
+
+func TestChain_Slice(t *testing.T) {
+	c := NewChain([]byte("ab"), []byte("cde"), []byte("fg"))
+	for _, tt := range []struct {
+		from, to int
+		want     string
+		segs     int
+	}{
+		{0, 7, "abcdefg", 3},
+		{1, 6, "bcdef", 3},
+		{2, 5, "cde", 1},
+		{3, 4, "d", 1},
+		{3, 3, "", 0},
+		{0, 0, "", 0},
+		{7, 7, "", 0},
+	} {
+		s, err := c.Slice(tt.from, tt.to)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, s.String(), tt)
+		assert.Len(t, s.Segments(), tt.segs, tt)
+		assert.Equal(t, len(tt.want), s.Len())
+	}
+
+	for _, bad := range [][2]int{{-1, 2}, {2, 8}, {3, 2}} {
+		_, err := c.Slice(bad[0], bad[1])
+		assert.ErrorIs(t, err, ErrChainRange)
+	}
+	assert.Equal(t, "abcdefg", c.String())
+}
+
+func TestChain_WriteTo(t *testing.T) {
+	c := NewChain([]byte("ab"), []byte("cde"))
+	_, _ = c.WriteString("f")
+	var buf bytes.Buffer
+	n, err := c.WriteTo(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), n)
+	assert.Equal(t, "abcdef", buf.String())
+	assert.Zero(t, c.Len())
+
+	// a partial write only consumes what was written.
+	c = NewChain([]byte("ab"), []byte("cde"))
+	n, err = c.WriteTo(&limitedWriter{n: 3})
+	assert.ErrorIs(t, err, io.ErrShortWrite)
+	assert.Equal(t, int64(3), n)
+	assert.Equal(t, "de", c.String())
+}
+
+func TestChain_WriteToConn(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer ln.Close()
+
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			received <- nil
+			return
+		}
+		defer conn.Close()
+		data, _ := io.ReadAll(conn)
+		received <- data
+	}()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	assert.NoError(t, err)
+	c := NewChain([]byte("GET / HTTP/1.1\r\n"), []byte("Host: example.com\r\n"), []byte("\r\n"))
+	_, err = c.WriteTo(conn)
+	assert.NoError(t, err)
+	assert.NoError(t, conn.Close())
+	assert.Equal(t, "GET / HTTP/1.1\r\nHost: example.com\r\n\r\n", string(<-received))
+}
+
+type limitedWriter struct {
+	n int
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		n := w.n
+		w.n = 0
+		return n, io.ErrShortWrite
+	}
+	w.n -= len(p)
+
+	return len(p), nil
+}
+
+func BenchmarkChain_Append(b *testing.B) {
+	seg := make([]byte, 4<<10)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var c Chain
+		for j := 0; j < 64; j++ {
+			c.Append(seg)
+		}
+		_, _ = c.WriteTo(io.Discard)
+	}
+}
+
+func BenchmarkBuffer_Write(b *testing.B) {
+	seg := make([]byte, 4<<10)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var buf bytes.Buffer
+		for j := 0; j < 64; j++ {
+			buf.Write(seg)
+		}
+		_, _ = buf.WriteTo(io.Discard)
+	}
+}
@@ -0,0 +1,172 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xbytes
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+const (
+	hexdumpWidth = 16
+	hexDigits    = "0123456789abcdef"
+)
+
+// ErrDumperClosed is returned when writing to a closed dumper.
+var ErrDumperClosed = errors.New("xbytes: dumper closed")
+
+type dumper struct {
+	w      io.Writer
+	line   [hexdumpWidth]byte
+	n      int
+	offset int
+	buf    []byte
+	closed bool
+}
+
+// Hexdump returns an xxd-style dump of b, one line per 16 bytes:
+//
+//	00000000: 4865 6c6c 6f2c 2077 6f72 6c64 210a       Hello, world!.
+func Hexdump(b []byte) string {
+	var buf []byte
+	for offset := 0; offset < len(b); offset += hexdumpWidth {
+		buf = appendHexdumpLine(buf, offset, b[offset:min(offset+hexdumpWidth, len(b))])
+	}
+
+	return string(buf)
+}
+
+// HexdumpDiff returns the lines of the dumps of a and b that differ, prefixed by "-" for a and "+" for b.
+// Gaps of equal lines are shown as "*". It returns an empty string if a and b are equal.
+func HexdumpDiff(a, b []byte) string {
+	if bytes.Equal(a, b) {
+		return ""
+	}
+
+	var buf []byte
+	gap := false
+	for offset := 0; offset < len(a) || offset < len(b); offset += hexdumpWidth {
+		la, lb := hexdumpSlice(a, offset), hexdumpSlice(b, offset)
+		if bytes.Equal(la, lb) {
+			gap = true
+			continue
+		}
+
+		if gap {
+			buf = append(buf, "*\n"...)
+			gap = false
+		}
+		if la != nil {
+			buf = append(buf, '-')
+			buf = appendHexdumpLine(buf, offset, la)
+		}
+		if lb != nil {
+			buf = append(buf, '+')
+			buf = appendHexdumpLine(buf, offset, lb)
+		}
+	}
+	if gap {
+		buf = append(buf, "*\n"...)
+	}
+
+	return string(buf)
+}
+
+// NewDumper returns an io.WriteCloser that writes an xxd-style dump of the data written to it to w.
+// Close writes the last incomplete line, it doesn't close w.
+func NewDumper(w io.Writer) io.WriteCloser {
+	return &dumper{w: w}
+}
+
+func (d *dumper) Write(p []byte) (int, error) {
+	if d.closed {
+		return 0, ErrDumperClosed
+	}
+
+	written := 0
+	for len(p) > 0 {
+		n := copy(d.line[d.n:], p)
+		d.n += n
+		p = p[n:]
+		if d.n == hexdumpWidth {
+			if err := d.flush(); err != nil {
+				return written, err
+			}
+		}
+		written += n
+	}
+
+	return written, nil
+}
+
+func (d *dumper) Close() error {
+	if d.closed {
+		return nil
+	}
+	d.closed = true
+	if d.n == 0 {
+		return nil
+	}
+
+	return d.flush()
+}
+
+func (d *dumper) flush() error {
+	d.buf = appendHexdumpLine(d.buf[:0], d.offset, d.line[:d.n])
+	d.offset += d.n
+	d.n = 0
+	_, err := d.w.Write(d.buf)
+
+	return err
+}
+
+func hexdumpSlice(b []byte, offset int) []byte {
+	if offset >= len(b) {
+		return nil
+	}
+
+	return b[offset:min(offset+hexdumpWidth, len(b))]
+}
+
+func appendHexdumpLine(buf []byte, offset int, line []byte) []byte {
+	for shift := 28; shift >= 0; shift -= 4 {
+		buf = append(buf, hexDigits[offset>>shift&0xf])
+	}
+	buf = append(buf, ':')
+
+	for i := 0; i < hexdumpWidth; i++ {
+		if i%2 == 0 {
+			buf = append(buf, ' ')
+		}
+		if i < len(line) {
+			buf = append(buf, hexDigits[line[i]>>4], hexDigits[line[i]&0xf])
+		} else {
+			buf = append(buf, ' ', ' ')
+		}
+	}
+	buf = append(buf, ' ', ' ')
+
+	for _, c := range line {
+		if c < 0x20 || c > 0x7e {
+			c = '.'
+		}
+		buf = append(buf, c)
+	}
+
+	return append(buf, '\n')
+}
@@ -0,0 +1,102 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xbytes
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestHexdump(t *testing.T) {
+	assert.Equal(t, "00000000: 4865 6c6c 6f2c 2077 6f72 6c64 210a       Hello, world!.\n",
+		Hexdump([]byte("Hello, world!\n")))
+	assert.Equal(t, "", Hexdump(nil))
+
+	data := make([]byte, 33)
+	for i := range data {
+		data[i] = byte(i * 8)
+	}
+	assert.Equal(t, ""+
+		"00000000: 0008 1018 2028 3038 4048 5058 6068 7078  .... (08@HPX`hpx\n"+
+		"00000010: 8088 9098 a0a8 b0b8 c0c8 d0d8 e0e8 f0f8  ................\n"+
+		"00000020: 00                                       .\n",
+		Hexdump(data))
+}
+
+func TestHexdumpDiff(t *testing.T) {
+	a := bytes.Repeat([]byte("a"), 64)
+	b := append([]byte(nil), a...)
+	assert.Equal(t, "", HexdumpDiff(a, b))
+
+	b[20] = 'b'
+	b = append(b, 'c')
+	assert.Equal(t, ""+
+		"*\n"+
+		"-00000010: 6161 6161 6161 6161 6161 6161 6161 6161  aaaaaaaaaaaaaaaa\n"+
+		"+00000010: 6161 6161 6261 6161 6161 6161 6161 6161  aaaabaaaaaaaaaaa\n"+
+		"*\n"+
+		"+00000040: 63                                       c\n",
+		HexdumpDiff(a, b))
+
+	assert.Equal(t, ""+
+		"-00000000: 61                                       a\n"+
+		"+00000000: 62                                       b\n",
+		HexdumpDiff([]byte("a"), []byte("b")))
+}
+
+func TestDumper(t *testing.T) {
+	data := make([]byte, 50)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	var buf bytes.Buffer
+	d := NewDumper(&buf)
+	for _, chunk := range [][]byte{data[:3], data[3:20], data[20:]} {
+		n, err := d.Write(chunk)
+		assert.NoError(t, err)
+		assert.Equal(t, len(chunk), n)
+	}
+	assert.NoError(t, d.Close())
+	assert.NoError(t, d.Close())
+	assert.Equal(t, Hexdump(data), buf.String())
+
+	_, err := d.Write([]byte{1})
+	assert.ErrorIs(t, err, ErrDumperClosed)
+
+	buf.Reset()
+	d = NewDumper(&buf)
+	_, _ = d.Write(data[:16])
+	assert.NoError(t, d.Close())
+	assert.Equal(t, Hexdump(data[:16]), buf.String())
+
+	cause := errors.New("broken")
+	d = NewDumper(errWriter{cause})
+	n, err := d.Write(data)
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, 0, n)
+}
+
+type errWriter struct {
+	err error
+}
+
+func (w errWriter) Write([]byte) (int, error) {
+	return 0, w.err
+}
@@ -0,0 +1,192 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xbytes
+
+import (
+	"io"
+	"net"
+)
+
+type (
+	// Joiner is used to construct a sequence of bytes separated by a delimiter and optionally starting with a supplied prefix and ending with a supplied suffix.
+	// Every write is an element of the sequence.
+	Joiner struct {
+		buf     []byte
+		opts    *joinerOptions
+		n       int // n is length of prefix and suffix
+		written bool
+	}
+	// joinerOptions a option.
+	joinerOptions struct {
+		prefix    []byte
+		step      []byte
+		suffix    []byte
+		skipEmpty bool
+	}
+
+	// JoinerOption defines the method to customize a Joiner.
+	JoinerOption func(*joinerOptions)
+)
+
+// WithJoinerStep returns a JoinerOption with step settings.
+func WithJoinerStep(step []byte) JoinerOption {
+	return func(options *joinerOptions) {
+		options.step = step
+	}
+}
+
+// WithJoinerPrefix returns a JoinerOption with prefix settings.
+func WithJoinerPrefix(prefix []byte) JoinerOption {
+	return func(options *joinerOptions) {
+		options.prefix = prefix
+	}
+}
+
+// WithJoinerSuffix returns a JoinerOption with suffix settings.
+func WithJoinerSuffix(suffix []byte) JoinerOption {
+	return func(options *joinerOptions) {
+		options.suffix = suffix
+	}
+}
+
+// WithJoiner returns a JoinerOption with prefix, step and suffix settings.
+func WithJoiner(prefix, step, suffix []byte) JoinerOption {
+	return func(options *joinerOptions) {
+		options.prefix = prefix
+		options.step = step
+		options.suffix = suffix
+	}
+}
+
+// WithJoinerSkipEmpty returns a JoinerOption that ignores empty elements, so no step is written for them.
+func WithJoinerSkipEmpty() JoinerOption {
+	return func(options *joinerOptions) {
+		options.skipEmpty = true
+	}
+}
+
+// NewJoiner returns a Joiner.
+func NewJoiner(opts ...JoinerOption) *Joiner {
+	op := new(joinerOptions)
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	return &Joiner{opts: op, n: len(op.prefix) + len(op.suffix)}
+}
+
+// Write appends the element p.
+// Write always returns len(p), nil.
+func (j *Joiner) Write(p []byte) (int, error) {
+	if j.skip(len(p)) {
+		return 0, nil
+	}
+
+	j.tryWriteStep()
+	j.buf = append(j.buf, p...)
+
+	return len(p), nil
+}
+
+// WriteString appends the element s.
+// It returns the length of s and a nil error.
+func (j *Joiner) WriteString(s string) (int, error) {
+	if j.skip(len(s)) {
+		return 0, nil
+	}
+
+	j.tryWriteStep()
+	j.buf = append(j.buf, s...)
+
+	return len(s), nil
+}
+
+// WriteByte appends the element c.
+// The returned error is always nil.
+func (j *Joiner) WriteByte(c byte) error {
+	j.tryWriteStep()
+	j.buf = append(j.buf, c)
+
+	return nil
+}
+
+// Bytes returns a copy of the accumulated bytes, with the prefix and the suffix.
+func (j *Joiner) Bytes() []byte {
+	b := make([]byte, 0, j.Len())
+	b = append(b, j.opts.prefix...)
+	b = append(b, j.buf...)
+
+	return append(b, j.opts.suffix...)
+}
+
+// String returns the accumulated bytes as a string.
+func (j *Joiner) String() string {
+	return string(j.Bytes())
+}
+
+// WriteTo writes the accumulated bytes to w, without copying them into a single slice first.
+// It implements io.WriterTo.
+func (j *Joiner) WriteTo(w io.Writer) (int64, error) {
+	bufs := make(net.Buffers, 0, 3)
+	for _, b := range [][]byte{j.opts.prefix, j.buf, j.opts.suffix} {
+		if len(b) > 0 {
+			bufs = append(bufs, b)
+		}
+	}
+
+	return bufs.WriteTo(w)
+}
+
+// Grow grows the capacity, if necessary, to guarantee space for another n bytes.
+// If n is negative, Grow panics.
+func (j *Joiner) Grow(n int) {
+	if n < 0 {
+		panic("xbytes.Joiner.Grow: negative count")
+	}
+	if cap(j.buf)-len(j.buf) < n {
+		buf := make([]byte, len(j.buf), 2*cap(j.buf)+n)
+		copy(buf, j.buf)
+		j.buf = buf
+	}
+}
+
+// Cap returns the capacity of the underlying byte slice, plus the length of the prefix and the suffix.
+func (j *Joiner) Cap() int {
+	return cap(j.buf) + j.n
+}
+
+// Len returns the length of the accumulated bytes.
+func (j *Joiner) Len() int {
+	return len(j.buf) + j.n
+}
+
+// Reset resets the Joiner to be empty, keeping its capacity.
+func (j *Joiner) Reset() {
+	j.buf = j.buf[:0]
+	j.written = false
+}
+
+func (j *Joiner) skip(n int) bool {
+	return n == 0 && j.opts.skipEmpty
+}
+
+func (j *Joiner) tryWriteStep() {
+	if j.written {
+		j.buf = append(j.buf, j.opts.step...)
+	}
+	j.written = true
+}
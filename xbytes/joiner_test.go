@@ -0,0 +1,118 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xbytes
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestJoiner_Write(t *testing.T) {
+	join := NewJoiner(WithJoiner([]byte("("), []byte(","), []byte(")")))
+	_, _ = join.Write([]byte("1"))
+	_, _ = join.WriteString("2")
+	_ = join.WriteByte('3')
+	assert.Equal(t, []byte("(1,2,3)"), join.Bytes())
+	assert.Equal(t, "(1,2,3)", join.String())
+
+	join = NewJoiner(WithJoinerStep([]byte{0}), WithJoinerPrefix([]byte{0xff}), WithJoinerSuffix([]byte{0xfe}))
+	_, _ = join.Write([]byte{1, 2})
+	_, _ = join.Write([]byte{3})
+	assert.Equal(t, []byte{0xff, 1, 2, 0, 3, 0xfe}, join.Bytes())
+
+	join = NewJoiner(WithJoinerStep([]byte(",")))
+	_, _ = join.WriteString("a")
+	_, _ = join.WriteString("")
+	_, _ = join.WriteString("b")
+	assert.Equal(t, "a,,b", join.String())
+
+	assert.Equal(t, "", NewJoiner().String())
+	assert.Equal(t, "[]", NewJoiner(WithJoiner([]byte("["), nil, []byte("]"))).String())
+}
+
+func TestJoiner_SkipEmpty(t *testing.T) {
+	join := NewJoiner(WithJoinerStep([]byte(",")), WithJoinerSkipEmpty())
+	n, _ := join.WriteString("")
+	assert.Equal(t, 0, n)
+	_, _ = join.WriteString("a")
+	_, _ = join.Write(nil)
+	_, _ = join.WriteString("")
+	_, _ = join.WriteString("b")
+	assert.Equal(t, "a,b", join.String())
+}
+
+func TestJoiner_WriteTo(t *testing.T) {
+	join := NewJoiner(WithJoiner([]byte("<"), []byte("|"), []byte(">")))
+	_, _ = join.WriteString("a")
+	_, _ = join.WriteString("b")
+
+	var buf bytes.Buffer
+	n, err := join.WriteTo(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+	assert.Equal(t, "<a|b>", buf.String())
+
+	buf.Reset()
+	n, err = NewJoiner().WriteTo(&buf)
+	assert.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func TestJoiner_LenCapReset(t *testing.T) {
+	join := NewJoiner(WithJoiner([]byte("("), []byte(","), []byte(")")))
+	assert.Equal(t, 2, join.Len())
+	assert.Equal(t, 2, join.Cap())
+	_, _ = join.WriteString("abc")
+	_, _ = join.WriteString("d")
+	assert.Equal(t, 7, join.Len())
+	assert.Equal(t, len(join.Bytes()), join.Len())
+
+	join.Grow(100)
+	assert.GreaterOrEqual(t, join.Cap(), 107)
+	assert.Equal(t, "(abc,d)", join.String())
+	assert.Panics(t, func() { join.Grow(-1) })
+
+	join.Reset()
+	assert.Equal(t, 2, join.Len())
+	_, _ = join.WriteString("x")
+	assert.Equal(t, "(x)", join.String())
+}
+
+func TestJoiner_Reuse(t *testing.T) {
+	p := bytes.Repeat([]byte{'a'}, 1000)
+	join := NewJoiner()
+	allocs := testing.AllocsPerRun(100, func() {
+		join.Reset()
+		_, _ = join.Write(p)
+	})
+	assert.Zero(t, allocs)
+}
+
+func BenchmarkJoiner_WriteTo(b *testing.B) {
+	join := NewJoiner(WithJoiner([]byte("("), []byte(","), []byte(")")))
+	var buf bytes.Buffer
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		join.Reset()
+		buf.Reset()
+		_, _ = join.WriteString("1")
+		_, _ = join.WriteString("2")
+		_, _ = join.WriteString("3")
+		_, _ = join.WriteTo(&buf)
+	}
+}
@@ -0,0 +1,154 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xchan
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Merge returns a channel of the values received from all chs, closed once all of them are closed.
+// The values are forwarded until received, use OrDone to stop reading early without leaking.
+func Merge[T any](chs ...<-chan T) <-chan T {
+	out := make(chan T)
+
+	var wg sync.WaitGroup
+	wg.Add(len(chs))
+	for _, ch := range chs {
+		go func(ch <-chan T) {
+			defer wg.Done()
+			for v := range ch {
+				out <- v
+			}
+		}(ch)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
+
+// FanOut calls fn with the values received from ch in n goroutines,
+// and returns after ch is closed and all the calls returned.
+// It panics if n is less than 1.
+func FanOut[T any](ch <-chan T, n int, fn func(T)) {
+	if n < 1 {
+		panic("xchan: FanOut needs at least one worker")
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+			for v := range ch {
+				fn(v)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Batch returns a channel of the values received from ch grouped in slices of up to maxSize.
+// A batch is also emitted maxWait after its first value, so that values don't wait forever
+// for a batch to fill. The last batch is emitted and the channel closed once ch is closed.
+// It panics if maxSize is less than 1.
+func Batch[T any](ch <-chan T, maxSize int, maxWait time.Duration) <-chan []T {
+	if maxSize < 1 {
+		panic("xchan: Batch needs a positive size")
+	}
+
+	out := make(chan []T)
+	go func() {
+		defer close(out)
+
+		timer := time.NewTimer(maxWait)
+		timer.Stop()
+		var batch []T
+		flush := func() {
+			timer.Stop()
+			out <- batch
+			batch = nil
+		}
+
+		for {
+			select {
+			case v, ok := <-ch:
+				if !ok {
+					if len(batch) > 0 {
+						flush()
+					}
+					return
+				}
+
+				if len(batch) == 0 {
+					batch = make([]T, 0, maxSize)
+					timer.Reset(maxWait)
+				}
+				batch = append(batch, v)
+				if len(batch) == maxSize {
+					flush()
+				}
+			case <-timer.C:
+				if len(batch) > 0 {
+					flush()
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// OrDone returns a channel of the values received from ch, closed when ch is closed or ctx is done.
+func OrDone[T any](ctx context.Context, ch <-chan T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-ch:
+				if !ok {
+					return
+				}
+				select {
+				case out <- v:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// Drain receives and discards the values of ch until it's closed, and returns how many there were.
+// It's typically used to unblock the senders of a channel nobody reads anymore.
+func Drain[T any](ch <-chan T) int {
+	n := 0
+	for range ch {
+		n++
+	}
+
+	return n
+}
@@ -0,0 +1,159 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xchan
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"runtime"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func generate(values ...int) <-chan int {
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for _, v := range values {
+			ch <- v
+		}
+	}()
+
+	return ch
+}
+
+func collect[T any](ch <-chan T) []T {
+	var values []T
+	for v := range ch {
+		values = append(values, v)
+	}
+
+	return values
+}
+
+func assertNoLeak(t *testing.T, before int) {
+	t.Helper()
+	// assert.Eventually runs its condition in another goroutine, so poll here.
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines leaked", runtime.NumGoroutine()-before)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	before := runtime.NumGoroutine()
+	values := collect(Merge(generate(1, 2, 3), generate(4, 5), generate()))
+	sort.Ints(values)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, values)
+	assert.Nil(t, collect(Merge[int]()))
+	assertNoLeak(t, before)
+}
+
+func TestFanOut(t *testing.T) {
+	var (
+		sum     int64
+		running int32
+		peak    int32
+	)
+	FanOut(generate(1, 2, 3, 4, 5, 6, 7, 8), 3, func(v int) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		atomic.AddInt64(&sum, int64(v))
+	})
+	assert.Equal(t, int64(36), sum)
+	assert.LessOrEqual(t, peak, int32(3))
+	assert.Zero(t, atomic.LoadInt32(&running))
+
+	assert.Panics(t, func() {
+		FanOut(generate(), 0, func(int) {})
+	})
+}
+
+func TestBatch(t *testing.T) {
+	before := runtime.NumGoroutine()
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, collect(Batch(generate(1, 2, 3, 4, 5), 2, time.Hour)))
+	assert.Nil(t, collect(Batch(generate(), 2, time.Hour)))
+
+	// a partial batch is emitted after maxWait.
+	ch := make(chan int)
+	batches := Batch(ch, 10, 20*time.Millisecond)
+	ch <- 1
+	ch <- 2
+	select {
+	case batch := <-batches:
+		assert.Equal(t, []int{1, 2}, batch)
+	case <-time.After(time.Second):
+		t.Fatal("the partial batch was not emitted")
+	}
+
+	ch <- 3
+	close(ch)
+	assert.Equal(t, [][]int{{3}}, collect(batches))
+	assertNoLeak(t, before)
+
+	assert.Panics(t, func() {
+		Batch(generate(), 0, time.Second)
+	})
+}
+
+func TestOrDone(t *testing.T) {
+	before := runtime.NumGoroutine()
+	assert.Equal(t, []int{1, 2, 3}, collect(OrDone(context.Background(), generate(1, 2, 3))))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan int)
+	out := OrDone(ctx, ch)
+	go func() { ch <- 1 }()
+	assert.Equal(t, 1, <-out)
+	cancel()
+	_, ok := <-out
+	assert.False(t, ok)
+	assertNoLeak(t, before)
+
+	// cancelled while forwarding a value nobody reads.
+	ctx, cancel = context.WithCancel(context.Background())
+	source := make(chan int, 1)
+	source <- 1
+	OrDone(ctx, source)
+	time.Sleep(5 * time.Millisecond)
+	cancel()
+	assertNoLeak(t, before)
+}
+
+func TestDrain(t *testing.T) {
+	assert.Equal(t, 3, Drain(generate(1, 2, 3)))
+	assert.Equal(t, 0, Drain(generate()))
+
+	// unblocks the senders of merged channels after stopping early.
+	before := runtime.NumGoroutine()
+	merged := Merge(generate(1, 2, 3), generate(4, 5, 6))
+	<-merged
+	assert.Equal(t, 5, Drain(merged))
+	assertNoLeak(t, before)
+}
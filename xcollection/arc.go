@@ -0,0 +1,246 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcollection
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// An ARC is a cache of bounded size using the Adaptive Replacement Cache policy.
+// It splits entries between a list of keys seen once recently and a list of keys seen at least twice,
+// and remembers the keys evicted from both to adapt their target sizes to the workload.
+// A scan of keys used once can't flush the frequently used entries like it does with an LRU.
+// It also tracks about its capacity in evicted keys, without their values. It's safe for concurrent use.
+type ARC[K comparable, V any] struct {
+	lock     sync.Mutex
+	items    map[K]*cacheEntry[K, V]
+	capacity int
+	// p is the target size of t1.
+	p int
+	// t1 holds the entries seen once recently, t2 the entries seen at least twice.
+	t1, t2 *cacheList[K, V]
+	// b1 and b2 are the ghost lists of the keys evicted from t1 and t2.
+	b1, b2  *cacheList[K, V]
+	onEvict func(key K, value V, reason EvictReason)
+
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+}
+
+var _ Cache[string, int] = (*ARC[string, int])(nil)
+
+// NewARC returns an empty ARC holding up to capacity entries, it panics if capacity is not positive.
+func NewARC[K comparable, V any](capacity int) *ARC[K, V] {
+	if capacity < 1 {
+		panic("xcollection: capacity should be greater than 0")
+	}
+
+	return &ARC[K, V]{
+		items:    make(map[K]*cacheEntry[K, V], 2*capacity),
+		capacity: capacity,
+		t1:       newCacheList[K, V](),
+		t2:       newCacheList[K, V](),
+		b1:       newCacheList[K, V](),
+		b2:       newCacheList[K, V](),
+	}
+}
+
+// OnEvict registers fn to be called after an entry is evicted or removed, replacing any previous one.
+// fn is called without holding the lock, so it may use the cache.
+func (c *ARC[K, V]) OnEvict(fn func(key K, value V, reason EvictReason)) {
+	c.lock.Lock()
+	c.onEvict = fn
+	c.lock.Unlock()
+}
+
+// Len returns the number of entries.
+func (c *ARC[K, V]) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.t1.len + c.t2.len
+}
+
+// Cap returns the capacity.
+func (c *ARC[K, V]) Cap() int {
+	return c.capacity
+}
+
+// Get returns the value of key and promotes it to the frequently used entries.
+func (c *ARC[K, V]) Get(key K) (V, bool) {
+	c.lock.Lock()
+	e, ok := c.items[key]
+	if !ok || !c.resident(e) {
+		c.lock.Unlock()
+		c.misses.Add(1)
+
+		var zero V
+		return zero, false
+	}
+
+	c.promote(e)
+	value := e.value
+	c.lock.Unlock()
+	c.hits.Add(1)
+
+	return value, true
+}
+
+// Peek returns the value of key without updating the policy nor the statistics.
+func (c *ARC[K, V]) Peek(key K) (V, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if e, ok := c.items[key]; ok && c.resident(e) {
+		return e.value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Set stores value under key, evicting an entry if the cache is full.
+func (c *ARC[K, V]) Set(key K, value V) {
+	c.lock.Lock()
+	e, ok := c.items[key]
+	if ok && c.resident(e) {
+		e.value = value
+		c.promote(e)
+		c.lock.Unlock()
+		return
+	}
+
+	var (
+		victimKey   K
+		victimValue V
+		evicted     bool
+	)
+	if ok {
+		// a ghost hit tells which list was evicted too early, grow its target size.
+		if e.list == c.b1 {
+			c.p = min(c.capacity, c.p+max(1, c.b2.len/c.b1.len))
+		} else {
+			c.p = max(0, c.p-max(1, c.b1.len/c.b2.len))
+		}
+		if c.t1.len+c.t2.len >= c.capacity {
+			victimKey, victimValue = c.replace(e.list == c.b2)
+			evicted = true
+		}
+		e.list.remove(e)
+		e.value = value
+		c.t2.pushFront(e)
+	} else {
+		if c.t1.len+c.t2.len >= c.capacity {
+			victimKey, victimValue = c.replace(false)
+			evicted = true
+		}
+		// bound the ghost lists.
+		if c.b1.len > c.capacity-c.p {
+			c.drop(c.b1.back())
+		}
+		if c.b2.len > c.p {
+			c.drop(c.b2.back())
+		}
+		e = &cacheEntry[K, V]{key: key, value: value}
+		c.items[key] = e
+		c.t1.pushFront(e)
+	}
+	onEvict := c.onEvict
+	c.lock.Unlock()
+
+	if evicted && onEvict != nil {
+		onEvict(victimKey, victimValue, EvictCapacity)
+	}
+}
+
+// Remove removes key and reports whether it was present.
+func (c *ARC[K, V]) Remove(key K) bool {
+	c.lock.Lock()
+	e, ok := c.items[key]
+	if !ok {
+		c.lock.Unlock()
+		return false
+	}
+
+	resident := c.resident(e)
+	c.drop(e)
+	onEvict := c.onEvict
+	c.lock.Unlock()
+
+	if resident && onEvict != nil {
+		onEvict(e.key, e.value, EvictRemoved)
+	}
+
+	return resident
+}
+
+// Clear removes all entries and forgets the evicted keys, without calling the eviction callback.
+func (c *ARC[K, V]) Clear() {
+	c.lock.Lock()
+	clear(c.items)
+	c.t1, c.t2 = newCacheList[K, V](), newCacheList[K, V]()
+	c.b1, c.b2 = newCacheList[K, V](), newCacheList[K, V]()
+	c.p = 0
+	c.lock.Unlock()
+}
+
+// Stats returns the hit, miss and eviction counters.
+func (c *ARC[K, V]) Stats() CacheStats {
+	return CacheStats{Hits: c.hits.Load(), Misses: c.misses.Load(), Evictions: c.evictions.Load()}
+}
+
+func (c *ARC[K, V]) resident(e *cacheEntry[K, V]) bool {
+	return e.list == c.t1 || e.list == c.t2
+}
+
+// promote moves a resident entry to the front of t2.
+func (c *ARC[K, V]) promote(e *cacheEntry[K, V]) {
+	if e.list == c.t2 {
+		c.t2.moveToFront(e)
+		return
+	}
+
+	c.t1.remove(e)
+	c.t2.pushFront(e)
+}
+
+// replace evicts the least recently used entry of t1 or t2 to its ghost list, following the target size of t1.
+// It returns the key and value evicted.
+func (c *ARC[K, V]) replace(ghostOfT2 bool) (K, V) {
+	from, to := c.t2, c.b2
+	if c.t1.len > 0 && (c.t1.len > c.p || (c.t1.len == c.p && ghostOfT2) || c.t2.len == 0) {
+		from, to = c.t1, c.b1
+	}
+
+	e := from.back()
+	value := e.value
+	from.remove(e)
+	var zero V
+	e.value = zero
+	to.pushFront(e)
+	c.evictions.Add(1)
+
+	return e.key, value
+}
+
+// drop forgets e whatever its list.
+func (c *ARC[K, V]) drop(e *cacheEntry[K, V]) {
+	e.list.remove(e)
+	delete(c.items, e.key)
+}
@@ -0,0 +1,195 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcollection
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func TestARC(t *testing.T) {
+	c := NewARC[string, int](2)
+	var events []cacheEvent
+	c.OnEvict(func(key string, value int, reason EvictReason) {
+		events = append(events, cacheEvent{key, value, reason})
+	})
+	assert.Equal(t, 2, c.Cap())
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	// a moves to the frequently used entries.
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	// new keys evict the recently used ones first.
+	c.Set("c", 3)
+	assert.Equal(t, []cacheEvent{{"b", 2, EvictCapacity}}, events)
+	c.Set("d", 4)
+	assert.Equal(t, cacheEvent{"c", 3, EvictCapacity}, events[1])
+	_, ok = c.Peek("a")
+	assert.True(t, ok)
+
+	// b is a ghost now: setting it again favors the recently used list and stores it as frequent.
+	_, ok = c.Get("b")
+	assert.False(t, ok)
+	c.Set("b", 20)
+	v, ok = c.Peek("b")
+	assert.True(t, ok)
+	assert.Equal(t, 20, v)
+	assert.Equal(t, 2, c.Len())
+
+	c.Set("b", 200)
+	v, _ = c.Get("b")
+	assert.Equal(t, 200, v)
+
+	_, ok = c.Peek("zzz")
+	assert.False(t, ok)
+
+	// removing a ghost reports false and no callback.
+	n := len(events)
+	assert.False(t, c.Remove("c"))
+	assert.Len(t, events, n)
+	assert.True(t, c.Remove("b"))
+	assert.Equal(t, cacheEvent{"b", 200, EvictRemoved}, events[n])
+	assert.False(t, c.Remove("b"))
+	assert.Equal(t, 1, c.Len())
+
+	stats := c.Stats()
+	assert.Equal(t, uint64(2), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+	assert.Equal(t, uint64(len(events)-1), stats.Evictions)
+
+	c.Clear()
+	assert.Equal(t, 0, c.Len())
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+
+	assert.Panics(t, func() {
+		NewARC[int, int](0)
+	})
+}
+
+func TestARC_ScanResistance(t *testing.T) {
+	const capacity = 100
+	arc := NewARC[int, int](capacity)
+	lru := NewLRU[int, int](capacity)
+	caches := []Cache[int, int]{arc, lru}
+
+	// a hot set accessed twice, then a long scan of keys used once.
+	for _, c := range caches {
+		for round := 0; round < 2; round++ {
+			for k := 0; k < 50; k++ {
+				if _, ok := c.Get(k); !ok {
+					c.Set(k, k)
+				}
+			}
+		}
+		for k := 1000; k < 2000; k++ {
+			c.Set(k, k)
+		}
+	}
+
+	hot := func(c Cache[int, int]) int {
+		n := 0
+		for k := 0; k < 50; k++ {
+			if _, ok := c.Peek(k); ok {
+				n++
+			}
+		}
+		return n
+	}
+	assert.Equal(t, 50, hot(arc))
+	assert.Equal(t, 0, hot(lru))
+}
+
+func TestARC_Invariants(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	const capacity = 16
+	c := NewARC[int, int](capacity)
+	for i := 0; i < 20000; i++ {
+		k := int(r.ExpFloat64() * 20)
+		switch r.Intn(10) {
+		case 0:
+			c.Remove(k)
+		case 1, 2, 3:
+			c.Set(k, k)
+		default:
+			if v, ok := c.Get(k); ok {
+				assert.Equal(t, k, v)
+			} else {
+				c.Set(k, k)
+			}
+		}
+
+		assert.LessOrEqual(t, c.t1.len+c.t2.len, capacity)
+		assert.LessOrEqual(t, c.t1.len+c.t2.len+c.b1.len+c.b2.len, 2*capacity)
+		assert.GreaterOrEqual(t, c.p, 0)
+		assert.LessOrEqual(t, c.p, capacity)
+		assert.Equal(t, len(c.items), c.t1.len+c.t2.len+c.b1.len+c.b2.len)
+	}
+}
+
+func TestARC_Concurrent(t *testing.T) {
+	c := NewARC[int, int](64)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(g)))
+			for i := 0; i < 5000; i++ {
+				k := r.Intn(256)
+				switch r.Intn(4) {
+				case 0:
+					c.Set(k, k)
+				case 1:
+					c.Remove(k)
+				default:
+					if v, ok := c.Get(k); ok {
+						assert.Equal(t, k, v)
+					}
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	assert.LessOrEqual(t, c.Len(), 64)
+}
+
+func BenchmarkARC_Freq(b *testing.B) {
+	c := NewARC[int64, int64](8192)
+	trace := make([]int64, b.N*2)
+	for i := range trace {
+		if i%2 == 0 {
+			trace[i] = rand.Int63() % 16384
+		} else {
+			trace[i] = rand.Int63() % 32768
+		}
+	}
+	for i := 0; i < b.N; i++ {
+		c.Set(trace[i], trace[i])
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Get(trace[i])
+	}
+}
@@ -0,0 +1,28 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcollection
+
+import "github.com/chenquan/go-pkg/xds"
+
+// A BitSet is a dense set of non-negative integers, backed by a growable slice of words.
+// It's an alias of xds.BitSet. It's not safe for concurrent use.
+type BitSet = xds.BitSet
+
+// NewBitSet returns an empty BitSet with room for the bits below n.
+func NewBitSet(n uint) *BitSet {
+	return xds.NewBitSet(n)
+}
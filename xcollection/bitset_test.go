@@ -0,0 +1,48 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcollection
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBitSet(t *testing.T) {
+	flags := NewBitSet(128).Set(1).Set(64).Flip(100).Flip(1)
+	assert.False(t, flags.Test(1))
+	assert.True(t, flags.Test(100))
+	assert.Equal(t, 2, flags.Count())
+
+	next, ok := flags.NextSetBit(65)
+	assert.True(t, ok)
+	assert.Equal(t, uint(100), next)
+	_, ok = flags.NextSetBit(101)
+	assert.False(t, ok)
+
+	mask := NewBitSet(0).Set(64).Set(5)
+	assert.Equal(t, "{64}", flags.And(mask).String())
+	assert.Equal(t, "{5 64 100}", flags.Or(mask).String())
+	assert.Equal(t, "{5 100}", flags.Xor(mask).String())
+	assert.Equal(t, "{100}", flags.AndNot(mask).String())
+
+	data, err := flags.MarshalBinary()
+	assert.Nil(t, err)
+	assert.Len(t, data, 13)
+	var decoded BitSet
+	assert.Nil(t, decoded.UnmarshalBinary(data))
+	assert.True(t, decoded.Equal(flags))
+}
@@ -0,0 +1,243 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcollection
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/chenquan/go-pkg/internal/hashing"
+	"github.com/chenquan/go-pkg/xds"
+	"math"
+)
+
+const (
+	bloomVersion    = 1
+	bloomHeaderSize = 1 + 4 + 8
+	// maxBloomHashCount bounds the work of every operation, no float64 false positive rate needs more.
+	maxBloomHashCount = 1024
+
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+var (
+	// ErrInvalidBloomFilter is returned when decoding malformed BloomFilter data.
+	ErrInvalidBloomFilter = errors.New("xcollection: invalid bloom filter data")
+	// ErrBloomFilterMismatch is returned when merging BloomFilters of different parameters.
+	ErrBloomFilterMismatch = errors.New("xcollection: bloom filter parameters mismatch")
+)
+
+// A BloomFilter is a probabilistic set: MayContain never misses an added element,
+// but may report one that was never added. It's not safe for concurrent use.
+//
+// Elements are hashed once with 64-bit FNV-1a, and the k bit positions are derived by double hashing,
+// so the encoded form is stable across processes and can be shared, e.g. through Redis.
+type BloomFilter struct {
+	bits *xds.BitSet
+	m    uint64
+	k    uint32
+}
+
+// NewBloomFilter returns a BloomFilter sized to hold n elements with a false positive rate of fpRate.
+// It panics if n is zero or fpRate is not in (0, 1).
+func NewBloomFilter(n uint, fpRate float64) *BloomFilter {
+	if n == 0 {
+		panic("xcollection: bloom filter capacity should be greater than 0")
+	}
+	if !(fpRate > 0 && fpRate < 1) {
+		panic("xcollection: bloom filter false positive rate should be in (0, 1)")
+	}
+
+	m := math.Ceil(-float64(n) * math.Log(fpRate) / (math.Ln2 * math.Ln2))
+	k := math.Round(m / float64(n) * math.Ln2)
+
+	return NewBloomFilterSize(uint64(m), uint32(max(k, 1)))
+}
+
+// NewBloomFilterSize returns a BloomFilter of m bits using k hash functions.
+// It panics if m or k is zero, or if k is greater than 1024.
+func NewBloomFilterSize(m uint64, k uint32) *BloomFilter {
+	if m == 0 || k == 0 {
+		panic("xcollection: bloom filter size and hash count should be greater than 0")
+	}
+	if k > maxBloomHashCount {
+		panic("xcollection: bloom filter hash count should not be greater than 1024")
+	}
+
+	return &BloomFilter{bits: xds.NewBitSet(uint(m)), m: m, k: k}
+}
+
+// Bits returns the number of bits of f.
+func (f *BloomFilter) Bits() uint64 {
+	return f.m
+}
+
+// HashCount returns the number of hash functions of f.
+func (f *BloomFilter) HashCount() uint32 {
+	return f.k
+}
+
+// Add adds data to f.
+func (f *BloomFilter) Add(data []byte) {
+	f.add(fnvBytes(data))
+}
+
+// AddString adds s to f.
+func (f *BloomFilter) AddString(s string) {
+	f.add(fnvString(s))
+}
+
+// MayContain reports whether data may have been added to f.
+func (f *BloomFilter) MayContain(data []byte) bool {
+	return f.test(fnvBytes(data))
+}
+
+// MayContainString reports whether s may have been added to f.
+func (f *BloomFilter) MayContainString(s string) bool {
+	return f.test(fnvString(s))
+}
+
+// FillRatio returns the fraction of bits set.
+func (f *BloomFilter) FillRatio() float64 {
+	return float64(f.bits.Count()) / float64(f.m)
+}
+
+// EstimatedCount returns an estimate of the number of distinct elements added,
+// or math.MaxInt if every bit is set.
+func (f *BloomFilter) EstimatedCount() int {
+	x := uint64(f.bits.Count())
+	if x == f.m {
+		return math.MaxInt
+	}
+
+	n := -float64(f.m) / float64(f.k) * math.Log1p(-float64(x)/float64(f.m))
+
+	return int(math.Round(n))
+}
+
+// EstimatedFalsePositiveRate returns the current probability that MayContain reports an element never added.
+func (f *BloomFilter) EstimatedFalsePositiveRate() float64 {
+	return math.Pow(f.FillRatio(), float64(f.k))
+}
+
+// Clear removes all elements.
+func (f *BloomFilter) Clear() {
+	f.bits = xds.NewBitSet(uint(f.m))
+}
+
+// Merge adds the elements of other to f.
+// It fails with ErrBloomFilterMismatch if the filters have different sizes or hash counts.
+func (f *BloomFilter) Merge(other *BloomFilter) error {
+	if f.m != other.m || f.k != other.k {
+		return ErrBloomFilterMismatch
+	}
+	f.bits = f.bits.Or(other.bits)
+
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (f *BloomFilter) MarshalBinary() ([]byte, error) {
+	bits, err := f.bits.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, bloomHeaderSize+bloomWords(f.m)*8)
+	data[0] = bloomVersion
+	binary.LittleEndian.PutUint32(data[1:], f.k)
+	binary.LittleEndian.PutUint64(data[5:], f.m)
+	// the bit set trims its trailing zero bytes, the encoded form has a fixed size.
+	copy(data[bloomHeaderSize:], bits)
+
+	return data, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, replacing f with the decoded filter.
+func (f *BloomFilter) UnmarshalBinary(data []byte) error {
+	if len(data) < bloomHeaderSize || data[0] != bloomVersion {
+		return ErrInvalidBloomFilter
+	}
+
+	k := binary.LittleEndian.Uint32(data[1:])
+	m := binary.LittleEndian.Uint64(data[5:])
+	data = data[bloomHeaderSize:]
+	if k == 0 || k > maxBloomHashCount || m == 0 || len(data)%8 != 0 || bloomWords(m) != uint64(len(data))/8 {
+		return ErrInvalidBloomFilter
+	}
+
+	bits := new(xds.BitSet)
+	if err := bits.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	if _, ok := bits.NextSetBit(uint(m)); ok {
+		// bits beyond m are never set.
+		return ErrInvalidBloomFilter
+	}
+	f.bits, f.m, f.k = bits, m, k
+
+	return nil
+}
+
+func (f *BloomFilter) add(h uint64) {
+	h1, h2 := bloomHashes(h)
+	for i := uint64(0); i < uint64(f.k); i++ {
+		f.bits.Set(uint((h1 + i*h2) % f.m))
+	}
+}
+
+func (f *BloomFilter) test(h uint64) bool {
+	h1, h2 := bloomHashes(h)
+	for i := uint64(0); i < uint64(f.k); i++ {
+		if !f.bits.Test(uint((h1 + i*h2) % f.m)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// bloomWords returns the number of 64-bit words holding m bits, without overflowing.
+func bloomWords(m uint64) uint64 {
+	return (m-1)/64 + 1
+}
+
+// bloomHashes derives the two hashes of double hashing from h,
+// the second one is odd to avoid a zero step in the common case of an even number of bits.
+func bloomHashes(h uint64) (uint64, uint64) {
+	return hashing.Mix64(h), hashing.Mix64(h^0x9e3779b97f4a7c15) | 1
+}
+
+func fnvBytes(data []byte) uint64 {
+	h := uint64(fnvOffset64)
+	for _, c := range data {
+		h ^= uint64(c)
+		h *= fnvPrime64
+	}
+
+	return h
+}
+
+func fnvString(s string) uint64 {
+	h := uint64(fnvOffset64)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= fnvPrime64
+	}
+
+	return h
+}
@@ -0,0 +1,174 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcollection
+
+import (
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestBloomFilter(t *testing.T) {
+	f := NewBloomFilter(1000, 0.01)
+	assert.Equal(t, uint64(9586), f.Bits())
+	assert.Equal(t, uint32(7), f.HashCount())
+	assert.False(t, f.MayContainString("a"))
+	assert.Equal(t, 0.0, f.FillRatio())
+
+	for i := 0; i < 1000; i++ {
+		f.AddString("key-" + strconv.Itoa(i))
+	}
+	for i := 0; i < 1000; i++ {
+		assert.True(t, f.MayContainString("key-"+strconv.Itoa(i)))
+		assert.True(t, f.MayContain([]byte("key-"+strconv.Itoa(i))))
+	}
+
+	falsePositives := 0
+	for i := 0; i < 10000; i++ {
+		if f.MayContainString("other-" + strconv.Itoa(i)) {
+			falsePositives++
+		}
+	}
+	assert.Less(t, float64(falsePositives)/10000, 0.02)
+
+	assert.InDelta(t, 0.5, f.FillRatio(), 0.05)
+	assert.InDelta(t, 1000, f.EstimatedCount(), 50)
+	assert.InDelta(t, 0.01, f.EstimatedFalsePositiveRate(), 0.005)
+
+	f.Add([]byte("bytes"))
+	assert.True(t, f.MayContainString("bytes"))
+
+	f.Clear()
+	assert.False(t, f.MayContainString("key-1"))
+	assert.Equal(t, 0, f.EstimatedCount())
+}
+
+func TestBloomFilter_Panics(t *testing.T) {
+	assert.Panics(t, func() { NewBloomFilter(0, 0.01) })
+	assert.Panics(t, func() { NewBloomFilter(10, 0) })
+	assert.Panics(t, func() { NewBloomFilter(10, 1) })
+	assert.Panics(t, func() { NewBloomFilter(10, math.NaN()) })
+	assert.Panics(t, func() { NewBloomFilterSize(0, 1) })
+	assert.Panics(t, func() { NewBloomFilterSize(1, 0) })
+	assert.Panics(t, func() { NewBloomFilterSize(1, 1025) })
+
+	f := NewBloomFilter(1, 0.9)
+	assert.Equal(t, uint32(1), f.HashCount())
+	f = NewBloomFilter(1, math.SmallestNonzeroFloat64)
+	assert.LessOrEqual(t, f.HashCount(), uint32(maxBloomHashCount))
+}
+
+func TestBloomFilter_Full(t *testing.T) {
+	f := NewBloomFilterSize(8, 3)
+	for i := 0; i < 100; i++ {
+		f.AddString(strconv.Itoa(i))
+	}
+	assert.Equal(t, 1.0, f.FillRatio())
+	assert.Equal(t, math.MaxInt, f.EstimatedCount())
+	assert.True(t, f.MayContainString("anything"))
+}
+
+func TestBloomFilter_Binary(t *testing.T) {
+	f := NewBloomFilter(100, 0.001)
+	for i := 0; i < 100; i++ {
+		f.AddString(strconv.Itoa(i))
+	}
+
+	data, err := f.MarshalBinary()
+	assert.Nil(t, err)
+
+	var g BloomFilter
+	assert.Nil(t, g.UnmarshalBinary(data))
+	assert.Equal(t, f.Bits(), g.Bits())
+	assert.Equal(t, f.HashCount(), g.HashCount())
+	for i := 0; i < 100; i++ {
+		assert.True(t, g.MayContainString(strconv.Itoa(i)))
+	}
+	again, _ := g.MarshalBinary()
+	assert.Equal(t, data, again)
+
+	assert.ErrorIs(t, g.UnmarshalBinary(nil), ErrInvalidBloomFilter)
+	assert.ErrorIs(t, g.UnmarshalBinary(data[:len(data)-1]), ErrInvalidBloomFilter)
+	bad := append([]byte{2}, data[1:]...)
+	assert.ErrorIs(t, g.UnmarshalBinary(bad), ErrInvalidBloomFilter)
+	bad = append([]byte(nil), data...)
+	bad[len(bad)-1] = 0xff
+	assert.ErrorIs(t, g.UnmarshalBinary(bad), ErrInvalidBloomFilter)
+	assert.True(t, g.MayContainString("1"), "a failed decoding keeps the filter")
+}
+
+func TestBloomFilter_UnmarshalCorrupt(t *testing.T) {
+	header := func(k uint32, m uint64, words int) []byte {
+		data := make([]byte, bloomHeaderSize+8*words)
+		data[0] = bloomVersion
+		binary.LittleEndian.PutUint32(data[1:], k)
+		binary.LittleEndian.PutUint64(data[5:], m)
+		return data
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"zero bits", header(3, 0, 0)},
+		{"zero hashes", header(0, 64, 1)},
+		{"too many hashes", header(maxBloomHashCount+1, 64, 1)},
+		{"max hashes", header(math.MaxUint32, 64, 1)},
+		{"max bits", header(3, math.MaxUint64, 0)},
+		{"overflowing bits", header(3, math.MaxUint64-62, 0)},
+		{"missing words", header(3, 65, 1)},
+		{"extra words", header(3, 64, 2)},
+		{"partial word", header(3, 64, 1)[:bloomHeaderSize+4]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var f BloomFilter
+			assert.ErrorIs(t, f.UnmarshalBinary(tt.data), ErrInvalidBloomFilter)
+		})
+	}
+
+	var f BloomFilter
+	assert.Nil(t, f.UnmarshalBinary(header(maxBloomHashCount, 65, 2)))
+	assert.False(t, f.MayContainString("a"))
+}
+
+func TestBloomFilter_Merge(t *testing.T) {
+	a := NewBloomFilter(100, 0.01)
+	b := NewBloomFilter(100, 0.01)
+	a.AddString("a")
+	b.AddString("b")
+	assert.Nil(t, a.Merge(b))
+	assert.True(t, a.MayContainString("a"))
+	assert.True(t, a.MayContainString("b"))
+
+	assert.ErrorIs(t, a.Merge(NewBloomFilter(200, 0.01)), ErrBloomFilterMismatch)
+}
+
+func BenchmarkBloomFilter_MayContain(b *testing.B) {
+	f := NewBloomFilter(1e6, 0.01)
+	for i := 0; i < 1e5; i++ {
+		f.AddString(strconv.Itoa(i))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f.MayContainString("key")
+	}
+}
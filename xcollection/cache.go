@@ -0,0 +1,146 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcollection
+
+// A Cache is a bounded key-value store with an eviction policy, see LRU, LFU and ARC.
+// Implementations are safe for concurrent use.
+type Cache[K comparable, V any] interface {
+	// Get returns the value of key, counting as an access for the eviction policy.
+	Get(key K) (V, bool)
+	// Peek returns the value of key without counting as an access.
+	Peek(key K) (V, bool)
+	// Set stores value under key, evicting an entry if the cache is full.
+	Set(key K, value V)
+	// Remove removes key and reports whether it was present.
+	Remove(key K) bool
+	// Len returns the number of entries.
+	Len() int
+	// Cap returns the maximum number of entries.
+	Cap() int
+	// Clear removes all entries without calling the eviction callback.
+	Clear()
+	// Stats returns the hit, miss and eviction counters.
+	Stats() CacheStats
+	// OnEvict registers fn to be called after an entry is evicted or removed.
+	OnEvict(fn func(key K, value V, reason EvictReason))
+}
+
+// EvictReason tells why an entry left a cache.
+type EvictReason int
+
+const (
+	// EvictCapacity means the entry was evicted to make room for a new one.
+	EvictCapacity EvictReason = iota
+	// EvictExpired means the entry outlived its TTL.
+	EvictExpired
+	// EvictRemoved means the entry was removed explicitly.
+	EvictRemoved
+)
+
+// CacheStats holds the counters of a cache.
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+// String returns the name of r.
+func (r EvictReason) String() string {
+	switch r {
+	case EvictCapacity:
+		return "capacity"
+	case EvictExpired:
+		return "expired"
+	case EvictRemoved:
+		return "removed"
+	default:
+		return "unknown"
+	}
+}
+
+// HitRatio returns the fraction of lookups that hit, or 0 if there was none.
+func (s CacheStats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+
+	return float64(s.Hits) / float64(total)
+}
+
+type (
+	// cacheList is a circular doubly linked list of cache entries whose front is the most recently used.
+	cacheList[K comparable, V any] struct {
+		root cacheEntry[K, V]
+		len  int
+	}
+
+	cacheEntry[K comparable, V any] struct {
+		key   K
+		value V
+		// expireAt is the expiration time in unix nanoseconds, 0 means never, see LRU.
+		expireAt int64
+		// freq is the access count, see LFU.
+		freq       int
+		list       *cacheList[K, V]
+		prev, next *cacheEntry[K, V]
+	}
+)
+
+func newCacheList[K comparable, V any]() *cacheList[K, V] {
+	l := &cacheList[K, V]{}
+	l.root.prev = &l.root
+	l.root.next = &l.root
+
+	return l
+}
+
+func (l *cacheList[K, V]) pushFront(e *cacheEntry[K, V]) {
+	e.list = l
+	e.prev = &l.root
+	e.next = l.root.next
+	l.root.next.prev = e
+	l.root.next = e
+	l.len++
+}
+
+func (l *cacheList[K, V]) remove(e *cacheEntry[K, V]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.prev, e.next, e.list = nil, nil, nil
+	l.len--
+}
+
+func (l *cacheList[K, V]) moveToFront(e *cacheEntry[K, V]) {
+	if l.root.next != e {
+		l.remove(e)
+		l.pushFront(e)
+	}
+}
+
+// back returns the least recently used entry or nil.
+func (l *cacheList[K, V]) back() *cacheEntry[K, V] {
+	if l.len == 0 {
+		return nil
+	}
+
+	return l.root.prev
+}
+
+func (e *cacheEntry[K, V]) expired(now int64) bool {
+	return e.expireAt != 0 && now >= e.expireAt
+}
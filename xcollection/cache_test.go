@@ -0,0 +1,73 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcollection
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEvictReason_String(t *testing.T) {
+	assert.Equal(t, "capacity", EvictCapacity.String())
+	assert.Equal(t, "expired", EvictExpired.String())
+	assert.Equal(t, "removed", EvictRemoved.String())
+	assert.Equal(t, "unknown", EvictReason(-1).String())
+}
+
+func TestCacheStats_HitRatio(t *testing.T) {
+	assert.Equal(t, 0.0, CacheStats{}.HitRatio())
+	assert.Equal(t, 0.75, CacheStats{Hits: 3, Misses: 1}.HitRatio())
+}
+
+func TestCache_Contract(t *testing.T) {
+	caches := map[string]func(capacity int) Cache[int, string]{
+		"LRU": func(capacity int) Cache[int, string] { return NewLRU[int, string](capacity) },
+		"LFU": func(capacity int) Cache[int, string] { return NewLFU[int, string](capacity) },
+		"ARC": func(capacity int) Cache[int, string] { return NewARC[int, string](capacity) },
+	}
+	for name, newCache := range caches {
+		t.Run(name, func(t *testing.T) {
+			c := newCache(4)
+			evictions := 0
+			c.OnEvict(func(key int, value string, reason EvictReason) {
+				if reason == EvictCapacity {
+					evictions++
+				}
+			})
+
+			for i := 0; i < 10; i++ {
+				c.Set(i, "v")
+				v, ok := c.Get(i)
+				assert.True(t, ok)
+				assert.Equal(t, "v", v)
+				assert.LessOrEqual(t, c.Len(), c.Cap())
+			}
+			assert.Equal(t, 4, c.Len())
+			assert.Equal(t, 6, evictions)
+			assert.Equal(t, uint64(6), c.Stats().Evictions)
+			assert.Equal(t, uint64(10), c.Stats().Hits)
+
+			assert.True(t, c.Remove(9))
+			assert.False(t, c.Remove(9))
+			_, ok := c.Peek(9)
+			assert.False(t, ok)
+
+			c.Clear()
+			assert.Equal(t, 0, c.Len())
+		})
+	}
+}
@@ -0,0 +1,247 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcollection
+
+import (
+	"github.com/chenquan/go-pkg/xds"
+	"iter"
+	"sync"
+)
+
+type (
+	// A Deque is a double-ended queue backed by a growable ring buffer,
+	// pushing and popping at both ends is amortized O(1).
+	// The zero value is an empty deque ready to use. It's not safe for concurrent use, see SyncDeque.
+	Deque[T any] struct {
+		d xds.Deque[T]
+	}
+
+	// A SyncDeque is a Deque safe for concurrent use.
+	SyncDeque[T any] struct {
+		lock sync.Mutex
+		d    Deque[T]
+	}
+)
+
+// NewDeque returns an empty Deque.
+func NewDeque[T any]() *Deque[T] {
+	return &Deque[T]{}
+}
+
+// Len returns the number of elements.
+func (d *Deque[T]) Len() int {
+	return d.d.Len()
+}
+
+// PushFront adds v at the front.
+func (d *Deque[T]) PushFront(v T) {
+	d.d.PushFront(v)
+}
+
+// PopFront removes and returns the front element, it panics if d is empty.
+func (d *Deque[T]) PopFront() T {
+	v, ok := d.d.PopFront()
+	if !ok {
+		panic("xcollection: pop from an empty deque")
+	}
+
+	return v
+}
+
+// TryPopFront removes and returns the front element, it returns false if d is empty.
+func (d *Deque[T]) TryPopFront() (T, bool) {
+	return d.d.PopFront()
+}
+
+// PeekFront returns the front element without removing it, it panics if d is empty.
+func (d *Deque[T]) PeekFront() T {
+	v, ok := d.d.PeekFront()
+	if !ok {
+		panic("xcollection: peek at an empty deque")
+	}
+
+	return v
+}
+
+// TryPeekFront returns the front element without removing it, it returns false if d is empty.
+func (d *Deque[T]) TryPeekFront() (T, bool) {
+	return d.d.PeekFront()
+}
+
+// PushBack adds v at the back.
+func (d *Deque[T]) PushBack(v T) {
+	d.d.PushBack(v)
+}
+
+// PopBack removes and returns the back element, it panics if d is empty.
+func (d *Deque[T]) PopBack() T {
+	v, ok := d.d.PopBack()
+	if !ok {
+		panic("xcollection: pop from an empty deque")
+	}
+
+	return v
+}
+
+// TryPopBack removes and returns the back element, it returns false if d is empty.
+func (d *Deque[T]) TryPopBack() (T, bool) {
+	return d.d.PopBack()
+}
+
+// PeekBack returns the back element without removing it, it panics if d is empty.
+func (d *Deque[T]) PeekBack() T {
+	v, ok := d.d.PeekBack()
+	if !ok {
+		panic("xcollection: peek at an empty deque")
+	}
+
+	return v
+}
+
+// TryPeekBack returns the back element without removing it, it returns false if d is empty.
+func (d *Deque[T]) TryPeekBack() (T, bool) {
+	return d.d.PeekBack()
+}
+
+// At returns the i-th element from the front, it panics if i is out of range.
+func (d *Deque[T]) At(i int) T {
+	return d.d.At(i)
+}
+
+// All returns an iterator over the elements from front to back.
+func (d *Deque[T]) All() iter.Seq[T] {
+	return d.d.Range
+}
+
+// Clear removes all elements.
+func (d *Deque[T]) Clear() {
+	d.d.Clear()
+}
+
+// NewSyncDeque returns an empty SyncDeque.
+func NewSyncDeque[T any]() *SyncDeque[T] {
+	return &SyncDeque[T]{}
+}
+
+// Len returns the number of elements.
+func (d *SyncDeque[T]) Len() int {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return d.d.Len()
+}
+
+// PushFront adds v at the front.
+func (d *SyncDeque[T]) PushFront(v T) {
+	d.lock.Lock()
+	d.d.PushFront(v)
+	d.lock.Unlock()
+}
+
+// PopFront removes and returns the front element, it panics if d is empty.
+func (d *SyncDeque[T]) PopFront() T {
+	v, ok := d.TryPopFront()
+	if !ok {
+		panic("xcollection: pop from an empty deque")
+	}
+
+	return v
+}
+
+// TryPopFront removes and returns the front element, it returns false if d is empty.
+func (d *SyncDeque[T]) TryPopFront() (T, bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return d.d.TryPopFront()
+}
+
+// PeekFront returns the front element without removing it, it panics if d is empty.
+func (d *SyncDeque[T]) PeekFront() T {
+	v, ok := d.TryPeekFront()
+	if !ok {
+		panic("xcollection: peek at an empty deque")
+	}
+
+	return v
+}
+
+// TryPeekFront returns the front element without removing it, it returns false if d is empty.
+func (d *SyncDeque[T]) TryPeekFront() (T, bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return d.d.TryPeekFront()
+}
+
+// PushBack adds v at the back.
+func (d *SyncDeque[T]) PushBack(v T) {
+	d.lock.Lock()
+	d.d.PushBack(v)
+	d.lock.Unlock()
+}
+
+// PopBack removes and returns the back element, it panics if d is empty.
+func (d *SyncDeque[T]) PopBack() T {
+	v, ok := d.TryPopBack()
+	if !ok {
+		panic("xcollection: pop from an empty deque")
+	}
+
+	return v
+}
+
+// TryPopBack removes and returns the back element, it returns false if d is empty.
+func (d *SyncDeque[T]) TryPopBack() (T, bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return d.d.TryPopBack()
+}
+
+// PeekBack returns the back element without removing it, it panics if d is empty.
+func (d *SyncDeque[T]) PeekBack() T {
+	v, ok := d.TryPeekBack()
+	if !ok {
+		panic("xcollection: peek at an empty deque")
+	}
+
+	return v
+}
+
+// TryPeekBack returns the back element without removing it, it returns false if d is empty.
+func (d *SyncDeque[T]) TryPeekBack() (T, bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return d.d.TryPeekBack()
+}
+
+// Snapshot returns a copy of the elements from front to back.
+func (d *SyncDeque[T]) Snapshot() []T {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return appendAll(make([]T, 0, d.d.Len()), d.d.All())
+}
+
+// Clear removes all elements.
+func (d *SyncDeque[T]) Clear() {
+	d.lock.Lock()
+	d.d.Clear()
+	d.lock.Unlock()
+}
@@ -0,0 +1,127 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcollection
+
+import (
+	"github.com/stretchr/testify/assert"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestDeque(t *testing.T) {
+	var d Deque[int]
+	_, ok := d.TryPopFront()
+	assert.False(t, ok)
+	_, ok = d.TryPopBack()
+	assert.False(t, ok)
+	_, ok = d.TryPeekFront()
+	assert.False(t, ok)
+	_, ok = d.TryPeekBack()
+	assert.False(t, ok)
+	assert.PanicsWithValue(t, "xcollection: pop from an empty deque", func() { d.PopFront() })
+	assert.PanicsWithValue(t, "xcollection: pop from an empty deque", func() { d.PopBack() })
+	assert.PanicsWithValue(t, "xcollection: peek at an empty deque", func() { d.PeekFront() })
+	assert.PanicsWithValue(t, "xcollection: peek at an empty deque", func() { d.PeekBack() })
+
+	for i := 0; i < 20; i++ {
+		d.PushBack(i)
+		d.PushFront(-i - 1)
+	}
+	assert.Equal(t, 40, d.Len())
+	assert.Equal(t, -20, d.PeekFront())
+	assert.Equal(t, 19, d.PeekBack())
+	assert.Equal(t, -20, d.At(0))
+	assert.Equal(t, 19, d.At(39))
+	assert.Panics(t, func() { d.At(40) })
+
+	all := slices.Collect(d.All())
+	assert.True(t, slices.IsSorted(all))
+
+	assert.Equal(t, -20, d.PopFront())
+	assert.Equal(t, 19, d.PopBack())
+	v, ok := d.TryPopFront()
+	assert.True(t, ok)
+	assert.Equal(t, -19, v)
+	v, ok = d.TryPopBack()
+	assert.True(t, ok)
+	assert.Equal(t, 18, v)
+	v, ok = d.TryPeekFront()
+	assert.True(t, ok)
+	assert.Equal(t, -18, v)
+	v, ok = d.TryPeekBack()
+	assert.True(t, ok)
+	assert.Equal(t, 17, v)
+
+	d.Clear()
+	assert.Equal(t, 0, d.Len())
+	assert.Equal(t, 0, NewDeque[int]().Len())
+}
+
+func TestSyncDeque(t *testing.T) {
+	d := NewSyncDeque[int]()
+	assert.Panics(t, func() { d.PopFront() })
+	assert.Panics(t, func() { d.PopBack() })
+	assert.Panics(t, func() { d.PeekFront() })
+	assert.Panics(t, func() { d.PeekBack() })
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				d.PushBack(i)
+				d.PushFront(i)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 800, d.Len())
+	assert.Len(t, d.Snapshot(), 800)
+
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				_, ok := d.TryPopFront()
+				assert.True(t, ok)
+				_, ok = d.TryPopBack()
+				assert.True(t, ok)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 0, d.Len())
+
+	d.PushBack(1)
+	d.PushBack(2)
+	assert.Equal(t, 1, d.PeekFront())
+	assert.Equal(t, 2, d.PeekBack())
+	v, ok := d.TryPeekFront()
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	v, ok = d.TryPeekBack()
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+	assert.Equal(t, 1, d.PopFront())
+	assert.Equal(t, 2, d.PopBack())
+	d.PushBack(3)
+	d.Clear()
+	assert.Empty(t, d.Snapshot())
+}
@@ -0,0 +1,231 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcollection
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// An LFU is a cache of bounded size that evicts the least frequently used entry when full,
+// the least recently used one among equally frequent entries. Operations are O(1), except that
+// the first eviction after a Remove emptied the least frequent bucket scans all frequency buckets.
+// It suits skewed workloads whose popular keys stay popular, see ARC for shifting ones.
+// It's safe for concurrent use.
+type LFU[K comparable, V any] struct {
+	lock     sync.Mutex
+	items    map[K]*cacheEntry[K, V]
+	buckets  map[int]*cacheList[K, V]
+	minFreq  int
+	capacity int
+	onEvict  func(key K, value V, reason EvictReason)
+
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+}
+
+var _ Cache[string, int] = (*LFU[string, int])(nil)
+
+// NewLFU returns an empty LFU holding up to capacity entries, it panics if capacity is not positive.
+func NewLFU[K comparable, V any](capacity int) *LFU[K, V] {
+	if capacity < 1 {
+		panic("xcollection: capacity should be greater than 0")
+	}
+
+	return &LFU[K, V]{
+		items:    make(map[K]*cacheEntry[K, V], capacity),
+		buckets:  make(map[int]*cacheList[K, V]),
+		capacity: capacity,
+	}
+}
+
+// OnEvict registers fn to be called after an entry is evicted or removed, replacing any previous one.
+// fn is called without holding the lock, so it may use the cache.
+func (c *LFU[K, V]) OnEvict(fn func(key K, value V, reason EvictReason)) {
+	c.lock.Lock()
+	c.onEvict = fn
+	c.lock.Unlock()
+}
+
+// Len returns the number of entries.
+func (c *LFU[K, V]) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return len(c.items)
+}
+
+// Cap returns the capacity.
+func (c *LFU[K, V]) Cap() int {
+	return c.capacity
+}
+
+// Get returns the value of key and increments its frequency.
+func (c *LFU[K, V]) Get(key K) (V, bool) {
+	c.lock.Lock()
+	e, ok := c.items[key]
+	if !ok {
+		c.lock.Unlock()
+		c.misses.Add(1)
+
+		var zero V
+		return zero, false
+	}
+
+	c.touch(e)
+	value := e.value
+	c.lock.Unlock()
+	c.hits.Add(1)
+
+	return value, true
+}
+
+// Peek returns the value of key without updating its frequency nor the statistics.
+func (c *LFU[K, V]) Peek(key K) (V, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if e, ok := c.items[key]; ok {
+		return e.value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Frequency returns the number of accesses of key, including the one that stored it.
+func (c *LFU[K, V]) Frequency(key K) int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if e, ok := c.items[key]; ok {
+		return e.freq
+	}
+
+	return 0
+}
+
+// Set stores value under key. Updating an existing key counts as an access,
+// a new key starts with a frequency of 1 and evicts the least frequently used entry if the cache is full.
+func (c *LFU[K, V]) Set(key K, value V) {
+	c.lock.Lock()
+	if e, ok := c.items[key]; ok {
+		e.value = value
+		c.touch(e)
+		c.lock.Unlock()
+		return
+	}
+
+	var victim *cacheEntry[K, V]
+	if len(c.items) >= c.capacity {
+		victim = c.leastFrequent()
+		c.unlink(victim)
+		delete(c.items, victim.key)
+		c.evictions.Add(1)
+	}
+
+	e := &cacheEntry[K, V]{key: key, value: value, freq: 1}
+	c.items[key] = e
+	c.bucket(1).pushFront(e)
+	c.minFreq = 1
+	onEvict := c.onEvict
+	c.lock.Unlock()
+
+	if victim != nil && onEvict != nil {
+		onEvict(victim.key, victim.value, EvictCapacity)
+	}
+}
+
+// Remove removes key and reports whether it was present.
+func (c *LFU[K, V]) Remove(key K) bool {
+	c.lock.Lock()
+	e, ok := c.items[key]
+	if !ok {
+		c.lock.Unlock()
+		return false
+	}
+	c.unlink(e)
+	delete(c.items, key)
+	onEvict := c.onEvict
+	c.lock.Unlock()
+
+	if onEvict != nil {
+		onEvict(e.key, e.value, EvictRemoved)
+	}
+
+	return true
+}
+
+// Clear removes all entries without calling the eviction callback.
+func (c *LFU[K, V]) Clear() {
+	c.lock.Lock()
+	clear(c.items)
+	clear(c.buckets)
+	c.minFreq = 0
+	c.lock.Unlock()
+}
+
+// Stats returns the hit, miss and eviction counters.
+func (c *LFU[K, V]) Stats() CacheStats {
+	return CacheStats{Hits: c.hits.Load(), Misses: c.misses.Load(), Evictions: c.evictions.Load()}
+}
+
+// leastFrequent returns the entry to evict, the cache must not be empty.
+func (c *LFU[K, V]) leastFrequent() *cacheEntry[K, V] {
+	if _, ok := c.buckets[c.minFreq]; !ok {
+		// the least frequent entries have been removed.
+		c.minFreq = 0
+		for freq := range c.buckets {
+			if c.minFreq == 0 || freq < c.minFreq {
+				c.minFreq = freq
+			}
+		}
+	}
+
+	return c.buckets[c.minFreq].back()
+}
+
+// touch moves e to the bucket of the next frequency.
+func (c *LFU[K, V]) touch(e *cacheEntry[K, V]) {
+	freq := e.freq
+	c.unlink(e)
+	if c.minFreq == freq && c.buckets[freq] == nil {
+		c.minFreq++
+	}
+	e.freq++
+	c.bucket(e.freq).pushFront(e)
+}
+
+// unlink removes e from its bucket, dropping the bucket once empty.
+func (c *LFU[K, V]) unlink(e *cacheEntry[K, V]) {
+	l := e.list
+	l.remove(e)
+	if l.len == 0 {
+		delete(c.buckets, e.freq)
+	}
+}
+
+func (c *LFU[K, V]) bucket(freq int) *cacheList[K, V] {
+	l, ok := c.buckets[freq]
+	if !ok {
+		l = newCacheList[K, V]()
+		c.buckets[freq] = l
+	}
+
+	return l
+}
@@ -0,0 +1,199 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcollection
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func TestLFU(t *testing.T) {
+	c := NewLFU[string, int](3)
+	var events []cacheEvent
+	c.OnEvict(func(key string, value int, reason EvictReason) {
+		events = append(events, cacheEvent{key, value, reason})
+	})
+	assert.Equal(t, 3, c.Cap())
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+	c.Get("a")
+	c.Get("a")
+	c.Get("b")
+	assert.Equal(t, 3, c.Frequency("a"))
+	assert.Equal(t, 2, c.Frequency("b"))
+	assert.Equal(t, 0, c.Frequency("x"))
+
+	// c is the least frequently used.
+	c.Set("d", 4)
+	_, ok := c.Peek("c")
+	assert.False(t, ok)
+	assert.Equal(t, []cacheEvent{{"c", 3, EvictCapacity}}, events)
+
+	// b and d have the same frequency, b is the least recently used.
+	c.Get("d")
+	c.Set("e", 5)
+	assert.Equal(t, cacheEvent{"b", 2, EvictCapacity}, events[1])
+
+	// updating counts as an access.
+	c.Set("e", 50)
+	c.Set("e", 500)
+	assert.Equal(t, 3, c.Frequency("e"))
+	v, ok := c.Peek("e")
+	assert.True(t, ok)
+	assert.Equal(t, 500, v)
+	assert.Equal(t, 3, c.Frequency("e"), "Peek doesn't count")
+
+	assert.True(t, c.Remove("d"))
+	assert.False(t, c.Remove("d"))
+	assert.Equal(t, cacheEvent{"d", 4, EvictRemoved}, events[2])
+	assert.Equal(t, 2, c.Len())
+
+	_, ok = c.Get("zzz")
+	assert.False(t, ok)
+	assert.Equal(t, CacheStats{Hits: 4, Misses: 1, Evictions: 2}, c.Stats())
+
+	c.Clear()
+	assert.Equal(t, 0, c.Len())
+	c.Set("f", 6)
+	assert.Equal(t, 1, c.Frequency("f"))
+
+	assert.Panics(t, func() {
+		NewLFU[int, int](0)
+	})
+}
+
+func TestLFU_RemoveLeastFrequent(t *testing.T) {
+	c := NewLFU[int, int](2)
+	c.Set(1, 1)
+	c.Set(2, 2)
+	c.Get(2)
+	c.Get(2)
+	// remove the only entry of the lowest frequency, then evict.
+	c.Remove(1)
+	c.Set(3, 3)
+	c.Get(3)
+	c.Get(3)
+	c.Get(3)
+	c.Set(4, 4)
+	_, ok := c.Peek(2)
+	assert.False(t, ok)
+	_, ok = c.Peek(3)
+	assert.True(t, ok)
+	_, ok = c.Peek(4)
+	assert.True(t, ok)
+}
+
+func TestLFU_Model(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	const capacity = 8
+	c := NewLFU[int, int](capacity)
+	type state struct{ freq, tick int }
+	model := map[int]*state{}
+	tick := 0
+	for i := 0; i < 5000; i++ {
+		tick++
+		k := r.Intn(20)
+		switch r.Intn(3) {
+		case 0:
+			_, ok := c.Get(k)
+			s, exists := model[k]
+			assert.Equal(t, exists, ok)
+			if exists {
+				s.freq++
+				s.tick = tick
+			}
+		case 1:
+			if s, exists := model[k]; exists {
+				s.freq++
+				s.tick = tick
+			} else {
+				if len(model) >= capacity {
+					victim := -1
+					for key, s := range model {
+						if victim < 0 || s.freq < model[victim].freq || (s.freq == model[victim].freq && s.tick < model[victim].tick) {
+							victim = key
+						}
+					}
+					delete(model, victim)
+				}
+				model[k] = &state{freq: 1, tick: tick}
+			}
+			c.Set(k, k)
+		default:
+			_, exists := model[k]
+			assert.Equal(t, exists, c.Remove(k))
+			delete(model, k)
+		}
+
+		assert.Equal(t, len(model), c.Len())
+		for key, s := range model {
+			assert.Equal(t, s.freq, c.Frequency(key))
+		}
+	}
+}
+
+func TestLFU_Concurrent(t *testing.T) {
+	c := NewLFU[int, int](64)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(g)))
+			for i := 0; i < 5000; i++ {
+				k := r.Intn(128)
+				switch r.Intn(4) {
+				case 0:
+					c.Set(k, k)
+				case 1:
+					c.Remove(k)
+				default:
+					if v, ok := c.Get(k); ok {
+						assert.Equal(t, k, v)
+					}
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	assert.LessOrEqual(t, c.Len(), 64)
+}
+
+func BenchmarkLFU_Freq(b *testing.B) {
+	c := NewLFU[int64, int64](8192)
+	trace := make([]int64, b.N*2)
+	for i := range trace {
+		if i%2 == 0 {
+			trace[i] = rand.Int63() % 16384
+		} else {
+			trace[i] = rand.Int63() % 32768
+		}
+	}
+	for i := 0; i < b.N; i++ {
+		c.Set(trace[i], trace[i])
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Get(trace[i])
+	}
+}
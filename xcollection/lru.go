@@ -0,0 +1,232 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcollection
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// An LRU is a cache of bounded size that evicts the least recently used entry when full.
+	// Entries may also expire after a TTL, see WithTTL and SetWithTTL. Expired entries are evicted
+	// lazily when accessed or when they become the least recently used. It's safe for concurrent use.
+	LRU[K comparable, V any] struct {
+		lock     sync.Mutex
+		items    map[K]*cacheEntry[K, V]
+		list     *cacheList[K, V]
+		capacity int
+		ttl      time.Duration
+		now      func() time.Time
+		onEvict  func(key K, value V, reason EvictReason)
+
+		hits      atomic.Uint64
+		misses    atomic.Uint64
+		evictions atomic.Uint64
+	}
+)
+
+var _ Cache[string, int] = (*LRU[string, int])(nil)
+
+// NewLRU returns an empty LRU holding up to capacity entries, it panics if capacity is not positive.
+// WithTTL and WithClock are the available options.
+func NewLRU[K comparable, V any](capacity int, opts ...Option) *LRU[K, V] {
+	if capacity < 1 {
+		panic("xcollection: capacity should be greater than 0")
+	}
+
+	o := newOptions(opts)
+	c := &LRU[K, V]{
+		items:    make(map[K]*cacheEntry[K, V], capacity),
+		list:     newCacheList[K, V](),
+		capacity: capacity,
+		ttl:      o.ttl,
+		now:      o.now,
+	}
+
+	return c
+}
+
+// OnEvict registers fn to be called after an entry is evicted or removed, replacing any previous one.
+// fn is called without holding the lock, so it may use the cache.
+func (c *LRU[K, V]) OnEvict(fn func(key K, value V, reason EvictReason)) {
+	c.lock.Lock()
+	c.onEvict = fn
+	c.lock.Unlock()
+}
+
+// Len returns the number of entries, including expired ones not evicted yet.
+func (c *LRU[K, V]) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return len(c.items)
+}
+
+// Cap returns the capacity.
+func (c *LRU[K, V]) Cap() int {
+	return c.capacity
+}
+
+// Get returns the value of key and marks it as the most recently used.
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	c.lock.Lock()
+	e, ok := c.items[key]
+	if ok && c.alive(e) {
+		c.list.moveToFront(e)
+		value := e.value
+		c.lock.Unlock()
+		c.hits.Add(1)
+
+		return value, true
+	}
+
+	if ok {
+		c.remove(e)
+		c.evictions.Add(1)
+	}
+	onEvict := c.onEvict
+	c.lock.Unlock()
+	c.misses.Add(1)
+	if ok && onEvict != nil {
+		onEvict(e.key, e.value, EvictExpired)
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Peek returns the value of key without updating its recency nor the statistics.
+func (c *LRU[K, V]) Peek(key K) (V, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if e, ok := c.items[key]; ok && c.alive(e) {
+		return e.value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Set stores value under key with the default TTL and marks it as the most recently used,
+// evicting the least recently used entry if the cache is full.
+func (c *LRU[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.ttl)
+}
+
+// SetWithTTL is like Set, but the entry expires after ttl, a non-positive ttl means never.
+func (c *LRU[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	var expireAt int64
+	if ttl > 0 {
+		expireAt = c.now().Add(ttl).UnixNano()
+	}
+
+	c.lock.Lock()
+	if e, ok := c.items[key]; ok {
+		e.value = value
+		e.expireAt = expireAt
+		c.list.moveToFront(e)
+		c.lock.Unlock()
+		return
+	}
+
+	var victim *cacheEntry[K, V]
+	if len(c.items) >= c.capacity {
+		victim = c.list.back()
+		c.remove(victim)
+		c.evictions.Add(1)
+	}
+	e := &cacheEntry[K, V]{key: key, value: value, expireAt: expireAt}
+	c.items[key] = e
+	c.list.pushFront(e)
+	onEvict := c.onEvict
+	c.lock.Unlock()
+
+	if victim != nil && onEvict != nil {
+		reason := EvictCapacity
+		if !c.alive(victim) {
+			reason = EvictExpired
+		}
+		onEvict(victim.key, victim.value, reason)
+	}
+}
+
+// Remove removes key and reports whether it was present and not expired.
+func (c *LRU[K, V]) Remove(key K) bool {
+	c.lock.Lock()
+	e, ok := c.items[key]
+	if !ok {
+		c.lock.Unlock()
+		return false
+	}
+	c.remove(e)
+	expired := !c.alive(e)
+	onEvict := c.onEvict
+	c.lock.Unlock()
+
+	if onEvict != nil {
+		reason := EvictRemoved
+		if expired {
+			reason = EvictExpired
+		}
+		onEvict(e.key, e.value, reason)
+	}
+
+	return !expired
+}
+
+// Keys returns the keys of the entries not expired, from the most to the least recently used.
+func (c *LRU[K, V]) Keys() []K {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.now().UnixNano()
+	keys := make([]K, 0, len(c.items))
+	for e := c.list.root.next; e != &c.list.root; e = e.next {
+		if !e.expired(now) {
+			keys = append(keys, e.key)
+		}
+	}
+
+	return keys
+}
+
+// Clear removes all entries without calling the eviction callback.
+func (c *LRU[K, V]) Clear() {
+	c.lock.Lock()
+	clear(c.items)
+	c.list = newCacheList[K, V]()
+	c.lock.Unlock()
+}
+
+// Stats returns the hit, miss and eviction counters.
+// Evictions count the entries evicted for room or expiration, not the removed ones.
+func (c *LRU[K, V]) Stats() CacheStats {
+	return CacheStats{Hits: c.hits.Load(), Misses: c.misses.Load(), Evictions: c.evictions.Load()}
+}
+
+func (c *LRU[K, V]) remove(e *cacheEntry[K, V]) {
+	delete(c.items, e.key)
+	c.list.remove(e)
+}
+
+// alive reports whether e has not expired, it avoids reading the clock for entries without TTL.
+func (c *LRU[K, V]) alive(e *cacheEntry[K, V]) bool {
+	return e.expireAt == 0 || c.now().UnixNano() < e.expireAt
+}
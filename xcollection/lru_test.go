@@ -0,0 +1,247 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcollection
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+type cacheEvent struct {
+	key    string
+	value  int
+	reason EvictReason
+}
+
+type testClock struct {
+	lock sync.Mutex
+	now  time.Time
+}
+
+func newTestClock() *testClock {
+	return &testClock{now: time.Unix(1700000000, 0)}
+}
+
+func (c *testClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	c.now = c.now.Add(d)
+	c.lock.Unlock()
+}
+
+func TestLRU(t *testing.T) {
+	c := NewLRU[string, int](2)
+	var events []cacheEvent
+	c.OnEvict(func(key string, value int, reason EvictReason) {
+		events = append(events, cacheEvent{key, value, reason})
+	})
+	assert.Equal(t, 2, c.Cap())
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	// b is the least recently used.
+	c.Set("c", 3)
+	_, ok = c.Get("b")
+	assert.False(t, ok)
+	assert.Equal(t, []cacheEvent{{"b", 2, EvictCapacity}}, events)
+	assert.Equal(t, []string{"c", "a"}, c.Keys())
+
+	// Peek doesn't change the recency.
+	v, ok = c.Peek("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	_, ok = c.Peek("x")
+	assert.False(t, ok)
+	c.Set("d", 4)
+	assert.Equal(t, []string{"d", "c"}, c.Keys())
+	assert.Equal(t, cacheEvent{"a", 1, EvictCapacity}, events[1])
+
+	// updating moves to the front without eviction.
+	c.Set("c", 30)
+	assert.Equal(t, []string{"c", "d"}, c.Keys())
+	assert.Len(t, events, 2)
+
+	assert.True(t, c.Remove("d"))
+	assert.False(t, c.Remove("d"))
+	assert.Equal(t, cacheEvent{"d", 4, EvictRemoved}, events[2])
+	assert.Equal(t, 1, c.Len())
+
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 1, Evictions: 2}, c.Stats())
+	assert.Equal(t, 0.5, c.Stats().HitRatio())
+
+	c.Clear()
+	assert.Equal(t, 0, c.Len())
+	assert.Empty(t, c.Keys())
+	assert.Len(t, events, 3)
+	c.Set("e", 5)
+	assert.Equal(t, []string{"e"}, c.Keys())
+
+	assert.Panics(t, func() {
+		NewLRU[int, int](0)
+	})
+}
+
+func TestLRU_TTL(t *testing.T) {
+	clock := newTestClock()
+	c := NewLRU[string, int](3, WithTTL(time.Minute), WithClock(clock.Now))
+	var events []cacheEvent
+	c.OnEvict(func(key string, value int, reason EvictReason) {
+		events = append(events, cacheEvent{key, value, reason})
+	})
+
+	c.Set("a", 1)
+	c.SetWithTTL("b", 2, time.Hour)
+	c.SetWithTTL("c", 3, 0)
+
+	clock.Advance(time.Minute)
+	assert.Equal(t, []string{"c", "b"}, c.Keys())
+	_, ok := c.Peek("a")
+	assert.False(t, ok)
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, []cacheEvent{{"a", 1, EvictExpired}}, events)
+	assert.Equal(t, 2, c.Len())
+
+	// an expired least recently used entry is reported as expired when evicted for room.
+	c.SetWithTTL("d", 4, time.Second)
+	c.Get("b")
+	c.Get("c")
+	clock.Advance(time.Second)
+	c.Set("e", 5)
+	assert.Equal(t, cacheEvent{"d", 4, EvictExpired}, events[1])
+
+	clock.Advance(time.Hour)
+	assert.False(t, c.Remove("b"))
+	assert.Equal(t, cacheEvent{"b", 2, EvictExpired}, events[2])
+	v, ok := c.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+}
+
+func TestLRU_CallbackReentrant(t *testing.T) {
+	c := NewLRU[string, int](1)
+	var backup sync.Map
+	c.OnEvict(func(key string, value int, _ EvictReason) {
+		backup.Store(key, value)
+		c.Peek(key)
+	})
+	c.Set("a", 1)
+	c.Set("b", 2)
+	v, ok := backup.Load("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+}
+
+func TestLRU_Concurrent(t *testing.T) {
+	c := NewLRU[int, int](100)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(g)))
+			for i := 0; i < 5000; i++ {
+				k := r.Intn(200)
+				switch r.Intn(4) {
+				case 0:
+					c.Set(k, k)
+				case 1:
+					c.Remove(k)
+				default:
+					if v, ok := c.Get(k); ok {
+						assert.Equal(t, k, v)
+					}
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	assert.LessOrEqual(t, c.Len(), 100)
+	assert.Len(t, c.Keys(), c.Len())
+}
+
+func BenchmarkLRU_Rand(b *testing.B) {
+	c := NewLRU[int64, int64](8192)
+	trace := make([]int64, b.N*2)
+	for i := range trace {
+		trace[i] = rand.Int63() % 32768
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < 2*b.N; i++ {
+		if i%2 == 0 {
+			c.Set(trace[i], trace[i])
+		} else {
+			c.Get(trace[i])
+		}
+	}
+}
+
+func BenchmarkLRU_Freq(b *testing.B) {
+	c := NewLRU[int64, int64](8192)
+	trace := make([]int64, b.N*2)
+	for i := range trace {
+		if i%2 == 0 {
+			trace[i] = rand.Int63() % 16384
+		} else {
+			trace[i] = rand.Int63() % 32768
+		}
+	}
+	for i := 0; i < b.N; i++ {
+		c.Set(trace[i], trace[i])
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Get(trace[i])
+	}
+}
+
+func BenchmarkLRU_Parallel(b *testing.B) {
+	c := NewLRU[string, int](1024)
+	for i := 0; i < 1024; i++ {
+		c.Set(strconv.Itoa(i), i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		r := rand.New(rand.NewSource(rand.Int63()))
+		for pb.Next() {
+			k := strconv.Itoa(r.Intn(2048))
+			if _, ok := c.Get(k); !ok {
+				c.Set(k, 0)
+			}
+		}
+	})
+}
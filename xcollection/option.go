@@ -0,0 +1,65 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcollection
+
+import "time"
+
+type (
+	// Option defines the method to customize the collections.
+	Option func(*options)
+
+	options struct {
+		capacity int
+		ttl      time.Duration
+		now      func() time.Time
+	}
+)
+
+// WithCapacity bounds the number of elements to n, what happens on overflow is documented by each collection.
+// A non-positive n means unbounded, which is the default.
+func WithCapacity(n int) Option {
+	return func(o *options) {
+		o.capacity = n
+	}
+}
+
+// WithTTL makes the entries of a cache expire after d by default.
+// A non-positive d means entries never expire, which is the default.
+func WithTTL(d time.Duration) Option {
+	return func(o *options) {
+		o.ttl = d
+	}
+}
+
+// WithClock customizes the clock used to compute expiration, default to time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{now: time.Now}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.capacity < 0 {
+		o.capacity = 0
+	}
+
+	return o
+}
@@ -0,0 +1,64 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcollection
+
+import "github.com/chenquan/go-pkg/xds"
+
+// A PriorityQueue is a heap ordered by a comparison function:
+// Pop returns first the element ordered first by cmp, which has the highest priority.
+// E.g. cmp.Compare pops the least element first, like a timer queue keyed by deadline.
+// Push returns an *xds.Handle to update, fix or remove the element later.
+// It's not safe for concurrent use.
+//
+// With WithCapacity(n), pushing into a full queue evicts the element with the lowest priority,
+// unless the new one has an even lower priority and is rejected, so the queue keeps the n elements
+// with the highest priority, e.g. a top-k. Eviction takes O(n).
+type PriorityQueue[T any] struct {
+	*xds.PriorityQueue[T]
+	cmp      func(a, b T) int
+	capacity int
+}
+
+// NewPriorityQueue returns an empty PriorityQueue ordered by cmp, it panics if cmp is nil.
+func NewPriorityQueue[T any](cmp func(a, b T) int, opts ...Option) *PriorityQueue[T] {
+	if cmp == nil {
+		panic("xcollection: nil cmp")
+	}
+
+	less := func(a, b T) bool {
+		return cmp(a, b) < 0
+	}
+
+	return &PriorityQueue[T]{
+		PriorityQueue: xds.NewPriorityQueue(less),
+		cmp:           cmp,
+		capacity:      newOptions(opts).capacity,
+	}
+}
+
+// Push adds v and returns its handle, or nil if v is rejected by a full bounded queue.
+func (q *PriorityQueue[T]) Push(v T) *xds.Handle[T] {
+	if q.capacity > 0 && q.Len() >= q.capacity {
+		lowest, _ := q.Max()
+		if q.cmp(v, lowest.Value()) >= 0 {
+			return nil
+		}
+		q.Remove(lowest)
+	}
+
+	return q.PriorityQueue.Push(v)
+}
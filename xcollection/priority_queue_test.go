@@ -0,0 +1,142 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcollection
+
+import (
+	"cmp"
+	"github.com/chenquan/go-pkg/xds"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+func drain[T any](q *PriorityQueue[T]) []T {
+	var out []T
+	for q.Len() > 0 {
+		v, _ := q.Pop()
+		out = append(out, v)
+	}
+
+	return out
+}
+
+func TestPriorityQueue(t *testing.T) {
+	q := NewPriorityQueue(cmp.Compare[int])
+	_, ok := q.Pop()
+	assert.False(t, ok)
+	_, ok = q.Peek()
+	assert.False(t, ok)
+
+	for _, v := range []int{5, 1, 4, 2, 3} {
+		q.Push(v)
+	}
+	assert.Equal(t, 5, q.Len())
+	v, ok := q.Peek()
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, drain(q))
+
+	assert.Panics(t, func() {
+		NewPriorityQueue[int](nil)
+	})
+}
+
+func TestPriorityQueue_Handles(t *testing.T) {
+	type timer struct {
+		name     string
+		deadline time.Duration
+	}
+	q := NewPriorityQueue(func(a, b *timer) int { return cmp.Compare(a.deadline, b.deadline) })
+
+	a := q.Push(&timer{"a", 3 * time.Second})
+	b := q.Push(&timer{"b", 2 * time.Second})
+	c := q.Push(&timer{"c", time.Second})
+	assert.Equal(t, "a", a.Value().name)
+
+	// reschedule a to fire first.
+	a.Value().deadline = 0
+	assert.True(t, q.Fix(a))
+	top, _ := q.Peek()
+	assert.Equal(t, "a", top.name)
+
+	assert.True(t, q.Update(b, &timer{"b2", time.Millisecond}))
+	assert.True(t, q.Remove(c))
+	assert.False(t, q.Remove(c))
+	assert.False(t, q.Fix(c))
+	assert.False(t, q.Update(c, &timer{}))
+
+	var names []string
+	for _, tm := range drain(q) {
+		names = append(names, tm.name)
+	}
+	assert.Equal(t, []string{"a", "b2"}, names)
+	assert.False(t, q.Remove(a), "popped handles are invalid")
+	assert.False(t, q.Remove(nil))
+
+	h := q.Push(&timer{"x", time.Second})
+	q.Clear()
+	assert.Equal(t, 0, q.Len())
+	assert.False(t, q.Fix(h))
+}
+
+func TestPriorityQueue_Bounded(t *testing.T) {
+	// keep the 3 greatest values, popping the greatest first.
+	q := NewPriorityQueue(func(a, b int) int { return cmp.Compare(b, a) }, WithCapacity(3))
+	assert.NotNil(t, q.Push(5))
+	assert.NotNil(t, q.Push(1))
+	assert.NotNil(t, q.Push(3))
+	assert.Nil(t, q.Push(0), "lower than the lowest priority")
+	assert.Nil(t, q.Push(1), "ties keep the existing element")
+	assert.NotNil(t, q.Push(4))
+	assert.Equal(t, 3, q.Len())
+	assert.Equal(t, []int{5, 4, 3}, drain(q))
+}
+
+func TestPriorityQueue_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for round := 0; round < 50; round++ {
+		k := r.Intn(10) + 1
+		topK := NewPriorityQueue(func(a, b int) int { return cmp.Compare(b, a) }, WithCapacity(k))
+		all := NewPriorityQueue(cmp.Compare[int])
+		pushed := make([]int, 100)
+		handles := make([]*xds.Handle[int], len(pushed))
+		for i := range pushed {
+			pushed[i] = r.Intn(1000)
+			topK.Push(pushed[i])
+			handles[i] = all.Push(pushed[i])
+		}
+
+		want := slices.Clone(pushed)
+		slices.Sort(want)
+		slices.Reverse(want)
+		assert.Equal(t, want[:k], drain(topK))
+
+		// remove a few elements by handle.
+		var kept []int
+		for i, h := range handles {
+			if r.Intn(4) == 0 {
+				assert.True(t, all.Remove(h))
+				continue
+			}
+			kept = append(kept, pushed[i])
+		}
+		slices.Sort(kept)
+		assert.Equal(t, kept, drain(all))
+	}
+}
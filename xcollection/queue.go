@@ -0,0 +1,163 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcollection
+
+import (
+	"github.com/chenquan/go-pkg/xds"
+	"iter"
+	"sync"
+)
+
+type (
+	// A Queue is a first-in-first-out queue backed by a growable ring buffer, operations are amortized O(1).
+	// The zero value is an empty queue ready to use. It's not safe for concurrent use, see SyncQueue.
+	Queue[T any] struct {
+		q xds.Queue[T]
+	}
+
+	// A SyncQueue is a Queue safe for concurrent use.
+	SyncQueue[T any] struct {
+		lock sync.Mutex
+		q    Queue[T]
+	}
+)
+
+// NewQueue returns an empty Queue.
+func NewQueue[T any]() *Queue[T] {
+	return &Queue[T]{}
+}
+
+// Len returns the number of elements.
+func (q *Queue[T]) Len() int {
+	return q.q.Len()
+}
+
+// Push adds v at the back.
+func (q *Queue[T]) Push(v T) {
+	q.q.Push(v)
+}
+
+// Pop removes and returns the front element, it panics if q is empty.
+func (q *Queue[T]) Pop() T {
+	v, ok := q.q.Pop()
+	if !ok {
+		panic("xcollection: pop from an empty queue")
+	}
+
+	return v
+}
+
+// TryPop removes and returns the front element, it returns false if q is empty.
+func (q *Queue[T]) TryPop() (T, bool) {
+	return q.q.Pop()
+}
+
+// Peek returns the front element without removing it, it panics if q is empty.
+func (q *Queue[T]) Peek() T {
+	v, ok := q.q.Peek()
+	if !ok {
+		panic("xcollection: peek at an empty queue")
+	}
+
+	return v
+}
+
+// TryPeek returns the front element without removing it, it returns false if q is empty.
+func (q *Queue[T]) TryPeek() (T, bool) {
+	return q.q.Peek()
+}
+
+// All returns an iterator over the elements from front to back.
+func (q *Queue[T]) All() iter.Seq[T] {
+	return q.q.Range
+}
+
+// Clear removes all elements.
+func (q *Queue[T]) Clear() {
+	q.q.Clear()
+}
+
+// NewSyncQueue returns an empty SyncQueue.
+func NewSyncQueue[T any]() *SyncQueue[T] {
+	return &SyncQueue[T]{}
+}
+
+// Len returns the number of elements.
+func (q *SyncQueue[T]) Len() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return q.q.Len()
+}
+
+// Push adds v at the back.
+func (q *SyncQueue[T]) Push(v T) {
+	q.lock.Lock()
+	q.q.Push(v)
+	q.lock.Unlock()
+}
+
+// Pop removes and returns the front element, it panics if q is empty.
+func (q *SyncQueue[T]) Pop() T {
+	v, ok := q.TryPop()
+	if !ok {
+		panic("xcollection: pop from an empty queue")
+	}
+
+	return v
+}
+
+// TryPop removes and returns the front element, it returns false if q is empty.
+func (q *SyncQueue[T]) TryPop() (T, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return q.q.TryPop()
+}
+
+// Peek returns the front element without removing it, it panics if q is empty.
+func (q *SyncQueue[T]) Peek() T {
+	v, ok := q.TryPeek()
+	if !ok {
+		panic("xcollection: peek at an empty queue")
+	}
+
+	return v
+}
+
+// TryPeek returns the front element without removing it, it returns false if q is empty.
+func (q *SyncQueue[T]) TryPeek() (T, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return q.q.TryPeek()
+}
+
+// Snapshot returns a copy of the elements from front to back.
+func (q *SyncQueue[T]) Snapshot() []T {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return appendAll(make([]T, 0, q.q.Len()), q.q.All())
+}
+
+// Clear removes all elements.
+func (q *SyncQueue[T]) Clear() {
+	q.lock.Lock()
+	q.q.Clear()
+	q.lock.Unlock()
+}
@@ -0,0 +1,100 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcollection
+
+import (
+	"github.com/stretchr/testify/assert"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestQueue(t *testing.T) {
+	var q Queue[string]
+	_, ok := q.TryPop()
+	assert.False(t, ok)
+	_, ok = q.TryPeek()
+	assert.False(t, ok)
+	assert.PanicsWithValue(t, "xcollection: pop from an empty queue", func() { q.Pop() })
+	assert.PanicsWithValue(t, "xcollection: peek at an empty queue", func() { q.Peek() })
+
+	for i := 0; i < 100; i++ {
+		q.Push(string(rune('a' + i%26)))
+	}
+	assert.Equal(t, 100, q.Len())
+	assert.Equal(t, "a", q.Peek())
+	assert.Equal(t, "a", q.Pop())
+	v, ok := q.TryPop()
+	assert.True(t, ok)
+	assert.Equal(t, "b", v)
+	v, ok = q.TryPeek()
+	assert.True(t, ok)
+	assert.Equal(t, "c", v)
+	assert.Equal(t, []string{"c", "d", "e"}, slices.Collect(q.All())[:3])
+
+	q.Clear()
+	assert.Equal(t, 0, q.Len())
+	assert.Equal(t, 0, NewQueue[int]().Len())
+}
+
+func TestSyncQueue(t *testing.T) {
+	q := NewSyncQueue[int]()
+	assert.Panics(t, func() { q.Pop() })
+	assert.Panics(t, func() { q.Peek() })
+
+	q.Push(1)
+	q.Push(2)
+	assert.Equal(t, []int{1, 2}, q.Snapshot())
+	assert.Equal(t, 1, q.Peek())
+	v, ok := q.TryPeek()
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	assert.Equal(t, 1, q.Pop())
+	q.Clear()
+
+	var (
+		wg     sync.WaitGroup
+		lock   sync.Mutex
+		popped []int
+	)
+	for g := 0; g < 4; g++ {
+		wg.Add(2)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				q.Push(g*100 + i)
+			}
+		}(g)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < 100; {
+				if v, ok := q.TryPop(); ok {
+					lock.Lock()
+					popped = append(popped, v)
+					lock.Unlock()
+					n++
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 0, q.Len())
+	slices.Sort(popped)
+	for i, v := range popped {
+		assert.Equal(t, i, v)
+	}
+}
@@ -0,0 +1,128 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcollection
+
+import (
+	"github.com/chenquan/go-pkg/xds"
+	"sync"
+)
+
+const (
+	// Overwrite makes a full ring buffer overwrite its oldest element.
+	Overwrite = xds.Overwrite
+	// Reject makes a full ring buffer reject new elements.
+	Reject = xds.Reject
+)
+
+type (
+	// FullPolicy decides what pushing into a full ring buffer does.
+	FullPolicy = xds.FullPolicy
+
+	// A RingBuffer is a fixed-capacity FIFO buffer, e.g. to keep the last N log lines.
+	// It's not safe for concurrent use, see SyncRingBuffer.
+	RingBuffer[T any] struct {
+		*xds.RingBuffer[T]
+	}
+
+	// A SyncRingBuffer is a RingBuffer safe for concurrent use.
+	SyncRingBuffer[T any] struct {
+		lock sync.Mutex
+		ring *xds.RingBuffer[T]
+	}
+)
+
+// NewRingBuffer returns a RingBuffer of the given capacity, it panics if capacity is not positive.
+func NewRingBuffer[T any](capacity int, policy FullPolicy) *RingBuffer[T] {
+	return &RingBuffer[T]{RingBuffer: xds.NewRingBuffer[T](capacity, policy)}
+}
+
+// NewSyncRingBuffer returns a SyncRingBuffer of the given capacity, it panics if capacity is not positive.
+func NewSyncRingBuffer[T any](capacity int, policy FullPolicy) *SyncRingBuffer[T] {
+	return &SyncRingBuffer[T]{ring: xds.NewRingBuffer[T](capacity, policy)}
+}
+
+// Len returns the number of elements.
+func (r *SyncRingBuffer[T]) Len() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.ring.Len()
+}
+
+// Cap returns the capacity.
+func (r *SyncRingBuffer[T]) Cap() int {
+	return r.ring.Cap()
+}
+
+// Full reports whether r is full.
+func (r *SyncRingBuffer[T]) Full() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.ring.Full()
+}
+
+// Push adds v as the newest element.
+// It returns false if r is full and its policy is Reject.
+func (r *SyncRingBuffer[T]) Push(v T) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.ring.Push(v)
+}
+
+// Pop removes and returns the oldest element.
+func (r *SyncRingBuffer[T]) Pop() (T, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.ring.Pop()
+}
+
+// Peek returns the oldest element without removing it.
+func (r *SyncRingBuffer[T]) Peek() (T, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.ring.Peek()
+}
+
+// Snapshot returns a copy of the elements from the oldest to the newest.
+func (r *SyncRingBuffer[T]) Snapshot() []T {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.ring.Snapshot()
+}
+
+// Drain removes and returns all elements from the oldest to the newest.
+func (r *SyncRingBuffer[T]) Drain() []T {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	elements := r.ring.Snapshot()
+	r.ring.Clear()
+
+	return elements
+}
+
+// Clear removes all elements.
+func (r *SyncRingBuffer[T]) Clear() {
+	r.lock.Lock()
+	r.ring.Clear()
+	r.lock.Unlock()
+}
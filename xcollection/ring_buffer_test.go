@@ -0,0 +1,109 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcollection
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestRingBuffer(t *testing.T) {
+	r := NewRingBuffer[string](3, Overwrite)
+	for _, line := range []string{"a", "b", "c", "d", "e"} {
+		assert.True(t, r.Push(line))
+	}
+	assert.True(t, r.Full())
+	assert.Equal(t, []string{"c", "d", "e"}, r.Snapshot())
+
+	v, ok := r.Peek()
+	assert.True(t, ok)
+	assert.Equal(t, "c", v)
+	v, ok = r.Pop()
+	assert.True(t, ok)
+	assert.Equal(t, "c", v)
+	assert.Equal(t, 2, r.Len())
+	assert.Equal(t, 3, r.Cap())
+
+	reject := NewRingBuffer[int](2, Reject)
+	assert.True(t, reject.Push(1))
+	assert.True(t, reject.Push(2))
+	assert.False(t, reject.Push(3))
+	assert.Equal(t, []int{1, 2}, reject.Snapshot())
+
+	assert.Panics(t, func() {
+		NewRingBuffer[int](0, Overwrite)
+	})
+}
+
+func TestSyncRingBuffer(t *testing.T) {
+	r := NewSyncRingBuffer[int](4, Overwrite)
+	_, ok := r.Pop()
+	assert.False(t, ok)
+	_, ok = r.Peek()
+	assert.False(t, ok)
+
+	for i := 1; i <= 6; i++ {
+		r.Push(i)
+	}
+	assert.True(t, r.Full())
+	assert.Equal(t, 4, r.Len())
+	assert.Equal(t, 4, r.Cap())
+	assert.Equal(t, []int{3, 4, 5, 6}, r.Snapshot())
+
+	v, ok := r.Peek()
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+	v, ok = r.Pop()
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+
+	assert.Equal(t, []int{4, 5, 6}, r.Drain())
+	assert.Equal(t, 0, r.Len())
+
+	r.Push(1)
+	r.Clear()
+	assert.Empty(t, r.Snapshot())
+
+	reject := NewSyncRingBuffer[int](1, Reject)
+	assert.True(t, reject.Push(1))
+	assert.False(t, reject.Push(2))
+}
+
+func TestSyncRingBuffer_Concurrent(t *testing.T) {
+	r := NewSyncRingBuffer[int](64, Overwrite)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				r.Push(i)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				r.Pop()
+				r.Snapshot()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.LessOrEqual(t, r.Len(), 64)
+}
@@ -0,0 +1,269 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcollection
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"iter"
+	"reflect"
+	"slices"
+)
+
+// A Set is an unordered collection of distinct elements.
+// The zero value is an empty set ready to use. It's not safe for concurrent use.
+type Set[T comparable] struct {
+	m map[T]struct{}
+}
+
+// NewSet returns a Set of elements.
+func NewSet[T comparable](elements ...T) *Set[T] {
+	s := &Set[T]{m: make(map[T]struct{}, len(elements))}
+	s.Add(elements...)
+
+	return s
+}
+
+// Len returns the number of elements.
+func (s *Set[T]) Len() int {
+	return len(s.m)
+}
+
+// Add adds elements to s.
+func (s *Set[T]) Add(elements ...T) {
+	if s.m == nil {
+		s.m = make(map[T]struct{}, len(elements))
+	}
+	for _, e := range elements {
+		s.m[e] = struct{}{}
+	}
+}
+
+// Remove removes elements from s.
+func (s *Set[T]) Remove(elements ...T) {
+	for _, e := range elements {
+		delete(s.m, e)
+	}
+}
+
+// Contains reports whether v is in s.
+func (s *Set[T]) Contains(v T) bool {
+	_, ok := s.m[v]
+	return ok
+}
+
+// Clear removes all elements.
+func (s *Set[T]) Clear() {
+	clear(s.m)
+}
+
+// Clone returns a copy of s.
+func (s *Set[T]) Clone() *Set[T] {
+	c := &Set[T]{m: make(map[T]struct{}, s.Len())}
+	for e := range s.m {
+		c.m[e] = struct{}{}
+	}
+
+	return c
+}
+
+// All returns an iterator over the elements in no particular order.
+func (s *Set[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for e := range s.m {
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// ToSlice returns the elements in no particular order, see Sorted and ToSortedFunc for a stable order.
+func (s *Set[T]) ToSlice() []T {
+	elements := make([]T, 0, s.Len())
+	for e := range s.m {
+		elements = append(elements, e)
+	}
+
+	return elements
+}
+
+// ToSortedFunc returns the elements sorted by cmp.
+func (s *Set[T]) ToSortedFunc(cmp func(a, b T) int) []T {
+	elements := s.ToSlice()
+	slices.SortFunc(elements, cmp)
+
+	return elements
+}
+
+// Sorted returns the elements of s in increasing order.
+func Sorted[T cmp.Ordered](s *Set[T]) []T {
+	elements := s.ToSlice()
+	slices.Sort(elements)
+
+	return elements
+}
+
+// Union returns a new set with the elements in s or other.
+func (s *Set[T]) Union(other *Set[T]) *Set[T] {
+	u := s.Clone()
+	for e := range other.m {
+		u.m[e] = struct{}{}
+	}
+
+	return u
+}
+
+// Intersect returns a new set with the elements in both s and other.
+func (s *Set[T]) Intersect(other *Set[T]) *Set[T] {
+	small, large := s, other
+	if small.Len() > large.Len() {
+		small, large = large, small
+	}
+
+	i := &Set[T]{m: make(map[T]struct{})}
+	for e := range small.m {
+		if large.Contains(e) {
+			i.m[e] = struct{}{}
+		}
+	}
+
+	return i
+}
+
+// Difference returns a new set with the elements in s but not in other.
+func (s *Set[T]) Difference(other *Set[T]) *Set[T] {
+	d := &Set[T]{m: make(map[T]struct{})}
+	for e := range s.m {
+		if !other.Contains(e) {
+			d.m[e] = struct{}{}
+		}
+	}
+
+	return d
+}
+
+// SymmetricDifference returns a new set with the elements in exactly one of s and other.
+func (s *Set[T]) SymmetricDifference(other *Set[T]) *Set[T] {
+	d := s.Difference(other)
+	for e := range other.m {
+		if !s.Contains(e) {
+			d.m[e] = struct{}{}
+		}
+	}
+
+	return d
+}
+
+// IsSubset reports whether every element of s is in other.
+func (s *Set[T]) IsSubset(other *Set[T]) bool {
+	if s.Len() > other.Len() {
+		return false
+	}
+	for e := range s.m {
+		if !other.Contains(e) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Equal reports whether s and other have the same elements.
+func (s *Set[T]) Equal(other *Set[T]) bool {
+	return s.Len() == other.Len() && s.IsSubset(other)
+}
+
+// MarshalJSON implements json.Marshaler. The set is encoded as a JSON array,
+// sorted when elements are strings or numbers so that the output is deterministic.
+func (s *Set[T]) MarshalJSON() ([]byte, error) {
+	if s == nil {
+		return []byte("null"), nil
+	}
+
+	elements := s.ToSlice()
+	if less := orderedLess[T](); less != nil {
+		slices.SortFunc(elements, less)
+		return json.Marshal(elements)
+	}
+
+	// order the encoded elements instead.
+	encoded := make([][]byte, len(elements))
+	for i, e := range elements {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return nil, err
+		}
+		encoded[i] = b
+	}
+	slices.SortFunc(encoded, bytes.Compare)
+
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, b := range encoded {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(b)
+	}
+	buf.WriteByte(']')
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It replaces the content of s with the elements of a JSON array,
+// duplicates are merged.
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	var elements []T
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return err
+	}
+	if elements == nil {
+		// JSON null.
+		return nil
+	}
+
+	s.m = make(map[T]struct{}, len(elements))
+	s.Add(elements...)
+
+	return nil
+}
+
+// orderedLess returns a comparison function for T if its underlying type is a string or a number.
+func orderedLess[T comparable]() func(a, b T) int {
+	switch reflect.TypeFor[T]().Kind() {
+	case reflect.String:
+		return func(a, b T) int {
+			return cmp.Compare(reflect.ValueOf(a).String(), reflect.ValueOf(b).String())
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(a, b T) int {
+			return cmp.Compare(reflect.ValueOf(a).Int(), reflect.ValueOf(b).Int())
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(a, b T) int {
+			return cmp.Compare(reflect.ValueOf(a).Uint(), reflect.ValueOf(b).Uint())
+		}
+	case reflect.Float32, reflect.Float64:
+		return func(a, b T) int {
+			return cmp.Compare(reflect.ValueOf(a).Float(), reflect.ValueOf(b).Float())
+		}
+	default:
+		return nil
+	}
+}
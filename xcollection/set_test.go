@@ -0,0 +1,139 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcollection
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	s := NewSet(1, 2, 3, 2)
+	assert.Equal(t, 3, s.Len())
+	assert.True(t, s.Contains(2))
+	assert.False(t, s.Contains(4))
+
+	s.Add(4, 5)
+	s.Remove(1, 10)
+	assert.Equal(t, []int{2, 3, 4, 5}, Sorted(s))
+	assert.Equal(t, []int{5, 4, 3, 2}, s.ToSortedFunc(func(a, b int) int { return b - a }))
+
+	elements := s.ToSlice()
+	sort.Ints(elements)
+	assert.Equal(t, []int{2, 3, 4, 5}, elements)
+
+	var all []int
+	for v := range s.All() {
+		all = append(all, v)
+	}
+	assert.ElementsMatch(t, []int{2, 3, 4, 5}, all)
+
+	c := s.Clone()
+	s.Clear()
+	assert.Equal(t, 0, s.Len())
+	assert.Equal(t, 4, c.Len())
+}
+
+func TestSet_ZeroValue(t *testing.T) {
+	var s Set[string]
+	assert.Equal(t, 0, s.Len())
+	assert.False(t, s.Contains("a"))
+	s.Remove("a")
+	s.Clear()
+	assert.Empty(t, s.ToSlice())
+
+	s.Add("a")
+	assert.True(t, s.Contains("a"))
+	assert.Equal(t, 1, s.Clone().Len())
+}
+
+func TestSet_Algebra(t *testing.T) {
+	a := NewSet(1, 2, 3, 4)
+	b := NewSet(3, 4, 5)
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, Sorted(a.Union(b)))
+	assert.Equal(t, []int{3, 4}, Sorted(a.Intersect(b)))
+	assert.Equal(t, []int{3, 4}, Sorted(b.Intersect(a)))
+	assert.Equal(t, []int{1, 2}, Sorted(a.Difference(b)))
+	assert.Equal(t, []int{5}, Sorted(b.Difference(a)))
+	assert.Equal(t, []int{1, 2, 5}, Sorted(a.SymmetricDifference(b)))
+	assert.Equal(t, []int{1, 2, 3, 4}, Sorted(a), "operands must not be modified")
+
+	var empty Set[int]
+	assert.Equal(t, a.Len(), a.Union(&empty).Len())
+	assert.Equal(t, 0, a.Intersect(&empty).Len())
+	assert.True(t, empty.IsSubset(a))
+	assert.True(t, NewSet(3, 4).IsSubset(a))
+	assert.False(t, a.IsSubset(b))
+
+	assert.True(t, a.Equal(NewSet(4, 3, 2, 1)))
+	assert.False(t, a.Equal(b))
+	assert.True(t, empty.Equal(NewSet[int]()))
+}
+
+type level string
+
+func TestSet_JSON(t *testing.T) {
+	b, err := json.Marshal(NewSet("c", "a", "b"))
+	assert.Nil(t, err)
+	assert.Equal(t, `["a","b","c"]`, string(b))
+
+	b, err = json.Marshal(NewSet(10, -1, 9))
+	assert.Nil(t, err)
+	assert.Equal(t, `[-1,9,10]`, string(b))
+
+	b, err = json.Marshal(NewSet[level]("warn", "debug"))
+	assert.Nil(t, err)
+	assert.Equal(t, `["debug","warn"]`, string(b))
+
+	b, err = json.Marshal(NewSet(1.5, 0.25))
+	assert.Nil(t, err)
+	assert.Equal(t, `[0.25,1.5]`, string(b))
+
+	type point struct{ X, Y int }
+	b, err = json.Marshal(NewSet(point{2, 1}, point{1, 2}))
+	assert.Nil(t, err)
+	assert.Equal(t, `[{"X":1,"Y":2},{"X":2,"Y":1}]`, string(b))
+
+	b, err = json.Marshal(&Set[int]{})
+	assert.Nil(t, err)
+	assert.Equal(t, `[]`, string(b))
+
+	var nilSet *Set[int]
+	b, err = json.Marshal(nilSet)
+	assert.Nil(t, err)
+	assert.Equal(t, `null`, string(b))
+
+	var s Set[string]
+	assert.Nil(t, json.Unmarshal([]byte(`["x","y","x"]`), &s))
+	assert.Equal(t, []string{"x", "y"}, Sorted(&s))
+	assert.Nil(t, s.UnmarshalJSON([]byte(`null`)))
+	assert.Equal(t, 2, s.Len())
+	assert.NotNil(t, json.Unmarshal([]byte(`[1]`), &s))
+	assert.NotNil(t, json.Unmarshal([]byte(`{}`), &s))
+
+	type config struct {
+		Tags *Set[string] `json:"tags"`
+	}
+	var c config
+	assert.Nil(t, json.Unmarshal([]byte(`{"tags":["b","a"]}`), &c))
+	b, err = json.Marshal(c)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"tags":["a","b"]}`, string(b))
+}
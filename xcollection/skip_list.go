@@ -0,0 +1,269 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcollection
+
+import (
+	"cmp"
+	"iter"
+	"math/rand/v2"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	skipListMaxLevel = 24
+	// the probability of a node to have one more level is 1/4.
+	skipListBranch = 4
+)
+
+type (
+	// A SkipList is an ordered map safe for concurrent use, implemented as a lazy skip list:
+	// Get and iteration are lock-free, while Set and Delete only lock the few nodes around the key,
+	// so writers on distant keys don't contend. Iteration is weakly consistent, it reflects some of
+	// the writes made concurrently. See xds.SkipList for rank queries under a single lock.
+	SkipList[K cmp.Ordered, V any] struct {
+		head   *skipListNode[K, V]
+		length atomic.Int64
+	}
+
+	skipListNode[K cmp.Ordered, V any] struct {
+		key   K
+		value atomic.Pointer[V]
+		next  []atomic.Pointer[skipListNode[K, V]]
+		lock  sync.Mutex
+		// marked is set when the node is being removed, linked once it's reachable at every level.
+		marked atomic.Bool
+		linked atomic.Bool
+	}
+)
+
+// NewSkipList returns an empty SkipList.
+func NewSkipList[K cmp.Ordered, V any]() *SkipList[K, V] {
+	return &SkipList[K, V]{head: &skipListNode[K, V]{next: make([]atomic.Pointer[skipListNode[K, V]], skipListMaxLevel)}}
+}
+
+// Len returns the number of elements.
+func (s *SkipList[K, V]) Len() int {
+	return int(s.length.Load())
+}
+
+// Get returns the value of key.
+func (s *SkipList[K, V]) Get(key K) (V, bool) {
+	x := s.head
+	for i := skipListMaxLevel - 1; i >= 0; i-- {
+		next := x.next[i].Load()
+		for next != nil && next.key < key {
+			x = next
+			next = x.next[i].Load()
+		}
+		if next != nil && next.key == key {
+			if next.linked.Load() && !next.marked.Load() {
+				return *next.value.Load(), true
+			}
+			break
+		}
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Set sets the value of key, it returns false if key already existed.
+func (s *SkipList[K, V]) Set(key K, value V) bool {
+	level := randomLevel()
+	var preds, succs [skipListMaxLevel]*skipListNode[K, V]
+	for {
+		if found := s.find(key, &preds, &succs); found >= 0 {
+			x := succs[found]
+			if !x.marked.Load() {
+				for !x.linked.Load() {
+					// wait for the concurrent insertion to complete.
+					runtime.Gosched()
+				}
+				x.value.Store(&value)
+				return false
+			}
+			// the node is being removed, retry once it's unlinked.
+			runtime.Gosched()
+			continue
+		}
+
+		highest, valid := lockPreds(&preds, level, func(i int) bool {
+			succ := succs[i]
+			return (succ == nil || !succ.marked.Load()) && preds[i].next[i].Load() == succ
+		})
+		if !valid {
+			unlockPreds(&preds, highest)
+			continue
+		}
+
+		x := &skipListNode[K, V]{key: key, next: make([]atomic.Pointer[skipListNode[K, V]], level)}
+		x.value.Store(&value)
+		for i := 0; i < level; i++ {
+			x.next[i].Store(succs[i])
+		}
+		for i := 0; i < level; i++ {
+			preds[i].next[i].Store(x)
+		}
+		x.linked.Store(true)
+		unlockPreds(&preds, highest)
+		s.length.Add(1)
+
+		return true
+	}
+}
+
+// Delete removes key, it returns false if key doesn't exist.
+func (s *SkipList[K, V]) Delete(key K) bool {
+	var (
+		preds, succs [skipListMaxLevel]*skipListNode[K, V]
+		victim       *skipListNode[K, V]
+	)
+	for {
+		found := s.find(key, &preds, &succs)
+		if victim == nil {
+			if found < 0 {
+				return false
+			}
+
+			x := succs[found]
+			// only a fully linked node found at its top level can be removed.
+			if !x.linked.Load() || len(x.next)-1 != found || x.marked.Load() {
+				return false
+			}
+			x.lock.Lock()
+			if x.marked.Load() {
+				x.lock.Unlock()
+				return false
+			}
+			x.marked.Store(true)
+			victim = x
+		}
+
+		level := len(victim.next)
+		highest, valid := lockPreds(&preds, level, func(i int) bool {
+			return preds[i].next[i].Load() == victim
+		})
+		if !valid {
+			unlockPreds(&preds, highest)
+			continue
+		}
+
+		for i := level - 1; i >= 0; i-- {
+			preds[i].next[i].Store(victim.next[i].Load())
+		}
+		victim.lock.Unlock()
+		unlockPreds(&preds, highest)
+		s.length.Add(-1)
+
+		return true
+	}
+}
+
+// All returns an iterator over the elements in ascending order.
+func (s *SkipList[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		s.ascend(s.head.next[0].Load(), nil, yield)
+	}
+}
+
+// Range returns an iterator over the elements with from <= key < to in ascending order.
+func (s *SkipList[K, V]) Range(from, to K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		x := s.head
+		for i := skipListMaxLevel - 1; i >= 0; i-- {
+			for next := x.next[i].Load(); next != nil && next.key < from; next = x.next[i].Load() {
+				x = next
+			}
+		}
+
+		s.ascend(x.next[0].Load(), &to, yield)
+	}
+}
+
+func (s *SkipList[K, V]) ascend(x *skipListNode[K, V], to *K, yield func(K, V) bool) {
+	for ; x != nil && (to == nil || x.key < *to); x = x.next[0].Load() {
+		if !x.linked.Load() || x.marked.Load() {
+			continue
+		}
+		if !yield(x.key, *x.value.Load()) {
+			return
+		}
+	}
+}
+
+// find fills the predecessors and successors of key at every level,
+// it returns the highest level where key is found or -1.
+func (s *SkipList[K, V]) find(key K, preds, succs *[skipListMaxLevel]*skipListNode[K, V]) int {
+	found := -1
+	x := s.head
+	for i := skipListMaxLevel - 1; i >= 0; i-- {
+		next := x.next[i].Load()
+		for next != nil && next.key < key {
+			x = next
+			next = x.next[i].Load()
+		}
+		if found < 0 && next != nil && next.key == key {
+			found = i
+		}
+		preds[i] = x
+		succs[i] = next
+	}
+
+	return found
+}
+
+// lockPreds locks the distinct predecessors below level from the bottom up and checks valid at every level
+// until one fails. It returns the highest level locked and whether all levels are valid.
+func lockPreds[K cmp.Ordered, V any](preds *[skipListMaxLevel]*skipListNode[K, V], level int, valid func(i int) bool) (int, bool) {
+	highest := -1
+	var prev *skipListNode[K, V]
+	for i := 0; i < level; i++ {
+		pred := preds[i]
+		if pred != prev {
+			pred.lock.Lock()
+			highest = i
+			prev = pred
+		}
+		if pred.marked.Load() || !valid(i) {
+			return highest, false
+		}
+	}
+
+	return highest, true
+}
+
+// unlockPreds unlocks the distinct predecessors up to highest.
+func unlockPreds[K cmp.Ordered, V any](preds *[skipListMaxLevel]*skipListNode[K, V], highest int) {
+	var prev *skipListNode[K, V]
+	for i := 0; i <= highest; i++ {
+		if preds[i] != prev {
+			preds[i].lock.Unlock()
+			prev = preds[i]
+		}
+	}
+}
+
+func randomLevel() int {
+	level := 1
+	for level < skipListMaxLevel && rand.IntN(skipListBranch) == 0 {
+		level++
+	}
+
+	return level
+}
@@ -0,0 +1,197 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcollection
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func collect[K comparable, V any](seq func(yield func(K, V) bool)) []K {
+	var keys []K
+	for k := range seq {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
+func TestSkipList(t *testing.T) {
+	s := NewSkipList[int, string]()
+	_, ok := s.Get(1)
+	assert.False(t, ok)
+	assert.False(t, s.Delete(1))
+
+	assert.True(t, s.Set(3, "c"))
+	assert.True(t, s.Set(1, "a"))
+	assert.True(t, s.Set(2, "b"))
+	assert.False(t, s.Set(2, "B"))
+	assert.Equal(t, 3, s.Len())
+
+	v, ok := s.Get(2)
+	assert.True(t, ok)
+	assert.Equal(t, "B", v)
+
+	assert.Equal(t, []int{1, 2, 3}, collect(s.All()))
+	assert.Equal(t, []int{2, 3}, collect(s.Range(2, 10)))
+	assert.Equal(t, []int{1}, collect(s.Range(0, 2)))
+	assert.Empty(t, collect(s.Range(3, 3)))
+
+	var values []string
+	for _, v := range s.All() {
+		values = append(values, v)
+		if len(values) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []string{"a", "B"}, values)
+
+	assert.True(t, s.Delete(2))
+	assert.False(t, s.Delete(2))
+	_, ok = s.Get(2)
+	assert.False(t, ok)
+	assert.Equal(t, []int{1, 3}, collect(s.All()))
+	assert.Equal(t, 2, s.Len())
+}
+
+func TestSkipList_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	s := NewSkipList[int, int]()
+	m := map[int]int{}
+	for i := 0; i < 5000; i++ {
+		k := r.Intn(500)
+		if r.Intn(3) == 0 {
+			_, existed := m[k]
+			assert.Equal(t, existed, s.Delete(k))
+			delete(m, k)
+			continue
+		}
+
+		_, existed := m[k]
+		assert.Equal(t, !existed, s.Set(k, i))
+		m[k] = i
+	}
+
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+		v, ok := s.Get(k)
+		assert.True(t, ok)
+		assert.Equal(t, m[k], v)
+	}
+	slices.Sort(keys)
+	assert.Equal(t, keys, collect(s.All()))
+	assert.Equal(t, len(m), s.Len())
+}
+
+func TestSkipList_Concurrent(t *testing.T) {
+	s := NewSkipList[int, int]()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			// each goroutine owns the keys equal to g modulo 8.
+			for i := 0; i < 2000; i++ {
+				k := i*8 + g
+				assert.True(t, s.Set(k, k))
+				if i%2 == 1 {
+					assert.True(t, s.Delete(k))
+				}
+			}
+		}(g)
+	}
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				prev := -1
+				for k, v := range s.Range(100, 10000) {
+					assert.Less(t, prev, k)
+					assert.Equal(t, k, v)
+					prev = k
+				}
+				s.Get(i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 8000, s.Len())
+	keys := collect(s.All())
+	assert.Len(t, keys, 8000)
+	assert.True(t, slices.IsSorted(keys))
+	for _, k := range keys {
+		assert.Equal(t, 0, (k/8)%2)
+	}
+}
+
+func TestSkipList_ConcurrentSameKeys(t *testing.T) {
+	s := NewSkipList[int, int]()
+	var (
+		wg    sync.WaitGroup
+		lock  sync.Mutex
+		added = map[int]int{}
+	)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(g)))
+			for i := 0; i < 2000; i++ {
+				k := r.Intn(64)
+				delta := 0
+				if r.Intn(2) == 0 {
+					if s.Set(k, g) {
+						delta = 1
+					}
+				} else if s.Delete(k) {
+					delta = -1
+				}
+				lock.Lock()
+				added[k] += delta
+				lock.Unlock()
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	n := 0
+	for k, count := range added {
+		_, ok := s.Get(k)
+		// every key is added and deleted alternately.
+		assert.Equal(t, ok, count == 1, "key %d", k)
+		n += count
+	}
+	assert.Equal(t, n, s.Len())
+	assert.Len(t, collect(s.All()), n)
+}
+
+func BenchmarkSkipList_Set(b *testing.B) {
+	s := NewSkipList[int, int]()
+	b.RunParallel(func(pb *testing.PB) {
+		r := rand.New(rand.NewSource(rand.Int63()))
+		for pb.Next() {
+			s.Set(r.Intn(1<<20), 0)
+		}
+	})
+}
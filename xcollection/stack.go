@@ -0,0 +1,171 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcollection
+
+import (
+	"github.com/chenquan/go-pkg/xds"
+	"iter"
+	"sync"
+)
+
+type (
+	// A Stack is a last-in-first-out stack backed by a slice.
+	// The zero value is an empty stack ready to use. It's not safe for concurrent use, see SyncStack.
+	Stack[T any] struct {
+		s xds.Stack[T]
+	}
+
+	// A SyncStack is a Stack safe for concurrent use.
+	SyncStack[T any] struct {
+		lock sync.Mutex
+		s    Stack[T]
+	}
+)
+
+// NewStack returns an empty Stack.
+func NewStack[T any]() *Stack[T] {
+	return &Stack[T]{}
+}
+
+// Len returns the number of elements.
+func (s *Stack[T]) Len() int {
+	return s.s.Len()
+}
+
+// Push adds v on the top.
+func (s *Stack[T]) Push(v T) {
+	s.s.Push(v)
+}
+
+// Pop removes and returns the top element, it panics if s is empty.
+func (s *Stack[T]) Pop() T {
+	v, ok := s.s.Pop()
+	if !ok {
+		panic("xcollection: pop from an empty stack")
+	}
+
+	return v
+}
+
+// TryPop removes and returns the top element, it returns false if s is empty.
+func (s *Stack[T]) TryPop() (T, bool) {
+	return s.s.Pop()
+}
+
+// Peek returns the top element without removing it, it panics if s is empty.
+func (s *Stack[T]) Peek() T {
+	v, ok := s.s.Peek()
+	if !ok {
+		panic("xcollection: peek at an empty stack")
+	}
+
+	return v
+}
+
+// TryPeek returns the top element without removing it, it returns false if s is empty.
+func (s *Stack[T]) TryPeek() (T, bool) {
+	return s.s.Peek()
+}
+
+// All returns an iterator over the elements from top to bottom.
+func (s *Stack[T]) All() iter.Seq[T] {
+	return s.s.Range
+}
+
+// Clear removes all elements.
+func (s *Stack[T]) Clear() {
+	s.s.Clear()
+}
+
+// NewSyncStack returns an empty SyncStack.
+func NewSyncStack[T any]() *SyncStack[T] {
+	return &SyncStack[T]{}
+}
+
+// Len returns the number of elements.
+func (s *SyncStack[T]) Len() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.s.Len()
+}
+
+// Push adds v on the top.
+func (s *SyncStack[T]) Push(v T) {
+	s.lock.Lock()
+	s.s.Push(v)
+	s.lock.Unlock()
+}
+
+// Pop removes and returns the top element, it panics if s is empty.
+func (s *SyncStack[T]) Pop() T {
+	v, ok := s.TryPop()
+	if !ok {
+		panic("xcollection: pop from an empty stack")
+	}
+
+	return v
+}
+
+// TryPop removes and returns the top element, it returns false if s is empty.
+func (s *SyncStack[T]) TryPop() (T, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.s.TryPop()
+}
+
+// Peek returns the top element without removing it, it panics if s is empty.
+func (s *SyncStack[T]) Peek() T {
+	v, ok := s.TryPeek()
+	if !ok {
+		panic("xcollection: peek at an empty stack")
+	}
+
+	return v
+}
+
+// TryPeek returns the top element without removing it, it returns false if s is empty.
+func (s *SyncStack[T]) TryPeek() (T, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.s.TryPeek()
+}
+
+// Snapshot returns a copy of the elements from top to bottom.
+func (s *SyncStack[T]) Snapshot() []T {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return appendAll(make([]T, 0, s.s.Len()), s.s.All())
+}
+
+// Clear removes all elements.
+func (s *SyncStack[T]) Clear() {
+	s.lock.Lock()
+	s.s.Clear()
+	s.lock.Unlock()
+}
+
+func appendAll[T any](s []T, seq iter.Seq[T]) []T {
+	for v := range seq {
+		s = append(s, v)
+	}
+
+	return s
+}
@@ -0,0 +1,95 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcollection
+
+import (
+	"github.com/stretchr/testify/assert"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestStack(t *testing.T) {
+	var s Stack[int]
+	_, ok := s.TryPop()
+	assert.False(t, ok)
+	_, ok = s.TryPeek()
+	assert.False(t, ok)
+	assert.PanicsWithValue(t, "xcollection: pop from an empty stack", func() { s.Pop() })
+	assert.PanicsWithValue(t, "xcollection: peek at an empty stack", func() { s.Peek() })
+
+	s.Push(1)
+	s.Push(2)
+	s.Push(3)
+	assert.Equal(t, 3, s.Len())
+	assert.Equal(t, []int{3, 2, 1}, slices.Collect(s.All()))
+	assert.Equal(t, 3, s.Peek())
+	assert.Equal(t, 3, s.Pop())
+	v, ok := s.TryPop()
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+	v, ok = s.TryPeek()
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	s.Clear()
+	assert.Equal(t, 0, NewStack[int]().Len())
+	assert.Equal(t, 0, s.Len())
+}
+
+func TestSyncStack(t *testing.T) {
+	s := NewSyncStack[int]()
+	assert.Panics(t, func() { s.Pop() })
+	assert.Panics(t, func() { s.Peek() })
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				s.Push(i)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 400, s.Len())
+	assert.Len(t, s.Snapshot(), 400)
+
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				_, ok := s.TryPop()
+				assert.True(t, ok)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 0, s.Len())
+
+	s.Push(7)
+	assert.Equal(t, 7, s.Peek())
+	v, ok := s.TryPeek()
+	assert.True(t, ok)
+	assert.Equal(t, 7, v)
+	assert.Equal(t, 7, s.Pop())
+	s.Push(8)
+	s.Clear()
+	assert.Empty(t, s.Snapshot())
+}
@@ -0,0 +1,116 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcollection
+
+import (
+	"github.com/chenquan/go-pkg/xds"
+	"strings"
+)
+
+type (
+	// A Trie maps string keys to values for prefix lookups, e.g. longest-prefix routing.
+	// It's not safe for concurrent use.
+	//
+	// A Trie made by NewTrie matches keys byte by byte, so "/api" is a prefix of "/apis".
+	// A Trie made by NewPathTrie matches whole segments instead, so "/api" is a prefix of "/api/v1"
+	// but not of "/apis". Empty segments are ignored, so "/a//b/" and "a/b" are the same key.
+	Trie[V any] struct {
+		trie      *xds.Trie[trieEntry[V]]
+		sep       byte
+		segmented bool
+	}
+
+	trieEntry[V any] struct {
+		key   string
+		value V
+	}
+)
+
+// NewTrie returns an empty Trie matching keys byte by byte.
+func NewTrie[V any]() *Trie[V] {
+	return &Trie[V]{trie: xds.NewTrie[trieEntry[V]]()}
+}
+
+// NewPathTrie returns an empty Trie matching keys segment by segment,
+// e.g. sep is '/' for URL paths or '.' for topic names.
+func NewPathTrie[V any](sep byte) *Trie[V] {
+	return &Trie[V]{trie: xds.NewTrie[trieEntry[V]](), sep: sep, segmented: true}
+}
+
+// Len returns the number of keys.
+func (t *Trie[V]) Len() int {
+	return t.trie.Len()
+}
+
+// Insert sets the value of key, it returns false if key already existed.
+func (t *Trie[V]) Insert(key string, value V) bool {
+	return t.trie.Insert(t.normalize(key), trieEntry[V]{key: key, value: value})
+}
+
+// Get returns the value of key.
+func (t *Trie[V]) Get(key string) (V, bool) {
+	e, ok := t.trie.Get(t.normalize(key))
+	return e.value, ok
+}
+
+// Delete removes key, it returns false if key doesn't exist.
+func (t *Trie[V]) Delete(key string) bool {
+	return t.trie.Delete(t.normalize(key))
+}
+
+// LongestPrefixMatch returns the longest key that is a prefix of s, as it was inserted, and its value.
+func (t *Trie[V]) LongestPrefixMatch(s string) (string, V, bool) {
+	_, e, ok := t.trie.LongestPrefix(t.normalize(s))
+	return e.key, e.value, ok
+}
+
+// WalkPrefix calls fn for each key starting with prefix until fn returns false.
+// Keys are walked in lexicographical order, segment by segment for a path Trie.
+func (t *Trie[V]) WalkPrefix(prefix string, fn func(key string, value V) bool) {
+	t.trie.WalkPrefix(t.normalize(prefix), func(_ string, e trieEntry[V]) bool {
+		return fn(e.key, e.value)
+	})
+}
+
+// Walk calls fn for each key in the order of WalkPrefix until fn returns false.
+func (t *Trie[V]) Walk(fn func(key string, value V) bool) {
+	t.WalkPrefix("", fn)
+}
+
+// normalize turns a path into its segments, each one terminated by the separator,
+// so that byte prefixes of normalized keys are whole segment prefixes.
+func (t *Trie[V]) normalize(key string) string {
+	if !t.segmented {
+		return key
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(key) + 1)
+	for len(key) > 0 {
+		i := strings.IndexByte(key, t.sep)
+		if i < 0 {
+			i = len(key)
+		}
+		if i > 0 {
+			sb.WriteString(key[:i])
+			sb.WriteByte(t.sep)
+		}
+		key = key[min(i+1, len(key)):]
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,122 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcollection
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func walkAll[V any](t *Trie[V], prefix string) []string {
+	var keys []string
+	t.WalkPrefix(prefix, func(key string, _ V) bool {
+		keys = append(keys, key)
+		return true
+	})
+
+	return keys
+}
+
+func TestTrie(t *testing.T) {
+	tr := NewTrie[int]()
+	assert.True(t, tr.Insert("/api", 1))
+	assert.True(t, tr.Insert("/apis", 2))
+	assert.True(t, tr.Insert("/api/v1", 3))
+	assert.False(t, tr.Insert("/api", 10))
+	assert.Equal(t, 3, tr.Len())
+
+	v, ok := tr.Get("/api")
+	assert.True(t, ok)
+	assert.Equal(t, 10, v)
+	_, ok = tr.Get("/ap")
+	assert.False(t, ok)
+
+	key, v, ok := tr.LongestPrefixMatch("/apis/x")
+	assert.True(t, ok)
+	assert.Equal(t, "/apis", key)
+	assert.Equal(t, 2, v)
+	key, _, ok = tr.LongestPrefixMatch("/apx")
+	assert.False(t, ok)
+	assert.Equal(t, "", key)
+
+	assert.Equal(t, []string{"/api", "/api/v1", "/apis"}, walkAll(tr, "/api"))
+	assert.Equal(t, []string{"/apis"}, walkAll(tr, "/apis"))
+	assert.Empty(t, walkAll(tr, "/x"))
+
+	assert.True(t, tr.Delete("/api"))
+	assert.False(t, tr.Delete("/api"))
+	assert.Equal(t, []string{"/api/v1", "/apis"}, walkAll(tr, ""))
+}
+
+func TestPathTrie(t *testing.T) {
+	tr := NewPathTrie[string]('/')
+	tr.Insert("/", "root")
+	tr.Insert("/api", "api")
+	tr.Insert("/api/v1/users", "users")
+	tr.Insert("/apis", "apis")
+
+	key, v, ok := tr.LongestPrefixMatch("/api/v1/users/42")
+	assert.True(t, ok)
+	assert.Equal(t, "/api/v1/users", key)
+	assert.Equal(t, "users", v)
+
+	key, v, _ = tr.LongestPrefixMatch("/api/v1/user")
+	assert.Equal(t, "/api", key)
+	assert.Equal(t, "api", v)
+
+	key, _, _ = tr.LongestPrefixMatch("/apix")
+	assert.Equal(t, "/", key)
+
+	// empty segments are ignored.
+	v, ok = tr.Get("api//v1/users/")
+	assert.True(t, ok)
+	assert.Equal(t, "users", v)
+	assert.False(t, tr.Insert("//api/", "api2"))
+	key, v, _ = tr.LongestPrefixMatch("/api/x")
+	assert.Equal(t, "//api/", key)
+	assert.Equal(t, "api2", v)
+
+	assert.Equal(t, []string{"//api/", "/api/v1/users"}, walkAll(tr, "/api"))
+	assert.Equal(t, []string{"/", "//api/", "/api/v1/users", "/apis"}, walkAll(tr, ""))
+
+	var keys []string
+	tr.Walk(func(key string, _ string) bool {
+		keys = append(keys, key)
+		return len(keys) < 2
+	})
+	assert.Equal(t, []string{"/", "//api/"}, keys)
+
+	assert.True(t, tr.Delete("/api/v1/users"))
+	assert.Equal(t, 3, tr.Len())
+}
+
+func TestPathTrie_Topics(t *testing.T) {
+	tr := NewPathTrie[int]('.')
+	tr.Insert("orders", 1)
+	tr.Insert("orders.eu", 2)
+
+	key, v, ok := tr.LongestPrefixMatch("orders.eu.created")
+	assert.True(t, ok)
+	assert.Equal(t, "orders.eu", key)
+	assert.Equal(t, 2, v)
+
+	key, _, _ = tr.LongestPrefixMatch("orders.europe")
+	assert.Equal(t, "orders", key)
+
+	_, _, ok = tr.LongestPrefixMatch("ordersx")
+	assert.False(t, ok)
+}
@@ -0,0 +1,62 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcontext
+
+import (
+	"context"
+	"time"
+)
+
+// WithDeadlineMargin returns a child of ctx that expires margin before the deadline of ctx,
+// so that the caller still has time to clean up or report once the child is done.
+// If ctx has no deadline, the child only has a cancel.
+func WithDeadlineMargin(ctx context.Context, margin time.Duration) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithDeadline(ctx, deadline.Add(-margin))
+}
+
+// RemainingBudget returns the time left before the deadline of ctx,
+// or false if ctx has no deadline. The budget is never negative.
+func RemainingBudget(ctx context.Context) (time.Duration, bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, false
+	}
+
+	remaining := time.Until(deadline)
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return remaining, true
+}
+
+// HasBudget reports whether an attempt taking need still fits before the deadline of ctx.
+// It's true if ctx has no deadline, and false if ctx is done.
+func HasBudget(ctx context.Context, need time.Duration) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	remaining, ok := RemainingBudget(ctx)
+
+	return !ok || remaining >= need
+}
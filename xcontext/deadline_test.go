@@ -0,0 +1,76 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcontext
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestWithDeadlineMargin(t *testing.T) {
+	deadline := time.Now().Add(time.Hour)
+	parent, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	ctx, cancelCtx := WithDeadlineMargin(parent, time.Minute)
+	defer cancelCtx()
+	d, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, deadline.Add(-time.Minute), d)
+
+	// the margin exceeds the budget.
+	short, cancelShort := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelShort()
+	ctx, cancelCtx = WithDeadlineMargin(short, time.Second)
+	defer cancelCtx()
+	<-ctx.Done()
+	assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+	assert.NoError(t, short.Err())
+
+	ctx, cancelCtx = WithDeadlineMargin(context.Background(), time.Second)
+	_, ok = ctx.Deadline()
+	assert.False(t, ok)
+	cancelCtx()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
+func TestRemainingBudget(t *testing.T) {
+	_, ok := RemainingBudget(context.Background())
+	assert.False(t, ok)
+	assert.True(t, HasBudget(context.Background(), time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	remaining, ok := RemainingBudget(ctx)
+	assert.True(t, ok)
+	assert.True(t, remaining > 59*time.Second && remaining <= time.Minute)
+	assert.True(t, HasBudget(ctx, time.Second))
+	assert.False(t, HasBudget(ctx, time.Hour))
+
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+	remaining, ok = RemainingBudget(expired)
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), remaining)
+	assert.False(t, HasBudget(expired, 0))
+
+	canceled, cancelCanceled := context.WithCancel(context.Background())
+	cancelCanceled()
+	assert.False(t, HasBudget(canceled, 0))
+}
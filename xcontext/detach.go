@@ -0,0 +1,26 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcontext
+
+import "context"
+
+// Detach returns a context that keeps the values of ctx, such as trace IDs and credentials,
+// but is never canceled and has no deadline.
+// It's useful for background work spawned by a request handler that must outlive the request.
+func Detach(ctx context.Context) context.Context {
+	return context.WithoutCancel(ctx)
+}
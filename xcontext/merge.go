@@ -0,0 +1,89 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcontext
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type mergedContext struct {
+	context.Context
+	other context.Context
+	lock  sync.Mutex
+	err   error
+}
+
+// Merge returns a context that is done as soon as either a or b is done,
+// e.g. to combine a request context with a service shutdown context.
+//
+// Its deadline is the earlier of both, values are looked up in a then in b and
+// context.Cause reports the cause of the parent that finished first.
+// The returned cancel releases the resources and must be called once the context is not used anymore.
+func Merge(a, b context.Context) (context.Context, context.CancelFunc) {
+	inner, cancel := context.WithCancelCause(a)
+	ctx := &mergedContext{Context: inner, other: b}
+
+	stop := context.AfterFunc(b, func() {
+		ctx.lock.Lock()
+		defer ctx.lock.Unlock()
+
+		if inner.Err() == nil {
+			ctx.err = b.Err()
+			cancel(context.Cause(b))
+		}
+	})
+	// once done, b doesn't need to be watched anymore.
+	context.AfterFunc(inner, func() {
+		stop()
+	})
+
+	return ctx, func() {
+		stop()
+		cancel(context.Canceled)
+	}
+}
+
+func (c *mergedContext) Deadline() (time.Time, bool) {
+	deadline, ok := c.Context.Deadline()
+	other, otherOk := c.other.Deadline()
+	if !ok || (otherOk && other.Before(deadline)) {
+		return other, otherOk
+	}
+
+	return deadline, ok
+}
+
+func (c *mergedContext) Err() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.err != nil {
+		return c.err
+	}
+
+	return c.Context.Err()
+}
+
+func (c *mergedContext) Value(key interface{}) interface{} {
+	if v := c.Context.Value(key); v != nil {
+		return v
+	}
+
+	return c.other.Value(key)
+}
@@ -0,0 +1,117 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcontext
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestMerge(t *testing.T) {
+	a, cancelA := context.WithCancel(context.WithValue(context.Background(), testKey("k"), "a"))
+	defer cancelA()
+	b, cancelB := context.WithCancelCause(context.WithValue(context.Background(), testKey("k"), "b"))
+	b = context.WithValue(b, testKey("only"), "b")
+
+	ctx, cancel := Merge(a, b)
+	defer cancel()
+
+	assert.NoError(t, ctx.Err())
+	assert.Equal(t, "a", ctx.Value(testKey("k")))
+	assert.Equal(t, "b", ctx.Value(testKey("only")))
+	assert.Nil(t, ctx.Value(testKey("none")))
+	_, ok := ctx.Deadline()
+	assert.False(t, ok)
+
+	shutdown := errors.New("shutdown")
+	cancelB(shutdown)
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("not done")
+	}
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	assert.Equal(t, shutdown, context.Cause(ctx))
+}
+
+func TestMerge_First(t *testing.T) {
+	a, cancelA := context.WithCancel(context.Background())
+	ctx, cancel := Merge(a, context.Background())
+	defer cancel()
+	child, cancelChild := context.WithCancel(ctx)
+	defer cancelChild()
+
+	cancelA()
+	<-ctx.Done()
+	<-child.Done()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	assert.ErrorIs(t, context.Cause(ctx), context.Canceled)
+}
+
+func TestMerge_Deadline(t *testing.T) {
+	now := time.Now()
+	a, cancelA := context.WithDeadline(context.Background(), now.Add(time.Hour))
+	defer cancelA()
+	b, cancelB := context.WithDeadline(context.Background(), now.Add(20*time.Millisecond))
+	defer cancelB()
+
+	ctx, cancel := Merge(a, b)
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, now.Add(20*time.Millisecond), deadline)
+
+	ctx2, cancel2 := Merge(b, a)
+	defer cancel2()
+	deadline, _ = ctx2.Deadline()
+	assert.Equal(t, now.Add(20*time.Millisecond), deadline)
+
+	ctx3, cancel3 := Merge(context.Background(), a)
+	defer cancel3()
+	deadline, _ = ctx3.Deadline()
+	assert.Equal(t, now.Add(time.Hour), deadline)
+
+	<-ctx.Done()
+	assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+	assert.ErrorIs(t, context.Cause(ctx), context.DeadlineExceeded)
+}
+
+func TestMerge_Cancel(t *testing.T) {
+	ctx, cancel := Merge(context.Background(), context.Background())
+	cancel()
+	cancel()
+	<-ctx.Done()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
+func TestMerge_Leak(t *testing.T) {
+	b, cancelB := context.WithCancel(context.Background())
+	defer cancelB()
+
+	before := runtime.NumGoroutine()
+	for i := 0; i < 100; i++ {
+		_, cancel := Merge(context.Background(), b)
+		cancel()
+	}
+	assert.Eventually(t, func() bool {
+		return runtime.NumGoroutine() <= before+1
+	}, time.Second, time.Millisecond)
+}
@@ -0,0 +1,100 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcontext
+
+import (
+	"context"
+	"github.com/chenquan/go-pkg/xds"
+)
+
+type (
+	metaContext struct {
+		context.Context
+		// a pointer, so that Value doesn't allocate to box the map.
+		meta *xds.Map[string, interface{}]
+	}
+
+	metaKey struct{}
+)
+
+// WithMeta returns a copy of ctx that carries key and val in its metadata,
+// overriding the value of key if any.
+//
+// Unlike context.WithValue, the metadata is kept in an immutable persistent map and
+// stacking WithMeta doesn't deepen the context chain,
+// so the lookup costs the same however many values have been added.
+func WithMeta(ctx context.Context, key string, val interface{}) context.Context {
+	if c, ok := ctx.(*metaContext); ok {
+		meta := c.meta.Set(key, val)
+		return &metaContext{Context: c.Context, meta: &meta}
+	}
+
+	meta := metaFrom(ctx).Set(key, val)
+
+	return &metaContext{Context: ctx, meta: &meta}
+}
+
+// Meta returns the metadata value of key in ctx.
+func Meta(ctx context.Context, key string) (interface{}, bool) {
+	return metaFrom(ctx).Get(key)
+}
+
+// MetaValue returns the metadata value of key in ctx if it's of type T.
+func MetaValue[T any](ctx context.Context, key string) (T, bool) {
+	v, ok := Meta(ctx, key)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+
+	t, ok := v.(T)
+
+	return t, ok
+}
+
+// MetaSnapshot returns all the metadata of ctx, e.g. to log or propagate it.
+func MetaSnapshot(ctx context.Context) map[string]interface{} {
+	meta := metaFrom(ctx)
+	snapshot := make(map[string]interface{}, meta.Len())
+	meta.Range(func(key string, value interface{}) bool {
+		snapshot[key] = value
+		return true
+	})
+
+	return snapshot
+}
+
+func (c *metaContext) Value(key interface{}) interface{} {
+	if key == (metaKey{}) {
+		return c.meta
+	}
+
+	return c.Context.Value(key)
+}
+
+func metaFrom(ctx context.Context) xds.Map[string, interface{}] {
+	if ctx == nil {
+		return xds.Map[string, interface{}]{}
+	}
+
+	meta, ok := ctx.Value(metaKey{}).(*xds.Map[string, interface{}])
+	if !ok {
+		return xds.Map[string, interface{}]{}
+	}
+
+	return *meta
+}
@@ -0,0 +1,94 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcontext
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+)
+
+func TestWithMeta(t *testing.T) {
+	parent, cancel := context.WithCancel(context.WithValue(context.Background(), testKey("k"), "v"))
+	defer cancel()
+
+	assert.Empty(t, MetaSnapshot(parent))
+	_, ok := Meta(parent, "user")
+	assert.False(t, ok)
+
+	ctx := WithMeta(parent, "user", "u1")
+	ctx2 := WithMeta(ctx, "tenant", 42)
+	ctx3 := WithMeta(ctx2, "user", "u2")
+
+	assert.Equal(t, map[string]interface{}{"user": "u1"}, MetaSnapshot(ctx))
+	assert.Equal(t, map[string]interface{}{"user": "u1", "tenant": 42}, MetaSnapshot(ctx2))
+	assert.Equal(t, map[string]interface{}{"user": "u2", "tenant": 42}, MetaSnapshot(ctx3))
+
+	// the chain is not deepened.
+	assert.Equal(t, parent, ctx3.(*metaContext).Context)
+	assert.Equal(t, "v", ctx3.Value(testKey("k")))
+
+	user, ok := MetaValue[string](ctx3, "user")
+	assert.True(t, ok)
+	assert.Equal(t, "u2", user)
+	_, ok = MetaValue[string](ctx3, "tenant")
+	assert.False(t, ok)
+	_, ok = MetaValue[int](ctx3, "none")
+	assert.False(t, ok)
+
+	// metadata is inherited through other contexts.
+	child := context.WithValue(ctx3, testKey("x"), "y")
+	child = WithMeta(child, "trace", "t1")
+	assert.Equal(t, map[string]interface{}{"user": "u2", "tenant": 42, "trace": "t1"}, MetaSnapshot(child))
+
+	cancel()
+	assert.Error(t, ctx3.Err())
+}
+
+func TestWithMeta_Many(t *testing.T) {
+	ctx := context.Background()
+	for i := 0; i < 2000; i++ {
+		ctx = WithMeta(ctx, strconv.Itoa(i), i)
+	}
+	ctx = WithMeta(ctx, "10", "ten")
+
+	snapshot := MetaSnapshot(ctx)
+	assert.Len(t, snapshot, 2000)
+	for i := 0; i < 2000; i++ {
+		v, ok := Meta(ctx, strconv.Itoa(i))
+		assert.True(t, ok)
+		if i == 10 {
+			assert.Equal(t, "ten", v)
+		} else {
+			assert.Equal(t, i, v)
+		}
+	}
+	_, ok := Meta(ctx, "2000")
+	assert.False(t, ok)
+}
+
+func BenchmarkMeta(b *testing.B) {
+	ctx := context.Background()
+	for i := 0; i < 100; i++ {
+		ctx = WithMeta(ctx, strconv.Itoa(i), i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Meta(ctx, "50")
+	}
+}
@@ -0,0 +1,578 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xconv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+const defaultCopyTagName = "copy"
+
+// ErrInvalidCopyTarget is returned when the destination isn't a non-nil pointer.
+var ErrInvalidCopyTarget = errors.New("xconv: copy destination must be a non-nil pointer")
+
+type (
+	// CopyOption defines the method to customize Copy and NewCopier.
+	CopyOption func(*copyOptions)
+
+	copyOptions struct {
+		tagName    string
+		ignore     map[string]struct{}
+		converters map[typePair]converter
+	}
+
+	// A Copier copies values of type S into values of type D with a precompiled field mapping.
+	// A Copier is safe for concurrent use.
+	Copier[D, S any] struct {
+		conv converter
+	}
+
+	typePair struct {
+		dst reflect.Type
+		src reflect.Type
+	}
+
+	// converter copies src into dst, dst is always settable.
+	converter func(dst, src reflect.Value) error
+
+	fieldPlan struct {
+		name string
+		dst  []int
+		src  []int
+		conv converter
+	}
+
+	compiler struct {
+		opts  *copyOptions
+		plans map[typePair]*converter
+	}
+)
+
+// WithCopyTagName customizes the struct tag used to rename fields, default to "copy".
+// A tag value of "-" excludes the field.
+func WithCopyTagName(name string) CopyOption {
+	return func(options *copyOptions) {
+		options.tagName = name
+	}
+}
+
+// WithCopyIgnore excludes the destination fields with the given names at any depth.
+func WithCopyIgnore(fields ...string) CopyOption {
+	return func(options *copyOptions) {
+		for _, field := range fields {
+			options.ignore[field] = struct{}{}
+		}
+	}
+}
+
+// WithCopyConverter registers fn to convert values of type S into values of type D.
+// Registered converters take precedence over the built-in rules.
+func WithCopyConverter[S, D any](fn func(S) (D, error)) CopyOption {
+	pair := typePair{
+		dst: reflect.TypeOf((*D)(nil)).Elem(),
+		src: reflect.TypeOf((*S)(nil)).Elem(),
+	}
+
+	return func(options *copyOptions) {
+		options.converters[pair] = func(dst, src reflect.Value) error {
+			s, _ := src.Interface().(S)
+			d, err := fn(s)
+			if err != nil {
+				return err
+			}
+			dst.Set(reflect.ValueOf(&d).Elem())
+
+			return nil
+		}
+	}
+}
+
+// Copy copies src into the value dst points to.
+//
+// Struct fields are matched by name, or by the name given in the `copy` tag, including
+// fields promoted from embedded structs. Nested structs, pointers, slices, arrays and
+// maps are deep copied, so the copy never shares them with src, and numeric or string
+// kinds are converted to each other. Values held in interfaces, channels, functions and
+// unexported fields are copied as is, structs without exported fields such as time.Time
+// are assigned as a whole. Destination fields without a source counterpart or excluded by
+// the tag or WithCopyIgnore are left untouched, and so are the unexported fields of a struct
+// holding excluded fields.
+//
+// Copy compiles the mapping on every call, use NewCopier for repeated copies.
+func Copy(dst, src interface{}, opts ...CopyOption) error {
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() {
+		return ErrInvalidCopyTarget
+	}
+	sv := reflect.ValueOf(src)
+	if !sv.IsValid() {
+		return nil
+	}
+
+	conv, err := newCompiler(opts...).compile(dv.Elem().Type(), sv.Type())
+	if err != nil {
+		return err
+	}
+
+	return conv(dv.Elem(), sv)
+}
+
+// NewCopier returns a Copier with the field mapping from S to D compiled once.
+func NewCopier[D, S any](opts ...CopyOption) (*Copier[D, S], error) {
+	conv, err := newCompiler(opts...).compile(reflect.TypeOf((*D)(nil)).Elem(), reflect.TypeOf((*S)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Copier[D, S]{conv: conv}, nil
+}
+
+// Copy copies src into the value dst points to.
+func (c *Copier[D, S]) Copy(dst *D, src S) error {
+	if dst == nil {
+		return ErrInvalidCopyTarget
+	}
+
+	return c.conv(reflect.ValueOf(dst).Elem(), reflect.ValueOf(&src).Elem())
+}
+
+func newCompiler(opts ...CopyOption) *compiler {
+	options := &copyOptions{
+		tagName:    defaultCopyTagName,
+		ignore:     map[string]struct{}{},
+		converters: map[typePair]converter{},
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return &compiler{opts: options, plans: map[typePair]*converter{}}
+}
+
+func (c *compiler) compile(dt, st reflect.Type) (converter, error) {
+	pair := typePair{dst: dt, src: st}
+	if conv, ok := c.opts.converters[pair]; ok {
+		return conv, nil
+	}
+	// a struct pair under compilation, e.g. a recursive type.
+	if conv, ok := c.plans[pair]; ok {
+		return func(dst, src reflect.Value) error {
+			return (*conv)(dst, src)
+		}, nil
+	}
+
+	switch {
+	case st == dt && !hasRefs(st) && c.wholeCopy(st),
+		st.AssignableTo(dt) && (isScalar(st.Kind()) || dt.Kind() == reflect.Interface):
+		return assign, nil
+	case st.Kind() == reflect.Struct && dt.Kind() == reflect.Struct:
+		if st == dt && !hasExportedFields(st) {
+			return assign, nil
+		}
+		return c.compileStruct(dt, st)
+	case st.Kind() == reflect.Ptr:
+		return c.compileFromPtr(dt, st)
+	case dt.Kind() == reflect.Ptr:
+		return c.compileToPtr(dt, st)
+	case dt.Kind() == reflect.Slice && (st.Kind() == reflect.Slice || st.Kind() == reflect.Array):
+		return c.compileSlice(dt, st)
+	case dt.Kind() == reflect.Array && st.Kind() == reflect.Array && dt.Len() == st.Len():
+		return c.compileArray(dt, st)
+	case dt.Kind() == reflect.Map && st.Kind() == reflect.Map:
+		return c.compileMap(dt, st)
+	case st.AssignableTo(dt):
+		return assign, nil
+	case convertible(dt, st):
+		return func(dst, src reflect.Value) error {
+			dst.Set(src.Convert(dt))
+			return nil
+		}, nil
+	}
+
+	return nil, fmt.Errorf("xconv: can't copy %s to %s", st, dt)
+}
+
+// wholeCopy reports whether a value of t can be assigned to another one of t as a whole,
+// ignored fields and custom converters need a field by field copy.
+func (c *compiler) wholeCopy(t reflect.Type) bool {
+	return len(c.opts.ignore) == 0 && len(c.opts.converters) == 0 && !c.excludes(t, map[reflect.Type]bool{})
+}
+
+// excludes reports whether values of t hold struct fields excluded by the tag at any depth.
+func (c *compiler) excludes(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return c.excludes(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.IsExported() && f.Tag.Get(c.opts.tagName) == "-" || c.excludes(f.Type, seen) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (c *compiler) compileStruct(dt, st reflect.Type) (converter, error) {
+	pair := typePair{dst: dt, src: st}
+	conv := new(converter)
+	c.plans[pair] = conv
+
+	srcFields, dstFields := c.fields(st), c.fields(dt)
+	var plans []fieldPlan
+	for _, df := range reflect.VisibleFields(dt) {
+		key, ok := c.fieldKey(df)
+		if !ok || !equalIndex(dstFields[key].Index, df.Index) || !allocatable(dt, df.Index) {
+			continue
+		}
+		if _, ignored := c.opts.ignore[df.Name]; ignored {
+			continue
+		}
+		sf, ok := srcFields[key]
+		if !ok {
+			continue
+		}
+
+		fc, err := c.compile(df.Type, sf.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", df.Name, err)
+		}
+		plans = append(plans, fieldPlan{name: df.Name, dst: df.Index, src: sf.Index, conv: fc})
+	}
+
+	// identical types start from a plain assignment to keep the unexported fields,
+	// the exported fields holding references are then cleared and deep copied.
+	var shared [][]int
+	sameType := st == dt && c.wholeCopy(dt)
+	if sameType {
+		shared = refFields(dt, nil)
+	}
+
+	*conv = func(dst, src reflect.Value) error {
+		if sameType {
+			dst.Set(src)
+			for _, index := range shared {
+				f := dst.FieldByIndex(index)
+				f.Set(reflect.Zero(f.Type()))
+			}
+		}
+		for _, p := range plans {
+			sv, ok := fieldByIndex(src, p.src)
+			if !ok {
+				continue
+			}
+			if err := p.conv(fieldByIndexAlloc(dst, p.dst), sv); err != nil {
+				return fmt.Errorf("field %s: %w", p.name, err)
+			}
+		}
+		return nil
+	}
+
+	return *conv, nil
+}
+
+// fields returns the copyable fields of t by key, shallower fields shadow deeper ones.
+func (c *compiler) fields(t reflect.Type) map[string]reflect.StructField {
+	fields := map[string]reflect.StructField{}
+	for _, f := range reflect.VisibleFields(t) {
+		key, ok := c.fieldKey(f)
+		if !ok {
+			continue
+		}
+		if old, exist := fields[key]; exist && len(old.Index) <= len(f.Index) {
+			continue
+		}
+		fields[key] = f
+	}
+
+	return fields
+}
+
+func (c *compiler) fieldKey(f reflect.StructField) (string, bool) {
+	if !f.IsExported() {
+		return "", false
+	}
+
+	tag := f.Tag.Get(c.opts.tagName)
+	if tag == "-" {
+		return "", false
+	}
+	if tag != "" {
+		return tag, true
+	}
+	// embedded structs are copied through their promoted fields.
+	if f.Anonymous && indirectType(f.Type).Kind() == reflect.Struct {
+		return "", false
+	}
+
+	return f.Name, true
+}
+
+func (c *compiler) compileFromPtr(dt, st reflect.Type) (converter, error) {
+	elem, err := c.compile(dt, st.Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	return func(dst, src reflect.Value) error {
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dt))
+			return nil
+		}
+		return elem(dst, src.Elem())
+	}, nil
+}
+
+func (c *compiler) compileToPtr(dt, st reflect.Type) (converter, error) {
+	elem, err := c.compile(dt.Elem(), st)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(dst, src reflect.Value) error {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dt.Elem()))
+		}
+		return elem(dst.Elem(), src)
+	}, nil
+}
+
+func (c *compiler) compileSlice(dt, st reflect.Type) (converter, error) {
+	elem, err := c.compile(dt.Elem(), st.Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	return func(dst, src reflect.Value) error {
+		if src.Kind() == reflect.Slice && src.IsNil() {
+			dst.Set(reflect.Zero(dt))
+			return nil
+		}
+
+		s := reflect.MakeSlice(dt, src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			if err := elem(s.Index(i), src.Index(i)); err != nil {
+				return fmt.Errorf("index %d: %w", i, err)
+			}
+		}
+		dst.Set(s)
+		return nil
+	}, nil
+}
+
+func (c *compiler) compileArray(dt, st reflect.Type) (converter, error) {
+	elem, err := c.compile(dt.Elem(), st.Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	return func(dst, src reflect.Value) error {
+		for i := 0; i < src.Len(); i++ {
+			if err := elem(dst.Index(i), src.Index(i)); err != nil {
+				return fmt.Errorf("index %d: %w", i, err)
+			}
+		}
+		return nil
+	}, nil
+}
+
+func (c *compiler) compileMap(dt, st reflect.Type) (converter, error) {
+	// keys of the same type are kept as is, copying a pointer key would change its identity.
+	key := assign
+	if st.Key() != dt.Key() {
+		var err error
+		if key, err = c.compile(dt.Key(), st.Key()); err != nil {
+			return nil, err
+		}
+	}
+	elem, err := c.compile(dt.Elem(), st.Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	return func(dst, src reflect.Value) error {
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dt))
+			return nil
+		}
+
+		m := reflect.MakeMapWithSize(dt, src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			k := reflect.New(dt.Key()).Elem()
+			if err := key(k, iter.Key()); err != nil {
+				return err
+			}
+			v := reflect.New(dt.Elem()).Elem()
+			if err := elem(v, iter.Value()); err != nil {
+				return fmt.Errorf("key %v: %w", iter.Key(), err)
+			}
+			m.SetMapIndex(k, v)
+		}
+		dst.Set(m)
+		return nil
+	}, nil
+}
+
+func assign(dst, src reflect.Value) error {
+	dst.Set(src)
+	return nil
+}
+
+// convertible reports whether a Go conversion between scalar kinds of the same family is safe to apply.
+func convertible(dt, st reflect.Type) bool {
+	if !st.ConvertibleTo(dt) {
+		return false
+	}
+
+	sk, dk := st.Kind(), dt.Kind()
+	switch {
+	case isNumber(sk) && isNumber(dk):
+		return true
+	case sk == reflect.String && dk == reflect.String,
+		sk == reflect.Bool && dk == reflect.Bool:
+		return true
+	}
+
+	return false
+}
+
+func isNumber(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Float64
+}
+
+func isScalar(k reflect.Kind) bool {
+	return isNumber(k) || k == reflect.String || k == reflect.Bool
+}
+
+// allocatable reports whether the embedded pointers on the path can be allocated,
+// pointers to unexported embedded structs can't be set through reflection.
+func allocatable(t reflect.Type, index []int) bool {
+	for _, x := range index[:len(index)-1] {
+		f := t.Field(x)
+		if f.Type.Kind() == reflect.Ptr && !f.IsExported() {
+			return false
+		}
+		t = indirectType(f.Type)
+	}
+
+	return true
+}
+
+func equalIndex(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hasRefs reports whether values of t may share memory through pointers, slices, maps and the like.
+func hasRefs(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Array:
+		return hasRefs(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if hasRefs(t.Field(i).Type) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return !isScalar(t.Kind()) && !(t.Kind() >= reflect.Complex64 && t.Kind() <= reflect.Complex128)
+}
+
+// refFields returns the settable fields of t holding references, looking into embedded structs.
+func refFields(t reflect.Type, prefix []int) [][]int {
+	var fields [][]int
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		index := append(append([]int(nil), prefix...), i)
+		switch {
+		case f.Anonymous && f.Type.Kind() == reflect.Struct:
+			fields = append(fields, refFields(f.Type, index)...)
+		case f.IsExported() && hasRefs(f.Type):
+			fields = append(fields, index)
+		}
+	}
+
+	return fields
+}
+
+func hasExportedFields(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+// fieldByIndex returns the nested field of v, false if an embedded pointer on the path is nil.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	return v, true
+}
+
+// fieldByIndexAlloc returns the nested field of v, allocating nil embedded pointers on the path.
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	return v
+}
@@ -0,0 +1,320 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xconv
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type (
+	base struct {
+		ID        int64
+		CreatedAt time.Time
+	}
+	address struct {
+		City   string
+		Street string
+	}
+	userEntity struct {
+		base
+		Name     string
+		Password string
+		Age      int32
+		Email    string `copy:"mail"`
+		Address  *address
+		Tags     []string
+		Friends  []address
+		Scores   map[string]int
+		Birthday time.Time
+		secret   string
+	}
+
+	addressDTO struct {
+		City string
+	}
+	userDTO struct {
+		ID       int64
+		Name     string
+		Password string
+		Age      int
+		Mail     string `copy:"mail"`
+		Address  addressDTO
+		Tags     []string
+		Friends  []*addressDTO
+		Scores   map[string]float64
+		Birthday string
+		Skipped  string `copy:"-"`
+		Extra    string
+	}
+
+	node struct {
+		Value    int
+		Children []node
+	}
+	nodeDTO struct {
+		Value    int
+		Children []nodeDTO
+	}
+)
+
+func newUserEntity() userEntity {
+	return userEntity{
+		base:     base{ID: 1, CreatedAt: time.Unix(0, 0)},
+		Name:     "bob",
+		Password: "pw",
+		Age:      20,
+		Email:    "bob@example.com",
+		Address:  &address{City: "Hangzhou", Street: "x"},
+		Tags:     []string{"a"},
+		Friends:  []address{{City: "Beijing"}},
+		Scores:   map[string]int{"math": 90},
+		Birthday: time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC),
+		secret:   "s",
+	}
+}
+
+func TestCopy(t *testing.T) {
+	src := newUserEntity()
+	dst := userDTO{Extra: "keep", Skipped: "keep"}
+	err := Copy(&dst, &src,
+		WithCopyIgnore("Password"),
+		WithCopyConverter(func(t time.Time) (string, error) {
+			return t.Format("2006-01-02"), nil
+		}),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, userDTO{
+		ID:       1,
+		Name:     "bob",
+		Age:      20,
+		Mail:     "bob@example.com",
+		Address:  addressDTO{City: "Hangzhou"},
+		Tags:     []string{"a"},
+		Friends:  []*addressDTO{{City: "Beijing"}},
+		Scores:   map[string]float64{"math": 90},
+		Birthday: "2000-01-02",
+		Skipped:  "keep",
+		Extra:    "keep",
+	}, dst)
+
+	// nil values
+	src.Address = nil
+	src.Tags = nil
+	src.Scores = nil
+	assert.NoError(t, Copy(&dst, src, WithCopyConverter(func(t time.Time) (string, error) {
+		return "", nil
+	})))
+	assert.Equal(t, addressDTO{}, dst.Address)
+	assert.Nil(t, dst.Tags)
+	assert.Nil(t, dst.Scores)
+	assert.Equal(t, "pw", dst.Password)
+}
+
+func TestCopy_Error(t *testing.T) {
+	var dst userDTO
+	assert.Equal(t, ErrInvalidCopyTarget, Copy(dst, newUserEntity()))
+	assert.Equal(t, ErrInvalidCopyTarget, Copy((*userDTO)(nil), newUserEntity()))
+	assert.NoError(t, Copy(&dst, nil))
+
+	// time.Time can't be copied to string without a converter.
+	err := Copy(&dst, newUserEntity())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Birthday")
+
+	errConv := errors.New("conv")
+	err = Copy(&dst, newUserEntity(), WithCopyConverter(func(t time.Time) (string, error) {
+		return "", errConv
+	}))
+	assert.True(t, errors.Is(err, errConv))
+}
+
+func TestCopy_Recursive(t *testing.T) {
+	src := node{Value: 1, Children: []node{{Value: 2, Children: []node{{Value: 3}}}}}
+	var dst nodeDTO
+	assert.NoError(t, Copy(&dst, src))
+	assert.Equal(t, nodeDTO{Value: 1, Children: []nodeDTO{{Value: 2, Children: []nodeDTO{{Value: 3}}}}}, dst)
+}
+
+func TestCopy_SameType(t *testing.T) {
+	src := newUserEntity()
+	var dst userEntity
+	assert.NoError(t, Copy(&dst, src))
+	assert.Equal(t, src, dst)
+
+	dst = userEntity{}
+	assert.NoError(t, Copy(&dst, src, WithCopyIgnore("Password", "City")))
+	assert.Equal(t, "", dst.Password)
+	assert.Equal(t, "", dst.Address.City)
+	assert.Equal(t, "x", dst.Address.Street)
+	assert.Equal(t, src.CreatedAt, dst.CreatedAt)
+	assert.Equal(t, "", dst.secret)
+}
+
+func TestCopy_SameTypeExcluded(t *testing.T) {
+	type (
+		inner struct {
+			A []int
+			B []int `copy:"-"`
+			C int   `copy:"-"`
+		}
+		outer struct {
+			Inner inner
+			Ptr   *inner
+			D     string
+		}
+	)
+
+	dst := inner{B: []int{9}, C: 9}
+	assert.NoError(t, Copy(&dst, inner{A: []int{1}, B: []int{2}, C: 2}))
+	assert.Equal(t, inner{A: []int{1}, B: []int{9}, C: 9}, dst)
+
+	src := outer{Inner: inner{A: []int{1}, B: []int{2}, C: 2}, Ptr: &inner{C: 2}, D: "d"}
+	o := outer{Inner: inner{B: []int{9}, C: 9}, Ptr: &inner{C: 9}}
+	assert.NoError(t, Copy(&o, src))
+	assert.Equal(t, outer{Inner: inner{A: []int{1}, B: []int{9}, C: 9}, Ptr: &inner{C: 9}, D: "d"}, o)
+}
+
+func TestCopy_Deep(t *testing.T) {
+	type (
+		Inner struct {
+			Tags []string
+		}
+		deep struct {
+			*Inner
+			Address *address
+			Friends []address
+			Scores  map[string]*address
+			Pair    [2]*address
+			secret  string
+		}
+	)
+
+	src := deep{
+		Inner:   &Inner{Tags: []string{"a"}},
+		Address: &address{City: "Hangzhou"},
+		Friends: []address{{City: "Beijing"}},
+		Scores:  map[string]*address{"home": {City: "Shanghai"}},
+		Pair:    [2]*address{{City: "Suzhou"}},
+		secret:  "s",
+	}
+	var dst deep
+	assert.NoError(t, Copy(&dst, src))
+	assert.Equal(t, src, dst)
+
+	dst.Inner.Tags[0] = "b"
+	dst.Address.City = "b"
+	dst.Friends[0].City = "b"
+	dst.Scores["home"].City = "b"
+	dst.Pair[0].City = "b"
+	assert.Equal(t, []string{"a"}, src.Tags)
+	assert.Equal(t, "Hangzhou", src.Address.City)
+	assert.Equal(t, "Beijing", src.Friends[0].City)
+	assert.Equal(t, "Shanghai", src.Scores["home"].City)
+	assert.Equal(t, "Suzhou", src.Pair[0].City)
+
+	u := newUserEntity()
+	var cu userEntity
+	assert.NoError(t, Copy(&cu, &u))
+	cu.Tags[0] = "b"
+	cu.Scores["math"] = 0
+	cu.Address.City = "b"
+	assert.Equal(t, newUserEntity(), u)
+}
+
+func TestCopy_Embedded(t *testing.T) {
+	type (
+		Inner struct {
+			A int
+		}
+		inner struct {
+			A int
+		}
+		outer struct {
+			*Inner
+			B int
+		}
+		hidden struct {
+			*inner
+			B int
+		}
+		flat struct {
+			A int
+			B int
+		}
+	)
+
+	var o outer
+	assert.NoError(t, Copy(&o, flat{A: 1, B: 2}))
+	assert.Equal(t, 1, o.A)
+	assert.Equal(t, 2, o.B)
+
+	var h hidden
+	assert.NoError(t, Copy(&h, flat{A: 1, B: 2}))
+	assert.Nil(t, h.inner)
+	assert.Equal(t, 2, h.B)
+
+	var f flat
+	assert.NoError(t, Copy(&f, outer{B: 3}))
+	assert.Equal(t, flat{B: 3}, f)
+}
+
+func TestCopy_Slice(t *testing.T) {
+	var dst []addressDTO
+	assert.NoError(t, Copy(&dst, []address{{City: "a"}, {City: "b"}}))
+	assert.Equal(t, []addressDTO{{City: "a"}, {City: "b"}}, dst)
+
+	var arr []int
+	assert.NoError(t, Copy(&arr, [2]int8{1, 2}))
+	assert.Equal(t, []int{1, 2}, arr)
+
+	var m map[int64]string
+	assert.Error(t, Copy(&m, map[string]string{}))
+}
+
+func TestNewCopier(t *testing.T) {
+	copier, err := NewCopier[userDTO, *userEntity](WithCopyConverter(func(t time.Time) (string, error) {
+		return strconv.Itoa(t.Year()), nil
+	}))
+	assert.NoError(t, err)
+
+	src := newUserEntity()
+	var dst userDTO
+	assert.NoError(t, copier.Copy(&dst, &src))
+	assert.Equal(t, "2000", dst.Birthday)
+	assert.Equal(t, "bob", dst.Name)
+	assert.Equal(t, ErrInvalidCopyTarget, copier.Copy(nil, &src))
+
+	_, err = NewCopier[userDTO, userEntity]()
+	assert.Error(t, err)
+}
+
+func BenchmarkCopier(b *testing.B) {
+	copier, _ := NewCopier[userDTO, userEntity](WithCopyConverter(func(t time.Time) (string, error) {
+		return "", nil
+	}))
+	src := newUserEntity()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var dst userDTO
+		_ = copier.Copy(&dst, src)
+	}
+}
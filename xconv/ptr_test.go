@@ -0,0 +1,56 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xconv
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPtr(t *testing.T) {
+	v := 1
+	p := Ptr(v)
+	assert.Equal(t, 1, *p)
+	*p = 2
+	assert.Equal(t, 1, v)
+	assert.Equal(t, "s", *Ptr("s"))
+}
+
+func TestDeref(t *testing.T) {
+	assert.Equal(t, 3, Deref(Ptr(3), 5))
+	assert.Equal(t, 5, Deref(nil, 5))
+	assert.Equal(t, "", Deref(Ptr(""), "def"))
+	assert.Equal(t, "def", Deref[string](nil, "def"))
+}
+
+func TestIsZero(t *testing.T) {
+	type point struct{ X, Y int }
+
+	assert.True(t, IsZero(0))
+	assert.True(t, IsZero(""))
+	assert.True(t, IsZero(point{}))
+	assert.True(t, IsZero[*int](nil))
+	assert.False(t, IsZero(1))
+	assert.False(t, IsZero(point{X: 1}))
+}
+
+func TestCoalesce(t *testing.T) {
+	assert.Equal(t, "b", Coalesce("", "b", "c"))
+	assert.Equal(t, 3, Coalesce(0, 0, 3))
+	assert.Equal(t, 0, Coalesce(0, 0))
+	assert.Equal(t, "", Coalesce[string]())
+}
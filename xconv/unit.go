@@ -0,0 +1,179 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xconv
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	siPrefixes  = []string{"", "k", "M", "G", "T", "P", "E"}
+	iecPrefixes = []string{"", "Ki", "Mi", "Gi", "Ti", "Pi", "Ei"}
+
+	// unitMultipliers maps lower-cased unit prefixes to their multipliers.
+	unitMultipliers = map[string]float64{
+		"":   1,
+		"k":  1e3,
+		"m":  1e6,
+		"g":  1e9,
+		"t":  1e12,
+		"p":  1e15,
+		"e":  1e18,
+		"ki": 1 << 10,
+		"mi": 1 << 20,
+		"gi": 1 << 30,
+		"ti": 1 << 40,
+		"pi": 1 << 50,
+		"ei": 1 << 60,
+	}
+)
+
+// ParseSize parses a byte size such as "512", "10MiB", "1.5 GB" or "64k".
+// SI units (k, M, G, ...) are powers of 1000 and IEC units (Ki, Mi, Gi, ...) are powers of 1024,
+// the trailing "B" is optional and units are case-insensitive.
+func ParseSize(s string) (int64, error) {
+	num, unit := splitUnit(s)
+	unit = strings.ToLower(unit)
+	unit = strings.TrimSuffix(unit, "b")
+
+	i, err := parseWithMultiplier(num, unit)
+	if err != nil {
+		return 0, fmt.Errorf("%w: size %q", err, s)
+	}
+
+	return i, nil
+}
+
+// ParseCount parses a count such as "500", "1.2k" or "3M", units are the same as ParseSize without "B".
+func ParseCount(s string) (int64, error) {
+	num, unit := splitUnit(s)
+
+	i, err := parseWithMultiplier(num, strings.ToLower(unit))
+	if err != nil {
+		return 0, fmt.Errorf("%w: count %q", err, s)
+	}
+
+	return i, nil
+}
+
+// ParsePercent parses a percentage such as "12.5%" into a fraction (0.125).
+// Values without "%" are taken as fractions as is, e.g. "0.3" is 0.3.
+func ParsePercent(s string) (float64, error) {
+	t := strings.TrimSpace(s)
+	percent := strings.HasSuffix(t, "%")
+	t = strings.TrimSpace(strings.TrimSuffix(t, "%"))
+
+	f, err := strconv.ParseFloat(t, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("%w: percent %q", ErrSyntax, s)
+	}
+	if percent {
+		f /= 100
+	}
+
+	return f, nil
+}
+
+// FormatSizeIEC formats n bytes with IEC units, e.g. 1536 is "1.5KiB".
+func FormatSizeIEC(n int64) string {
+	return formatWithUnit(n, 1024, iecPrefixes) + "B"
+}
+
+// FormatSizeSI formats n bytes with SI units, e.g. 1500 is "1.5kB".
+func FormatSizeSI(n int64) string {
+	return formatWithUnit(n, 1000, siPrefixes) + "B"
+}
+
+// FormatCount formats n with SI units, e.g. 1200 is "1.2k".
+func FormatCount(n int64) string {
+	return formatWithUnit(n, 1000, siPrefixes)
+}
+
+// FormatPercent formats the fraction f as a percentage with at most prec decimals, e.g. 0.125 is "12.5%".
+func FormatPercent(f float64, prec int) string {
+	s := strconv.FormatFloat(f*100, 'f', prec, 64)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+
+	return s + "%"
+}
+
+func splitUnit(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	i := len(s)
+	for i > 0 && isUnitLetter(s[i-1]) {
+		i--
+	}
+
+	return strings.TrimSpace(s[:i]), s[i:]
+}
+
+func isUnitLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func parseWithMultiplier(num, unit string) (int64, error) {
+	multiplier, ok := unitMultipliers[unit]
+	if !ok {
+		return 0, ErrSyntax
+	}
+
+	if i, err := strconv.ParseInt(num, 10, 64); err == nil {
+		if multiplier == 1 {
+			return i, nil
+		}
+		// exact integer math when possible.
+		m := int64(multiplier)
+		if i == 0 || (i > 0 && i <= math.MaxInt64/m) || (i < 0 && i >= math.MinInt64/m) {
+			return i * m, nil
+		}
+		return 0, ErrOutOfRange
+	}
+
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) || strings.ContainsAny(num, "eExX") {
+		return 0, ErrSyntax
+	}
+
+	v := math.Round(f * multiplier)
+	if v >= math.MaxInt64 || v < math.MinInt64 {
+		return 0, ErrOutOfRange
+	}
+
+	return int64(v), nil
+}
+
+func formatWithUnit(n int64, base float64, prefixes []string) string {
+	f := float64(n)
+	i := 0
+	for math.Abs(f) >= base && i < len(prefixes)-1 {
+		f /= base
+		i++
+	}
+	if i == 0 {
+		return strconv.FormatInt(n, 10) + prefixes[0]
+	}
+
+	s := strconv.FormatFloat(f, 'f', 2, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+
+	return s + prefixes[i]
+}
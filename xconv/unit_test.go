@@ -0,0 +1,126 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xconv
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"0", 0},
+		{"512", 512},
+		{"512B", 512},
+		{"1k", 1000},
+		{"1KB", 1000},
+		{"1KiB", 1024},
+		{"10MiB", 10 << 20},
+		{"10mib", 10 << 20},
+		{" 1.5 GB ", 1500000000},
+		{"1.5Gi", 3 << 29},
+		{"2TiB", 2 << 40},
+		{"1PB", 1e15},
+		{"7EiB", 7 << 60},
+		{"-1KiB", -1024},
+	}
+	for _, tt := range tests {
+		got, err := ParseSize(tt.in)
+		assert.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+
+	for _, in := range []string{"", "MiB", "1XB", "1.2.3MB", "1e3", "0x10"} {
+		_, err := ParseSize(in)
+		assert.True(t, errors.Is(err, ErrSyntax), in)
+	}
+	for _, in := range []string{"8EiB", "9.5EiB", "9223372036854775807KB"} {
+		_, err := ParseSize(in)
+		assert.True(t, errors.Is(err, ErrOutOfRange), in)
+	}
+}
+
+func TestParseCount(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"500", 500},
+		{"1.2k", 1200},
+		{"1.2K", 1200},
+		{"3M", 3000000},
+		{"2Ki", 2048},
+		{"0.5", 1},
+	}
+	for _, tt := range tests {
+		got, err := ParseCount(tt.in)
+		assert.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+
+	_, err := ParseCount("1kb")
+	assert.True(t, errors.Is(err, ErrSyntax))
+}
+
+func TestParsePercent(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+	}{
+		{"12.5%", 0.125},
+		{" 100 % ", 1},
+		{"-5%", -0.05},
+		{"0.3", 0.3},
+	}
+	for _, tt := range tests {
+		got, err := ParsePercent(tt.in)
+		assert.NoError(t, err, tt.in)
+		assert.InDelta(t, tt.want, got, 1e-12, tt.in)
+	}
+
+	for _, in := range []string{"", "%", "abc%", "NaN%", "Inf"} {
+		_, err := ParsePercent(in)
+		assert.True(t, errors.Is(err, ErrSyntax), in)
+	}
+}
+
+func TestFormatUnit(t *testing.T) {
+	assert.Equal(t, "512B", FormatSizeIEC(512))
+	assert.Equal(t, "1.5KiB", FormatSizeIEC(1536))
+	assert.Equal(t, "10MiB", FormatSizeIEC(10<<20))
+	assert.Equal(t, "-2GiB", FormatSizeIEC(-2<<30))
+	assert.Equal(t, "8EiB", FormatSizeIEC(1<<63-1))
+	assert.Equal(t, "1.5kB", FormatSizeSI(1500))
+	assert.Equal(t, "999B", FormatSizeSI(999))
+	assert.Equal(t, "1.23MB", FormatSizeSI(1234567))
+	assert.Equal(t, "1.2k", FormatCount(1200))
+	assert.Equal(t, "42", FormatCount(42))
+	assert.Equal(t, "12.5%", FormatPercent(0.125, 2))
+	assert.Equal(t, "33.33%", FormatPercent(1.0/3, 2))
+	assert.Equal(t, "100%", FormatPercent(1, 0))
+
+	// round trip
+	for _, n := range []int64{0, 1, 1023, 1024, 1536, 10 << 20, 3 << 40} {
+		got, err := ParseSize(FormatSizeIEC(n))
+		assert.NoError(t, err)
+		assert.Equal(t, n, got)
+	}
+}
@@ -0,0 +1,434 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xconv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrUnsupportedType is returned when a value's type can't be converted.
+	ErrUnsupportedType = errors.New("xconv: unsupported type")
+	// ErrOutOfRange is returned when a value doesn't fit into the target type.
+	ErrOutOfRange = errors.New("xconv: value out of range")
+	// ErrLossy is returned when a conversion would silently drop information, e.g. 1.5 to int64.
+	ErrLossy = errors.New("xconv: lossy conversion")
+	// ErrSyntax is returned when a string can't be parsed as the target type.
+	ErrSyntax = errors.New("xconv: invalid syntax")
+)
+
+// ToInt64 converts v to an int64.
+// Strings are trimmed and parsed in base 10 unless prefixed with "0x", "0o" or "0b",
+// floats must be integral, bools convert to 0 or 1 and nil converts to 0.
+func ToInt64(v interface{}) (int64, error) {
+	switch x := v.(type) {
+	case nil:
+		return 0, nil
+	case int:
+		return int64(x), nil
+	case int8:
+		return int64(x), nil
+	case int16:
+		return int64(x), nil
+	case int32:
+		return int64(x), nil
+	case int64:
+		return x, nil
+	case uint:
+		return uint64ToInt64(uint64(x), v)
+	case uint8:
+		return int64(x), nil
+	case uint16:
+		return int64(x), nil
+	case uint32:
+		return int64(x), nil
+	case uint64:
+		return uint64ToInt64(x, v)
+	case float32:
+		return float64ToInt64(float64(x), v)
+	case float64:
+		return float64ToInt64(x, v)
+	case bool:
+		if x {
+			return 1, nil
+		}
+		return 0, nil
+	case string:
+		return parseInt64(x, v)
+	case []byte:
+		return parseInt64(string(x), v)
+	case json.Number:
+		return parseInt64(string(x), v)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return uint64ToInt64(rv.Uint(), v)
+	case reflect.Float32, reflect.Float64:
+		return float64ToInt64(rv.Float(), v)
+	case reflect.Bool:
+		return ToInt64(rv.Bool())
+	case reflect.String:
+		return parseInt64(rv.String(), v)
+	}
+
+	return 0, convError(v, "int64", ErrUnsupportedType)
+}
+
+// MustToInt64 is like ToInt64 but panics when error happens.
+func MustToInt64(v interface{}) int64 {
+	i, err := ToInt64(v)
+	if err != nil {
+		panic(err)
+	}
+
+	return i
+}
+
+// ToInt converts v to an int, see ToInt64 for accepted inputs.
+func ToInt(v interface{}) (int, error) {
+	i, err := ToInt64(v)
+	if err != nil {
+		return 0, err
+	}
+	if int64(int(i)) != i {
+		return 0, convError(v, "int", ErrOutOfRange)
+	}
+
+	return int(i), nil
+}
+
+// MustToInt is like ToInt but panics when error happens.
+func MustToInt(v interface{}) int {
+	i, err := ToInt(v)
+	if err != nil {
+		panic(err)
+	}
+
+	return i
+}
+
+// ToFloat64 converts v to a float64.
+// Strings are trimmed and parsed, bools convert to 0 or 1 and nil converts to 0.
+func ToFloat64(v interface{}) (float64, error) {
+	switch x := v.(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		return x, nil
+	case float32:
+		return float64(x), nil
+	case int:
+		return float64(x), nil
+	case int8:
+		return float64(x), nil
+	case int16:
+		return float64(x), nil
+	case int32:
+		return float64(x), nil
+	case int64:
+		return float64(x), nil
+	case uint:
+		return float64(x), nil
+	case uint8:
+		return float64(x), nil
+	case uint16:
+		return float64(x), nil
+	case uint32:
+		return float64(x), nil
+	case uint64:
+		return float64(x), nil
+	case bool:
+		if x {
+			return 1, nil
+		}
+		return 0, nil
+	case string:
+		return parseFloat64(x, v)
+	case []byte:
+		return parseFloat64(string(x), v)
+	case json.Number:
+		return parseFloat64(string(x), v)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	case reflect.Bool:
+		return ToFloat64(rv.Bool())
+	case reflect.String:
+		return parseFloat64(rv.String(), v)
+	}
+
+	return 0, convError(v, "float64", ErrUnsupportedType)
+}
+
+// MustToFloat64 is like ToFloat64 but panics when error happens.
+func MustToFloat64(v interface{}) float64 {
+	f, err := ToFloat64(v)
+	if err != nil {
+		panic(err)
+	}
+
+	return f
+}
+
+// ToBool converts v to a bool.
+// Strings accept the strconv.ParseBool forms plus "yes", "no", "on", "off" and "" (false),
+// numbers are true if non-zero and nil converts to false.
+func ToBool(v interface{}) (bool, error) {
+	switch x := v.(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return x, nil
+	case string:
+		return parseBool(x, v)
+	case []byte:
+		return parseBool(string(x), v)
+	case json.Number:
+		f, err := parseFloat64(string(x), v)
+		return f != 0, err
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int() != 0, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint() != 0, nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float() != 0, nil
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.String:
+		return parseBool(rv.String(), v)
+	}
+
+	return false, convError(v, "bool", ErrUnsupportedType)
+}
+
+// MustToBool is like ToBool but panics when error happens.
+func MustToBool(v interface{}) bool {
+	b, err := ToBool(v)
+	if err != nil {
+		panic(err)
+	}
+
+	return b
+}
+
+// ToString converts v to a string.
+// Numbers are formatted in the shortest exact representation, fmt.Stringer and error
+// use their own methods and nil converts to "".
+func ToString(v interface{}) (string, error) {
+	switch x := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return x, nil
+	case []byte:
+		return string(x), nil
+	case json.Number:
+		return string(x), nil
+	case bool:
+		return strconv.FormatBool(x), nil
+	case int:
+		return strconv.Itoa(x), nil
+	case int64:
+		return strconv.FormatInt(x, 10), nil
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64), nil
+	case float32:
+		return strconv.FormatFloat(float64(x), 'f', -1, 32), nil
+	case fmt.Stringer:
+		return x.String(), nil
+	case error:
+		return x.Error(), nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64), nil
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), nil
+	case reflect.String:
+		return rv.String(), nil
+	}
+
+	return "", convError(v, "string", ErrUnsupportedType)
+}
+
+// MustToString is like ToString but panics when error happens.
+func MustToString(v interface{}) string {
+	s, err := ToString(v)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+// ToStringSlice converts v to a []string.
+// Slices and arrays convert each element with ToString, a string is split with strings.Fields
+// and nil converts to a nil slice.
+func ToStringSlice(v interface{}) ([]string, error) {
+	switch x := v.(type) {
+	case nil:
+		return nil, nil
+	case []string:
+		return x, nil
+	case string:
+		return strings.Fields(x), nil
+	case []interface{}:
+		ss := make([]string, len(x))
+		for i, item := range x {
+			s, err := ToString(item)
+			if err != nil {
+				return nil, fmt.Errorf("index %d: %w", i, err)
+			}
+			ss[i] = s
+		}
+		return ss, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		ss := make([]string, rv.Len())
+		for i := range ss {
+			s, err := ToString(rv.Index(i).Interface())
+			if err != nil {
+				return nil, fmt.Errorf("index %d: %w", i, err)
+			}
+			ss[i] = s
+		}
+		return ss, nil
+	}
+
+	return nil, convError(v, "[]string", ErrUnsupportedType)
+}
+
+// MustToStringSlice is like ToStringSlice but panics when error happens.
+func MustToStringSlice(v interface{}) []string {
+	ss, err := ToStringSlice(v)
+	if err != nil {
+		panic(err)
+	}
+
+	return ss
+}
+
+func parseInt64(s string, v interface{}) (int64, error) {
+	s = strings.TrimSpace(s)
+	i, err := strconv.ParseInt(s, intBase(s), 64)
+	if err == nil {
+		return i, nil
+	}
+	if errors.Is(err, strconv.ErrRange) {
+		return 0, convError(v, "int64", ErrOutOfRange)
+	}
+
+	// accept integral floats such as "1e3" or "2.0".
+	f, ferr := strconv.ParseFloat(s, 64)
+	if ferr != nil {
+		return 0, convError(v, "int64", ErrSyntax)
+	}
+
+	return float64ToInt64(f, v)
+}
+
+// intBase returns 0 to let strconv honor an explicit "0x", "0o" or "0b" prefix, and 10 otherwise,
+// so that a leading zero as in "010" isn't taken for octal.
+func intBase(s string) int {
+	s = strings.TrimLeft(s, "+-")
+	if len(s) > 2 && s[0] == '0' {
+		switch s[1] {
+		case 'x', 'X', 'o', 'O', 'b', 'B':
+			return 0
+		}
+	}
+
+	return 10
+}
+
+func parseFloat64(s string, v interface{}) (float64, error) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, convError(v, "float64", ErrOutOfRange)
+		}
+		return 0, convError(v, "float64", ErrSyntax)
+	}
+
+	return f, nil
+}
+
+func parseBool(s string, v interface{}) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "1", "t", "true", "y", "yes", "on":
+		return true, nil
+	case "", "0", "f", "false", "n", "no", "off":
+		return false, nil
+	}
+
+	return false, convError(v, "bool", ErrSyntax)
+}
+
+func uint64ToInt64(u uint64, v interface{}) (int64, error) {
+	if u > math.MaxInt64 {
+		return 0, convError(v, "int64", ErrOutOfRange)
+	}
+
+	return int64(u), nil
+}
+
+func float64ToInt64(f float64, v interface{}) (int64, error) {
+	if math.IsNaN(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, convError(v, "int64", ErrOutOfRange)
+	}
+	if f != math.Trunc(f) {
+		return 0, convError(v, "int64", ErrLossy)
+	}
+
+	return int64(f), nil
+}
+
+func convError(v interface{}, target string, err error) error {
+	return fmt.Errorf("%w: can't convert %#v (%T) to %s", err, v, v, target)
+}
@@ -0,0 +1,241 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xconv
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+	"time"
+)
+
+type (
+	myInt    int
+	myString string
+	stringer struct{}
+)
+
+func (stringer) String() string {
+	return "stringer"
+}
+
+func TestToInt64(t *testing.T) {
+	tests := []struct {
+		in   interface{}
+		want int64
+	}{
+		{nil, 0},
+		{1, 1},
+		{int8(-8), -8},
+		{int16(16), 16},
+		{int32(32), 32},
+		{int64(64), 64},
+		{uint(1), 1},
+		{uint8(8), 8},
+		{uint16(16), 16},
+		{uint32(32), 32},
+		{uint64(64), 64},
+		{float32(2), 2},
+		{3.0, 3},
+		{true, 1},
+		{false, 0},
+		{" 42 ", 42},
+		{"0x10", 16},
+		{"-0x10", -16},
+		{"0o17", 15},
+		{"0b101", 5},
+		{"010", 10},
+		{"08", 8},
+		{"-007", -7},
+		{"1e3", 1000},
+		{[]byte("7"), 7},
+		{json.Number("9"), 9},
+		{myInt(5), 5},
+		{myString("6"), 6},
+		{time.Second, int64(time.Second)},
+	}
+	for _, tt := range tests {
+		got, err := ToInt64(tt.in)
+		assert.NoError(t, err, "%#v", tt.in)
+		assert.Equal(t, tt.want, got, "%#v", tt.in)
+	}
+
+	errTests := []struct {
+		in  interface{}
+		err error
+	}{
+		{uint64(math.MaxUint64), ErrOutOfRange},
+		{1.5, ErrLossy},
+		{math.NaN(), ErrOutOfRange},
+		{"abc", ErrSyntax},
+		{"1.5", ErrLossy},
+		{"99999999999999999999", ErrOutOfRange},
+		{struct{}{}, ErrUnsupportedType},
+		{[]int{1}, ErrUnsupportedType},
+	}
+	for _, tt := range errTests {
+		_, err := ToInt64(tt.in)
+		assert.True(t, errors.Is(err, tt.err), "%#v: %v", tt.in, err)
+	}
+
+	assert.Equal(t, int64(1), MustToInt64("1"))
+	assert.Panics(t, func() {
+		MustToInt64("x")
+	})
+}
+
+func TestToInt(t *testing.T) {
+	i, err := ToInt("12")
+	assert.NoError(t, err)
+	assert.Equal(t, 12, i)
+	_, err = ToInt("x")
+	assert.Error(t, err)
+	assert.Equal(t, 3, MustToInt(3.0))
+	assert.Panics(t, func() {
+		MustToInt(struct{}{})
+	})
+}
+
+func TestToFloat64(t *testing.T) {
+	tests := []struct {
+		in   interface{}
+		want float64
+	}{
+		{nil, 0},
+		{1.5, 1.5},
+		{float32(0.5), 0.5},
+		{1, 1},
+		{int8(1), 1},
+		{int16(1), 1},
+		{int32(1), 1},
+		{int64(1), 1},
+		{uint(1), 1},
+		{uint8(1), 1},
+		{uint16(1), 1},
+		{uint32(1), 1},
+		{uint64(1), 1},
+		{true, 1},
+		{false, 0},
+		{" 2.5", 2.5},
+		{[]byte("3.5"), 3.5},
+		{json.Number("1e2"), 100},
+		{myInt(2), 2},
+		{myString("4.5"), 4.5},
+	}
+	for _, tt := range tests {
+		got, err := ToFloat64(tt.in)
+		assert.NoError(t, err, "%#v", tt.in)
+		assert.Equal(t, tt.want, got, "%#v", tt.in)
+	}
+
+	_, err := ToFloat64("x")
+	assert.True(t, errors.Is(err, ErrSyntax))
+	_, err = ToFloat64("1e999")
+	assert.True(t, errors.Is(err, ErrOutOfRange))
+	_, err = ToFloat64(map[string]int{})
+	assert.True(t, errors.Is(err, ErrUnsupportedType))
+	assert.Equal(t, 1.0, MustToFloat64("1"))
+	assert.Panics(t, func() {
+		MustToFloat64("x")
+	})
+}
+
+func TestToBool(t *testing.T) {
+	trues := []interface{}{true, 1, int8(-1), uint(1), 0.1, "true", "YES", " on ", "1", []byte("t"), json.Number("2"), myString("y")}
+	for _, v := range trues {
+		b, err := ToBool(v)
+		assert.NoError(t, err, "%#v", v)
+		assert.True(t, b, "%#v", v)
+	}
+
+	falses := []interface{}{nil, false, 0, uint8(0), 0.0, "false", "no", "OFF", "", "0", myInt(0)}
+	for _, v := range falses {
+		b, err := ToBool(v)
+		assert.NoError(t, err, "%#v", v)
+		assert.False(t, b, "%#v", v)
+	}
+
+	_, err := ToBool("maybe")
+	assert.True(t, errors.Is(err, ErrSyntax))
+	_, err = ToBool([]int{})
+	assert.True(t, errors.Is(err, ErrUnsupportedType))
+	assert.True(t, MustToBool("true"))
+	assert.Panics(t, func() {
+		MustToBool("x")
+	})
+}
+
+func TestToString(t *testing.T) {
+	tests := []struct {
+		in   interface{}
+		want string
+	}{
+		{nil, ""},
+		{"s", "s"},
+		{[]byte("b"), "b"},
+		{json.Number("1.5"), "1.5"},
+		{true, "true"},
+		{-1, "-1"},
+		{int64(64), "64"},
+		{int8(8), "8"},
+		{uint64(math.MaxUint64), "18446744073709551615"},
+		{0.1, "0.1"},
+		{float32(0.1), "0.1"},
+		{1e21, "1000000000000000000000"},
+		{stringer{}, "stringer"},
+		{errors.New("err"), "err"},
+		{time.Second, "1s"},
+		{myInt(3), "3"},
+		{myString("m"), "m"},
+	}
+	for _, tt := range tests {
+		got, err := ToString(tt.in)
+		assert.NoError(t, err, "%#v", tt.in)
+		assert.Equal(t, tt.want, got, "%#v", tt.in)
+	}
+
+	_, err := ToString([]int{1})
+	assert.True(t, errors.Is(err, ErrUnsupportedType))
+	assert.Equal(t, "1", MustToString(1))
+	assert.Panics(t, func() {
+		MustToString(struct{}{})
+	})
+}
+
+func TestToStringSlice(t *testing.T) {
+	ss, err := ToStringSlice(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, ss)
+
+	assert.Equal(t, []string{"a", "b"}, MustToStringSlice([]string{"a", "b"}))
+	assert.Equal(t, []string{"a", "b"}, MustToStringSlice(" a  b "))
+	assert.Equal(t, []string{"1", "x", "true"}, MustToStringSlice([]interface{}{1, "x", true}))
+	assert.Equal(t, []string{"1", "2"}, MustToStringSlice([]int{1, 2}))
+	assert.Equal(t, []string{"1.5"}, MustToStringSlice([1]float64{1.5}))
+
+	_, err = ToStringSlice([]interface{}{1, struct{}{}})
+	assert.True(t, errors.Is(err, ErrUnsupportedType))
+	_, err = ToStringSlice([]struct{}{{}})
+	assert.True(t, errors.Is(err, ErrUnsupportedType))
+	_, err = ToStringSlice(1)
+	assert.True(t, errors.Is(err, ErrUnsupportedType))
+	assert.Panics(t, func() {
+		MustToStringSlice(1)
+	})
+}
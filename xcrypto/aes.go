@@ -0,0 +1,388 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcrypto
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"golang.org/x/crypto/hkdf"
+	"io"
+)
+
+const (
+	// DefaultChunkSize is the default plaintext size of a chunk in the streaming mode.
+	DefaultChunkSize = 64 << 10
+
+	streamSaltSize = 32
+	streamHeader   = streamSaltSize + 4
+	lastChunk      = 1
+)
+
+var (
+	// ErrKeySize is an error that indicates the key is not 16, 24 or 32 bytes long.
+	ErrKeySize = errors.New("xcrypto: invalid key size")
+	// ErrCiphertext is an error that indicates the ciphertext is malformed.
+	ErrCiphertext = errors.New("xcrypto: malformed ciphertext")
+	// ErrAuthentication is an error that indicates the ciphertext or aad has been tampered with
+	// or the key is wrong.
+	ErrAuthentication = errors.New("xcrypto: message authentication failed")
+	// ErrClosed is an error that indicates writing into a closed Writer.
+	ErrClosed = errors.New("xcrypto: write to closed writer")
+)
+
+type (
+	// StreamOption defines the method to customize the streaming mode.
+	StreamOption func(*streamOptions)
+
+	streamOptions struct {
+		chunkSize int
+	}
+
+	encryptWriter struct {
+		w      io.Writer
+		aead   cipher.AEAD
+		aad    []byte
+		nonce  []byte
+		buf    []byte
+		out    []byte
+		size   int
+		seq    uint32
+		err    error
+		closed bool
+	}
+
+	decryptReader struct {
+		r     *bufio.Reader
+		aead  cipher.AEAD
+		aad   []byte
+		nonce []byte
+		in    []byte
+		buf   []byte
+		seq   uint32
+		err   error
+	}
+)
+
+// WithChunkSize customizes the plaintext size of a chunk, default to DefaultChunkSize.
+func WithChunkSize(size int) StreamOption {
+	return func(o *streamOptions) {
+		if size > 0 {
+			o.chunkSize = size
+		}
+	}
+}
+
+// Encrypt encrypts and authenticates plaintext and authenticates aad with AES-GCM.
+// The key must be 16, 24 or 32 bytes long to select AES-128, AES-192 or AES-256.
+// A random nonce is generated and the result is nonce||ciphertext.
+func Encrypt(key, plaintext, aad []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+// Decrypt decrypts a ciphertext produced by Encrypt with the same key and aad.
+func Decrypt(key, ciphertext, aad []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < aead.NonceSize()+aead.Overhead() {
+		return nil, ErrCiphertext
+	}
+
+	nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, aad)
+	if err != nil {
+		return nil, ErrAuthentication
+	}
+
+	return plaintext, nil
+}
+
+// NewEncryptWriter returns a Writer that encrypts into w in authenticated chunks,
+// so that large data can be processed with constant memory.
+// Close must be called to write the final chunk, it doesn't close w.
+//
+// Every stream is encrypted with its own key, derived from key with HKDF and a random salt stored
+// in the header, so nonces never repeat across streams. Each chunk uses a nonce made of its
+// sequence number and a final flag, which prevents the chunks from being reordered, dropped
+// or the stream from being truncated.
+func NewEncryptWriter(w io.Writer, key, aad []byte, opts ...StreamOption) (io.WriteCloser, error) {
+	if err := checkKeySize(key); err != nil {
+		return nil, err
+	}
+
+	options := newStreamOptions(opts)
+	header := make([]byte, streamHeader)
+	if _, err := io.ReadFull(rand.Reader, header[:streamSaltSize]); err != nil {
+		return nil, err
+	}
+	binary.BigEndian.PutUint32(header[streamSaltSize:], uint32(options.chunkSize))
+
+	aead, err := newStreamGCM(key, header[:streamSaltSize])
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &encryptWriter{
+		w:     w,
+		aead:  aead,
+		aad:   streamAAD(header, aad),
+		nonce: make([]byte, aead.NonceSize()),
+		buf:   make([]byte, 0, options.chunkSize),
+		out:   make([]byte, 0, options.chunkSize+aead.Overhead()),
+		size:  options.chunkSize,
+	}, nil
+}
+
+// NewDecryptReader returns a Reader that decrypts from r a stream produced by NewEncryptWriter.
+// An error is returned by Read if the stream has been tampered with or truncated,
+// the data read before must be discarded in that case.
+func NewDecryptReader(r io.Reader, key, aad []byte) (io.Reader, error) {
+	if err := checkKeySize(key); err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, streamHeader)
+	if _, err := io.ReadFull(r, header); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, ErrCiphertext
+		}
+		return nil, err
+	}
+
+	size := binary.BigEndian.Uint32(header[streamSaltSize:])
+	if size == 0 || size > 1<<30 {
+		return nil, ErrCiphertext
+	}
+
+	aead, err := newStreamGCM(key, header[:streamSaltSize])
+	if err != nil {
+		return nil, err
+	}
+
+	return &decryptReader{
+		r:     bufio.NewReader(r),
+		aead:  aead,
+		aad:   streamAAD(header, aad),
+		nonce: make([]byte, aead.NonceSize()),
+		in:    make([]byte, int(size)+aead.Overhead()),
+	}, nil
+}
+
+// EncryptStream encrypts src into dst, see NewEncryptWriter.
+func EncryptStream(dst io.Writer, src io.Reader, key, aad []byte, opts ...StreamOption) error {
+	w, err := NewEncryptWriter(dst, key, aad, opts...)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(w, src); err != nil {
+		return err
+	}
+
+	return w.Close()
+}
+
+// DecryptStream decrypts src into dst, see NewDecryptReader.
+func DecryptStream(dst io.Writer, src io.Reader, key, aad []byte) error {
+	r, err := NewDecryptReader(src, key, aad)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(dst, r)
+
+	return err
+}
+
+func (w *encryptWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, ErrClosed
+	}
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	n := 0
+	for len(p) > 0 {
+		// a full chunk is only sealed once more data arrives, the final one is sealed by Close.
+		if len(w.buf) == w.size {
+			if w.err = w.flush(false); w.err != nil {
+				return n, w.err
+			}
+		}
+
+		m := copy(w.buf[len(w.buf):w.size], p)
+		w.buf = w.buf[:len(w.buf)+m]
+		p = p[m:]
+		n += m
+	}
+
+	return n, nil
+}
+
+func (w *encryptWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if w.err != nil {
+		return w.err
+	}
+
+	return w.flush(true)
+}
+
+func (w *encryptWriter) flush(last bool) error {
+	if w.seq == 1<<32-1 {
+		return ErrCiphertext
+	}
+
+	setStreamNonce(w.nonce, w.seq, last)
+	w.out = w.aead.Seal(w.out[:0], w.nonce, w.buf, w.aad)
+	w.buf = w.buf[:0]
+	w.seq++
+
+	_, err := w.w.Write(w.out)
+
+	return err
+}
+
+func (r *decryptReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		r.err = r.next()
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+
+	return n, nil
+}
+
+func (r *decryptReader) next() error {
+	n, err := io.ReadFull(r.r, r.in)
+	last := false
+	switch {
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		last = true
+	case err != nil:
+		return err
+	default:
+		if _, err := r.r.Peek(1); errors.Is(err, io.EOF) {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	}
+
+	if n < r.aead.Overhead() {
+		return ErrCiphertext
+	}
+
+	setStreamNonce(r.nonce, r.seq, last)
+	plaintext, err := r.aead.Open(r.in[:0], r.nonce, r.in[:n], r.aad)
+	if err != nil {
+		return ErrAuthentication
+	}
+	r.seq++
+	r.buf = plaintext
+	if last {
+		return io.EOF
+	}
+
+	return nil
+}
+
+func newStreamOptions(opts []StreamOption) streamOptions {
+	options := streamOptions{chunkSize: DefaultChunkSize}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
+}
+
+func checkKeySize(key []byte) error {
+	switch len(key) {
+	case 16, 24, 32:
+		return nil
+	default:
+		return ErrKeySize
+	}
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if err := checkKeySize(key); err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// streamAAD authenticates the header together with every chunk.
+func streamAAD(header, aad []byte) []byte {
+	b := make([]byte, 0, len(header)+len(aad))
+	b = append(b, header...)
+
+	return append(b, aad...)
+}
+
+// newStreamGCM returns an AEAD keyed with a stream key derived from key and salt.
+func newStreamGCM(key, salt []byte) (cipher.AEAD, error) {
+	streamKey := make([]byte, len(key))
+	if _, err := io.ReadFull(hkdf.New(sha256.New, key, salt, []byte("xcrypto stream")), streamKey); err != nil {
+		return nil, err
+	}
+
+	return newGCM(streamKey)
+}
+
+// setStreamNonce lays out the nonce as zeros||seq||final flag.
+func setStreamNonce(nonce []byte, seq uint32, last bool) {
+	binary.BigEndian.PutUint32(nonce[len(nonce)-5:], seq)
+	nonce[len(nonce)-1] = 0
+	if last {
+		nonce[len(nonce)-1] = lastChunk
+	}
+}
@@ -0,0 +1,177 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcrypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+)
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return b
+}
+
+func TestEncrypt(t *testing.T) {
+	for _, size := range []int{16, 24, 32} {
+		key := randomBytes(size)
+		plaintext := []byte("hello world")
+		aad := []byte("header")
+
+		ciphertext, err := Encrypt(key, plaintext, aad)
+		assert.NoError(t, err)
+		assert.Len(t, ciphertext, 12+len(plaintext)+16)
+
+		another, err := Encrypt(key, plaintext, aad)
+		assert.NoError(t, err)
+		assert.NotEqual(t, ciphertext, another)
+
+		decrypted, err := Decrypt(key, ciphertext, aad)
+		assert.NoError(t, err)
+		assert.Equal(t, plaintext, decrypted)
+
+		_, err = Decrypt(key, ciphertext, []byte("other"))
+		assert.ErrorIs(t, err, ErrAuthentication)
+
+		ciphertext[len(ciphertext)-1] ^= 1
+		_, err = Decrypt(key, ciphertext, aad)
+		assert.ErrorIs(t, err, ErrAuthentication)
+	}
+
+	_, err := Encrypt(randomBytes(10), nil, nil)
+	assert.ErrorIs(t, err, ErrKeySize)
+	_, err = Decrypt(randomBytes(33), nil, nil)
+	assert.ErrorIs(t, err, ErrKeySize)
+	_, err = Decrypt(randomBytes(16), randomBytes(27), nil)
+	assert.ErrorIs(t, err, ErrCiphertext)
+
+	ciphertext, err := Encrypt(randomBytes(16), nil, nil)
+	assert.NoError(t, err)
+	assert.Len(t, ciphertext, 28)
+}
+
+func TestStream(t *testing.T) {
+	key := randomBytes(32)
+	aad := []byte("file.bin")
+
+	for _, n := range []int{0, 1, 99, 100, 101, 1000, 12345} {
+		plaintext := randomBytes(n)
+
+		var encrypted bytes.Buffer
+		assert.NoError(t, EncryptStream(&encrypted, bytes.NewReader(plaintext), key, aad, WithChunkSize(100)))
+
+		var decrypted bytes.Buffer
+		assert.NoError(t, DecryptStream(&decrypted, bytes.NewReader(encrypted.Bytes()), key, aad))
+		assert.Equal(t, plaintext, append([]byte{}, decrypted.Bytes()...), "size %d", n)
+	}
+}
+
+func TestStream_KeyPerStream(t *testing.T) {
+	key := randomBytes(16)
+	plaintext := make([]byte, 64)
+
+	var a, b bytes.Buffer
+	assert.NoError(t, EncryptStream(&a, bytes.NewReader(plaintext), key, nil))
+	assert.NoError(t, EncryptStream(&b, bytes.NewReader(plaintext), key, nil))
+	assert.NotEqual(t, a.Bytes()[:streamSaltSize], b.Bytes()[:streamSaltSize])
+	// the same chunk nonce under different stream keys gives unrelated ciphertexts.
+	assert.NotEqual(t, a.Bytes()[streamHeader:], b.Bytes()[streamHeader:])
+
+	_, err := NewEncryptWriter(io.Discard, randomBytes(10), nil)
+	assert.Equal(t, ErrKeySize, err)
+	_, err = NewDecryptReader(bytes.NewReader(a.Bytes()), randomBytes(10), nil)
+	assert.Equal(t, ErrKeySize, err)
+}
+
+func TestStream_Tampered(t *testing.T) {
+	key := randomBytes(16)
+	plaintext := randomBytes(350)
+
+	var encrypted bytes.Buffer
+	assert.NoError(t, EncryptStream(&encrypted, bytes.NewReader(plaintext), key, nil, WithChunkSize(100)))
+	data := encrypted.Bytes()
+	chunk := 100 + 16
+
+	decrypt := func(data []byte, aad []byte) error {
+		return DecryptStream(io.Discard, bytes.NewReader(data), key, aad)
+	}
+
+	// truncated at a chunk boundary.
+	assert.ErrorIs(t, decrypt(data[:streamHeader+chunk*2], nil), ErrAuthentication)
+	// a chunk dropped.
+	dropped := append(append([]byte{}, data[:streamHeader+chunk]...), data[streamHeader+chunk*2:]...)
+	assert.ErrorIs(t, decrypt(dropped, nil), ErrAuthentication)
+	// chunks reordered.
+	reordered := append([]byte{}, data[:streamHeader]...)
+	reordered = append(reordered, data[streamHeader+chunk:streamHeader+chunk*2]...)
+	reordered = append(reordered, data[streamHeader:streamHeader+chunk]...)
+	reordered = append(reordered, data[streamHeader+chunk*2:]...)
+	assert.ErrorIs(t, decrypt(reordered, nil), ErrAuthentication)
+	// header tampered.
+	header := append([]byte{}, data...)
+	header[0] ^= 1
+	assert.ErrorIs(t, decrypt(header, nil), ErrAuthentication)
+	// wrong aad.
+	assert.ErrorIs(t, decrypt(data, []byte("x")), ErrAuthentication)
+	// garbage.
+	assert.ErrorIs(t, decrypt(data[:5], nil), ErrCiphertext)
+	assert.ErrorIs(t, decrypt(data[:streamHeader+3], nil), ErrCiphertext)
+
+	assert.NoError(t, decrypt(data, nil))
+}
+
+func TestEncryptWriter(t *testing.T) {
+	key := randomBytes(16)
+	var buf bytes.Buffer
+	w, err := NewEncryptWriter(&buf, key, nil, WithChunkSize(4))
+	assert.NoError(t, err)
+
+	for _, s := range []string{"ab", "cdef", "g", "hijklmnop"} {
+		n, err := w.Write([]byte(s))
+		assert.NoError(t, err)
+		assert.Equal(t, len(s), n)
+	}
+	assert.NoError(t, w.Close())
+	assert.NoError(t, w.Close())
+	_, err = w.Write([]byte("x"))
+	assert.ErrorIs(t, err, ErrClosed)
+
+	r, err := NewDecryptReader(&buf, key, nil)
+	assert.NoError(t, err)
+	b, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "abcdefghijklmnop", string(b))
+
+	_, err = NewEncryptWriter(&buf, randomBytes(3), nil)
+	assert.ErrorIs(t, err, ErrKeySize)
+	_, err = NewDecryptReader(&buf, randomBytes(3), nil)
+	assert.ErrorIs(t, err, ErrKeySize)
+}
+
+func BenchmarkEncryptStream(b *testing.B) {
+	key := randomBytes(32)
+	data := randomBytes(1 << 20)
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = EncryptStream(io.Discard, bytes.NewReader(data), key, nil)
+	}
+}
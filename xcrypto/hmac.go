@@ -0,0 +1,103 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcrypto
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrSignature is an error that indicates the signature is malformed or doesn't match.
+	ErrSignature = errors.New("xcrypto: invalid signature")
+	// ErrSignatureExpired is an error that indicates the signature is valid but has expired.
+	ErrSignatureExpired = errors.New("xcrypto: signature expired")
+
+	now = time.Now
+)
+
+// Sign returns the HMAC-SHA256 of payload with key.
+func Sign(key, payload []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+
+	return mac.Sum(nil)
+}
+
+// Verify reports whether signature is the HMAC-SHA256 of payload with any of keys,
+// the comparison is done in constant time.
+// To rotate keys, pass the current key followed by the ones still accepted.
+func Verify(payload, signature []byte, keys ...[]byte) bool {
+	ok := false
+	for _, key := range keys {
+		// every key is tried so that the time doesn't tell which one matched.
+		if hmac.Equal(Sign(key, payload), signature) {
+			ok = true
+		}
+	}
+
+	return ok
+}
+
+// SignWithExpiry returns a token that signs payload with key until expiresAt,
+// suitable for webhooks and URLs. The format is "<unix seconds>.<base64url signature>".
+func SignWithExpiry(key, payload []byte, expiresAt time.Time) string {
+	expiry := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	return expiry + "." + base64.RawURLEncoding.EncodeToString(Sign(key, expiryPayload(expiry, payload)))
+}
+
+// VerifyWithExpiry verifies a token produced by SignWithExpiry with any of keys.
+// It returns ErrSignature if the token is invalid, or ErrSignatureExpired if it's valid but has expired.
+func VerifyWithExpiry(payload []byte, token string, keys ...[]byte) error {
+	i := strings.IndexByte(token, '.')
+	if i < 0 {
+		return ErrSignature
+	}
+
+	expiry := token[:i]
+	expiresAt, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return ErrSignature
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(token[i+1:])
+	if err != nil {
+		return ErrSignature
+	}
+
+	if !Verify(expiryPayload(expiry, payload), signature, keys...) {
+		return ErrSignature
+	}
+	if now().Unix() >= expiresAt {
+		return ErrSignatureExpired
+	}
+
+	return nil
+}
+
+func expiryPayload(expiry string, payload []byte) []byte {
+	b := make([]byte, 0, len(expiry)+1+len(payload))
+	b = append(b, expiry...)
+	b = append(b, '.')
+
+	return append(b, payload...)
+}
@@ -0,0 +1,70 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcrypto
+
+import (
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	// RFC 4231 test case 2.
+	signature := Sign([]byte("Jefe"), []byte("what do ya want for nothing?"))
+	assert.Equal(t, "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843", hex.EncodeToString(signature))
+
+	payload := []byte("payload")
+	oldKey, newKey := []byte("old"), []byte("new")
+
+	assert.True(t, Verify(payload, Sign(newKey, payload), newKey))
+	assert.True(t, Verify(payload, Sign(oldKey, payload), newKey, oldKey))
+	assert.False(t, Verify(payload, Sign(oldKey, payload), newKey))
+	assert.False(t, Verify([]byte("other"), Sign(newKey, payload), newKey, oldKey))
+	assert.False(t, Verify(payload, Sign(newKey, payload)[:16], newKey))
+	assert.False(t, Verify(payload, Sign(newKey, payload)))
+}
+
+func TestSignWithExpiry(t *testing.T) {
+	defer func() {
+		now = time.Now
+	}()
+	current := time.Unix(1000, 0)
+	now = func() time.Time {
+		return current
+	}
+
+	key, oldKey := []byte("key"), []byte("old")
+	payload := []byte("/download?file=a")
+	token := SignWithExpiry(key, payload, current.Add(time.Minute))
+	assert.True(t, strings.HasPrefix(token, "1060."))
+
+	assert.NoError(t, VerifyWithExpiry(payload, token, key))
+	assert.NoError(t, VerifyWithExpiry(payload, SignWithExpiry(oldKey, payload, current.Add(time.Minute)), key, oldKey))
+	assert.ErrorIs(t, VerifyWithExpiry(payload, token, oldKey), ErrSignature)
+	assert.ErrorIs(t, VerifyWithExpiry([]byte("/download?file=b"), token, key), ErrSignature)
+	// the expiry is signed.
+	assert.ErrorIs(t, VerifyWithExpiry(payload, "9999"+token[4:], key), ErrSignature)
+
+	for _, token := range []string{"", "1060", "x.abc", "1060.!!!"} {
+		assert.ErrorIs(t, VerifyWithExpiry(payload, token, key), ErrSignature, token)
+	}
+
+	current = current.Add(time.Minute)
+	assert.ErrorIs(t, VerifyWithExpiry(payload, token, key), ErrSignatureExpired)
+}
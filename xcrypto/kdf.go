@@ -0,0 +1,310 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcrypto
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/hkdf"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const versionSize = 4
+
+var (
+	// ErrKeyNotFound is an error that indicates the key version is not in the KeyRing.
+	ErrKeyNotFound = errors.New("xcrypto: key not found")
+	// ErrKeyExists is an error that indicates the key version has already been added.
+	ErrKeyExists = errors.New("xcrypto: key already exists")
+	// ErrActiveKey is an error that indicates the active key can't be removed.
+	ErrActiveKey = errors.New("xcrypto: can't remove the active key")
+)
+
+var (
+	keyRingEncryptionInfo = []byte("xcrypto keyring encryption")
+	keyRingSigningInfo    = []byte("xcrypto keyring signing")
+)
+
+type (
+	// A KeyRing manages versioned keys with one active key.
+	// New data is encrypted and signed with the active key and the version is embedded into the result,
+	// so that it can still be decrypted and verified after the active key has been rotated.
+	// Separate encryption and signing subkeys are derived from each key with DeriveKeys.
+	// It's safe for concurrent use.
+	KeyRing struct {
+		lock   sync.RWMutex
+		keys   map[uint32]*ringKey
+		active uint32
+	}
+
+	ringKey struct {
+		key []byte
+		enc []byte
+		mac []byte
+	}
+)
+
+// DeriveKeys derives n independent keys of size bytes from master with HKDF-SHA256.
+// salt and info are optional, info binds the keys to a purpose, e.g. "orders encryption".
+func DeriveKeys(master, salt, info []byte, n, size int) ([][]byte, error) {
+	if len(master) == 0 {
+		return nil, errors.New("xcrypto: empty master key")
+	}
+	if n <= 0 || size <= 0 || n*size > 255*sha256.Size {
+		return nil, fmt.Errorf("xcrypto: can't derive %d keys of %d bytes", n, size)
+	}
+
+	r := hkdf.New(sha256.New, master, salt, info)
+	keys := make([][]byte, n)
+	for i := range keys {
+		keys[i] = make([]byte, size)
+		if _, err := io.ReadFull(r, keys[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return keys, nil
+}
+
+// NewKeyRing returns an empty KeyRing.
+func NewKeyRing() *KeyRing {
+	return &KeyRing{keys: map[uint32]*ringKey{}}
+}
+
+// Add adds key as version. The key must be 16, 24 or 32 bytes long, see Encrypt.
+// The first key added becomes the active one.
+func (r *KeyRing) Add(version uint32, key []byte) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, ok := r.keys[version]; ok {
+		return ErrKeyExists
+	}
+	if err := checkKeySize(key); err != nil {
+		return err
+	}
+
+	enc, err := DeriveKeys(key, nil, keyRingEncryptionInfo, 1, len(key))
+	if err != nil {
+		return err
+	}
+	mac, err := DeriveKeys(key, nil, keyRingSigningInfo, 1, sha256.Size)
+	if err != nil {
+		return err
+	}
+
+	if len(r.keys) == 0 {
+		r.active = version
+	}
+	r.keys[version] = &ringKey{key: append([]byte(nil), key...), enc: enc[0], mac: mac[0]}
+
+	return nil
+}
+
+// SetActive makes version the active key.
+func (r *KeyRing) SetActive(version uint32) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, ok := r.keys[version]; !ok {
+		return ErrKeyNotFound
+	}
+	r.active = version
+
+	return nil
+}
+
+// Remove removes version, the active key can't be removed.
+func (r *KeyRing) Remove(version uint32) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, ok := r.keys[version]; !ok {
+		return ErrKeyNotFound
+	}
+	if version == r.active {
+		return ErrActiveKey
+	}
+	delete(r.keys, version)
+
+	return nil
+}
+
+// Active returns a copy of the active key and its version.
+func (r *KeyRing) Active() (uint32, []byte, error) {
+	version, key, err := r.activeKey()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return version, append([]byte(nil), key.key...), nil
+}
+
+// Key returns a copy of the key of version.
+func (r *KeyRing) Key(version uint32) ([]byte, bool) {
+	key, ok := r.lookup(version)
+	if !ok {
+		return nil, false
+	}
+
+	return append([]byte(nil), key.key...), true
+}
+
+// Versions returns the versions in ascending order.
+func (r *KeyRing) Versions() []uint32 {
+	r.lock.RLock()
+	versions := make([]uint32, 0, len(r.keys))
+	for version := range r.keys {
+		versions = append(versions, version)
+	}
+	r.lock.RUnlock()
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i] < versions[j]
+	})
+
+	return versions
+}
+
+// Encrypt encrypts plaintext with the active key, see Encrypt.
+// The result is version||nonce||ciphertext.
+func (r *KeyRing) Encrypt(plaintext, aad []byte) ([]byte, error) {
+	version, key, err := r.activeKey()
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := versionPrefix(version)
+	ciphertext, err := Encrypt(key.enc, plaintext, append(prefix, aad...))
+	if err != nil {
+		return nil, err
+	}
+
+	return append(prefix, ciphertext...), nil
+}
+
+// Decrypt decrypts a ciphertext produced by Encrypt with the key of the embedded version.
+func (r *KeyRing) Decrypt(ciphertext, aad []byte) ([]byte, error) {
+	if len(ciphertext) < versionSize {
+		return nil, ErrCiphertext
+	}
+
+	key, ok := r.lookup(binary.BigEndian.Uint32(ciphertext))
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+
+	prefix := append([]byte(nil), ciphertext[:versionSize]...)
+
+	return Decrypt(key.enc, ciphertext[versionSize:], append(prefix, aad...))
+}
+
+// Sign signs payload with the active key, see Sign.
+// The result is version||signature.
+func (r *KeyRing) Sign(payload []byte) ([]byte, error) {
+	version, key, err := r.activeKey()
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := versionPrefix(version)
+
+	return append(prefix, Sign(key.mac, append(prefix, payload...))...), nil
+}
+
+// Verify reports whether signature is produced by Sign for payload.
+func (r *KeyRing) Verify(payload, signature []byte) bool {
+	if len(signature) < versionSize {
+		return false
+	}
+
+	key, ok := r.lookup(binary.BigEndian.Uint32(signature))
+	if !ok {
+		return false
+	}
+
+	prefix := append([]byte(nil), signature[:versionSize]...)
+
+	return Verify(append(prefix, payload...), signature[versionSize:], key.mac)
+}
+
+// SignWithExpiry signs payload with the active key until expiresAt, see SignWithExpiry.
+// The format is "<version>.<unix seconds>.<base64url signature>".
+func (r *KeyRing) SignWithExpiry(payload []byte, expiresAt time.Time) (string, error) {
+	version, key, err := r.activeKey()
+	if err != nil {
+		return "", err
+	}
+
+	v := strconv.FormatUint(uint64(version), 10)
+
+	return v + "." + SignWithExpiry(key.mac, expiryPayload(v, payload), expiresAt), nil
+}
+
+// VerifyWithExpiry verifies a token produced by SignWithExpiry, see VerifyWithExpiry.
+func (r *KeyRing) VerifyWithExpiry(payload []byte, token string) error {
+	i := strings.IndexByte(token, '.')
+	if i < 0 {
+		return ErrSignature
+	}
+
+	version, err := strconv.ParseUint(token[:i], 10, 32)
+	if err != nil {
+		return ErrSignature
+	}
+	key, ok := r.lookup(uint32(version))
+	if !ok {
+		return ErrSignature
+	}
+
+	return VerifyWithExpiry(expiryPayload(token[:i], payload), token[i+1:], key.mac)
+}
+
+func (r *KeyRing) activeKey() (uint32, *ringKey, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	key, ok := r.keys[r.active]
+	if !ok {
+		return 0, nil, ErrKeyNotFound
+	}
+
+	return r.active, key, nil
+}
+
+func (r *KeyRing) lookup(version uint32) (*ringKey, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	key, ok := r.keys[version]
+
+	return key, ok
+}
+
+func versionPrefix(version uint32) []byte {
+	b := make([]byte, versionSize)
+	binary.BigEndian.PutUint32(b, version)
+
+	return b
+}
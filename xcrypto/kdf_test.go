@@ -0,0 +1,206 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcrypto
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDeriveKeys(t *testing.T) {
+	master := []byte("master secret")
+	keys, err := DeriveKeys(master, []byte("salt"), []byte("orders"), 3, 32)
+	assert.NoError(t, err)
+	assert.Len(t, keys, 3)
+	for _, key := range keys {
+		assert.Len(t, key, 32)
+	}
+	assert.NotEqual(t, keys[0], keys[1])
+	assert.NotEqual(t, keys[1], keys[2])
+
+	again, err := DeriveKeys(master, []byte("salt"), []byte("orders"), 3, 32)
+	assert.NoError(t, err)
+	assert.Equal(t, keys, again)
+
+	other, err := DeriveKeys(master, []byte("salt"), []byte("users"), 1, 32)
+	assert.NoError(t, err)
+	assert.NotEqual(t, keys[0], other[0])
+
+	_, err = DeriveKeys(nil, nil, nil, 1, 32)
+	assert.Error(t, err)
+	_, err = DeriveKeys(master, nil, nil, 0, 32)
+	assert.Error(t, err)
+	_, err = DeriveKeys(master, nil, nil, 256, 32)
+	assert.Error(t, err)
+}
+
+func newTestKeyRing(t *testing.T) *KeyRing {
+	keys, err := DeriveKeys([]byte("master"), nil, []byte("test"), 2, 32)
+	assert.NoError(t, err)
+
+	r := NewKeyRing()
+	assert.NoError(t, r.Add(1, keys[0]))
+	assert.NoError(t, r.Add(2, keys[1]))
+
+	return r
+}
+
+func TestKeyRing(t *testing.T) {
+	r := NewKeyRing()
+	_, _, err := r.Active()
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+	_, err = r.Encrypt(nil, nil)
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	r = newTestKeyRing(t)
+	assert.ErrorIs(t, r.Add(1, []byte("x")), ErrKeyExists)
+	assert.Equal(t, []uint32{1, 2}, r.Versions())
+
+	version, _, err := r.Active()
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), version)
+
+	assert.ErrorIs(t, r.SetActive(3), ErrKeyNotFound)
+	assert.NoError(t, r.SetActive(2))
+	version, _, _ = r.Active()
+	assert.Equal(t, uint32(2), version)
+
+	assert.ErrorIs(t, r.Remove(2), ErrActiveKey)
+	assert.ErrorIs(t, r.Remove(3), ErrKeyNotFound)
+	assert.NoError(t, r.Remove(1))
+	assert.Equal(t, []uint32{2}, r.Versions())
+	_, ok := r.Key(1)
+	assert.False(t, ok)
+
+	assert.ErrorIs(t, r.Add(3, []byte("short")), ErrKeySize)
+	_, ok = r.Key(3)
+	assert.False(t, ok)
+}
+
+func TestKeyRing_KeyCopy(t *testing.T) {
+	r := newTestKeyRing(t)
+	payload := []byte("payload")
+	signature, err := r.Sign(payload)
+	assert.NoError(t, err)
+
+	key, ok := r.Key(1)
+	assert.True(t, ok)
+	key[0] ^= 1
+	_, active, err := r.Active()
+	assert.NoError(t, err)
+	active[0] ^= 1
+
+	again, _ := r.Key(1)
+	assert.NotEqual(t, key, again)
+	assert.True(t, r.Verify(payload, signature))
+}
+
+func TestKeyRing_Subkeys(t *testing.T) {
+	r := newTestKeyRing(t)
+	key, _ := r.Key(1)
+
+	// neither the ciphertexts nor the signatures are produced with the raw key.
+	ciphertext, err := r.Encrypt([]byte("plaintext"), nil)
+	assert.NoError(t, err)
+	_, err = Decrypt(key, ciphertext[versionSize:], ciphertext[:versionSize])
+	assert.ErrorIs(t, err, ErrAuthentication)
+
+	payload := []byte("payload")
+	signature, err := r.Sign(payload)
+	assert.NoError(t, err)
+	assert.False(t, Verify(append(versionPrefix(1), payload...), signature[versionSize:], key))
+}
+
+func TestKeyRing_Encrypt(t *testing.T) {
+	r := newTestKeyRing(t)
+	aad := []byte("aad")
+
+	old, err := r.Encrypt([]byte("old"), aad)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0, 1}, old[:4])
+
+	assert.NoError(t, r.SetActive(2))
+	current, err := r.Encrypt([]byte("current"), aad)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0, 2}, current[:4])
+
+	plaintext, err := r.Decrypt(old, aad)
+	assert.NoError(t, err)
+	assert.Equal(t, "old", string(plaintext))
+	plaintext, err = r.Decrypt(current, aad)
+	assert.NoError(t, err)
+	assert.Equal(t, "current", string(plaintext))
+
+	// the version is authenticated.
+	current[3] = 1
+	_, err = r.Decrypt(current, aad)
+	assert.ErrorIs(t, err, ErrAuthentication)
+
+	_, err = r.Decrypt([]byte{0, 0}, aad)
+	assert.ErrorIs(t, err, ErrCiphertext)
+	_, err = r.Decrypt([]byte{0, 0, 0, 9, 1}, aad)
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestKeyRing_Sign(t *testing.T) {
+	r := newTestKeyRing(t)
+	payload := []byte("payload")
+
+	old, err := r.Sign(payload)
+	assert.NoError(t, err)
+	assert.NoError(t, r.SetActive(2))
+	current, err := r.Sign(payload)
+	assert.NoError(t, err)
+
+	assert.True(t, r.Verify(payload, old))
+	assert.True(t, r.Verify(payload, current))
+	assert.False(t, r.Verify([]byte("other"), current))
+	assert.False(t, r.Verify(payload, current[:3]))
+	assert.False(t, r.Verify(payload, append([]byte{0, 0, 0, 9}, current[4:]...)))
+
+	assert.NoError(t, r.Remove(1))
+	assert.False(t, r.Verify(payload, old))
+}
+
+func TestKeyRing_SignWithExpiry(t *testing.T) {
+	defer func() {
+		now = time.Now
+	}()
+	current := time.Unix(1000, 0)
+	now = func() time.Time {
+		return current
+	}
+
+	r := newTestKeyRing(t)
+	payload := []byte("payload")
+
+	token, err := r.SignWithExpiry(payload, current.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, "1.1060.", token[:7])
+	assert.NoError(t, r.VerifyWithExpiry(payload, token))
+
+	assert.NoError(t, r.SetActive(2))
+	assert.NoError(t, r.VerifyWithExpiry(payload, token))
+	assert.ErrorIs(t, r.VerifyWithExpiry(payload, "2"+token[1:]), ErrSignature)
+	assert.ErrorIs(t, r.VerifyWithExpiry(payload, "9"+token[1:]), ErrSignature)
+	assert.ErrorIs(t, r.VerifyWithExpiry(payload, "x"+token[1:]), ErrSignature)
+	assert.ErrorIs(t, r.VerifyWithExpiry(payload, "1"), ErrSignature)
+
+	current = current.Add(time.Hour)
+	assert.ErrorIs(t, r.VerifyWithExpiry(payload, token), ErrSignatureExpired)
+}
@@ -0,0 +1,218 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcrypto
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"io"
+	"strings"
+)
+
+const (
+	defaultArgon2Time    = 3
+	defaultArgon2Memory  = 64 << 10
+	defaultArgon2Threads = 4
+	defaultSaltLength    = 16
+	defaultKeyLength     = 32
+
+	argon2idPrefix = "$argon2id$"
+)
+
+var (
+	// ErrPasswordMismatch is an error that indicates the password doesn't match the hash.
+	ErrPasswordMismatch = errors.New("xcrypto: password mismatch")
+	// ErrHashFormat is an error that indicates the hash is malformed or of an unsupported algorithm.
+	ErrHashFormat = errors.New("xcrypto: invalid password hash")
+
+	b64 = base64.RawStdEncoding
+)
+
+type (
+	// PasswordOption defines the method to customize the argon2id parameters.
+	PasswordOption func(*passwordOptions)
+
+	passwordOptions struct {
+		time       uint32
+		memory     uint32
+		threads    uint8
+		saltLength uint32
+		keyLength  uint32
+	}
+)
+
+// WithPasswordTime customizes the number of passes over the memory, default to 3.
+func WithPasswordTime(t uint32) PasswordOption {
+	return func(o *passwordOptions) {
+		o.time = t
+	}
+}
+
+// WithPasswordMemory customizes the memory in KiB, default to 64 MiB.
+func WithPasswordMemory(kib uint32) PasswordOption {
+	return func(o *passwordOptions) {
+		o.memory = kib
+	}
+}
+
+// WithPasswordThreads customizes the degree of parallelism, default to 4.
+func WithPasswordThreads(threads uint8) PasswordOption {
+	return func(o *passwordOptions) {
+		o.threads = threads
+	}
+}
+
+// WithPasswordSaltLength customizes the length in bytes of the random salt, default to 16.
+func WithPasswordSaltLength(n uint32) PasswordOption {
+	return func(o *passwordOptions) {
+		o.saltLength = n
+	}
+}
+
+// WithPasswordKeyLength customizes the length in bytes of the derived key, default to 32.
+func WithPasswordKeyLength(n uint32) PasswordOption {
+	return func(o *passwordOptions) {
+		o.keyLength = n
+	}
+}
+
+// HashPassword hashes password with argon2id.
+// The parameters and the salt are encoded into the result in the PHC string format,
+// e.g. $argon2id$v=19$m=65536,t=3,p=4$<salt>$<key>.
+func HashPassword(password string, opts ...PasswordOption) (string, error) {
+	options := newPasswordOptions(opts)
+	if options.time == 0 || options.memory == 0 || options.threads == 0 ||
+		options.saltLength == 0 || options.keyLength == 0 {
+		return "", errors.New("xcrypto: invalid argon2id parameters")
+	}
+
+	salt := make([]byte, options.saltLength)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, options.time, options.memory, options.threads, options.keyLength)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version,
+		options.memory, options.time, options.threads, b64.EncodeToString(salt), b64.EncodeToString(key)), nil
+}
+
+// VerifyPassword reports whether password matches hash.
+// It returns nil on success, ErrPasswordMismatch if they don't match,
+// or ErrHashFormat if hash can't be parsed.
+// Besides argon2id, bcrypt hashes are verified so that old hashes keep working during a migration.
+func VerifyPassword(hash, password string) error {
+	if isBcrypt(hash) {
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		switch {
+		case err == nil:
+			return nil
+		case errors.Is(err, bcrypt.ErrMismatchedHashAndPassword):
+			return ErrPasswordMismatch
+		default:
+			return fmt.Errorf("%w: %v", ErrHashFormat, err)
+		}
+	}
+
+	params, salt, key, err := parseArgon2id(hash)
+	if err != nil {
+		return err
+	}
+
+	other := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.threads, params.keyLength)
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return ErrPasswordMismatch
+	}
+
+	return nil
+}
+
+// NeedsRehash reports whether hash wasn't produced by HashPassword with the same opts,
+// e.g. it's a bcrypt hash or the parameters have been upgraded since.
+// The password should be hashed again after a successful VerifyPassword in that case.
+func NeedsRehash(hash string, opts ...PasswordOption) bool {
+	params, _, _, err := parseArgon2id(hash)
+	if err != nil {
+		return true
+	}
+
+	return params != newPasswordOptions(opts)
+}
+
+func newPasswordOptions(opts []PasswordOption) passwordOptions {
+	options := passwordOptions{
+		time:       defaultArgon2Time,
+		memory:     defaultArgon2Memory,
+		threads:    defaultArgon2Threads,
+		saltLength: defaultSaltLength,
+		keyLength:  defaultKeyLength,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
+}
+
+func isBcrypt(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+func parseArgon2id(hash string) (params passwordOptions, salt, key []byte, err error) {
+	if !strings.HasPrefix(hash, argon2idPrefix) {
+		return params, nil, nil, ErrHashFormat
+	}
+
+	fields := strings.Split(hash[len(argon2idPrefix):], "$")
+	if len(fields) != 4 {
+		return params, nil, nil, ErrHashFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(fields[0], "v=%d", &version); err != nil {
+		return params, nil, nil, ErrHashFormat
+	}
+	if version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("%w: unsupported version %d", ErrHashFormat, version)
+	}
+
+	if _, err := fmt.Sscanf(fields[1], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return params, nil, nil, ErrHashFormat
+	}
+	if params.memory == 0 || params.time == 0 || params.threads == 0 {
+		return params, nil, nil, ErrHashFormat
+	}
+
+	salt, err = b64.DecodeString(fields[2])
+	if err != nil || len(salt) == 0 {
+		return params, nil, nil, ErrHashFormat
+	}
+	key, err = b64.DecodeString(fields[3])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, ErrHashFormat
+	}
+
+	params.saltLength = uint32(len(salt))
+	params.keyLength = uint32(len(key))
+
+	return params, salt, key, nil
+}
@@ -0,0 +1,90 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xcrypto
+
+import (
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"testing"
+)
+
+var fastPassword = []PasswordOption{WithPasswordTime(1), WithPasswordMemory(64), WithPasswordThreads(1)}
+
+func TestHashPassword(t *testing.T) {
+	hash, err := HashPassword("secret", fastPassword...)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"))
+
+	another, err := HashPassword("secret", fastPassword...)
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, another)
+
+	assert.NoError(t, VerifyPassword(hash, "secret"))
+	assert.ErrorIs(t, VerifyPassword(hash, "Secret"), ErrPasswordMismatch)
+
+	_, err = HashPassword("secret", WithPasswordTime(0))
+	assert.Error(t, err)
+}
+
+func TestHashPassword_Default(t *testing.T) {
+	hash, err := HashPassword("secret")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=65536,t=3,p=4$"))
+	assert.NoError(t, VerifyPassword(hash, "secret"))
+	assert.False(t, NeedsRehash(hash))
+}
+
+func TestVerifyPassword_Bcrypt(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	assert.NoError(t, VerifyPassword(string(hash), "secret"))
+	assert.ErrorIs(t, VerifyPassword(string(hash), "other"), ErrPasswordMismatch)
+	assert.True(t, NeedsRehash(string(hash)))
+	assert.ErrorIs(t, VerifyPassword("$2a$10$short", "secret"), ErrHashFormat)
+}
+
+func TestVerifyPassword_Malformed(t *testing.T) {
+	for _, hash := range []string{
+		"",
+		"plain",
+		"$argon2i$v=19$m=64,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$c2FsdA",
+		"$argon2id$v=18$m=64,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=x$m=64,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=64,t=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=0,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$!!!$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$c2FsdA$",
+	} {
+		assert.ErrorIs(t, VerifyPassword(hash, "secret"), ErrHashFormat, hash)
+		assert.True(t, NeedsRehash(hash), hash)
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	hash, err := HashPassword("secret", fastPassword...)
+	assert.NoError(t, err)
+
+	assert.False(t, NeedsRehash(hash, fastPassword...))
+	assert.True(t, NeedsRehash(hash, append(fastPassword, WithPasswordTime(2))...))
+	assert.True(t, NeedsRehash(hash, append(fastPassword, WithPasswordMemory(128))...))
+	assert.True(t, NeedsRehash(hash, append(fastPassword, WithPasswordThreads(2))...))
+	assert.True(t, NeedsRehash(hash, append(fastPassword, WithPasswordKeyLength(64))...))
+	assert.True(t, NeedsRehash(hash, append(fastPassword, WithPasswordSaltLength(32))...))
+}
@@ -0,0 +1,267 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+const wordBits = 64
+
+// A BitSet is a dense set of non-negative integers, backed by a growable slice of words.
+// See SparseBitSet for sparse sets of high cardinality. It's not safe for concurrent use.
+type BitSet struct {
+	words []uint64
+}
+
+// NewBitSet returns an empty BitSet with room for the bits below n.
+func NewBitSet(n uint) *BitSet {
+	return &BitSet{words: make([]uint64, 0, (n+wordBits-1)/wordBits)}
+}
+
+// Set adds i.
+func (b *BitSet) Set(i uint) *BitSet {
+	w := int(i / wordBits)
+	if w >= len(b.words) {
+		b.words = append(b.words, make([]uint64, w+1-len(b.words))...)
+	}
+	b.words[w] |= 1 << (i % wordBits)
+
+	return b
+}
+
+// Clear removes i.
+func (b *BitSet) Clear(i uint) *BitSet {
+	if w := int(i / wordBits); w < len(b.words) {
+		b.words[w] &^= 1 << (i % wordBits)
+	}
+
+	return b
+}
+
+// Flip toggles i.
+func (b *BitSet) Flip(i uint) *BitSet {
+	if b.Test(i) {
+		return b.Clear(i)
+	}
+
+	return b.Set(i)
+}
+
+// Test reports whether i is in b.
+func (b *BitSet) Test(i uint) bool {
+	w := int(i / wordBits)
+
+	return w < len(b.words) && b.words[w]&(1<<(i%wordBits)) != 0
+}
+
+// Count returns the number of bits set.
+func (b *BitSet) Count() int {
+	count := 0
+	for _, w := range b.words {
+		count += bits.OnesCount64(w)
+	}
+
+	return count
+}
+
+// Rank returns the number of bits set below i.
+func (b *BitSet) Rank(i uint) int {
+	w := int(i / wordBits)
+	rank := 0
+	for j := 0; j < w && j < len(b.words); j++ {
+		rank += bits.OnesCount64(b.words[j])
+	}
+	if w < len(b.words) {
+		rank += bits.OnesCount64(b.words[w] & (1<<(i%wordBits) - 1))
+	}
+
+	return rank
+}
+
+// Select returns the k-th bit set, counting from 0, so that Rank(Select(k)) == k.
+func (b *BitSet) Select(k int) (uint, bool) {
+	if k < 0 {
+		return 0, false
+	}
+
+	for w, word := range b.words {
+		count := bits.OnesCount64(word)
+		if k < count {
+			return uint(w)*wordBits + uint(selectInWord(word, k)), true
+		}
+		k -= count
+	}
+
+	return 0, false
+}
+
+// NextSetBit returns the first bit set from i.
+func (b *BitSet) NextSetBit(i uint) (uint, bool) {
+	w := int(i / wordBits)
+	if w >= len(b.words) {
+		return 0, false
+	}
+
+	word := b.words[w] >> (i % wordBits)
+	if word != 0 {
+		return i + uint(bits.TrailingZeros64(word)), true
+	}
+	for w++; w < len(b.words); w++ {
+		if b.words[w] != 0 {
+			return uint(w)*wordBits + uint(bits.TrailingZeros64(b.words[w])), true
+		}
+	}
+
+	return 0, false
+}
+
+// Range calls fn for each bit set in ascending order until fn returns false.
+func (b *BitSet) Range(fn func(i uint) bool) {
+	for w, word := range b.words {
+		for word != 0 {
+			t := bits.TrailingZeros64(word)
+			if !fn(uint(w)*wordBits + uint(t)) {
+				return
+			}
+			word &= word - 1
+		}
+	}
+}
+
+// And returns the intersection of b and other.
+func (b *BitSet) And(other *BitSet) *BitSet {
+	n := min(len(b.words), len(other.words))
+	words := make([]uint64, n)
+	for i := range words {
+		words[i] = b.words[i] & other.words[i]
+	}
+
+	return &BitSet{words: words}
+}
+
+// Or returns the union of b and other.
+func (b *BitSet) Or(other *BitSet) *BitSet {
+	return b.combine(other, func(x, y uint64) uint64 {
+		return x | y
+	})
+}
+
+// AndNot returns the bits of b that are not in other.
+func (b *BitSet) AndNot(other *BitSet) *BitSet {
+	words := make([]uint64, len(b.words))
+	for i, w := range b.words {
+		if i < len(other.words) {
+			w &^= other.words[i]
+		}
+		words[i] = w
+	}
+
+	return &BitSet{words: words}
+}
+
+// Xor returns the bits that are in either b or other but not both.
+func (b *BitSet) Xor(other *BitSet) *BitSet {
+	return b.combine(other, func(x, y uint64) uint64 {
+		return x ^ y
+	})
+}
+
+// Equal reports whether b and other contain the same bits.
+func (b *BitSet) Equal(other *BitSet) bool {
+	n := max(len(b.words), len(other.words))
+	for i := 0; i < n; i++ {
+		if b.word(i) != other.word(i) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Clone returns a copy of b.
+func (b *BitSet) Clone() *BitSet {
+	return &BitSet{words: append([]uint64(nil), b.words...)}
+}
+
+// String returns the bits set, e.g. {1 3 5}.
+func (b *BitSet) String() string {
+	var sb strings.Builder
+	sb.WriteByte('{')
+	b.Range(func(i uint) bool {
+		if sb.Len() > 1 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(strconv.FormatUint(uint64(i), 10))
+		return true
+	})
+	sb.WriteByte('}')
+
+	return sb.String()
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+// Bit i is stored in byte i/8 at position i%8, trailing zero bytes are trimmed.
+func (b *BitSet) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, 8*len(b.words))
+	for _, w := range b.words {
+		data = binary.LittleEndian.AppendUint64(data, w)
+	}
+
+	return bytes.TrimRight(data, "\x00"), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, replacing b with the decoded bits.
+func (b *BitSet) UnmarshalBinary(data []byte) error {
+	words := make([]uint64, (len(data)+7)/8)
+	for i, c := range data {
+		words[i/8] |= uint64(c) << (8 * (i % 8))
+	}
+	b.words = words
+
+	return nil
+}
+
+func (b *BitSet) word(i int) uint64 {
+	if i < len(b.words) {
+		return b.words[i]
+	}
+
+	return 0
+}
+
+func (b *BitSet) combine(other *BitSet, op func(x, y uint64) uint64) *BitSet {
+	words := make([]uint64, max(len(b.words), len(other.words)))
+	for i := range words {
+		words[i] = op(b.word(i), other.word(i))
+	}
+
+	return &BitSet{words: words}
+}
+
+// selectInWord returns the position of the k-th bit set of word.
+func selectInWord(word uint64, k int) int {
+	for ; k > 0; k-- {
+		word &= word - 1
+	}
+
+	return bits.TrailingZeros64(word)
+}
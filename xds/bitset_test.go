@@ -0,0 +1,130 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func bitsOf(b *BitSet) []uint {
+	var set []uint
+	b.Range(func(i uint) bool {
+		set = append(set, i)
+		return true
+	})
+	return set
+}
+
+func TestBitSet(t *testing.T) {
+	b := NewBitSet(100)
+	assert.Equal(t, 0, b.Count())
+	assert.False(t, b.Test(3))
+	assert.Equal(t, "{}", b.String())
+
+	b.Set(1).Set(3).Set(64).Set(200)
+	assert.True(t, b.Test(64))
+	assert.False(t, b.Test(65))
+	assert.False(t, b.Test(1000))
+	assert.Equal(t, 4, b.Count())
+	assert.Equal(t, []uint{1, 3, 64, 200}, bitsOf(b))
+	assert.Equal(t, "{1 3 64 200}", b.String())
+
+	b.Clear(3).Clear(1000)
+	assert.Equal(t, []uint{1, 64, 200}, bitsOf(b))
+
+	assert.Equal(t, 0, b.Rank(1))
+	assert.Equal(t, 1, b.Rank(2))
+	assert.Equal(t, 2, b.Rank(65))
+	assert.Equal(t, 3, b.Rank(10000))
+	for k := 0; k < 3; k++ {
+		i, ok := b.Select(k)
+		assert.True(t, ok)
+		assert.Equal(t, k, b.Rank(i))
+	}
+	_, ok := b.Select(3)
+	assert.False(t, ok)
+	_, ok = b.Select(-1)
+	assert.False(t, ok)
+
+	i, ok := b.NextSetBit(2)
+	assert.True(t, ok)
+	assert.Equal(t, uint(64), i)
+	i, _ = b.NextSetBit(64)
+	assert.Equal(t, uint(64), i)
+	i, _ = b.NextSetBit(65)
+	assert.Equal(t, uint(200), i)
+	_, ok = b.NextSetBit(201)
+	assert.False(t, ok)
+	_, ok = b.NextSetBit(10000)
+	assert.False(t, ok)
+
+	var first []uint
+	b.Range(func(i uint) bool {
+		first = append(first, i)
+		return false
+	})
+	assert.Equal(t, []uint{1}, first)
+}
+
+func TestBitSet_Ops(t *testing.T) {
+	a := NewBitSet(0).Set(1).Set(2).Set(130)
+	b := NewBitSet(0).Set(2).Set(3)
+
+	assert.Equal(t, []uint{2}, bitsOf(a.And(b)))
+	assert.Equal(t, []uint{1, 2, 3, 130}, bitsOf(a.Or(b)))
+	assert.Equal(t, []uint{1, 130}, bitsOf(a.AndNot(b)))
+	assert.Equal(t, []uint{3}, bitsOf(b.AndNot(a)))
+	assert.Equal(t, []uint{1, 3, 130}, bitsOf(a.Xor(b)))
+
+	clone := a.Clone()
+	assert.True(t, clone.Equal(a))
+	clone.Set(5)
+	assert.False(t, clone.Equal(a))
+	assert.False(t, a.Test(5))
+
+	// trailing zero words don't matter.
+	c := NewBitSet(0).Set(1000).Clear(1000)
+	assert.True(t, c.Equal(NewBitSet(0)))
+	assert.True(t, NewBitSet(0).Equal(c))
+}
+
+func TestBitSet_Flip(t *testing.T) {
+	b := NewBitSet(0).Flip(3).Flip(70)
+	assert.Equal(t, []uint{3, 70}, bitsOf(b))
+	b.Flip(3)
+	assert.Equal(t, []uint{70}, bitsOf(b))
+}
+
+func TestBitSet_Binary(t *testing.T) {
+	b := NewBitSet(0).Set(0).Set(9).Set(130)
+	data, err := b.MarshalBinary()
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x01, 0x02, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x04}, data)
+
+	var got BitSet
+	assert.Nil(t, got.UnmarshalBinary(data))
+	assert.True(t, got.Equal(b))
+	assert.Equal(t, []uint{0, 9, 130}, bitsOf(&got))
+
+	// trailing zero words are not encoded.
+	data, err = NewBitSet(0).Set(1000).Clear(1000).MarshalBinary()
+	assert.Nil(t, err)
+	assert.Empty(t, data)
+	assert.Nil(t, got.UnmarshalBinary(nil))
+	assert.Equal(t, 0, got.Count())
+}
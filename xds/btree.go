@@ -0,0 +1,507 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+import (
+	"cmp"
+	"sort"
+)
+
+type (
+	// A BTree is an in-memory B-tree ordered map.
+	// Items are kept in nodes of degree-1 to 2*degree-1 items, which is friendlier to the CPU cache
+	// than a binary tree. Clone is O(1): the clones share their nodes and copy them on write.
+	// It's not safe for concurrent use, but distinct clones may be used concurrently.
+	BTree[K cmp.Ordered, V any] struct {
+		degree int
+		root   *btreeNode[K, V]
+		length int
+		cow    *cowToken
+	}
+
+	// An Entry is a key and its value.
+	Entry[K any, V any] struct {
+		Key   K
+		Value V
+	}
+
+	btreeNode[K cmp.Ordered, V any] struct {
+		items    []Entry[K, V]
+		children []*btreeNode[K, V]
+		cow      *cowToken
+	}
+
+	// cowToken identifies the nodes a BTree owns, a node owned by another one is copied before it's written.
+	cowToken struct {
+		_ byte
+	}
+
+	removeKind int
+)
+
+const (
+	removeKey removeKind = iota
+	removeMax
+)
+
+// NewBTree returns an empty BTree of the given degree, it panics if degree is less than 2.
+func NewBTree[K cmp.Ordered, V any](degree int) *BTree[K, V] {
+	if degree < 2 {
+		panic("xds: degree should be greater than 1")
+	}
+
+	return &BTree[K, V]{degree: degree, cow: &cowToken{}}
+}
+
+// NewBTreeFromSorted builds a BTree from entries sorted by Key in O(n),
+// it panics if degree is less than 2 or the keys are not strictly ascending.
+func NewBTreeFromSorted[K cmp.Ordered, V any](degree int, entries []Entry[K, V]) *BTree[K, V] {
+	t := NewBTree[K, V](degree)
+	for i := 1; i < len(entries); i++ {
+		if entries[i-1].Key >= entries[i].Key {
+			panic("xds: keys should be strictly ascending")
+		}
+	}
+	if len(entries) == 0 {
+		return t
+	}
+
+	items := append([]Entry[K, V](nil), entries...)
+	var children []*btreeNode[K, V]
+	for {
+		// group the items into nodes, one item between every two nodes is pulled up.
+		nodes := (len(items) + 1 + t.maxItems()) / (t.maxItems() + 1)
+		if nodes <= 1 {
+			t.root = &btreeNode[K, V]{items: items, children: children, cow: t.cow}
+			break
+		}
+
+		total := len(items) - (nodes - 1)
+		base, extra := total/nodes, total%nodes
+		parents := make([]Entry[K, V], 0, nodes-1)
+		level := make([]*btreeNode[K, V], 0, nodes)
+		for i := 0; i < nodes; i++ {
+			size := base
+			if i < extra {
+				size++
+			}
+
+			n := &btreeNode[K, V]{items: items[:size:size], cow: t.cow}
+			if children != nil {
+				n.children = children[: size+1 : size+1]
+				children = children[size+1:]
+			}
+			level = append(level, n)
+			if i < nodes-1 {
+				parents = append(parents, items[size])
+				items = items[size+1:]
+			} else {
+				items = items[size:]
+			}
+		}
+		items, children = parents, level
+	}
+	t.length = len(entries)
+
+	return t
+}
+
+// Len returns the number of items.
+func (t *BTree[K, V]) Len() int {
+	return t.length
+}
+
+// Clone returns a copy of t in O(1), the nodes are copied lazily when either tree is modified.
+func (t *BTree[K, V]) Clone() *BTree[K, V] {
+	// both trees get a new token, so that none of them owns the shared nodes anymore.
+	clone := *t
+	t.cow = &cowToken{}
+	clone.cow = &cowToken{}
+
+	return &clone
+}
+
+// Get returns the value of key.
+func (t *BTree[K, V]) Get(key K) (V, bool) {
+	for n := t.root; n != nil; {
+		i, found := n.find(key)
+		if found {
+			return n.items[i].Value, true
+		}
+		if len(n.children) == 0 {
+			break
+		}
+		n = n.children[i]
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Set sets the value of key, it returns false if key already existed.
+func (t *BTree[K, V]) Set(key K, value V) bool {
+	item := Entry[K, V]{Key: key, Value: value}
+	if t.root == nil {
+		t.root = &btreeNode[K, V]{items: []Entry[K, V]{item}, cow: t.cow}
+		t.length++
+		return true
+	}
+
+	t.root = t.root.mutableFor(t.cow)
+	if len(t.root.items) >= t.maxItems() {
+		middle, second := t.root.split(t.maxItems() / 2)
+		t.root = &btreeNode[K, V]{
+			items:    []Entry[K, V]{middle},
+			children: []*btreeNode[K, V]{t.root, second},
+			cow:      t.cow,
+		}
+	}
+
+	added := t.root.insert(item, t.maxItems())
+	if added {
+		t.length++
+	}
+
+	return added
+}
+
+// Delete removes key, it returns false if key doesn't exist.
+func (t *BTree[K, V]) Delete(key K) bool {
+	if t.root == nil {
+		return false
+	}
+
+	t.root = t.root.mutableFor(t.cow)
+	_, deleted := t.root.remove(key, t.minItems(), removeKey)
+	if len(t.root.items) == 0 {
+		if len(t.root.children) > 0 {
+			t.root = t.root.children[0]
+		} else {
+			t.root = nil
+		}
+	}
+	if deleted {
+		t.length--
+	}
+
+	return deleted
+}
+
+// Min returns the least item.
+func (t *BTree[K, V]) Min() (K, V, bool) {
+	n := t.root
+	if n == nil {
+		var key K
+		var value V
+		return key, value, false
+	}
+
+	for len(n.children) > 0 {
+		n = n.children[0]
+	}
+
+	return n.items[0].Key, n.items[0].Value, true
+}
+
+// Max returns the greatest item.
+func (t *BTree[K, V]) Max() (K, V, bool) {
+	n := t.root
+	if n == nil {
+		var key K
+		var value V
+		return key, value, false
+	}
+
+	for len(n.children) > 0 {
+		n = n.children[len(n.children)-1]
+	}
+	item := n.items[len(n.items)-1]
+
+	return item.Key, item.Value, true
+}
+
+// Ascend calls fn for each item in ascending order until fn returns false.
+func (t *BTree[K, V]) Ascend(fn func(key K, value V) bool) {
+	t.root.ascend(nil, nil, fn)
+}
+
+// AscendRange calls fn for each item with from <= key < to in ascending order until fn returns false.
+func (t *BTree[K, V]) AscendRange(from, to K, fn func(key K, value V) bool) {
+	t.root.ascend(&from, &to, fn)
+}
+
+// Descend calls fn for each item in descending order until fn returns false.
+func (t *BTree[K, V]) Descend(fn func(key K, value V) bool) {
+	t.root.descend(nil, nil, fn)
+}
+
+// DescendRange calls fn for each item with from >= key > to in descending order until fn returns false.
+func (t *BTree[K, V]) DescendRange(from, to K, fn func(key K, value V) bool) {
+	t.root.descend(&from, &to, fn)
+}
+
+func (t *BTree[K, V]) maxItems() int {
+	return t.degree*2 - 1
+}
+
+func (t *BTree[K, V]) minItems() int {
+	return t.degree - 1
+}
+
+// find returns the index of the first item whose key is not less than key, and whether it's equal.
+func (n *btreeNode[K, V]) find(key K) (int, bool) {
+	i := sort.Search(len(n.items), func(i int) bool {
+		return key <= n.items[i].Key
+	})
+
+	return i, i < len(n.items) && n.items[i].Key == key
+}
+
+func (n *btreeNode[K, V]) mutableFor(cow *cowToken) *btreeNode[K, V] {
+	if n.cow == cow {
+		return n
+	}
+
+	node := &btreeNode[K, V]{cow: cow}
+	node.items = append(make([]Entry[K, V], 0, cap(n.items)), n.items...)
+	if len(n.children) > 0 {
+		node.children = append(make([]*btreeNode[K, V], 0, cap(n.children)), n.children...)
+	}
+
+	return node
+}
+
+func (n *btreeNode[K, V]) mutableChild(i int) *btreeNode[K, V] {
+	child := n.children[i].mutableFor(n.cow)
+	n.children[i] = child
+
+	return child
+}
+
+// split moves the items after i and their children into a new node, it returns the item at i and the new node.
+func (n *btreeNode[K, V]) split(i int) (Entry[K, V], *btreeNode[K, V]) {
+	item := n.items[i]
+	next := &btreeNode[K, V]{cow: n.cow}
+	next.items = append(next.items, n.items[i+1:]...)
+	clear(n.items[i:])
+	n.items = n.items[:i]
+	if len(n.children) > 0 {
+		next.children = append(next.children, n.children[i+1:]...)
+		clear(n.children[i+1:])
+		n.children = n.children[:i+1]
+	}
+
+	return item, next
+}
+
+// maybeSplitChild splits the child at i if it's full, it reports whether it has been split.
+func (n *btreeNode[K, V]) maybeSplitChild(i, maxItems int) bool {
+	if len(n.children[i].items) < maxItems {
+		return false
+	}
+
+	first := n.mutableChild(i)
+	item, second := first.split(maxItems / 2)
+	n.items = insertAt(n.items, i, item)
+	n.children = insertAt(n.children, i+1, second)
+
+	return true
+}
+
+func (n *btreeNode[K, V]) insert(item Entry[K, V], maxItems int) bool {
+	i, found := n.find(item.Key)
+	if found {
+		n.items[i] = item
+		return false
+	}
+	if len(n.children) == 0 {
+		n.items = insertAt(n.items, i, item)
+		return true
+	}
+
+	if n.maybeSplitChild(i, maxItems) {
+		switch middle := n.items[i].Key; {
+		case item.Key == middle:
+			n.items[i] = item
+			return false
+		case item.Key > middle:
+			i++
+		}
+	}
+
+	return n.mutableChild(i).insert(item, maxItems)
+}
+
+func (n *btreeNode[K, V]) remove(key K, minItems int, kind removeKind) (Entry[K, V], bool) {
+	var i int
+	var found bool
+	switch kind {
+	case removeMax:
+		if len(n.children) == 0 {
+			item := n.items[len(n.items)-1]
+			n.items = removeAt(n.items, len(n.items)-1)
+			return item, true
+		}
+		i = len(n.items)
+	default:
+		i, found = n.find(key)
+		if len(n.children) == 0 {
+			if !found {
+				return Entry[K, V]{}, false
+			}
+			item := n.items[i]
+			n.items = removeAt(n.items, i)
+			return item, true
+		}
+	}
+
+	// make sure the child to descend into can lose an item.
+	if len(n.children[i].items) <= minItems {
+		n.growChild(i, minItems)
+		return n.remove(key, minItems, kind)
+	}
+
+	child := n.mutableChild(i)
+	if found {
+		// replace the item with its predecessor.
+		item := n.items[i]
+		n.items[i], _ = child.remove(key, minItems, removeMax)
+		return item, true
+	}
+
+	return child.remove(key, minItems, kind)
+}
+
+// growChild gives the child at i one more item, by stealing from a sibling or merging with it.
+func (n *btreeNode[K, V]) growChild(i, minItems int) {
+	switch {
+	case i > 0 && len(n.children[i-1].items) > minItems:
+		child := n.mutableChild(i)
+		left := n.mutableChild(i - 1)
+		stolen := left.items[len(left.items)-1]
+		left.items = removeAt(left.items, len(left.items)-1)
+		child.items = insertAt(child.items, 0, n.items[i-1])
+		n.items[i-1] = stolen
+		if len(left.children) > 0 {
+			last := left.children[len(left.children)-1]
+			left.children = removeAt(left.children, len(left.children)-1)
+			child.children = insertAt(child.children, 0, last)
+		}
+	case i < len(n.items) && len(n.children[i+1].items) > minItems:
+		child := n.mutableChild(i)
+		right := n.mutableChild(i + 1)
+		stolen := right.items[0]
+		right.items = removeAt(right.items, 0)
+		child.items = append(child.items, n.items[i])
+		n.items[i] = stolen
+		if len(right.children) > 0 {
+			first := right.children[0]
+			right.children = removeAt(right.children, 0)
+			child.children = append(child.children, first)
+		}
+	default:
+		if i >= len(n.items) {
+			i--
+		}
+		// merge the child at i+1 into the child at i.
+		child := n.mutableChild(i)
+		item := n.items[i]
+		n.items = removeAt(n.items, i)
+		merged := n.children[i+1]
+		n.children = removeAt(n.children, i+1)
+		child.items = append(child.items, item)
+		child.items = append(child.items, merged.items...)
+		child.children = append(child.children, merged.children...)
+	}
+}
+
+func (n *btreeNode[K, V]) ascend(from, to *K, fn func(key K, value V) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	start := 0
+	if from != nil {
+		start, _ = n.find(*from)
+	}
+	for i := start; i < len(n.items); i++ {
+		if len(n.children) > 0 && !n.children[i].ascend(from, to, fn) {
+			return false
+		}
+		item := n.items[i]
+		if to != nil && item.Key >= *to {
+			return false
+		}
+		if !fn(item.Key, item.Value) {
+			return false
+		}
+	}
+	if len(n.children) > 0 {
+		return n.children[len(n.children)-1].ascend(from, to, fn)
+	}
+
+	return true
+}
+
+func (n *btreeNode[K, V]) descend(from, to *K, fn func(key K, value V) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	end := len(n.items)
+	if from != nil {
+		i, found := n.find(*from)
+		end = i
+		if found {
+			end++
+		}
+	}
+	if len(n.children) > 0 && !n.children[end].descend(from, to, fn) {
+		return false
+	}
+	for i := end - 1; i >= 0; i-- {
+		item := n.items[i]
+		if to != nil && item.Key <= *to {
+			return false
+		}
+		if !fn(item.Key, item.Value) {
+			return false
+		}
+		if len(n.children) > 0 && !n.children[i].descend(from, to, fn) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func insertAt[T any](s []T, i int, v T) []T {
+	var zero T
+	s = append(s, zero)
+	copy(s[i+1:], s[i:])
+	s[i] = v
+
+	return s
+}
+
+func removeAt[T any](s []T, i int) []T {
+	var zero T
+	copy(s[i:], s[i+1:])
+	s[len(s)-1] = zero
+
+	return s[:len(s)-1]
+}
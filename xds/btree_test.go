@@ -0,0 +1,241 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func btreeKeys(t *BTree[int, int]) []int {
+	var keys []int
+	t.Ascend(func(key, _ int) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// checkBTree verifies the invariants of tree.
+func checkBTree(t *testing.T, tree *BTree[int, int]) {
+	var check func(n *btreeNode[int, int], root bool) int
+	check = func(n *btreeNode[int, int], root bool) int {
+		if !root {
+			assert.GreaterOrEqual(t, len(n.items), tree.minItems())
+		}
+		assert.LessOrEqual(t, len(n.items), tree.maxItems())
+		assert.True(t, sort.SliceIsSorted(n.items, func(i, j int) bool {
+			return n.items[i].Key < n.items[j].Key
+		}))
+		if len(n.children) == 0 {
+			return 1
+		}
+
+		assert.Len(t, n.children, len(n.items)+1)
+		height := check(n.children[0], false)
+		for _, child := range n.children[1:] {
+			assert.Equal(t, height, check(child, false))
+		}
+		return height + 1
+	}
+
+	if tree.root != nil {
+		check(tree.root, true)
+	}
+	assert.Len(t, btreeKeys(tree), tree.Len())
+}
+
+func TestBTree(t *testing.T) {
+	tree := NewBTree[int, int](2)
+	_, ok := tree.Get(1)
+	assert.False(t, ok)
+	_, _, ok = tree.Min()
+	assert.False(t, ok)
+	_, _, ok = tree.Max()
+	assert.False(t, ok)
+	assert.False(t, tree.Delete(1))
+	assert.Empty(t, btreeKeys(tree))
+
+	for _, k := range rand.Perm(100) {
+		assert.True(t, tree.Set(k, k*10))
+	}
+	assert.False(t, tree.Set(5, 5))
+	assert.Equal(t, 100, tree.Len())
+	checkBTree(t, tree)
+
+	v, ok := tree.Get(5)
+	assert.True(t, ok)
+	assert.Equal(t, 5, v)
+	v, _ = tree.Get(6)
+	assert.Equal(t, 60, v)
+
+	k, _, _ := tree.Min()
+	assert.Equal(t, 0, k)
+	k, _, _ = tree.Max()
+	assert.Equal(t, 99, k)
+
+	var keys []int
+	tree.AscendRange(10, 15, func(key, _ int) bool {
+		keys = append(keys, key)
+		return true
+	})
+	assert.Equal(t, []int{10, 11, 12, 13, 14}, keys)
+
+	keys = nil
+	tree.DescendRange(15, 10, func(key, _ int) bool {
+		keys = append(keys, key)
+		return true
+	})
+	assert.Equal(t, []int{15, 14, 13, 12, 11}, keys)
+
+	keys = nil
+	tree.Descend(func(key, _ int) bool {
+		keys = append(keys, key)
+		return len(keys) < 3
+	})
+	assert.Equal(t, []int{99, 98, 97}, keys)
+
+	keys = nil
+	tree.Ascend(func(key, _ int) bool {
+		keys = append(keys, key)
+		return len(keys) < 3
+	})
+	assert.Equal(t, []int{0, 1, 2}, keys)
+
+	for k := 0; k < 100; k += 2 {
+		assert.True(t, tree.Delete(k))
+	}
+	assert.False(t, tree.Delete(0))
+	assert.Equal(t, 50, tree.Len())
+	checkBTree(t, tree)
+
+	assert.Panics(t, func() {
+		NewBTree[int, int](1)
+	})
+}
+
+func TestBTree_Random(t *testing.T) {
+	for _, degree := range []int{2, 3, 8, 32} {
+		tree := NewBTree[int, int](degree)
+		reference := map[int]int{}
+		for i := 0; i < 5000; i++ {
+			k := rand.Intn(2000)
+			if rand.Intn(3) == 0 {
+				_, exists := reference[k]
+				assert.Equal(t, exists, tree.Delete(k))
+				delete(reference, k)
+			} else {
+				_, exists := reference[k]
+				assert.Equal(t, !exists, tree.Set(k, i))
+				reference[k] = i
+			}
+		}
+		checkBTree(t, tree)
+		assert.Equal(t, len(reference), tree.Len())
+		for k, v := range reference {
+			got, ok := tree.Get(k)
+			assert.True(t, ok)
+			assert.Equal(t, v, got)
+		}
+
+		for k := range reference {
+			assert.True(t, tree.Delete(k))
+		}
+		assert.Equal(t, 0, tree.Len())
+		assert.Nil(t, tree.root)
+	}
+}
+
+func TestBTree_Clone(t *testing.T) {
+	tree := NewBTree[int, int](2)
+	for i := 0; i < 100; i++ {
+		tree.Set(i, i)
+	}
+
+	clone := tree.Clone()
+	for i := 0; i < 100; i += 2 {
+		clone.Delete(i)
+	}
+	clone.Set(1, -1)
+	clone.Set(1000, 1000)
+	tree.Set(3, -3)
+	tree.Set(-1, -1)
+
+	checkBTree(t, tree)
+	checkBTree(t, clone)
+	assert.Equal(t, 101, tree.Len())
+	assert.Equal(t, 51, clone.Len())
+
+	v, _ := tree.Get(1)
+	assert.Equal(t, 1, v)
+	v, _ = clone.Get(1)
+	assert.Equal(t, -1, v)
+	v, _ = clone.Get(3)
+	assert.Equal(t, 3, v)
+	_, ok := tree.Get(1000)
+	assert.False(t, ok)
+	_, ok = tree.Get(0)
+	assert.True(t, ok)
+
+	// clones of clones stay independent too.
+	another := clone.Clone()
+	another.Delete(1)
+	_, ok = clone.Get(1)
+	assert.True(t, ok)
+}
+
+func TestNewBTreeFromSorted(t *testing.T) {
+	for _, degree := range []int{2, 3, 5} {
+		for _, n := range []int{0, 1, 2, 3, 4, 5, 10, 17, 100, 1000} {
+			entries := make([]Entry[int, int], n)
+			for i := range entries {
+				entries[i] = Entry[int, int]{Key: i * 2, Value: i}
+			}
+
+			tree := NewBTreeFromSorted(degree, entries)
+			checkBTree(t, tree)
+			assert.Equal(t, n, tree.Len())
+			for _, e := range entries {
+				v, ok := tree.Get(e.Key)
+				assert.True(t, ok)
+				assert.Equal(t, e.Value, v)
+			}
+
+			// the tree keeps working after the bulk load.
+			for i := 0; i < n; i++ {
+				tree.Set(i*2+1, i)
+				if i%3 == 0 {
+					tree.Delete(i * 2)
+				}
+			}
+			checkBTree(t, tree)
+		}
+	}
+
+	assert.Panics(t, func() {
+		NewBTreeFromSorted(2, []Entry[int, int]{{Key: 2}, {Key: 1}})
+	})
+}
+
+func BenchmarkBTree_Set(b *testing.B) {
+	tree := NewBTree[int, int](32)
+	for i := 0; i < b.N; i++ {
+		tree.Set(rand.Int(), i)
+	}
+}
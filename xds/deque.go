@@ -0,0 +1,186 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+const minDequeSize = 8
+
+// A Deque is a double-ended queue backed by a growable ring buffer,
+// pushing and popping at both ends is amortized O(1).
+// It's not safe for concurrent use.
+type Deque[T any] struct {
+	buf      []T
+	head     int
+	size     int
+	capacity int
+}
+
+// NewDeque returns an empty Deque.
+func NewDeque[T any](opts ...Option) *Deque[T] {
+	return &Deque[T]{capacity: newOptions(opts).capacity}
+}
+
+// Len returns the number of elements.
+func (d *Deque[T]) Len() int {
+	return d.size
+}
+
+// Full reports whether d is bounded and full.
+func (d *Deque[T]) Full() bool {
+	return d.capacity > 0 && d.size >= d.capacity
+}
+
+// PushBack adds v at the back, it returns false if d is full.
+func (d *Deque[T]) PushBack(v T) bool {
+	if d.Full() {
+		return false
+	}
+
+	d.grow()
+	d.buf[d.index(d.size)] = v
+	d.size++
+
+	return true
+}
+
+// PushFront adds v at the front, it returns false if d is full.
+func (d *Deque[T]) PushFront(v T) bool {
+	if d.Full() {
+		return false
+	}
+
+	d.grow()
+	d.head = (d.head - 1 + len(d.buf)) & (len(d.buf) - 1)
+	d.buf[d.head] = v
+	d.size++
+
+	return true
+}
+
+// PopFront removes and returns the front element.
+func (d *Deque[T]) PopFront() (T, bool) {
+	var zero T
+	if d.size == 0 {
+		return zero, false
+	}
+
+	v := d.buf[d.head]
+	d.buf[d.head] = zero
+	d.head = d.index(1)
+	d.size--
+	d.shrink()
+
+	return v, true
+}
+
+// PopBack removes and returns the back element.
+func (d *Deque[T]) PopBack() (T, bool) {
+	var zero T
+	if d.size == 0 {
+		return zero, false
+	}
+
+	i := d.index(d.size - 1)
+	v := d.buf[i]
+	d.buf[i] = zero
+	d.size--
+	d.shrink()
+
+	return v, true
+}
+
+// PeekFront returns the front element without removing it.
+func (d *Deque[T]) PeekFront() (T, bool) {
+	if d.size == 0 {
+		var zero T
+		return zero, false
+	}
+
+	return d.buf[d.head], true
+}
+
+// PeekBack returns the back element without removing it.
+func (d *Deque[T]) PeekBack() (T, bool) {
+	if d.size == 0 {
+		var zero T
+		return zero, false
+	}
+
+	return d.buf[d.index(d.size-1)], true
+}
+
+// At returns the i-th element from the front, it panics if i is out of range.
+func (d *Deque[T]) At(i int) T {
+	if i < 0 || i >= d.size {
+		panic("xds: index out of range")
+	}
+
+	return d.buf[d.index(i)]
+}
+
+// Range calls fn for each element from front to back until fn returns false.
+func (d *Deque[T]) Range(fn func(v T) bool) {
+	for i := 0; i < d.size; i++ {
+		if !fn(d.buf[d.index(i)]) {
+			return
+		}
+	}
+}
+
+// Clear removes all elements.
+func (d *Deque[T]) Clear() {
+	d.buf = nil
+	d.head = 0
+	d.size = 0
+}
+
+func (d *Deque[T]) index(i int) int {
+	// len(d.buf) is always a power of two.
+	return (d.head + i) & (len(d.buf) - 1)
+}
+
+func (d *Deque[T]) grow() {
+	if d.size < len(d.buf) {
+		return
+	}
+
+	size := len(d.buf) << 1
+	if size == 0 {
+		size = minDequeSize
+	}
+	d.resize(size)
+}
+
+func (d *Deque[T]) shrink() {
+	if len(d.buf) > minDequeSize && d.size <= len(d.buf)>>2 {
+		d.resize(len(d.buf) >> 1)
+	}
+}
+
+func (d *Deque[T]) resize(size int) {
+	buf := make([]T, size)
+	if d.size > 0 {
+		if d.head+d.size <= len(d.buf) {
+			copy(buf, d.buf[d.head:d.head+d.size])
+		} else {
+			n := copy(buf, d.buf[d.head:])
+			copy(buf[n:], d.buf[:d.size-n])
+		}
+	}
+
+	d.buf = buf
+	d.head = 0
+}
@@ -0,0 +1,121 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func dequeElements[T any](d *Deque[T]) []T {
+	var elements []T
+	d.Range(func(v T) bool {
+		elements = append(elements, v)
+		return true
+	})
+	return elements
+}
+
+func TestDeque(t *testing.T) {
+	d := NewDeque[int]()
+	_, ok := d.PopFront()
+	assert.False(t, ok)
+	_, ok = d.PopBack()
+	assert.False(t, ok)
+	_, ok = d.PeekFront()
+	assert.False(t, ok)
+	_, ok = d.PeekBack()
+	assert.False(t, ok)
+
+	for i := 0; i < 5; i++ {
+		assert.True(t, d.PushBack(i))
+		assert.True(t, d.PushFront(-i-1))
+	}
+	assert.Equal(t, 10, d.Len())
+	assert.Equal(t, []int{-5, -4, -3, -2, -1, 0, 1, 2, 3, 4}, dequeElements(d))
+	assert.Equal(t, -5, d.At(0))
+	assert.Equal(t, 4, d.At(9))
+	assert.Panics(t, func() {
+		d.At(10)
+	})
+
+	v, _ := d.PeekFront()
+	assert.Equal(t, -5, v)
+	v, _ = d.PeekBack()
+	assert.Equal(t, 4, v)
+	v, _ = d.PopFront()
+	assert.Equal(t, -5, v)
+	v, _ = d.PopBack()
+	assert.Equal(t, 4, v)
+	assert.Equal(t, 8, d.Len())
+
+	var visited []int
+	d.Range(func(v int) bool {
+		visited = append(visited, v)
+		return len(visited) < 2
+	})
+	assert.Equal(t, []int{-4, -3}, visited)
+
+	d.Clear()
+	assert.Equal(t, 0, d.Len())
+	assert.True(t, d.PushFront(1))
+	assert.Equal(t, []int{1}, dequeElements(d))
+}
+
+func TestDeque_GrowShrink(t *testing.T) {
+	d := NewDeque[int]()
+	for i := 0; i < 1000; i++ {
+		d.PushBack(i)
+		if i%3 == 0 {
+			d.PopFront()
+		}
+	}
+	assert.Equal(t, 666, d.Len())
+	assert.Equal(t, 334, d.At(0))
+
+	for d.Len() > 1 {
+		d.PopBack()
+	}
+	assert.LessOrEqual(t, len(d.buf), minDequeSize*2)
+	assert.Equal(t, []int{334}, dequeElements(d))
+}
+
+func TestDeque_Capacity(t *testing.T) {
+	d := NewDeque[string](WithCapacity(2))
+	assert.True(t, d.PushBack("a"))
+	assert.True(t, d.PushFront("b"))
+	assert.True(t, d.Full())
+	assert.False(t, d.PushBack("c"))
+	assert.False(t, d.PushFront("c"))
+	assert.Equal(t, []string{"b", "a"}, dequeElements(d))
+
+	d.PopBack()
+	assert.False(t, d.Full())
+	assert.True(t, d.PushBack("c"))
+
+	assert.False(t, NewDeque[int](WithCapacity(-1)).Full())
+}
+
+func BenchmarkDeque(b *testing.B) {
+	d := NewDeque[int]()
+	for i := 0; i < b.N; i++ {
+		d.PushBack(i)
+		if i%2 == 0 {
+			d.PopFront()
+		}
+	}
+}
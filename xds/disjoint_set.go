@@ -0,0 +1,131 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+// A DisjointSet partitions elements into disjoint sets, also known as union-find.
+// It uses union by rank and path compression, so that operations are nearly O(1).
+// Unknown elements are added as singletons. It's not safe for concurrent use.
+type DisjointSet[T comparable] struct {
+	ids      map[T]int
+	elements []T
+	parent   []int
+	rank     []uint8
+	count    int
+}
+
+// NewDisjointSet returns a DisjointSet of elements, each in its own set.
+func NewDisjointSet[T comparable](elements ...T) *DisjointSet[T] {
+	s := &DisjointSet[T]{ids: make(map[T]int, len(elements))}
+	for _, e := range elements {
+		s.Add(e)
+	}
+
+	return s
+}
+
+// Add adds x as a singleton, it returns false if x already exists.
+func (s *DisjointSet[T]) Add(x T) bool {
+	if _, ok := s.ids[x]; ok {
+		return false
+	}
+
+	s.ids[x] = len(s.elements)
+	s.parent = append(s.parent, len(s.elements))
+	s.elements = append(s.elements, x)
+	s.rank = append(s.rank, 0)
+	s.count++
+
+	return true
+}
+
+// Len returns the number of elements.
+func (s *DisjointSet[T]) Len() int {
+	return len(s.elements)
+}
+
+// Count returns the number of sets.
+func (s *DisjointSet[T]) Count() int {
+	return s.count
+}
+
+// Find returns the representative of the set x belongs to.
+func (s *DisjointSet[T]) Find(x T) T {
+	return s.elements[s.find(s.id(x))]
+}
+
+// Union merges the sets of a and b, it returns false if they were already in the same set.
+func (s *DisjointSet[T]) Union(a, b T) bool {
+	ra, rb := s.find(s.id(a)), s.find(s.id(b))
+	if ra == rb {
+		return false
+	}
+
+	switch {
+	case s.rank[ra] < s.rank[rb]:
+		s.parent[ra] = rb
+	case s.rank[ra] > s.rank[rb]:
+		s.parent[rb] = ra
+	default:
+		s.parent[rb] = ra
+		s.rank[ra]++
+	}
+	s.count--
+
+	return true
+}
+
+// Connected reports whether a and b are in the same set.
+func (s *DisjointSet[T]) Connected(a, b T) bool {
+	return s.find(s.id(a)) == s.find(s.id(b))
+}
+
+// Groups returns the sets, the elements and the sets are in the order they were added.
+func (s *DisjointSet[T]) Groups() [][]T {
+	index := make(map[int]int, s.count)
+	groups := make([][]T, 0, s.count)
+	for i, e := range s.elements {
+		root := s.find(i)
+		g, ok := index[root]
+		if !ok {
+			g = len(groups)
+			index[root] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], e)
+	}
+
+	return groups
+}
+
+func (s *DisjointSet[T]) id(x T) int {
+	s.Add(x)
+
+	return s.ids[x]
+}
+
+func (s *DisjointSet[T]) find(i int) int {
+	root := i
+	for s.parent[root] != root {
+		root = s.parent[root]
+	}
+	// compress the path.
+	for s.parent[i] != root {
+		s.parent[i], i = root, s.parent[i]
+	}
+
+	return root
+}
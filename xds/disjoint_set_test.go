@@ -0,0 +1,59 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDisjointSet(t *testing.T) {
+	s := NewDisjointSet("a", "b", "c", "d", "e")
+	assert.Equal(t, 5, s.Len())
+	assert.Equal(t, 5, s.Count())
+	assert.False(t, s.Add("a"))
+	assert.False(t, s.Connected("a", "b"))
+
+	assert.True(t, s.Union("a", "b"))
+	assert.True(t, s.Union("c", "d"))
+	assert.True(t, s.Union("b", "d"))
+	assert.False(t, s.Union("a", "c"))
+	assert.Equal(t, 2, s.Count())
+
+	assert.True(t, s.Connected("a", "d"))
+	assert.False(t, s.Connected("a", "e"))
+	assert.Equal(t, s.Find("a"), s.Find("c"))
+	assert.Equal(t, "e", s.Find("e"))
+
+	// unknown elements are added.
+	assert.Equal(t, "f", s.Find("f"))
+	assert.True(t, s.Union("g", "e"))
+	assert.Equal(t, 7, s.Len())
+	assert.Equal(t, 3, s.Count())
+
+	assert.Equal(t, [][]string{{"a", "b", "c", "d"}, {"e", "g"}, {"f"}}, s.Groups())
+}
+
+func TestDisjointSet_Chain(t *testing.T) {
+	s := NewDisjointSet[int]()
+	for i := 1; i < 10000; i++ {
+		s.Union(i-1, i)
+	}
+	assert.Equal(t, 1, s.Count())
+	assert.True(t, s.Connected(0, 9999))
+	assert.LessOrEqual(t, int(s.rank[s.find(0)]), 14)
+}
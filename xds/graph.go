@@ -0,0 +1,439 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+var (
+	// ErrNodeNotFound is an error that indicates the node is not in the Graph.
+	ErrNodeNotFound = errors.New("xds: node not found")
+	// ErrNoPath is an error that indicates there is no path between the nodes.
+	ErrNoPath = errors.New("xds: no path")
+	// ErrNegativeWeight is an error that indicates Dijkstra can't run on a negative weight.
+	ErrNegativeWeight = errors.New("xds: negative edge weight")
+)
+
+type (
+	// A Graph is a directed or undirected weighted graph.
+	// Nodes and edges are iterated in the order they were added. It's not safe for concurrent use.
+	Graph[N comparable] struct {
+		directed bool
+		nodes    []N
+		index    map[N]int
+		edges    [][]graphEdge
+	}
+
+	graphEdge struct {
+		to     int
+		weight float64
+	}
+)
+
+// NewGraph returns an empty Graph.
+func NewGraph[N comparable](directed bool) *Graph[N] {
+	return &Graph[N]{directed: directed, index: map[N]int{}}
+}
+
+// Directed reports whether g is directed.
+func (g *Graph[N]) Directed() bool {
+	return g.directed
+}
+
+// Len returns the number of nodes.
+func (g *Graph[N]) Len() int {
+	return len(g.nodes)
+}
+
+// Nodes returns the nodes.
+func (g *Graph[N]) Nodes() []N {
+	return append([]N(nil), g.nodes...)
+}
+
+// AddNode adds n, it returns false if n already exists.
+func (g *Graph[N]) AddNode(n N) bool {
+	if _, ok := g.index[n]; ok {
+		return false
+	}
+
+	g.index[n] = len(g.nodes)
+	g.nodes = append(g.nodes, n)
+	g.edges = append(g.edges, nil)
+
+	return true
+}
+
+// AddEdge adds an edge of weight from from to to, adding the nodes if needed.
+// The weight of an existing edge is updated. Undirected edges go both ways.
+func (g *Graph[N]) AddEdge(from, to N, weight float64) {
+	g.AddNode(from)
+	g.AddNode(to)
+
+	f, t := g.index[from], g.index[to]
+	g.setEdge(f, t, weight)
+	if !g.directed && f != t {
+		g.setEdge(t, f, weight)
+	}
+}
+
+// RemoveEdge removes the edge from from to to, it returns false if there is none.
+func (g *Graph[N]) RemoveEdge(from, to N) bool {
+	f, ok := g.index[from]
+	if !ok {
+		return false
+	}
+	t, ok := g.index[to]
+	if !ok {
+		return false
+	}
+
+	removed := g.removeEdge(f, t)
+	if !g.directed && f != t {
+		g.removeEdge(t, f)
+	}
+
+	return removed
+}
+
+// Weight returns the weight of the edge from from to to.
+func (g *Graph[N]) Weight(from, to N) (float64, bool) {
+	f, ok := g.index[from]
+	if !ok {
+		return 0, false
+	}
+	t, ok := g.index[to]
+	if !ok {
+		return 0, false
+	}
+
+	for _, e := range g.edges[f] {
+		if e.to == t {
+			return e.weight, true
+		}
+	}
+
+	return 0, false
+}
+
+// HasEdge reports whether there is an edge from from to to.
+func (g *Graph[N]) HasEdge(from, to N) bool {
+	_, ok := g.Weight(from, to)
+
+	return ok
+}
+
+// Neighbors returns the nodes n has an edge to.
+func (g *Graph[N]) Neighbors(n N) []N {
+	i, ok := g.index[n]
+	if !ok {
+		return nil
+	}
+
+	neighbors := make([]N, len(g.edges[i]))
+	for j, e := range g.edges[i] {
+		neighbors[j] = g.nodes[e.to]
+	}
+
+	return neighbors
+}
+
+// BFS visits the nodes reachable from start in breadth-first order,
+// calling fn with each node and its depth until fn returns false.
+func (g *Graph[N]) BFS(start N, fn func(n N, depth int) bool) {
+	s, ok := g.index[start]
+	if !ok {
+		return
+	}
+
+	depths := make([]int, len(g.nodes))
+	for i := range depths {
+		depths[i] = -1
+	}
+	depths[s] = 0
+	queue := NewQueue[int]()
+	queue.Push(s)
+	for queue.Len() > 0 {
+		i, _ := queue.Pop()
+		if !fn(g.nodes[i], depths[i]) {
+			return
+		}
+		for _, e := range g.edges[i] {
+			if depths[e.to] < 0 {
+				depths[e.to] = depths[i] + 1
+				queue.Push(e.to)
+			}
+		}
+	}
+}
+
+// DFS visits the nodes reachable from start in depth-first preorder until fn returns false.
+func (g *Graph[N]) DFS(start N, fn func(n N) bool) {
+	s, ok := g.index[start]
+	if !ok {
+		return
+	}
+
+	visited := make([]bool, len(g.nodes))
+	stack := NewStack[int]()
+	stack.Push(s)
+	for stack.Len() > 0 {
+		i, _ := stack.Pop()
+		if visited[i] {
+			continue
+		}
+		visited[i] = true
+		if !fn(g.nodes[i]) {
+			return
+		}
+		// push in reverse so that the first neighbor is visited first.
+		for j := len(g.edges[i]) - 1; j >= 0; j-- {
+			if to := g.edges[i][j].to; !visited[to] {
+				stack.Push(to)
+			}
+		}
+	}
+}
+
+// ShortestPath returns the path of least total weight from from to to and its weight with Dijkstra.
+func (g *Graph[N]) ShortestPath(from, to N) ([]N, float64, error) {
+	f, ok := g.index[from]
+	if !ok {
+		return nil, 0, ErrNodeNotFound
+	}
+	t, ok := g.index[to]
+	if !ok {
+		return nil, 0, ErrNodeNotFound
+	}
+	for _, edges := range g.edges {
+		for _, e := range edges {
+			if e.weight < 0 {
+				return nil, 0, ErrNegativeWeight
+			}
+		}
+	}
+
+	type candidate struct {
+		node     int
+		distance float64
+	}
+	distances := make([]float64, len(g.nodes))
+	previous := make([]int, len(g.nodes))
+	for i := range distances {
+		distances[i] = math.Inf(1)
+		previous[i] = -1
+	}
+	distances[f] = 0
+
+	queue := NewPriorityQueue(func(a, b candidate) bool {
+		return a.distance < b.distance
+	})
+	queue.Push(candidate{node: f})
+	for queue.Len() > 0 {
+		c, _ := queue.Pop()
+		if c.distance > distances[c.node] {
+			continue
+		}
+		if c.node == t {
+			break
+		}
+		for _, e := range g.edges[c.node] {
+			if d := c.distance + e.weight; d < distances[e.to] {
+				distances[e.to] = d
+				previous[e.to] = c.node
+				queue.Push(candidate{node: e.to, distance: d})
+			}
+		}
+	}
+
+	if math.IsInf(distances[t], 1) {
+		return nil, 0, ErrNoPath
+	}
+
+	var path []N
+	for i := t; i >= 0; i = previous[i] {
+		path = append(path, g.nodes[i])
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path, distances[t], nil
+}
+
+// HasCycle reports whether g has a cycle, a self-loop counts as one.
+func (g *Graph[N]) HasCycle() bool {
+	if !g.directed {
+		set := NewDisjointSet[int]()
+		for i, edges := range g.edges {
+			for _, e := range edges {
+				// every undirected edge is stored twice, so only look at it once.
+				if e.to == i {
+					return true
+				}
+				if e.to > i && !set.Union(i, e.to) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	const (
+		white = iota
+		gray
+		black
+	)
+	colors := make([]int, len(g.nodes))
+	var visit func(i int) bool
+	visit = func(i int) bool {
+		colors[i] = gray
+		for _, e := range g.edges[i] {
+			switch colors[e.to] {
+			case gray:
+				return true
+			case white:
+				if visit(e.to) {
+					return true
+				}
+			}
+		}
+		colors[i] = black
+		return false
+	}
+
+	for i := range g.nodes {
+		if colors[i] == white && visit(i) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// StronglyConnectedComponents returns the strongly connected components with Tarjan's algorithm,
+// or the connected components if g is undirected.
+func (g *Graph[N]) StronglyConnectedComponents() [][]N {
+	index := make([]int, len(g.nodes))
+	low := make([]int, len(g.nodes))
+	onStack := make([]bool, len(g.nodes))
+	for i := range index {
+		index[i] = -1
+	}
+
+	var components [][]N
+	var stack []int
+	counter := 0
+	var connect func(i int)
+	connect = func(i int) {
+		index[i], low[i] = counter, counter
+		counter++
+		stack = append(stack, i)
+		onStack[i] = true
+
+		for _, e := range g.edges[i] {
+			if index[e.to] < 0 {
+				connect(e.to)
+				low[i] = min(low[i], low[e.to])
+			} else if onStack[e.to] {
+				low[i] = min(low[i], index[e.to])
+			}
+		}
+
+		if low[i] == index[i] {
+			var component []N
+			for {
+				j := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[j] = false
+				component = append(component, g.nodes[j])
+				if j == i {
+					break
+				}
+			}
+			components = append(components, component)
+		}
+	}
+
+	for i := range g.nodes {
+		if index[i] < 0 {
+			connect(i)
+		}
+	}
+
+	return components
+}
+
+// WriteDOT writes g in the Graphviz DOT language, e.g. to debug it with `dot -Tsvg`.
+func (g *Graph[N]) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	kind, arrow := "digraph", "->"
+	if !g.directed {
+		kind, arrow = "graph", "--"
+	}
+
+	fmt.Fprintf(bw, "%s {\n", kind)
+	for _, n := range g.nodes {
+		fmt.Fprintf(bw, "\t%q;\n", fmt.Sprint(n))
+	}
+	for i, edges := range g.edges {
+		for _, e := range edges {
+			if !g.directed && e.to < i {
+				continue
+			}
+			fmt.Fprintf(bw, "\t%q %s %q [weight=%g];\n", fmt.Sprint(g.nodes[i]), arrow, fmt.Sprint(g.nodes[e.to]), e.weight)
+		}
+	}
+	bw.WriteString("}\n")
+
+	return bw.Flush()
+}
+
+// DOT returns g in the Graphviz DOT language.
+func (g *Graph[N]) DOT() string {
+	var b strings.Builder
+	_ = g.WriteDOT(&b)
+
+	return b.String()
+}
+
+func (g *Graph[N]) setEdge(from, to int, weight float64) {
+	for i, e := range g.edges[from] {
+		if e.to == to {
+			g.edges[from][i].weight = weight
+			return
+		}
+	}
+
+	g.edges[from] = append(g.edges[from], graphEdge{to: to, weight: weight})
+}
+
+func (g *Graph[N]) removeEdge(from, to int) bool {
+	for i, e := range g.edges[from] {
+		if e.to == to {
+			g.edges[from] = append(g.edges[from][:i], g.edges[from][i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
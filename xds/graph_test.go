@@ -0,0 +1,188 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"testing"
+)
+
+func TestGraph(t *testing.T) {
+	g := NewGraph[string](true)
+	assert.True(t, g.Directed())
+	assert.True(t, g.AddNode("a"))
+	assert.False(t, g.AddNode("a"))
+	g.AddEdge("a", "b", 1)
+	g.AddEdge("a", "c", 4)
+	g.AddEdge("a", "b", 2)
+	assert.Equal(t, 3, g.Len())
+	assert.Equal(t, []string{"a", "b", "c"}, g.Nodes())
+	assert.Equal(t, []string{"b", "c"}, g.Neighbors("a"))
+	assert.Nil(t, g.Neighbors("x"))
+
+	w, ok := g.Weight("a", "b")
+	assert.True(t, ok)
+	assert.Equal(t, 2.0, w)
+	assert.False(t, g.HasEdge("b", "a"))
+	_, ok = g.Weight("x", "a")
+	assert.False(t, ok)
+	_, ok = g.Weight("a", "x")
+	assert.False(t, ok)
+
+	assert.True(t, g.RemoveEdge("a", "b"))
+	assert.False(t, g.RemoveEdge("a", "b"))
+	assert.False(t, g.RemoveEdge("x", "b"))
+	assert.False(t, g.RemoveEdge("a", "x"))
+	assert.Equal(t, []string{"c"}, g.Neighbors("a"))
+}
+
+func TestGraph_Undirected(t *testing.T) {
+	g := NewGraph[int](false)
+	g.AddEdge(1, 2, 1)
+	assert.True(t, g.HasEdge(2, 1))
+	assert.False(t, g.HasCycle())
+
+	g.AddEdge(2, 3, 1)
+	g.AddEdge(4, 5, 1)
+	assert.False(t, g.HasCycle())
+	assert.Len(t, g.StronglyConnectedComponents(), 2)
+
+	g.AddEdge(3, 1, 1)
+	assert.True(t, g.HasCycle())
+
+	assert.True(t, g.RemoveEdge(1, 3))
+	assert.False(t, g.HasEdge(3, 1))
+	assert.False(t, g.HasCycle())
+
+	g.AddEdge(5, 5, 1)
+	assert.True(t, g.HasCycle())
+	assert.Equal(t, "graph {\n\t\"1\";\n\t\"2\";\n\t\"3\";\n\t\"4\";\n\t\"5\";\n"+
+		"\t\"1\" -- \"2\" [weight=1];\n\t\"2\" -- \"3\" [weight=1];\n\t\"4\" -- \"5\" [weight=1];\n\t\"5\" -- \"5\" [weight=1];\n}\n", g.DOT())
+}
+
+func TestGraph_Traversal(t *testing.T) {
+	g := NewGraph[string](true)
+	for _, e := range [][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}, {"d", "e"}, {"x", "a"}} {
+		g.AddEdge(e[0], e[1], 1)
+	}
+
+	var bfs []string
+	var depths []int
+	g.BFS("a", func(n string, depth int) bool {
+		bfs = append(bfs, n)
+		depths = append(depths, depth)
+		return true
+	})
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, bfs)
+	assert.Equal(t, []int{0, 1, 1, 2, 3}, depths)
+
+	var dfs []string
+	g.DFS("a", func(n string) bool {
+		dfs = append(dfs, n)
+		return true
+	})
+	assert.Equal(t, []string{"a", "b", "d", "e", "c"}, dfs)
+
+	var visited []string
+	g.BFS("a", func(n string, _ int) bool {
+		visited = append(visited, n)
+		return len(visited) < 2
+	})
+	assert.Equal(t, []string{"a", "b"}, visited)
+	visited = nil
+	g.DFS("a", func(n string) bool {
+		visited = append(visited, n)
+		return false
+	})
+	assert.Equal(t, []string{"a"}, visited)
+
+	g.BFS("none", func(string, int) bool {
+		t.Fatal("visited")
+		return true
+	})
+	g.DFS("none", func(string) bool {
+		t.Fatal("visited")
+		return true
+	})
+}
+
+func TestGraph_ShortestPath(t *testing.T) {
+	g := NewGraph[string](true)
+	g.AddEdge("a", "b", 7)
+	g.AddEdge("a", "c", 9)
+	g.AddEdge("a", "f", 14)
+	g.AddEdge("b", "c", 10)
+	g.AddEdge("b", "d", 15)
+	g.AddEdge("c", "d", 11)
+	g.AddEdge("c", "f", 2)
+	g.AddEdge("d", "e", 6)
+	g.AddEdge("f", "e", 9)
+	g.AddNode("z")
+
+	path, distance, err := g.ShortestPath("a", "e")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "c", "f", "e"}, path)
+	assert.Equal(t, 20.0, distance)
+
+	path, distance, err = g.ShortestPath("a", "a")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a"}, path)
+	assert.Equal(t, 0.0, distance)
+
+	_, _, err = g.ShortestPath("e", "a")
+	assert.ErrorIs(t, err, ErrNoPath)
+	_, _, err = g.ShortestPath("a", "z")
+	assert.ErrorIs(t, err, ErrNoPath)
+	_, _, err = g.ShortestPath("x", "a")
+	assert.ErrorIs(t, err, ErrNodeNotFound)
+	_, _, err = g.ShortestPath("a", "x")
+	assert.ErrorIs(t, err, ErrNodeNotFound)
+
+	g.AddEdge("e", "a", -1)
+	_, _, err = g.ShortestPath("a", "e")
+	assert.ErrorIs(t, err, ErrNegativeWeight)
+}
+
+func TestGraph_Cycles(t *testing.T) {
+	g := NewGraph[int](true)
+	g.AddEdge(1, 2, 1)
+	g.AddEdge(2, 3, 1)
+	g.AddEdge(1, 3, 1)
+	assert.False(t, g.HasCycle())
+
+	g.AddEdge(3, 1, 1)
+	g.AddEdge(3, 4, 1)
+	g.AddEdge(4, 5, 1)
+	g.AddEdge(5, 4, 1)
+	g.AddNode(6)
+	assert.True(t, g.HasCycle())
+
+	components := g.StronglyConnectedComponents()
+	for _, c := range components {
+		sort.Ints(c)
+	}
+	sort.Slice(components, func(i, j int) bool {
+		return components[i][0] < components[j][0]
+	})
+	assert.Equal(t, [][]int{{1, 2, 3}, {4, 5}, {6}}, components)
+
+	self := NewGraph[int](true)
+	self.AddEdge(1, 1, 1)
+	assert.True(t, self.HasCycle())
+	assert.Equal(t, "digraph {\n\t\"1\";\n\t\"1\" -> \"1\" [weight=1];\n}\n", self.DOT())
+}
@@ -0,0 +1,240 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+import (
+	"cmp"
+	"math/rand"
+)
+
+type (
+	// An IntervalTree stores closed intervals [Lo, Hi] with a value,
+	// and finds those containing a point or overlapping an interval in O(log n + m),
+	// e.g. for scheduling conflict detection and IP or byte range lookups.
+	// It's a treap ordered by Lo and augmented with the max Hi of each subtree.
+	// It's not safe for concurrent use.
+	IntervalTree[K cmp.Ordered, V any] struct {
+		root *intervalNode[K, V]
+		size int
+	}
+
+	// An Interval is a closed interval [Lo, Hi] with a value.
+	Interval[K cmp.Ordered, V any] struct {
+		Lo, Hi K
+		Value  V
+	}
+
+	intervalNode[K cmp.Ordered, V any] struct {
+		interval    Interval[K, V]
+		priority    uint32
+		maxHi       K
+		left, right *intervalNode[K, V]
+	}
+)
+
+// NewIntervalTree returns an empty IntervalTree.
+func NewIntervalTree[K cmp.Ordered, V any]() *IntervalTree[K, V] {
+	return &IntervalTree[K, V]{}
+}
+
+// Len returns the number of intervals.
+func (t *IntervalTree[K, V]) Len() int {
+	return t.size
+}
+
+// Insert adds the interval [lo, hi] with value, it panics if lo > hi.
+// Intervals with the same bounds are kept apart.
+func (t *IntervalTree[K, V]) Insert(lo, hi K, value V) {
+	if lo > hi {
+		panic("xds: lo should not be greater than hi")
+	}
+
+	n := &intervalNode[K, V]{
+		interval: Interval[K, V]{Lo: lo, Hi: hi, Value: value},
+		priority: rand.Uint32(),
+		maxHi:    hi,
+	}
+	t.root = t.root.insert(n)
+	t.size++
+}
+
+// Delete removes one interval [lo, hi], it returns false if there is none.
+func (t *IntervalTree[K, V]) Delete(lo, hi K) bool {
+	var deleted bool
+	t.root, deleted = t.root.delete(lo, hi)
+	if deleted {
+		t.size--
+	}
+
+	return deleted
+}
+
+// Stab returns the intervals containing point, ordered by Lo.
+func (t *IntervalTree[K, V]) Stab(point K) []Interval[K, V] {
+	return t.Overlaps(point, point)
+}
+
+// Overlaps returns the intervals overlapping [lo, hi], ordered by Lo.
+func (t *IntervalTree[K, V]) Overlaps(lo, hi K) []Interval[K, V] {
+	var intervals []Interval[K, V]
+	t.root.overlaps(lo, hi, func(interval Interval[K, V]) {
+		intervals = append(intervals, interval)
+	})
+
+	return intervals
+}
+
+// AnyOverlap reports whether an interval overlaps [lo, hi].
+func (t *IntervalTree[K, V]) AnyOverlap(lo, hi K) bool {
+	for n := t.root; n != nil; {
+		if n.interval.Lo <= hi && lo <= n.interval.Hi {
+			return true
+		}
+		// the left subtree can only overlap if it reaches lo.
+		if n.left != nil && n.left.maxHi >= lo {
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+
+	return false
+}
+
+// Range calls fn for each interval ordered by Lo until fn returns false.
+func (t *IntervalTree[K, V]) Range(fn func(interval Interval[K, V]) bool) {
+	t.root.walk(fn)
+}
+
+func (n *intervalNode[K, V]) less(lo, hi K) bool {
+	if n.interval.Lo != lo {
+		return n.interval.Lo < lo
+	}
+
+	return n.interval.Hi < hi
+}
+
+func (n *intervalNode[K, V]) update() {
+	n.maxHi = n.interval.Hi
+	if n.left != nil && n.left.maxHi > n.maxHi {
+		n.maxHi = n.left.maxHi
+	}
+	if n.right != nil && n.right.maxHi > n.maxHi {
+		n.maxHi = n.right.maxHi
+	}
+}
+
+func (n *intervalNode[K, V]) rotateRight() *intervalNode[K, V] {
+	l := n.left
+	n.left = l.right
+	l.right = n
+	n.update()
+	l.update()
+
+	return l
+}
+
+func (n *intervalNode[K, V]) rotateLeft() *intervalNode[K, V] {
+	r := n.right
+	n.right = r.left
+	r.left = n
+	n.update()
+	r.update()
+
+	return r
+}
+
+func (n *intervalNode[K, V]) insert(node *intervalNode[K, V]) *intervalNode[K, V] {
+	if n == nil {
+		return node
+	}
+
+	if n.less(node.interval.Lo, node.interval.Hi) {
+		n.right = n.right.insert(node)
+		if n.right.priority > n.priority {
+			return n.rotateLeft()
+		}
+	} else {
+		n.left = n.left.insert(node)
+		if n.left.priority > n.priority {
+			return n.rotateRight()
+		}
+	}
+	n.update()
+
+	return n
+}
+
+func (n *intervalNode[K, V]) delete(lo, hi K) (*intervalNode[K, V], bool) {
+	if n == nil {
+		return nil, false
+	}
+
+	var deleted bool
+	switch {
+	case n.interval.Lo == lo && n.interval.Hi == hi:
+		return n.left.merge(n.right), true
+	case n.less(lo, hi):
+		n.right, deleted = n.right.delete(lo, hi)
+	default:
+		n.left, deleted = n.left.delete(lo, hi)
+	}
+	n.update()
+
+	return n, deleted
+}
+
+// merge joins two treaps, all the intervals of n are ordered before those of other.
+func (n *intervalNode[K, V]) merge(other *intervalNode[K, V]) *intervalNode[K, V] {
+	switch {
+	case n == nil:
+		return other
+	case other == nil:
+		return n
+	case n.priority > other.priority:
+		n.right = n.right.merge(other)
+		n.update()
+		return n
+	default:
+		other.left = n.merge(other.left)
+		other.update()
+		return other
+	}
+}
+
+func (n *intervalNode[K, V]) overlaps(lo, hi K, fn func(interval Interval[K, V])) {
+	if n == nil || n.maxHi < lo {
+		return
+	}
+
+	n.left.overlaps(lo, hi, fn)
+	if n.interval.Lo <= hi && lo <= n.interval.Hi {
+		fn(n.interval)
+	}
+	// the intervals on the right start after n.
+	if n.interval.Lo <= hi {
+		n.right.overlaps(lo, hi, fn)
+	}
+}
+
+func (n *intervalNode[K, V]) walk(fn func(interval Interval[K, V]) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	return n.left.walk(fn) && fn(n.interval) && n.right.walk(fn)
+}
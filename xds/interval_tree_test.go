@@ -0,0 +1,106 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestIntervalTree(t *testing.T) {
+	tree := NewIntervalTree[int, string]()
+	assert.Empty(t, tree.Stab(1))
+	assert.False(t, tree.AnyOverlap(0, 10))
+
+	tree.Insert(9, 12, "standup")
+	tree.Insert(10, 11, "review")
+	tree.Insert(13, 14, "lunch")
+	tree.Insert(15, 18, "planning")
+	tree.Insert(13, 14, "lunch2")
+	assert.Equal(t, 5, tree.Len())
+
+	values := func(intervals []Interval[int, string]) []string {
+		var values []string
+		for _, interval := range intervals {
+			values = append(values, interval.Value)
+		}
+		sort.Strings(values)
+		return values
+	}
+
+	assert.Equal(t, []string{"review", "standup"}, values(tree.Stab(10)))
+	assert.Equal(t, []string{"standup"}, values(tree.Stab(12)))
+	assert.Empty(t, tree.Stab(8))
+	assert.Equal(t, []string{"lunch", "lunch2", "planning", "standup"}, values(tree.Overlaps(12, 15)))
+	assert.True(t, tree.AnyOverlap(18, 20))
+	assert.False(t, tree.AnyOverlap(19, 20))
+
+	var los []int
+	tree.Range(func(interval Interval[int, string]) bool {
+		los = append(los, interval.Lo)
+		return true
+	})
+	assert.Equal(t, []int{9, 10, 13, 13, 15}, los)
+
+	assert.True(t, tree.Delete(13, 14))
+	assert.True(t, tree.Delete(13, 14))
+	assert.False(t, tree.Delete(13, 14))
+	assert.Equal(t, 3, tree.Len())
+	assert.Empty(t, tree.Stab(13))
+
+	assert.Panics(t, func() {
+		tree.Insert(2, 1, "")
+	})
+}
+
+func TestIntervalTree_Random(t *testing.T) {
+	type interval struct{ lo, hi int }
+	tree := NewIntervalTree[int, int]()
+	var reference []interval
+	for i := 0; i < 500; i++ {
+		lo := rand.Intn(1000)
+		hi := lo + rand.Intn(50)
+		tree.Insert(lo, hi, i)
+		reference = append(reference, interval{lo, hi})
+	}
+	for i := 0; i < 100; i++ {
+		j := rand.Intn(len(reference))
+		assert.True(t, tree.Delete(reference[j].lo, reference[j].hi))
+		reference = append(reference[:j], reference[j+1:]...)
+	}
+	assert.Equal(t, len(reference), tree.Len())
+
+	for i := 0; i < 200; i++ {
+		lo := rand.Intn(1100) - 50
+		hi := lo + rand.Intn(30)
+		expected := 0
+		for _, r := range reference {
+			if r.lo <= hi && lo <= r.hi {
+				expected++
+			}
+		}
+
+		overlaps := tree.Overlaps(lo, hi)
+		assert.Len(t, overlaps, expected)
+		assert.Equal(t, expected > 0, tree.AnyOverlap(lo, hi))
+		assert.True(t, sort.SliceIsSorted(overlaps, func(i, j int) bool {
+			return overlaps[i].Lo < overlaps[j].Lo
+		}))
+	}
+}
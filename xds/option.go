@@ -0,0 +1,47 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+type (
+	// Option defines the method to customize the containers.
+	Option func(*options)
+
+	options struct {
+		capacity int
+	}
+)
+
+// WithCapacity bounds the number of elements to n, pushing into a full container fails
+// unless documented otherwise.
+// A non-positive n means unbounded, which is the default.
+func WithCapacity(n int) Option {
+	return func(o *options) {
+		o.capacity = n
+	}
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.capacity < 0 {
+		o.capacity = 0
+	}
+
+	return o
+}
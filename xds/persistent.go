@@ -0,0 +1,435 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+import (
+	"github.com/chenquan/go-pkg/internal/hashing"
+	"hash/maphash"
+	"math"
+	"math/bits"
+	"reflect"
+)
+
+const (
+	hamtBits  = 5
+	hamtMask  = 1<<hamtBits - 1
+	hamtDepth = 64 / hamtBits
+)
+
+var hashSeed = maphash.MakeSeed()
+
+type (
+	// A List is an immutable singly linked list, the zero value is an empty List.
+	// Push returns a new List that shares the elements of the old one,
+	// so keeping old versions, e.g. for an undo history, is cheap.
+	List[T any] struct {
+		head *listNode[T]
+		len  int
+	}
+
+	listNode[T any] struct {
+		value T
+		next  *listNode[T]
+	}
+
+	// A Map is an immutable hash array mapped trie, the zero value is an empty Map.
+	// Set and Delete return a new Map that shares all but O(log n) nodes with the old one,
+	// so snapshots of config don't require deep copies. It's safe for concurrent use.
+	Map[K comparable, V any] struct {
+		root *hamtNode[K, V]
+		size int
+		hash func(K) uint64
+	}
+
+	hamtNode[K comparable, V any] struct {
+		bitmap  uint32
+		entries []hamtEntry[K, V]
+	}
+
+	hamtEntry[K comparable, V any] struct {
+		hash  uint64
+		key   K
+		value V
+		child *hamtNode[K, V]
+	}
+)
+
+// ListOf returns a List of values in the same order.
+func ListOf[T any](values ...T) List[T] {
+	var l List[T]
+	for i := len(values) - 1; i >= 0; i-- {
+		l = l.Push(values[i])
+	}
+
+	return l
+}
+
+// Len returns the number of elements.
+func (l List[T]) Len() int {
+	return l.len
+}
+
+// Push returns a List with v in front of the elements of l.
+func (l List[T]) Push(v T) List[T] {
+	return List[T]{head: &listNode[T]{value: v, next: l.head}, len: l.len + 1}
+}
+
+// Head returns the first element.
+func (l List[T]) Head() (T, bool) {
+	if l.head == nil {
+		var zero T
+		return zero, false
+	}
+
+	return l.head.value, true
+}
+
+// Tail returns the List without its first element.
+func (l List[T]) Tail() List[T] {
+	if l.head == nil {
+		return l
+	}
+
+	return List[T]{head: l.head.next, len: l.len - 1}
+}
+
+// Reverse returns a List with the elements of l in reverse order.
+func (l List[T]) Reverse() List[T] {
+	var r List[T]
+	for n := l.head; n != nil; n = n.next {
+		r = r.Push(n.value)
+	}
+
+	return r
+}
+
+// Range calls fn for each element until fn returns false.
+func (l List[T]) Range(fn func(v T) bool) {
+	for n := l.head; n != nil; n = n.next {
+		if !fn(n.value) {
+			return
+		}
+	}
+}
+
+// Slice returns the elements.
+func (l List[T]) Slice() []T {
+	s := make([]T, 0, l.len)
+	for n := l.head; n != nil; n = n.next {
+		s = append(s, n.value)
+	}
+
+	return s
+}
+
+// NewMap returns an empty Map.
+// The keys are hashed with hash/maphash, strings and integers take a fast path
+// and other comparable types are hashed by reflection.
+func NewMap[K comparable, V any]() Map[K, V] {
+	return Map[K, V]{}
+}
+
+// NewMapWithHasher returns an empty Map that hashes the keys with hash.
+// Equal keys must have the same hash.
+func NewMapWithHasher[K comparable, V any](hash func(K) uint64) Map[K, V] {
+	return Map[K, V]{hash: hash}
+}
+
+// Len returns the number of keys.
+func (m Map[K, V]) Len() int {
+	return m.size
+}
+
+// Get returns the value of key.
+func (m Map[K, V]) Get(key K) (V, bool) {
+	var zero V
+	if m.root == nil {
+		return zero, false
+	}
+
+	hash := m.hasher()(key)
+	n := m.root
+	for shift := uint(0); ; shift += hamtBits {
+		if shift >= hamtDepth*hamtBits {
+			for _, e := range n.entries {
+				if e.key == key {
+					return e.value, true
+				}
+			}
+			return zero, false
+		}
+
+		bit := uint32(1) << ((hash >> shift) & hamtMask)
+		if n.bitmap&bit == 0 {
+			return zero, false
+		}
+
+		e := &n.entries[bits.OnesCount32(n.bitmap&(bit-1))]
+		if e.child == nil {
+			if e.key == key {
+				return e.value, true
+			}
+			return zero, false
+		}
+		n = e.child
+	}
+}
+
+// Set returns a Map with key set to value.
+func (m Map[K, V]) Set(key K, value V) Map[K, V] {
+	hash := m.hasher()
+	root, added := m.root.set(0, hamtEntry[K, V]{hash: hash(key), key: key, value: value})
+	size := m.size
+	if added {
+		size++
+	}
+
+	return Map[K, V]{root: root, size: size, hash: hash}
+}
+
+// Delete returns a Map without key.
+func (m Map[K, V]) Delete(key K) Map[K, V] {
+	if m.root == nil {
+		return m
+	}
+
+	root, deleted := m.root.delete(0, m.hasher()(key), key)
+	if !deleted {
+		return m
+	}
+	if root != nil && len(root.entries) == 0 {
+		root = nil
+	}
+
+	return Map[K, V]{root: root, size: m.size - 1, hash: m.hash}
+}
+
+// Range calls fn for each key and value in no particular order until fn returns false.
+func (m Map[K, V]) Range(fn func(key K, value V) bool) {
+	m.root.walk(fn)
+}
+
+func (m Map[K, V]) hasher() func(K) uint64 {
+	if m.hash != nil {
+		return m.hash
+	}
+
+	return defaultHasher[K]()
+}
+
+func (n *hamtNode[K, V]) set(shift uint, e hamtEntry[K, V]) (*hamtNode[K, V], bool) {
+	if n == nil {
+		n = &hamtNode[K, V]{}
+	}
+
+	if shift >= hamtDepth*hamtBits {
+		for i, old := range n.entries {
+			if old.key == e.key {
+				return n.replace(i, e), false
+			}
+		}
+		return n.insert(len(n.entries), 0, e), true
+	}
+
+	bit := uint32(1) << ((e.hash >> shift) & hamtMask)
+	pos := bits.OnesCount32(n.bitmap & (bit - 1))
+	if n.bitmap&bit == 0 {
+		return n.insert(pos, bit, e), true
+	}
+
+	old := n.entries[pos]
+	switch {
+	case old.child != nil:
+		child, added := old.child.set(shift+hamtBits, e)
+		return n.replace(pos, hamtEntry[K, V]{child: child}), added
+	case old.key == e.key:
+		return n.replace(pos, e), false
+	default:
+		child, _ := (*hamtNode[K, V])(nil).set(shift+hamtBits, old)
+		child, _ = child.set(shift+hamtBits, e)
+		return n.replace(pos, hamtEntry[K, V]{child: child}), true
+	}
+}
+
+func (n *hamtNode[K, V]) delete(shift uint, hash uint64, key K) (*hamtNode[K, V], bool) {
+	if shift >= hamtDepth*hamtBits {
+		for i, e := range n.entries {
+			if e.key == key {
+				return n.remove(i, 0), true
+			}
+		}
+		return n, false
+	}
+
+	bit := uint32(1) << ((hash >> shift) & hamtMask)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+
+	pos := bits.OnesCount32(n.bitmap & (bit - 1))
+	e := n.entries[pos]
+	if e.child == nil {
+		if e.key != key {
+			return n, false
+		}
+		return n.remove(pos, bit), true
+	}
+
+	child, deleted := e.child.delete(shift+hamtBits, hash, key)
+	if !deleted {
+		return n, false
+	}
+
+	switch {
+	case len(child.entries) == 0:
+		return n.remove(pos, bit), true
+	case len(child.entries) == 1 && child.entries[0].child == nil:
+		// pull a lone leaf up.
+		return n.replace(pos, child.entries[0]), true
+	default:
+		return n.replace(pos, hamtEntry[K, V]{child: child}), true
+	}
+}
+
+func (n *hamtNode[K, V]) replace(pos int, e hamtEntry[K, V]) *hamtNode[K, V] {
+	entries := make([]hamtEntry[K, V], len(n.entries))
+	copy(entries, n.entries)
+	entries[pos] = e
+
+	return &hamtNode[K, V]{bitmap: n.bitmap, entries: entries}
+}
+
+func (n *hamtNode[K, V]) insert(pos int, bit uint32, e hamtEntry[K, V]) *hamtNode[K, V] {
+	entries := make([]hamtEntry[K, V], len(n.entries)+1)
+	copy(entries, n.entries[:pos])
+	entries[pos] = e
+	copy(entries[pos+1:], n.entries[pos:])
+
+	return &hamtNode[K, V]{bitmap: n.bitmap | bit, entries: entries}
+}
+
+func (n *hamtNode[K, V]) remove(pos int, bit uint32) *hamtNode[K, V] {
+	entries := make([]hamtEntry[K, V], 0, len(n.entries)-1)
+	entries = append(entries, n.entries[:pos]...)
+	entries = append(entries, n.entries[pos+1:]...)
+
+	return &hamtNode[K, V]{bitmap: n.bitmap &^ bit, entries: entries}
+}
+
+func (n *hamtNode[K, V]) walk(fn func(key K, value V) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	for _, e := range n.entries {
+		if e.child != nil {
+			if !e.child.walk(fn) {
+				return false
+			}
+		} else if !fn(e.key, e.value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func defaultHasher[K comparable]() func(K) uint64 {
+	var zero K
+	switch any(zero).(type) {
+	case string:
+		return func(key K) uint64 {
+			return maphash.String(hashSeed, any(key).(string))
+		}
+	case int:
+		return func(key K) uint64 {
+			return hashing.Mix64(uint64(any(key).(int)))
+		}
+	case int64:
+		return func(key K) uint64 {
+			return hashing.Mix64(uint64(any(key).(int64)))
+		}
+	case uint64:
+		return func(key K) uint64 {
+			return hashing.Mix64(any(key).(uint64))
+		}
+	default:
+		return func(key K) uint64 {
+			var h maphash.Hash
+			h.SetSeed(hashSeed)
+			hashValue(&h, reflect.ValueOf(&key).Elem())
+			return h.Sum64()
+		}
+	}
+}
+
+// hashValue writes a comparable value into h, so that equal values give the same hash.
+func hashValue(h *maphash.Hash, v reflect.Value) {
+	var buf [8]byte
+	writeUint := func(x uint64) {
+		for i := range buf {
+			buf[i] = byte(x >> (8 * i))
+		}
+		_, _ = h.Write(buf[:])
+	}
+	writeFloat := func(f float64) {
+		if f == 0 {
+			// +0 and -0 are equal.
+			f = 0
+		}
+		writeUint(math.Float64bits(f))
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			_ = h.WriteByte(1)
+		} else {
+			_ = h.WriteByte(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeFloat(v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		writeFloat(real(c))
+		writeFloat(imag(c))
+	case reflect.String:
+		_, _ = h.WriteString(v.String())
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		writeUint(uint64(v.Pointer()))
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			hashValue(h, v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			hashValue(h, v.Field(i))
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			_ = h.WriteByte(0)
+			return
+		}
+		elem := v.Elem()
+		_, _ = h.WriteString(elem.Type().String())
+		hashValue(h, elem)
+	}
+}
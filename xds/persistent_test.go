@@ -0,0 +1,211 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestList(t *testing.T) {
+	var empty List[int]
+	assert.Equal(t, 0, empty.Len())
+	_, ok := empty.Head()
+	assert.False(t, ok)
+	assert.Equal(t, 0, empty.Tail().Len())
+	assert.Empty(t, empty.Slice())
+
+	l := ListOf(1, 2, 3)
+	assert.Equal(t, 3, l.Len())
+	assert.Equal(t, []int{1, 2, 3}, l.Slice())
+
+	l2 := l.Push(0)
+	tail := l.Tail()
+	assert.Equal(t, []int{0, 1, 2, 3}, l2.Slice())
+	assert.Equal(t, []int{1, 2, 3}, l.Slice())
+	assert.Equal(t, []int{2, 3}, tail.Slice())
+	// the versions share their elements.
+	assert.Same(t, l.head, l2.head.next)
+
+	head, ok := l2.Head()
+	assert.True(t, ok)
+	assert.Equal(t, 0, head)
+	assert.Equal(t, []int{3, 2, 1}, l.Reverse().Slice())
+
+	var visited []int
+	l2.Range(func(v int) bool {
+		visited = append(visited, v)
+		return v < 1
+	})
+	assert.Equal(t, []int{0, 1}, visited)
+}
+
+func TestMap(t *testing.T) {
+	var empty Map[string, int]
+	_, ok := empty.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, empty.Delete("a").Len())
+
+	m1 := NewMap[string, int]().Set("a", 1).Set("b", 2)
+	m2 := m1.Set("a", 10).Set("c", 3)
+	m3 := m2.Delete("b")
+
+	assert.Equal(t, 2, m1.Len())
+	assert.Equal(t, 3, m2.Len())
+	assert.Equal(t, 2, m3.Len())
+
+	v, _ := m1.Get("a")
+	assert.Equal(t, 1, v)
+	v, _ = m2.Get("a")
+	assert.Equal(t, 10, v)
+	_, ok = m3.Get("b")
+	assert.False(t, ok)
+	_, ok = m2.Get("b")
+	assert.True(t, ok)
+	assert.Same(t, m3.root, m3.Delete("missing").root)
+
+	snapshot := map[string]int{}
+	m2.Range(func(k string, v int) bool {
+		snapshot[k] = v
+		return true
+	})
+	assert.Equal(t, map[string]int{"a": 10, "b": 2, "c": 3}, snapshot)
+
+	count := 0
+	m2.Range(func(string, int) bool {
+		count++
+		return false
+	})
+	assert.Equal(t, 1, count)
+
+	assert.Nil(t, m1.Delete("a").Delete("b").root)
+}
+
+func TestMap_Random(t *testing.T) {
+	m := NewMap[int, int]()
+	reference := map[int]int{}
+	versions := []Map[int, int]{m}
+	snapshots := []map[int]int{{}}
+	for i := 0; i < 5000; i++ {
+		k := rand.Intn(1000)
+		if rand.Intn(3) == 0 {
+			m = m.Delete(k)
+			delete(reference, k)
+		} else {
+			m = m.Set(k, i)
+			reference[k] = i
+		}
+		if i%500 == 0 {
+			snapshot := make(map[int]int, len(reference))
+			for k, v := range reference {
+				snapshot[k] = v
+			}
+			versions = append(versions, m)
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	versions = append(versions, m)
+	snapshots = append(snapshots, reference)
+
+	// every version is intact.
+	for i, version := range versions {
+		assert.Equal(t, len(snapshots[i]), version.Len())
+		for k, v := range snapshots[i] {
+			got, ok := version.Get(k)
+			assert.True(t, ok)
+			assert.Equal(t, v, got)
+		}
+	}
+}
+
+func TestMap_Collision(t *testing.T) {
+	m := NewMapWithHasher[string, int](func(string) uint64 {
+		return 42
+	})
+	for i := 0; i < 10; i++ {
+		m = m.Set(fmt.Sprint(i), i)
+	}
+	assert.Equal(t, 10, m.Len())
+	for i := 0; i < 10; i++ {
+		v, ok := m.Get(fmt.Sprint(i))
+		assert.True(t, ok)
+		assert.Equal(t, i, v)
+	}
+	_, ok := m.Get("x")
+	assert.False(t, ok)
+	assert.Equal(t, 10, m.Delete("x").Len())
+
+	for i := 0; i < 9; i++ {
+		m = m.Delete(fmt.Sprint(i))
+	}
+	assert.Equal(t, 1, m.Len())
+	v, _ := m.Get("9")
+	assert.Equal(t, 9, v)
+	// the last leaf is pulled up to the root.
+	assert.Nil(t, m.root.entries[0].child)
+}
+
+func TestMap_Keys(t *testing.T) {
+	type point struct {
+		X, Y float64
+		Name interface{}
+	}
+	m := NewMap[point, string]().
+		Set(point{X: 1, Y: 2, Name: "a"}, "a").
+		Set(point{X: math.Copysign(0, -1), Y: 0}, "zero").
+		Set(point{X: 1, Y: 2, Name: 3}, "b")
+
+	v, ok := m.Get(point{X: 1, Y: 2, Name: "a"})
+	assert.True(t, ok)
+	assert.Equal(t, "a", v)
+	v, _ = m.Get(point{X: 1, Y: 2, Name: 3})
+	assert.Equal(t, "b", v)
+	v, ok = m.Get(point{})
+	assert.True(t, ok)
+	assert.Equal(t, "zero", v)
+
+	one, two := 1, 1
+	pointers := NewMap[*int, int]().Set(&one, 1)
+	_, ok = pointers.Get(&one)
+	assert.True(t, ok)
+	_, ok = pointers.Get(&two)
+	assert.False(t, ok)
+
+	mixed := NewMap[interface{}, int]().Set(1, 1).Set("1", 2).Set([2]bool{true}, 3).Set(complex(1, 2), 4).Set(uint8(1), 5).Set(nil, 6)
+	for key, expected := range map[interface{}]int{1: 1, "1": 2, [2]bool{true}: 3, complex(1, 2): 4, uint8(1): 5, nil: 6} {
+		v, ok := mixed.Get(key)
+		assert.True(t, ok)
+		assert.Equal(t, expected, v)
+	}
+
+	u := NewMap[uint64, int]().Set(1, 1)
+	_, ok = u.Get(1)
+	assert.True(t, ok)
+	i64 := NewMap[int64, int]().Set(1, 1)
+	_, ok = i64.Get(1)
+	assert.True(t, ok)
+}
+
+func BenchmarkMap_Set(b *testing.B) {
+	m := NewMap[int, int]()
+	for i := 0; i < b.N; i++ {
+		m = m.Set(i, i)
+	}
+}
@@ -0,0 +1,229 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+type (
+	// A PriorityQueue is a binary heap ordered by a less func, Pop returns the least element first.
+	// It's not safe for concurrent use.
+	//
+	// With WithCapacity(k) it works as a top-k: once full, an element greater than the least one
+	// evicts it and a lesser one is rejected, so it keeps the k greatest elements pushed.
+	PriorityQueue[T any] struct {
+		items    []*Handle[T]
+		less     func(a, b T) bool
+		capacity int
+	}
+
+	// A Handle refers to an element of a PriorityQueue, to update or remove it.
+	Handle[T any] struct {
+		value T
+		index int
+	}
+)
+
+// NewPriorityQueue returns an empty PriorityQueue ordered by less.
+func NewPriorityQueue[T any](less func(a, b T) bool, opts ...Option) *PriorityQueue[T] {
+	return &PriorityQueue[T]{less: less, capacity: newOptions(opts).capacity}
+}
+
+// Value returns the value of the element.
+func (h *Handle[T]) Value() T {
+	return h.value
+}
+
+// Len returns the number of elements.
+func (q *PriorityQueue[T]) Len() int {
+	return len(q.items)
+}
+
+// Push adds v and returns its Handle, or nil if it's rejected by a full top-k.
+func (q *PriorityQueue[T]) Push(v T) *Handle[T] {
+	if q.capacity > 0 && len(q.items) >= q.capacity {
+		if !q.less(q.items[0].value, v) {
+			return nil
+		}
+
+		// evict the least element.
+		q.items[0].index = -1
+		h := &Handle[T]{value: v}
+		q.items[0] = h
+		q.down(0)
+
+		return h
+	}
+
+	h := &Handle[T]{value: v, index: len(q.items)}
+	q.items = append(q.items, h)
+	q.up(h.index)
+
+	return h
+}
+
+// Pop removes and returns the least element.
+func (q *PriorityQueue[T]) Pop() (T, bool) {
+	if len(q.items) == 0 {
+		var zero T
+		return zero, false
+	}
+
+	return q.remove(0), true
+}
+
+// Peek returns the least element without removing it.
+func (q *PriorityQueue[T]) Peek() (T, bool) {
+	if len(q.items) == 0 {
+		var zero T
+		return zero, false
+	}
+
+	return q.items[0].value, true
+}
+
+// Update changes the value of the element h refers to and restores the order,
+// e.g. to decrease a key. It returns false if h has been removed.
+func (q *PriorityQueue[T]) Update(h *Handle[T], v T) bool {
+	if !q.contains(h) {
+		return false
+	}
+
+	h.value = v
+	if !q.up(h.index) {
+		q.down(h.index)
+	}
+
+	return true
+}
+
+// Fix restores the order after the value of the element h refers to has changed in place,
+// e.g. through a pointer. It returns false if h has been removed.
+func (q *PriorityQueue[T]) Fix(h *Handle[T]) bool {
+	if !q.contains(h) {
+		return false
+	}
+
+	if !q.up(h.index) {
+		q.down(h.index)
+	}
+
+	return true
+}
+
+// Remove removes the element h refers to, it returns false if h has been removed.
+func (q *PriorityQueue[T]) Remove(h *Handle[T]) bool {
+	if !q.contains(h) {
+		return false
+	}
+
+	q.remove(h.index)
+
+	return true
+}
+
+// Max returns the handle of the greatest element, the last one Pop would return.
+// It scans the leaves of the heap so it takes O(n).
+func (q *PriorityQueue[T]) Max() (*Handle[T], bool) {
+	n := len(q.items)
+	if n == 0 {
+		return nil, false
+	}
+
+	last := n / 2
+	for i := last + 1; i < n; i++ {
+		if q.less(q.items[last].value, q.items[i].value) {
+			last = i
+		}
+	}
+
+	return q.items[last], true
+}
+
+// Clear removes all the elements, their handles become invalid.
+func (q *PriorityQueue[T]) Clear() {
+	for _, h := range q.items {
+		h.index = -1
+	}
+	clear(q.items)
+	q.items = q.items[:0]
+}
+
+// Range calls fn for each element in no particular order until fn returns false.
+func (q *PriorityQueue[T]) Range(fn func(v T) bool) {
+	for _, h := range q.items {
+		if !fn(h.value) {
+			return
+		}
+	}
+}
+
+func (q *PriorityQueue[T]) contains(h *Handle[T]) bool {
+	return h != nil && h.index >= 0 && h.index < len(q.items) && q.items[h.index] == h
+}
+
+func (q *PriorityQueue[T]) remove(i int) T {
+	h := q.items[i]
+	last := len(q.items) - 1
+	if i != last {
+		q.swap(i, last)
+	}
+	q.items[last] = nil
+	q.items = q.items[:last]
+	if i != last && !q.up(i) {
+		q.down(i)
+	}
+	h.index = -1
+
+	return h.value
+}
+
+// up moves the element at i up, it reports whether the element moved.
+func (q *PriorityQueue[T]) up(i int) bool {
+	start := i
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !q.less(q.items[i].value, q.items[parent].value) {
+			break
+		}
+		q.swap(i, parent)
+		i = parent
+	}
+
+	return i != start
+}
+
+func (q *PriorityQueue[T]) down(i int) {
+	n := len(q.items)
+	for {
+		least := i
+		if l := 2*i + 1; l < n && q.less(q.items[l].value, q.items[least].value) {
+			least = l
+		}
+		if r := 2*i + 2; r < n && q.less(q.items[r].value, q.items[least].value) {
+			least = r
+		}
+		if least == i {
+			return
+		}
+		q.swap(i, least)
+		i = least
+	}
+}
+
+func (q *PriorityQueue[T]) swap(i, j int) {
+	q.items[i], q.items[j] = q.items[j], q.items[i]
+	q.items[i].index = i
+	q.items[j].index = j
+}
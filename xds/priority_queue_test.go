@@ -0,0 +1,172 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func lessInt(a, b int) bool {
+	return a < b
+}
+
+func popAll[T any](q *PriorityQueue[T]) []T {
+	var elements []T
+	for q.Len() > 0 {
+		v, _ := q.Pop()
+		elements = append(elements, v)
+	}
+	return elements
+}
+
+func TestPriorityQueue(t *testing.T) {
+	q := NewPriorityQueue(lessInt)
+	_, ok := q.Pop()
+	assert.False(t, ok)
+	_, ok = q.Peek()
+	assert.False(t, ok)
+
+	values := rand.Perm(100)
+	for _, v := range values {
+		assert.Equal(t, v, q.Push(v).Value())
+	}
+	assert.Equal(t, 100, q.Len())
+	v, _ := q.Peek()
+	assert.Equal(t, 0, v)
+
+	count := 0
+	q.Range(func(int) bool {
+		count++
+		return count < 10
+	})
+	assert.Equal(t, 10, count)
+
+	sort.Ints(values)
+	assert.Equal(t, values, popAll(q))
+}
+
+func TestPriorityQueue_Handle(t *testing.T) {
+	type task struct {
+		name     string
+		priority int
+	}
+	q := NewPriorityQueue(func(a, b task) bool {
+		return a.priority < b.priority
+	})
+
+	a := q.Push(task{"a", 5})
+	b := q.Push(task{"b", 3})
+	c := q.Push(task{"c", 8})
+	d := q.Push(task{"d", 1})
+
+	// decrease key.
+	assert.True(t, q.Update(c, task{"c", 0}))
+	// increase key.
+	assert.True(t, q.Update(d, task{"d", 9}))
+	assert.True(t, q.Remove(b))
+	assert.False(t, q.Remove(b))
+	assert.False(t, q.Update(b, task{"b", 1}))
+	assert.False(t, q.Remove(nil))
+
+	var names []string
+	for _, v := range popAll(q) {
+		names = append(names, v.name)
+	}
+	assert.Equal(t, []string{"c", "a", "d"}, names)
+	assert.False(t, q.Remove(a))
+}
+
+func TestPriorityQueue_FixMaxClear(t *testing.T) {
+	type task struct {
+		priority int
+	}
+	q := NewPriorityQueue(func(a, b *task) bool {
+		return a.priority < b.priority
+	})
+	_, ok := q.Max()
+	assert.False(t, ok)
+
+	a := q.Push(&task{5})
+	q.Push(&task{3})
+	c := q.Push(&task{8})
+	q.Push(&task{1})
+
+	max, ok := q.Max()
+	assert.True(t, ok)
+	assert.Equal(t, c, max)
+
+	a.Value().priority = 0
+	assert.True(t, q.Fix(a))
+	v, _ := q.Peek()
+	assert.Equal(t, 0, v.priority)
+
+	q.Clear()
+	assert.Equal(t, 0, q.Len())
+	assert.False(t, q.Fix(a))
+	assert.False(t, q.Remove(c))
+	_, ok = q.Max()
+	assert.False(t, ok)
+}
+
+func TestPriorityQueue_Random(t *testing.T) {
+	q := NewPriorityQueue(lessInt)
+	var handles []*Handle[int]
+	for i := 0; i < 500; i++ {
+		handles = append(handles, q.Push(rand.Intn(1000)))
+	}
+	for i, h := range handles {
+		switch i % 3 {
+		case 0:
+			q.Remove(h)
+		case 1:
+			q.Update(h, rand.Intn(1000))
+		}
+	}
+
+	elements := popAll(q)
+	assert.Len(t, elements, 333)
+	assert.True(t, sort.IntsAreSorted(elements))
+}
+
+func TestPriorityQueue_TopK(t *testing.T) {
+	q := NewPriorityQueue(lessInt, WithCapacity(3))
+	for _, v := range []int{5, 1, 9, 3, 7, 2, 8} {
+		q.Push(v)
+	}
+	assert.Equal(t, 3, q.Len())
+
+	assert.Nil(t, q.Push(4))
+	evicted, _ := q.Peek()
+	h := q.Push(10)
+	assert.NotNil(t, h)
+	assert.Equal(t, 7, evicted)
+
+	assert.Equal(t, []int{8, 9, 10}, popAll(q))
+}
+
+func BenchmarkPriorityQueue(b *testing.B) {
+	q := NewPriorityQueue(lessInt)
+	for i := 0; i < b.N; i++ {
+		q.Push(rand.Int())
+		if i%2 == 0 {
+			q.Pop()
+		}
+	}
+}
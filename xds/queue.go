@@ -0,0 +1,62 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+// A Queue is a first-in-first-out queue, it's not safe for concurrent use.
+type Queue[T any] struct {
+	deque Deque[T]
+}
+
+// NewQueue returns an empty Queue.
+func NewQueue[T any](opts ...Option) *Queue[T] {
+	return &Queue[T]{deque: Deque[T]{capacity: newOptions(opts).capacity}}
+}
+
+// Len returns the number of elements.
+func (q *Queue[T]) Len() int {
+	return q.deque.Len()
+}
+
+// Full reports whether q is bounded and full.
+func (q *Queue[T]) Full() bool {
+	return q.deque.Full()
+}
+
+// Push adds v at the back, it returns false if q is full.
+func (q *Queue[T]) Push(v T) bool {
+	return q.deque.PushBack(v)
+}
+
+// Pop removes and returns the front element.
+func (q *Queue[T]) Pop() (T, bool) {
+	return q.deque.PopFront()
+}
+
+// Peek returns the front element without removing it.
+func (q *Queue[T]) Peek() (T, bool) {
+	return q.deque.PeekFront()
+}
+
+// Range calls fn for each element from front to back until fn returns false.
+func (q *Queue[T]) Range(fn func(v T) bool) {
+	q.deque.Range(fn)
+}
+
+// Clear removes all elements.
+func (q *Queue[T]) Clear() {
+	q.deque.Clear()
+}
@@ -0,0 +1,64 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestQueue(t *testing.T) {
+	q := NewQueue[int]()
+	_, ok := q.Pop()
+	assert.False(t, ok)
+
+	for i := 0; i < 20; i++ {
+		assert.True(t, q.Push(i))
+	}
+	assert.Equal(t, 20, q.Len())
+	v, ok := q.Peek()
+	assert.True(t, ok)
+	assert.Equal(t, 0, v)
+
+	var elements []int
+	q.Range(func(v int) bool {
+		elements = append(elements, v)
+		return v < 2
+	})
+	assert.Equal(t, []int{0, 1, 2}, elements)
+
+	for i := 0; i < 20; i++ {
+		v, ok := q.Pop()
+		assert.True(t, ok)
+		assert.Equal(t, i, v)
+	}
+	assert.Equal(t, 0, q.Len())
+
+	q.Push(1)
+	q.Clear()
+	_, ok = q.Peek()
+	assert.False(t, ok)
+}
+
+func TestQueue_Capacity(t *testing.T) {
+	q := NewQueue[int](WithCapacity(1))
+	assert.True(t, q.Push(1))
+	assert.True(t, q.Full())
+	assert.False(t, q.Push(2))
+	q.Pop()
+	assert.True(t, q.Push(2))
+}
@@ -0,0 +1,258 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+import (
+	"sort"
+	"strings"
+)
+
+type (
+	// A RadixTree is a compressed Trie where a chain of nodes with a single child is merged into one edge,
+	// e.g. for routing tables, prefix-based config lookup and autocomplete.
+	// Keys are walked in lexicographical order. It's not safe for concurrent use.
+	RadixTree[V any] struct {
+		root radixNode[V]
+		size int
+	}
+
+	radixNode[V any] struct {
+		// prefix is the label of the edge leading to the node.
+		prefix string
+		// children are sorted by the first byte of their prefix.
+		children []*radixNode[V]
+		value    V
+		ok       bool
+	}
+)
+
+// NewRadixTree returns an empty RadixTree.
+func NewRadixTree[V any]() *RadixTree[V] {
+	return &RadixTree[V]{}
+}
+
+// Len returns the number of keys.
+func (t *RadixTree[V]) Len() int {
+	return t.size
+}
+
+// Insert sets the value of key, it returns false if key already existed.
+func (t *RadixTree[V]) Insert(key string, value V) bool {
+	n := &t.root
+	for {
+		if key == "" {
+			added := !n.ok
+			if added {
+				t.size++
+			}
+			n.value = value
+			n.ok = true
+			return added
+		}
+
+		i, child := n.child(key[0])
+		if child == nil {
+			n.add(&radixNode[V]{prefix: key, value: value, ok: true})
+			t.size++
+			return true
+		}
+
+		common := commonPrefix(key, child.prefix)
+		if common < len(child.prefix) {
+			// split the edge at the common prefix.
+			split := &radixNode[V]{prefix: child.prefix[:common]}
+			child.prefix = child.prefix[common:]
+			split.children = []*radixNode[V]{child}
+			n.children[i] = split
+			child = split
+		}
+
+		n = child
+		key = key[common:]
+	}
+}
+
+// Get returns the value of key.
+func (t *RadixTree[V]) Get(key string) (V, bool) {
+	n := &t.root
+	for key != "" {
+		_, child := n.child(key[0])
+		if child == nil || !strings.HasPrefix(key, child.prefix) {
+			var zero V
+			return zero, false
+		}
+		n = child
+		key = key[len(child.prefix):]
+	}
+
+	return n.value, n.ok
+}
+
+// Delete removes key, it returns false if key doesn't exist.
+func (t *RadixTree[V]) Delete(key string) bool {
+	var parent *radixNode[V]
+	n := &t.root
+	for key != "" {
+		_, child := n.child(key[0])
+		if child == nil || !strings.HasPrefix(key, child.prefix) {
+			return false
+		}
+		parent, n = n, child
+		key = key[len(child.prefix):]
+	}
+	if !n.ok {
+		return false
+	}
+
+	var zero V
+	n.value = zero
+	n.ok = false
+	t.size--
+
+	if parent == nil {
+		return true
+	}
+
+	switch len(n.children) {
+	case 0:
+		parent.remove(n.prefix[0])
+		// the parent may now be mergeable with its only child.
+		if parent != &t.root && !parent.ok && len(parent.children) == 1 {
+			parent.merge()
+		}
+	case 1:
+		n.merge()
+	}
+
+	return true
+}
+
+// LongestPrefix returns the longest key that is a prefix of s and its value.
+func (t *RadixTree[V]) LongestPrefix(s string) (string, V, bool) {
+	var value V
+	length, ok := 0, false
+	n := &t.root
+	consumed := 0
+	for {
+		if n.ok {
+			length, value, ok = consumed, n.value, true
+		}
+		if consumed == len(s) {
+			break
+		}
+
+		_, child := n.child(s[consumed])
+		if child == nil || !strings.HasPrefix(s[consumed:], child.prefix) {
+			break
+		}
+		n = child
+		consumed += len(child.prefix)
+	}
+
+	return s[:length], value, ok
+}
+
+// WalkPrefix calls fn for each key starting with prefix in lexicographical order until fn returns false.
+func (t *RadixTree[V]) WalkPrefix(prefix string, fn func(key string, value V) bool) {
+	n := &t.root
+	key := make([]byte, 0, len(prefix))
+	for rest := prefix; rest != ""; {
+		_, child := n.child(rest[0])
+		if child == nil {
+			return
+		}
+
+		switch {
+		case strings.HasPrefix(rest, child.prefix):
+			rest = rest[len(child.prefix):]
+		case strings.HasPrefix(child.prefix, rest):
+			// prefix ends in the middle of the edge.
+			rest = ""
+		default:
+			return
+		}
+		key = append(key, child.prefix...)
+		n = child
+	}
+
+	n.walk(key, fn)
+}
+
+// Walk calls fn for each key in lexicographical order until fn returns false.
+func (t *RadixTree[V]) Walk(fn func(key string, value V) bool) {
+	t.root.walk(nil, fn)
+}
+
+func (n *radixNode[V]) search(label byte) int {
+	return sort.Search(len(n.children), func(i int) bool {
+		return n.children[i].prefix[0] >= label
+	})
+}
+
+func (n *radixNode[V]) child(label byte) (int, *radixNode[V]) {
+	i := n.search(label)
+	if i < len(n.children) && n.children[i].prefix[0] == label {
+		return i, n.children[i]
+	}
+
+	return i, nil
+}
+
+func (n *radixNode[V]) add(child *radixNode[V]) {
+	i := n.search(child.prefix[0])
+	n.children = append(n.children, nil)
+	copy(n.children[i+1:], n.children[i:])
+	n.children[i] = child
+}
+
+func (n *radixNode[V]) remove(label byte) {
+	if i, child := n.child(label); child != nil {
+		n.children = append(n.children[:i], n.children[i+1:]...)
+	}
+}
+
+// merge merges n with its only child.
+func (n *radixNode[V]) merge() {
+	child := n.children[0]
+	n.prefix += child.prefix
+	n.children = child.children
+	n.value = child.value
+	n.ok = child.ok
+}
+
+func (n *radixNode[V]) walk(key []byte, fn func(key string, value V) bool) bool {
+	if n.ok && !fn(string(key), n.value) {
+		return false
+	}
+
+	for _, child := range n.children {
+		if !child.walk(append(key, child.prefix...), fn) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func commonPrefix(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+
+	return i
+}
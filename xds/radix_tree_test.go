@@ -0,0 +1,46 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRadixTree(t *testing.T) {
+	testPrefixTree(t, NewRadixTree[int]())
+	testPrefixTreeRandom(t, NewRadixTree[int]())
+}
+
+func TestRadixTree_Compress(t *testing.T) {
+	tree := NewRadixTree[int]()
+	tree.Insert("/api/users", 1)
+	tree.Insert("/api/orders", 2)
+	assert.Len(t, tree.root.children, 1)
+	assert.Equal(t, "/api/", tree.root.children[0].prefix)
+
+	tree.Delete("/api/orders")
+	// the split edge is merged back.
+	assert.Len(t, tree.root.children, 1)
+	assert.Equal(t, "/api/users", tree.root.children[0].prefix)
+
+	tree.Insert("/api", 3)
+	tree.Insert("/api/users/1", 4)
+	tree.Delete("/api/users")
+	assert.Equal(t, "/api", tree.root.children[0].prefix)
+	assert.Equal(t, "/users/1", tree.root.children[0].children[0].prefix)
+}
@@ -0,0 +1,217 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+import "sync/atomic"
+
+const (
+	// Overwrite makes a full RingBuffer overwrite its oldest element.
+	Overwrite FullPolicy = iota
+	// Reject makes a full RingBuffer reject new elements.
+	Reject
+)
+
+type (
+	// FullPolicy decides what pushing into a full RingBuffer does.
+	FullPolicy int
+
+	// A RingBuffer is a fixed-capacity FIFO buffer, e.g. to keep the recent events.
+	// It's not safe for concurrent use, see MPSCRingBuffer.
+	RingBuffer[T any] struct {
+		buf    []T
+		head   int
+		size   int
+		policy FullPolicy
+	}
+
+	// A MPSCRingBuffer is a fixed-capacity lock-free FIFO buffer that rejects new elements when full.
+	// Push is safe to be called concurrently by many producers, while Pop must be called by a single consumer.
+	MPSCRingBuffer[T any] struct {
+		slots []mpscSlot[T]
+		mask  uint64
+		_     [56]byte
+		tail  atomic.Uint64
+		_     [56]byte
+		head  atomic.Uint64
+	}
+
+	mpscSlot[T any] struct {
+		seq   atomic.Uint64
+		value T
+	}
+)
+
+// NewRingBuffer returns a RingBuffer of the given capacity, it panics if capacity is not positive.
+func NewRingBuffer[T any](capacity int, policy FullPolicy) *RingBuffer[T] {
+	if capacity < 1 {
+		panic("xds: capacity should be greater than 0")
+	}
+
+	return &RingBuffer[T]{buf: make([]T, capacity), policy: policy}
+}
+
+// Len returns the number of elements.
+func (r *RingBuffer[T]) Len() int {
+	return r.size
+}
+
+// Cap returns the capacity.
+func (r *RingBuffer[T]) Cap() int {
+	return len(r.buf)
+}
+
+// Full reports whether r is full.
+func (r *RingBuffer[T]) Full() bool {
+	return r.size == len(r.buf)
+}
+
+// Push adds v as the newest element.
+// It returns false if r is full and its policy is Reject.
+func (r *RingBuffer[T]) Push(v T) bool {
+	if r.Full() {
+		if r.policy == Reject {
+			return false
+		}
+
+		r.buf[r.head] = v
+		r.head = (r.head + 1) % len(r.buf)
+
+		return true
+	}
+
+	r.buf[(r.head+r.size)%len(r.buf)] = v
+	r.size++
+
+	return true
+}
+
+// Pop removes and returns the oldest element.
+func (r *RingBuffer[T]) Pop() (T, bool) {
+	var zero T
+	if r.size == 0 {
+		return zero, false
+	}
+
+	v := r.buf[r.head]
+	r.buf[r.head] = zero
+	r.head = (r.head + 1) % len(r.buf)
+	r.size--
+
+	return v, true
+}
+
+// Peek returns the oldest element without removing it.
+func (r *RingBuffer[T]) Peek() (T, bool) {
+	if r.size == 0 {
+		var zero T
+		return zero, false
+	}
+
+	return r.buf[r.head], true
+}
+
+// Snapshot returns a copy of the elements from the oldest to the newest.
+func (r *RingBuffer[T]) Snapshot() []T {
+	elements := make([]T, r.size)
+	n := copy(elements, r.buf[r.head:min(r.head+r.size, len(r.buf))])
+	copy(elements[n:], r.buf[:r.size-n])
+
+	return elements
+}
+
+// Clear removes all elements.
+func (r *RingBuffer[T]) Clear() {
+	clear(r.buf)
+	r.head = 0
+	r.size = 0
+}
+
+// NewMPSCRingBuffer returns a MPSCRingBuffer whose capacity is at least capacity,
+// rounded up to a power of two. It panics if capacity is not positive.
+func NewMPSCRingBuffer[T any](capacity int) *MPSCRingBuffer[T] {
+	if capacity < 1 {
+		panic("xds: capacity should be greater than 0")
+	}
+
+	size := 1
+	for size < capacity {
+		size <<= 1
+	}
+
+	r := &MPSCRingBuffer[T]{slots: make([]mpscSlot[T], size), mask: uint64(size - 1)}
+	for i := range r.slots {
+		r.slots[i].seq.Store(uint64(i))
+	}
+
+	return r
+}
+
+// Cap returns the capacity.
+func (r *MPSCRingBuffer[T]) Cap() int {
+	return len(r.slots)
+}
+
+// Len returns the approximate number of elements.
+func (r *MPSCRingBuffer[T]) Len() int {
+	head := r.head.Load()
+	tail := r.tail.Load()
+	if tail < head {
+		return 0
+	}
+
+	return int(tail - head)
+}
+
+// Push adds v as the newest element, it returns false if r is full.
+func (r *MPSCRingBuffer[T]) Push(v T) bool {
+	for {
+		pos := r.tail.Load()
+		slot := &r.slots[pos&r.mask]
+		seq := slot.seq.Load()
+
+		switch diff := int64(seq) - int64(pos); {
+		case diff == 0:
+			if r.tail.CompareAndSwap(pos, pos+1) {
+				slot.value = v
+				// publish the value to the consumer.
+				slot.seq.Store(pos + 1)
+				return true
+			}
+		case diff < 0:
+			return false
+		}
+		// otherwise another producer has claimed pos, retry with the new tail.
+	}
+}
+
+// Pop removes and returns the oldest element, it must not be called concurrently.
+func (r *MPSCRingBuffer[T]) Pop() (T, bool) {
+	var zero T
+	pos := r.head.Load()
+	slot := &r.slots[pos&r.mask]
+	if int64(slot.seq.Load())-int64(pos+1) < 0 {
+		return zero, false
+	}
+
+	v := slot.value
+	slot.value = zero
+	// hand the slot back to the producers for the next lap.
+	slot.seq.Store(pos + r.mask + 1)
+	r.head.Store(pos + 1)
+
+	return v, true
+}
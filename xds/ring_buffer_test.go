@@ -0,0 +1,149 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+import (
+	"github.com/stretchr/testify/assert"
+	"runtime"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func TestRingBuffer_Overwrite(t *testing.T) {
+	r := NewRingBuffer[int](3, Overwrite)
+	assert.Equal(t, 3, r.Cap())
+	assert.Empty(t, r.Snapshot())
+	_, ok := r.Pop()
+	assert.False(t, ok)
+	_, ok = r.Peek()
+	assert.False(t, ok)
+
+	for i := 1; i <= 5; i++ {
+		assert.True(t, r.Push(i))
+	}
+	assert.True(t, r.Full())
+	assert.Equal(t, 3, r.Len())
+	assert.Equal(t, []int{3, 4, 5}, r.Snapshot())
+
+	v, _ := r.Peek()
+	assert.Equal(t, 3, v)
+	v, _ = r.Pop()
+	assert.Equal(t, 3, v)
+	assert.Equal(t, []int{4, 5}, r.Snapshot())
+
+	r.Push(6)
+	r.Push(7)
+	assert.Equal(t, []int{5, 6, 7}, r.Snapshot())
+
+	r.Clear()
+	assert.Equal(t, 0, r.Len())
+	r.Push(8)
+	assert.Equal(t, []int{8}, r.Snapshot())
+}
+
+func TestRingBuffer_Reject(t *testing.T) {
+	r := NewRingBuffer[string](2, Reject)
+	assert.True(t, r.Push("a"))
+	assert.True(t, r.Push("b"))
+	assert.False(t, r.Push("c"))
+	assert.Equal(t, []string{"a", "b"}, r.Snapshot())
+
+	r.Pop()
+	assert.True(t, r.Push("c"))
+	assert.Equal(t, []string{"b", "c"}, r.Snapshot())
+
+	assert.Panics(t, func() {
+		NewRingBuffer[int](0, Reject)
+	})
+}
+
+func TestMPSCRingBuffer(t *testing.T) {
+	r := NewMPSCRingBuffer[int](3)
+	assert.Equal(t, 4, r.Cap())
+	_, ok := r.Pop()
+	assert.False(t, ok)
+
+	for i := 0; i < 4; i++ {
+		assert.True(t, r.Push(i))
+	}
+	assert.False(t, r.Push(4))
+	assert.Equal(t, 4, r.Len())
+
+	for lap := 0; lap < 3; lap++ {
+		for i := 0; i < 4; i++ {
+			v, ok := r.Pop()
+			assert.True(t, ok)
+			assert.Equal(t, lap*4+i, v)
+			assert.True(t, r.Push((lap+1)*4+i))
+		}
+	}
+	assert.Equal(t, 4, r.Len())
+
+	assert.Panics(t, func() {
+		NewMPSCRingBuffer[int](0)
+	})
+}
+
+func TestMPSCRingBuffer_Concurrent(t *testing.T) {
+	const producers, n = 8, 1000
+	r := NewMPSCRingBuffer[int](64)
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				for !r.Push(p*n + i) {
+					runtime.Gosched()
+				}
+			}
+		}(p)
+	}
+
+	received := make([]int, 0, producers*n)
+	last := make(map[int]int)
+	for len(received) < producers*n {
+		v, ok := r.Pop()
+		if !ok {
+			runtime.Gosched()
+			continue
+		}
+		// the order of each producer is kept.
+		if prev, ok := last[v/n]; ok {
+			assert.Less(t, prev, v)
+		}
+		last[v/n] = v
+		received = append(received, v)
+	}
+	wg.Wait()
+
+	sort.Ints(received)
+	for i, v := range received {
+		assert.Equal(t, i, v)
+	}
+}
+
+func BenchmarkMPSCRingBuffer(b *testing.B) {
+	r := NewMPSCRingBuffer[int](1024)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			r.Push(1)
+		}
+	})
+}
@@ -0,0 +1,291 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+import (
+	"cmp"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+const (
+	skipListMaxLevel = 32
+	// the probability of a node to have one more level is 1/4.
+	skipListBranch = 4
+)
+
+type (
+	// A SkipList is an ordered map safe for concurrent use.
+	// Lookups take a read lock and writes only need O(log n) work under the write lock
+	// without rebalancing, which suits write-heavy ordered data.
+	// Every link keeps the number of elements it skips, so rank queries are O(log n) too.
+	SkipList[K cmp.Ordered, V any] struct {
+		lock   sync.RWMutex
+		head   *skipListNode[K, V]
+		level  int
+		length int
+		rand   *rand.Rand
+	}
+
+	skipListNode[K cmp.Ordered, V any] struct {
+		key   K
+		value V
+		next  []*skipListNode[K, V]
+		span  []int
+	}
+)
+
+// NewSkipList returns an empty SkipList.
+func NewSkipList[K cmp.Ordered, V any]() *SkipList[K, V] {
+	return &SkipList[K, V]{
+		head:  newSkipListNode[K, V](skipListMaxLevel),
+		level: 1,
+		rand:  rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func newSkipListNode[K cmp.Ordered, V any](level int) *skipListNode[K, V] {
+	return &skipListNode[K, V]{
+		next: make([]*skipListNode[K, V], level),
+		span: make([]int, level),
+	}
+}
+
+// Len returns the number of elements.
+func (s *SkipList[K, V]) Len() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.length
+}
+
+// Get returns the value of key.
+func (s *SkipList[K, V]) Get(key K) (V, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && x.next[i].key < key {
+			x = x.next[i]
+		}
+	}
+
+	x = x.next[0]
+	if x != nil && x.key == key {
+		return x.value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Set sets the value of key.
+func (s *SkipList[K, V]) Set(key K, value V) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var update [skipListMaxLevel]*skipListNode[K, V]
+	var rank [skipListMaxLevel]int
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		if i < s.level-1 {
+			rank[i] = rank[i+1]
+		}
+		for x.next[i] != nil && x.next[i].key < key {
+			rank[i] += x.span[i]
+			x = x.next[i]
+		}
+		update[i] = x
+	}
+
+	if next := x.next[0]; next != nil && next.key == key {
+		next.value = value
+		return
+	}
+
+	level := s.randomLevel()
+	if level > s.level {
+		for i := s.level; i < level; i++ {
+			rank[i] = 0
+			update[i] = s.head
+			update[i].span[i] = s.length
+		}
+		s.level = level
+	}
+
+	n := newSkipListNode[K, V](level)
+	n.key = key
+	n.value = value
+	for i := 0; i < level; i++ {
+		n.next[i] = update[i].next[i]
+		update[i].next[i] = n
+		n.span[i] = update[i].span[i] - (rank[0] - rank[i])
+		update[i].span[i] = rank[0] - rank[i] + 1
+	}
+	for i := level; i < s.level; i++ {
+		update[i].span[i]++
+	}
+	s.length++
+}
+
+// Delete removes key, it returns false if key doesn't exist.
+func (s *SkipList[K, V]) Delete(key K) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var update [skipListMaxLevel]*skipListNode[K, V]
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && x.next[i].key < key {
+			x = x.next[i]
+		}
+		update[i] = x
+	}
+
+	x = x.next[0]
+	if x == nil || x.key != key {
+		return false
+	}
+
+	for i := 0; i < s.level; i++ {
+		if update[i].next[i] == x {
+			update[i].span[i] += x.span[i] - 1
+			update[i].next[i] = x.next[i]
+		} else {
+			update[i].span[i]--
+		}
+	}
+	for s.level > 1 && s.head.next[s.level-1] == nil {
+		s.level--
+	}
+	s.length--
+
+	return true
+}
+
+// Rank returns the number of keys less than key, i.e. the index key has or would have.
+func (s *SkipList[K, V]) Rank(key K) int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	rank := 0
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && x.next[i].key < key {
+			rank += x.span[i]
+			x = x.next[i]
+		}
+	}
+
+	return rank
+}
+
+// At returns the i-th element in ascending order.
+func (s *SkipList[K, V]) At(i int) (K, V, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var key K
+	var value V
+	if i < 0 || i >= s.length {
+		return key, value, false
+	}
+
+	target := i + 1
+	traversed := 0
+	x := s.head
+	for l := s.level - 1; l >= 0; l-- {
+		for x.next[l] != nil && traversed+x.span[l] <= target {
+			traversed += x.span[l]
+			x = x.next[l]
+		}
+		if traversed == target {
+			return x.key, x.value, true
+		}
+	}
+
+	return key, value, false
+}
+
+// Min returns the least element.
+func (s *SkipList[K, V]) Min() (K, V, bool) {
+	return s.At(0)
+}
+
+// Max returns the greatest element.
+func (s *SkipList[K, V]) Max() (K, V, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i] != nil {
+			x = x.next[i]
+		}
+	}
+	if x == s.head {
+		var key K
+		var value V
+		return key, value, false
+	}
+
+	return x.key, x.value, true
+}
+
+// Ascend calls fn for each element in ascending order until fn returns false.
+// fn must not modify s.
+func (s *SkipList[K, V]) Ascend(fn func(key K, value V) bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	for x := s.head.next[0]; x != nil; x = x.next[0] {
+		if !fn(x.key, x.value) {
+			return
+		}
+	}
+}
+
+// Range calls fn for each element with from <= key < to in ascending order until fn returns false.
+// fn must not modify s.
+func (s *SkipList[K, V]) Range(from, to K, fn func(key K, value V) bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && x.next[i].key < from {
+			x = x.next[i]
+		}
+	}
+
+	for x = x.next[0]; x != nil && x.key < to; x = x.next[0] {
+		if !fn(x.key, x.value) {
+			return
+		}
+	}
+}
+
+func (s *SkipList[K, V]) randomLevel() int {
+	level := 1
+	for level < skipListMaxLevel && s.rand.Intn(skipListBranch) == 0 {
+		level++
+	}
+
+	return level
+}
@@ -0,0 +1,147 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func TestSkipList(t *testing.T) {
+	s := NewSkipList[int, string]()
+	_, ok := s.Get(1)
+	assert.False(t, ok)
+	_, _, ok = s.Min()
+	assert.False(t, ok)
+	_, _, ok = s.Max()
+	assert.False(t, ok)
+	assert.False(t, s.Delete(1))
+
+	for _, k := range []int{5, 1, 9, 3, 7} {
+		s.Set(k, "v")
+	}
+	s.Set(3, "three")
+	assert.Equal(t, 5, s.Len())
+
+	v, ok := s.Get(3)
+	assert.True(t, ok)
+	assert.Equal(t, "three", v)
+
+	var keys []int
+	s.Ascend(func(key int, _ string) bool {
+		keys = append(keys, key)
+		return true
+	})
+	assert.Equal(t, []int{1, 3, 5, 7, 9}, keys)
+
+	keys = nil
+	s.Range(2, 9, func(key int, _ string) bool {
+		keys = append(keys, key)
+		return true
+	})
+	assert.Equal(t, []int{3, 5, 7}, keys)
+
+	keys = nil
+	s.Range(0, 100, func(key int, _ string) bool {
+		keys = append(keys, key)
+		return len(keys) < 2
+	})
+	assert.Equal(t, []int{1, 3}, keys)
+
+	assert.Equal(t, 0, s.Rank(1))
+	assert.Equal(t, 2, s.Rank(5))
+	assert.Equal(t, 2, s.Rank(4))
+	assert.Equal(t, 5, s.Rank(10))
+
+	k, _, _ := s.Min()
+	assert.Equal(t, 1, k)
+	k, _, _ = s.Max()
+	assert.Equal(t, 9, k)
+	k, _, ok = s.At(3)
+	assert.True(t, ok)
+	assert.Equal(t, 7, k)
+	_, _, ok = s.At(5)
+	assert.False(t, ok)
+	_, _, ok = s.At(-1)
+	assert.False(t, ok)
+
+	assert.True(t, s.Delete(5))
+	assert.False(t, s.Delete(5))
+	assert.Equal(t, 4, s.Len())
+	assert.Equal(t, 2, s.Rank(7))
+}
+
+func TestSkipList_Random(t *testing.T) {
+	s := NewSkipList[int, int]()
+	reference := map[int]int{}
+	for i := 0; i < 5000; i++ {
+		k := rand.Intn(1000)
+		if rand.Intn(3) == 0 {
+			_, exists := reference[k]
+			assert.Equal(t, exists, s.Delete(k))
+			delete(reference, k)
+		} else {
+			s.Set(k, i)
+			reference[k] = i
+		}
+	}
+
+	keys := make([]int, 0, len(reference))
+	for k := range reference {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+
+	assert.Equal(t, len(keys), s.Len())
+	for i, k := range keys {
+		v, ok := s.Get(k)
+		assert.True(t, ok)
+		assert.Equal(t, reference[k], v)
+		assert.Equal(t, i, s.Rank(k))
+		key, _, ok := s.At(i)
+		assert.True(t, ok)
+		assert.Equal(t, k, key)
+	}
+}
+
+func TestSkipList_Concurrent(t *testing.T) {
+	s := NewSkipList[int, int]()
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				s.Set(g*500+i, i)
+				s.Get(i)
+				s.Rank(i)
+			}
+		}(g)
+	}
+	wg.Wait()
+	assert.Equal(t, 4000, s.Len())
+}
+
+func BenchmarkSkipList_Set(b *testing.B) {
+	s := NewSkipList[int, int]()
+	for i := 0; i < b.N; i++ {
+		s.Set(rand.Int(), i)
+	}
+}
@@ -0,0 +1,463 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+import (
+	"math/bits"
+	"sort"
+)
+
+const (
+	// a container switches from a sorted array to a bitmap once it has more values,
+	// where both take 8 KiB.
+	arrayContainerMax = 4096
+	bitmapWords       = 1 << 16 / wordBits
+)
+
+type (
+	// A SparseBitSet is a compressed set of uint32 in the style of roaring bitmaps.
+	// Values are grouped by their high 16 bits into containers that are either a sorted array,
+	// when they're sparse, or a bitmap, when they're dense.
+	// It suits sparse sets of high cardinality such as feature flags per user or posting lists.
+	// It's not safe for concurrent use.
+	SparseBitSet struct {
+		keys       []uint16
+		containers []*roaringContainer
+	}
+
+	roaringContainer struct {
+		// array is used while bitmap is nil.
+		array  []uint16
+		bitmap []uint64
+		card   int
+	}
+
+	setOp int
+)
+
+const (
+	opAnd setOp = iota
+	opOr
+	opAndNot
+	opXor
+)
+
+// NewSparseBitSet returns an empty SparseBitSet.
+func NewSparseBitSet(values ...uint32) *SparseBitSet {
+	s := &SparseBitSet{}
+	for _, v := range values {
+		s.Add(v)
+	}
+
+	return s
+}
+
+// Add adds x, it returns false if x already exists.
+func (s *SparseBitSet) Add(x uint32) bool {
+	high := uint16(x >> 16)
+	i, ok := s.find(high)
+	if !ok {
+		s.keys = insertAt(s.keys, i, high)
+		s.containers = insertAt(s.containers, i, &roaringContainer{})
+	}
+
+	return s.containers[i].add(uint16(x))
+}
+
+// Remove removes x, it returns false if x doesn't exist.
+func (s *SparseBitSet) Remove(x uint32) bool {
+	i, ok := s.find(uint16(x >> 16))
+	if !ok || !s.containers[i].remove(uint16(x)) {
+		return false
+	}
+
+	if s.containers[i].card == 0 {
+		s.keys = removeAt(s.keys, i)
+		s.containers = removeAt(s.containers, i)
+	}
+
+	return true
+}
+
+// Contains reports whether x is in s.
+func (s *SparseBitSet) Contains(x uint32) bool {
+	i, ok := s.find(uint16(x >> 16))
+
+	return ok && s.containers[i].contains(uint16(x))
+}
+
+// Count returns the number of values.
+func (s *SparseBitSet) Count() int {
+	count := 0
+	for _, c := range s.containers {
+		count += c.card
+	}
+
+	return count
+}
+
+// Rank returns the number of values less than x.
+func (s *SparseBitSet) Rank(x uint32) int {
+	high := uint16(x >> 16)
+	rank := 0
+	for i, key := range s.keys {
+		if key > high {
+			break
+		}
+		if key < high {
+			rank += s.containers[i].card
+			continue
+		}
+		rank += s.containers[i].rank(uint16(x))
+	}
+
+	return rank
+}
+
+// Select returns the k-th value in ascending order, counting from 0, so that Rank(Select(k)) == k.
+func (s *SparseBitSet) Select(k int) (uint32, bool) {
+	if k < 0 {
+		return 0, false
+	}
+
+	for i, c := range s.containers {
+		if k < c.card {
+			return uint32(s.keys[i])<<16 | uint32(c.selectAt(k)), true
+		}
+		k -= c.card
+	}
+
+	return 0, false
+}
+
+// Range calls fn for each value in ascending order until fn returns false.
+func (s *SparseBitSet) Range(fn func(x uint32) bool) {
+	for i, c := range s.containers {
+		high := uint32(s.keys[i]) << 16
+		if !c.each(func(low uint16) bool {
+			return fn(high | uint32(low))
+		}) {
+			return
+		}
+	}
+}
+
+// And returns the intersection of s and other.
+func (s *SparseBitSet) And(other *SparseBitSet) *SparseBitSet {
+	return s.combine(other, opAnd)
+}
+
+// Or returns the union of s and other.
+func (s *SparseBitSet) Or(other *SparseBitSet) *SparseBitSet {
+	return s.combine(other, opOr)
+}
+
+// AndNot returns the values of s that are not in other.
+func (s *SparseBitSet) AndNot(other *SparseBitSet) *SparseBitSet {
+	return s.combine(other, opAndNot)
+}
+
+// Xor returns the values that are in either s or other but not both.
+func (s *SparseBitSet) Xor(other *SparseBitSet) *SparseBitSet {
+	return s.combine(other, opXor)
+}
+
+// Equal reports whether s and other contain the same values.
+func (s *SparseBitSet) Equal(other *SparseBitSet) bool {
+	if len(s.keys) != len(other.keys) {
+		return false
+	}
+
+	for i, key := range s.keys {
+		if key != other.keys[i] || s.containers[i].card != other.containers[i].card {
+			return false
+		}
+		if combineContainers(s.containers[i], other.containers[i], opXor) != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Clone returns a copy of s.
+func (s *SparseBitSet) Clone() *SparseBitSet {
+	clone := &SparseBitSet{
+		keys:       append([]uint16(nil), s.keys...),
+		containers: make([]*roaringContainer, len(s.containers)),
+	}
+	for i, c := range s.containers {
+		clone.containers[i] = c.clone()
+	}
+
+	return clone
+}
+
+func (s *SparseBitSet) find(high uint16) (int, bool) {
+	i := sort.Search(len(s.keys), func(i int) bool {
+		return s.keys[i] >= high
+	})
+
+	return i, i < len(s.keys) && s.keys[i] == high
+}
+
+func (s *SparseBitSet) combine(other *SparseBitSet, op setOp) *SparseBitSet {
+	result := &SparseBitSet{}
+	appendContainer := func(key uint16, c *roaringContainer) {
+		if c != nil {
+			result.keys = append(result.keys, key)
+			result.containers = append(result.containers, c)
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(s.keys) || j < len(other.keys) {
+		switch {
+		case j == len(other.keys) || (i < len(s.keys) && s.keys[i] < other.keys[j]):
+			if op != opAnd {
+				appendContainer(s.keys[i], s.containers[i].clone())
+			}
+			i++
+		case i == len(s.keys) || other.keys[j] < s.keys[i]:
+			if op == opOr || op == opXor {
+				appendContainer(other.keys[j], other.containers[j].clone())
+			}
+			j++
+		default:
+			appendContainer(s.keys[i], combineContainers(s.containers[i], other.containers[j], op))
+			i++
+			j++
+		}
+	}
+
+	return result
+}
+
+func (c *roaringContainer) search(v uint16) (int, bool) {
+	i := sort.Search(len(c.array), func(i int) bool {
+		return c.array[i] >= v
+	})
+
+	return i, i < len(c.array) && c.array[i] == v
+}
+
+func (c *roaringContainer) contains(v uint16) bool {
+	if c.bitmap != nil {
+		return c.bitmap[v/wordBits]&(1<<(v%wordBits)) != 0
+	}
+
+	_, ok := c.search(v)
+
+	return ok
+}
+
+func (c *roaringContainer) add(v uint16) bool {
+	if c.bitmap != nil {
+		w, bit := v/wordBits, uint64(1)<<(v%wordBits)
+		if c.bitmap[w]&bit != 0 {
+			return false
+		}
+		c.bitmap[w] |= bit
+		c.card++
+		return true
+	}
+
+	i, ok := c.search(v)
+	if ok {
+		return false
+	}
+	c.array = insertAt(c.array, i, v)
+	c.card++
+	c.normalize()
+
+	return true
+}
+
+func (c *roaringContainer) remove(v uint16) bool {
+	if c.bitmap != nil {
+		w, bit := v/wordBits, uint64(1)<<(v%wordBits)
+		if c.bitmap[w]&bit == 0 {
+			return false
+		}
+		c.bitmap[w] &^= bit
+		c.card--
+		c.normalize()
+		return true
+	}
+
+	i, ok := c.search(v)
+	if !ok {
+		return false
+	}
+	c.array = removeAt(c.array, i)
+	c.card--
+
+	return true
+}
+
+// rank returns the number of values less than v.
+func (c *roaringContainer) rank(v uint16) int {
+	if c.bitmap == nil {
+		i, _ := c.search(v)
+		return i
+	}
+
+	w := int(v / wordBits)
+	rank := 0
+	for _, word := range c.bitmap[:w] {
+		rank += bits.OnesCount64(word)
+	}
+
+	return rank + bits.OnesCount64(c.bitmap[w]&(1<<(v%wordBits)-1))
+}
+
+func (c *roaringContainer) selectAt(k int) uint16 {
+	if c.bitmap == nil {
+		return c.array[k]
+	}
+
+	for w, word := range c.bitmap {
+		count := bits.OnesCount64(word)
+		if k < count {
+			return uint16(w*wordBits + selectInWord(word, k))
+		}
+		k -= count
+	}
+
+	return 0
+}
+
+func (c *roaringContainer) each(fn func(v uint16) bool) bool {
+	if c.bitmap == nil {
+		for _, v := range c.array {
+			if !fn(v) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for w, word := range c.bitmap {
+		for word != 0 {
+			if !fn(uint16(w*wordBits + bits.TrailingZeros64(word))) {
+				return false
+			}
+			word &= word - 1
+		}
+	}
+
+	return true
+}
+
+func (c *roaringContainer) words() []uint64 {
+	if c.bitmap != nil {
+		return c.bitmap
+	}
+
+	words := make([]uint64, bitmapWords)
+	for _, v := range c.array {
+		words[v/wordBits] |= 1 << (v % wordBits)
+	}
+
+	return words
+}
+
+// normalize switches c to the representation that suits its cardinality.
+func (c *roaringContainer) normalize() {
+	switch {
+	case c.bitmap == nil && c.card > arrayContainerMax:
+		c.bitmap = c.words()
+		c.array = nil
+	case c.bitmap != nil && c.card <= arrayContainerMax:
+		array := make([]uint16, 0, c.card)
+		c.each(func(v uint16) bool {
+			array = append(array, v)
+			return true
+		})
+		c.array = array
+		c.bitmap = nil
+	}
+}
+
+func (c *roaringContainer) clone() *roaringContainer {
+	return &roaringContainer{
+		array:  append([]uint16(nil), c.array...),
+		bitmap: append([]uint64(nil), c.bitmap...),
+		card:   c.card,
+	}
+}
+
+// combineContainers returns the result of op, or nil if it's empty.
+func combineContainers(a, b *roaringContainer, op setOp) *roaringContainer {
+	var result *roaringContainer
+	if a.bitmap == nil && b.bitmap == nil {
+		result = &roaringContainer{array: mergeArrays(a.array, b.array, op)}
+		result.card = len(result.array)
+	} else {
+		wa, wb := a.words(), b.words()
+		words := make([]uint64, bitmapWords)
+		card := 0
+		for i := range words {
+			switch op {
+			case opAnd:
+				words[i] = wa[i] & wb[i]
+			case opOr:
+				words[i] = wa[i] | wb[i]
+			case opAndNot:
+				words[i] = wa[i] &^ wb[i]
+			case opXor:
+				words[i] = wa[i] ^ wb[i]
+			}
+			card += bits.OnesCount64(words[i])
+		}
+		result = &roaringContainer{bitmap: words, card: card}
+	}
+
+	if result.card == 0 {
+		return nil
+	}
+	result.normalize()
+
+	return result
+}
+
+func mergeArrays(a, b []uint16, op setOp) []uint16 {
+	var result []uint16
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case j == len(b) || (i < len(a) && a[i] < b[j]):
+			if op != opAnd {
+				result = append(result, a[i])
+			}
+			i++
+		case i == len(a) || b[j] < a[i]:
+			if op == opOr || op == opXor {
+				result = append(result, b[j])
+			}
+			j++
+		default:
+			if op == opAnd || op == opOr {
+				result = append(result, a[i])
+			}
+			i++
+			j++
+		}
+	}
+
+	return result
+}
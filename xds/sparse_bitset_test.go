@@ -0,0 +1,172 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func sparseValues(s *SparseBitSet) []uint32 {
+	var values []uint32
+	s.Range(func(x uint32) bool {
+		values = append(values, x)
+		return true
+	})
+	return values
+}
+
+func TestSparseBitSet(t *testing.T) {
+	s := NewSparseBitSet()
+	assert.Equal(t, 0, s.Count())
+	assert.False(t, s.Contains(1))
+	assert.False(t, s.Remove(1))
+	_, ok := s.Select(0)
+	assert.False(t, ok)
+
+	assert.True(t, s.Add(1<<20))
+	assert.True(t, s.Add(5))
+	assert.True(t, s.Add(1<<31))
+	assert.False(t, s.Add(5))
+	assert.Equal(t, 3, s.Count())
+	assert.True(t, s.Contains(1<<20))
+	assert.False(t, s.Contains(6))
+	assert.Equal(t, []uint32{5, 1 << 20, 1 << 31}, sparseValues(s))
+
+	assert.Equal(t, 0, s.Rank(5))
+	assert.Equal(t, 1, s.Rank(6))
+	assert.Equal(t, 2, s.Rank(1<<31))
+	assert.Equal(t, 3, s.Rank(1<<32-1))
+	x, ok := s.Select(1)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(1<<20), x)
+	_, ok = s.Select(3)
+	assert.False(t, ok)
+	_, ok = s.Select(-1)
+	assert.False(t, ok)
+
+	assert.True(t, s.Remove(1<<20))
+	assert.Len(t, s.keys, 2)
+
+	var first []uint32
+	s.Range(func(x uint32) bool {
+		first = append(first, x)
+		return false
+	})
+	assert.Equal(t, []uint32{5}, first)
+}
+
+func TestSparseBitSet_Containers(t *testing.T) {
+	s := NewSparseBitSet()
+	for i := uint32(0); i < 10000; i += 2 {
+		s.Add(i)
+	}
+	// 5000 values are switched to a bitmap.
+	assert.NotNil(t, s.containers[0].bitmap)
+	assert.Equal(t, 5000, s.Count())
+	assert.Equal(t, 2500, s.Rank(5000))
+	x, _ := s.Select(2500)
+	assert.Equal(t, uint32(5000), x)
+	assert.False(t, s.Add(0))
+	assert.False(t, s.Remove(1))
+
+	for i := uint32(0); i < 2000; i += 2 {
+		s.Remove(i)
+	}
+	// and back to an array.
+	assert.Nil(t, s.containers[0].bitmap)
+	assert.Equal(t, 4000, s.Count())
+	assert.True(t, s.Contains(2000))
+	assert.False(t, s.Contains(1998))
+}
+
+func TestSparseBitSet_Ops(t *testing.T) {
+	randomSet := func(n int, max uint32) (*SparseBitSet, map[uint32]bool) {
+		s := NewSparseBitSet()
+		reference := map[uint32]bool{}
+		for i := 0; i < n; i++ {
+			x := rand.Uint32() % max
+			s.Add(x)
+			reference[x] = true
+		}
+		return s, reference
+	}
+	expected := func(a, b map[uint32]bool, keep func(inA, inB bool) bool) []uint32 {
+		var values []uint32
+		for x := range a {
+			if keep(true, b[x]) {
+				values = append(values, x)
+			}
+		}
+		for x := range b {
+			if !a[x] && keep(false, true) {
+				values = append(values, x)
+			}
+		}
+		sort.Slice(values, func(i, j int) bool {
+			return values[i] < values[j]
+		})
+		return values
+	}
+
+	for _, sizes := range [][2]int{{100, 100}, {20000, 100}, {20000, 20000}} {
+		a, ra := randomSet(sizes[0], 1<<18)
+		b, rb := randomSet(sizes[1], 1<<18)
+
+		assert.Equal(t, expected(ra, rb, func(x, y bool) bool { return x && y }), sparseValues(a.And(b)))
+		assert.Equal(t, expected(ra, rb, func(x, y bool) bool { return x || y }), sparseValues(a.Or(b)))
+		assert.Equal(t, expected(ra, rb, func(x, y bool) bool { return x && !y }), sparseValues(a.AndNot(b)))
+		assert.Equal(t, expected(ra, rb, func(x, y bool) bool { return x != y }), sparseValues(a.Xor(b)))
+		assert.Equal(t, len(ra), a.Count())
+
+		values := sparseValues(a)
+		for k := 0; k < len(values); k += 97 {
+			x, ok := a.Select(k)
+			assert.True(t, ok)
+			assert.Equal(t, values[k], x)
+			assert.Equal(t, k, a.Rank(x))
+		}
+	}
+}
+
+func TestSparseBitSet_Equal(t *testing.T) {
+	a := NewSparseBitSet(1, 2, 1<<20)
+	b := a.Clone()
+	assert.True(t, a.Equal(b))
+
+	b.Add(3)
+	assert.False(t, a.Equal(b))
+	assert.False(t, a.Contains(3))
+	b.Remove(3)
+	assert.True(t, a.Equal(b))
+
+	b.Remove(2)
+	b.Add(4)
+	assert.False(t, a.Equal(b))
+	assert.False(t, a.Equal(NewSparseBitSet(1, 2)))
+	assert.False(t, a.Equal(NewSparseBitSet(1, 2, 1<<21)))
+	assert.True(t, a.Xor(a).Equal(NewSparseBitSet()))
+}
+
+func BenchmarkSparseBitSet_Add(b *testing.B) {
+	s := NewSparseBitSet()
+	for i := 0; i < b.N; i++ {
+		s.Add(rand.Uint32())
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+// A Stack is a last-in-first-out stack, it's not safe for concurrent use.
+type Stack[T any] struct {
+	elements []T
+	capacity int
+}
+
+// NewStack returns an empty Stack.
+func NewStack[T any](opts ...Option) *Stack[T] {
+	return &Stack[T]{capacity: newOptions(opts).capacity}
+}
+
+// Len returns the number of elements.
+func (s *Stack[T]) Len() int {
+	return len(s.elements)
+}
+
+// Full reports whether s is bounded and full.
+func (s *Stack[T]) Full() bool {
+	return s.capacity > 0 && len(s.elements) >= s.capacity
+}
+
+// Push adds v on the top, it returns false if s is full.
+func (s *Stack[T]) Push(v T) bool {
+	if s.Full() {
+		return false
+	}
+
+	s.elements = append(s.elements, v)
+
+	return true
+}
+
+// Pop removes and returns the top element.
+func (s *Stack[T]) Pop() (T, bool) {
+	var zero T
+	n := len(s.elements)
+	if n == 0 {
+		return zero, false
+	}
+
+	v := s.elements[n-1]
+	s.elements[n-1] = zero
+	s.elements = s.elements[:n-1]
+
+	return v, true
+}
+
+// Peek returns the top element without removing it.
+func (s *Stack[T]) Peek() (T, bool) {
+	n := len(s.elements)
+	if n == 0 {
+		var zero T
+		return zero, false
+	}
+
+	return s.elements[n-1], true
+}
+
+// Range calls fn for each element from top to bottom until fn returns false.
+func (s *Stack[T]) Range(fn func(v T) bool) {
+	for i := len(s.elements) - 1; i >= 0; i-- {
+		if !fn(s.elements[i]) {
+			return
+		}
+	}
+}
+
+// Clear removes all elements.
+func (s *Stack[T]) Clear() {
+	s.elements = nil
+}
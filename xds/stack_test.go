@@ -0,0 +1,63 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestStack(t *testing.T) {
+	s := NewStack[string]()
+	_, ok := s.Pop()
+	assert.False(t, ok)
+	_, ok = s.Peek()
+	assert.False(t, ok)
+
+	s.Push("a")
+	s.Push("b")
+	s.Push("c")
+	assert.Equal(t, 3, s.Len())
+	v, _ := s.Peek()
+	assert.Equal(t, "c", v)
+
+	var elements []string
+	s.Range(func(v string) bool {
+		elements = append(elements, v)
+		return v != "b"
+	})
+	assert.Equal(t, []string{"c", "b"}, elements)
+
+	v, _ = s.Pop()
+	assert.Equal(t, "c", v)
+	v, _ = s.Pop()
+	assert.Equal(t, "b", v)
+	assert.Equal(t, 1, s.Len())
+
+	s.Clear()
+	assert.Equal(t, 0, s.Len())
+}
+
+func TestStack_Capacity(t *testing.T) {
+	s := NewStack[int](WithCapacity(2))
+	assert.True(t, s.Push(1))
+	assert.True(t, s.Push(2))
+	assert.True(t, s.Full())
+	assert.False(t, s.Push(3))
+	s.Pop()
+	assert.True(t, s.Push(3))
+}
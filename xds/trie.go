@@ -0,0 +1,198 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+import "sort"
+
+type (
+	// A Trie maps string keys to values byte by byte, for prefix lookups.
+	// Keys are walked in lexicographical order. It's not safe for concurrent use.
+	// See RadixTree for a compressed variant that uses less memory on long keys.
+	Trie[V any] struct {
+		root trieNode[V]
+		size int
+	}
+
+	trieNode[V any] struct {
+		children []trieEdge[V]
+		value    V
+		ok       bool
+	}
+
+	trieEdge[V any] struct {
+		label byte
+		node  *trieNode[V]
+	}
+)
+
+// NewTrie returns an empty Trie.
+func NewTrie[V any]() *Trie[V] {
+	return &Trie[V]{}
+}
+
+// Len returns the number of keys.
+func (t *Trie[V]) Len() int {
+	return t.size
+}
+
+// Insert sets the value of key, it returns false if key already existed.
+func (t *Trie[V]) Insert(key string, value V) bool {
+	n := &t.root
+	for i := 0; i < len(key); i++ {
+		child := n.child(key[i])
+		if child == nil {
+			child = &trieNode[V]{}
+			n.add(key[i], child)
+		}
+		n = child
+	}
+
+	added := !n.ok
+	if added {
+		t.size++
+	}
+	n.value = value
+	n.ok = true
+
+	return added
+}
+
+// Get returns the value of key.
+func (t *Trie[V]) Get(key string) (V, bool) {
+	n := t.find(key)
+	if n == nil || !n.ok {
+		var zero V
+		return zero, false
+	}
+
+	return n.value, true
+}
+
+// Delete removes key, it returns false if key doesn't exist.
+func (t *Trie[V]) Delete(key string) bool {
+	path := make([]*trieNode[V], 0, len(key)+1)
+	n := &t.root
+	path = append(path, n)
+	for i := 0; i < len(key); i++ {
+		if n = n.child(key[i]); n == nil {
+			return false
+		}
+		path = append(path, n)
+	}
+	if !n.ok {
+		return false
+	}
+
+	var zero V
+	n.value = zero
+	n.ok = false
+	t.size--
+
+	// prune the nodes that lead to no key.
+	for i := len(path) - 1; i > 0 && !path[i].ok && len(path[i].children) == 0; i-- {
+		path[i-1].remove(key[i-1])
+	}
+
+	return true
+}
+
+// LongestPrefix returns the longest key that is a prefix of s and its value.
+func (t *Trie[V]) LongestPrefix(s string) (string, V, bool) {
+	var value V
+	length, ok := 0, false
+	n := &t.root
+	for i := 0; ; i++ {
+		if n.ok {
+			length, value, ok = i, n.value, true
+		}
+		if i == len(s) {
+			break
+		}
+		if n = n.child(s[i]); n == nil {
+			break
+		}
+	}
+
+	return s[:length], value, ok
+}
+
+// WalkPrefix calls fn for each key starting with prefix in lexicographical order until fn returns false.
+func (t *Trie[V]) WalkPrefix(prefix string, fn func(key string, value V) bool) {
+	n := t.find(prefix)
+	if n == nil {
+		return
+	}
+
+	n.walk([]byte(prefix), fn)
+}
+
+// Walk calls fn for each key in lexicographical order until fn returns false.
+func (t *Trie[V]) Walk(fn func(key string, value V) bool) {
+	t.root.walk(nil, fn)
+}
+
+func (t *Trie[V]) find(key string) *trieNode[V] {
+	n := &t.root
+	for i := 0; i < len(key) && n != nil; i++ {
+		n = n.child(key[i])
+	}
+
+	return n
+}
+
+func (n *trieNode[V]) search(label byte) int {
+	return sort.Search(len(n.children), func(i int) bool {
+		return n.children[i].label >= label
+	})
+}
+
+func (n *trieNode[V]) child(label byte) *trieNode[V] {
+	i := n.search(label)
+	if i < len(n.children) && n.children[i].label == label {
+		return n.children[i].node
+	}
+
+	return nil
+}
+
+func (n *trieNode[V]) add(label byte, child *trieNode[V]) {
+	i := n.search(label)
+	n.children = append(n.children, trieEdge[V]{})
+	copy(n.children[i+1:], n.children[i:])
+	n.children[i] = trieEdge[V]{label: label, node: child}
+}
+
+func (n *trieNode[V]) remove(label byte) {
+	i := n.search(label)
+	if i < len(n.children) && n.children[i].label == label {
+		n.children = append(n.children[:i], n.children[i+1:]...)
+	}
+}
+
+func (n *trieNode[V]) walk(key []byte, fn func(key string, value V) bool) bool {
+	if n.ok && !fn(string(key), n.value) {
+		return false
+	}
+
+	for _, e := range n.children {
+		if !e.node.walk(append(key, e.label), fn) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,158 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xds
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+type prefixTree[V any] interface {
+	Len() int
+	Insert(key string, value V) bool
+	Get(key string) (V, bool)
+	Delete(key string) bool
+	LongestPrefix(s string) (string, V, bool)
+	WalkPrefix(prefix string, fn func(key string, value V) bool)
+	Walk(fn func(key string, value V) bool)
+}
+
+func walkKeys(walk func(fn func(key string, value int) bool)) []string {
+	var keys []string
+	walk(func(key string, _ int) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+func testPrefixTree(t *testing.T, tree prefixTree[int]) {
+	for i, key := range []string{"team", "test", "te", "toast", "to", "a", ""} {
+		assert.True(t, tree.Insert(key, i))
+	}
+	assert.False(t, tree.Insert("te", 10))
+	assert.Equal(t, 7, tree.Len())
+
+	v, ok := tree.Get("te")
+	assert.True(t, ok)
+	assert.Equal(t, 10, v)
+	v, ok = tree.Get("")
+	assert.True(t, ok)
+	assert.Equal(t, 6, v)
+	_, ok = tree.Get("t")
+	assert.False(t, ok)
+	_, ok = tree.Get("teams")
+	assert.False(t, ok)
+	_, ok = tree.Get("x")
+	assert.False(t, ok)
+
+	assert.Equal(t, []string{"", "a", "te", "team", "test", "to", "toast"}, walkKeys(tree.Walk))
+	walkPrefix := func(prefix string) []string {
+		return walkKeys(func(fn func(key string, value int) bool) {
+			tree.WalkPrefix(prefix, fn)
+		})
+	}
+	assert.Equal(t, []string{"te", "team", "test"}, walkPrefix("te"))
+	assert.Equal(t, []string{"team"}, walkPrefix("tea"))
+	assert.Equal(t, []string{"toast"}, walkPrefix("toa"))
+	assert.Empty(t, walkPrefix("tx"))
+	assert.Empty(t, walkPrefix("toasts"))
+
+	var first []string
+	tree.Walk(func(key string, _ int) bool {
+		first = append(first, key)
+		return len(first) < 3
+	})
+	assert.Equal(t, []string{"", "a", "te"}, first)
+
+	key, v, ok := tree.LongestPrefix("teamwork")
+	assert.True(t, ok)
+	assert.Equal(t, "team", key)
+	assert.Equal(t, 0, v)
+	key, _, _ = tree.LongestPrefix("tea")
+	assert.Equal(t, "te", key)
+	key, _, _ = tree.LongestPrefix("toas")
+	assert.Equal(t, "to", key)
+	key, _, ok = tree.LongestPrefix("xyz")
+	assert.True(t, ok)
+	assert.Equal(t, "", key)
+
+	assert.True(t, tree.Delete(""))
+	_, _, ok = tree.LongestPrefix("xyz")
+	assert.False(t, ok)
+	assert.False(t, tree.Delete("t"))
+	assert.False(t, tree.Delete("tes"))
+	assert.True(t, tree.Delete("te"))
+	assert.True(t, tree.Delete("team"))
+	assert.False(t, tree.Delete("team"))
+	assert.Equal(t, []string{"a", "test", "to", "toast"}, walkKeys(tree.Walk))
+	assert.Equal(t, 4, tree.Len())
+}
+
+func testPrefixTreeRandom(t *testing.T, tree prefixTree[int]) {
+	reference := map[string]int{}
+	letters := "abc"
+	randomKey := func() string {
+		b := make([]byte, rand.Intn(6))
+		for i := range b {
+			b[i] = letters[rand.Intn(len(letters))]
+		}
+		return string(b)
+	}
+
+	for i := 0; i < 3000; i++ {
+		key := randomKey()
+		if rand.Intn(3) == 0 {
+			_, exists := reference[key]
+			assert.Equal(t, exists, tree.Delete(key))
+			delete(reference, key)
+		} else {
+			_, exists := reference[key]
+			assert.Equal(t, !exists, tree.Insert(key, i))
+			reference[key] = i
+		}
+	}
+
+	keys := make([]string, 0, len(reference))
+	for key := range reference {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	assert.Equal(t, len(keys), tree.Len())
+	if len(keys) > 0 {
+		assert.Equal(t, keys, walkKeys(tree.Walk))
+	}
+	for _, key := range keys {
+		v, ok := tree.Get(key)
+		assert.True(t, ok)
+		assert.Equal(t, reference[key], v)
+	}
+}
+
+func TestTrie(t *testing.T) {
+	testPrefixTree(t, NewTrie[int]())
+	testPrefixTreeRandom(t, NewTrie[int]())
+}
+
+func TestTrie_Prune(t *testing.T) {
+	trie := NewTrie[int]()
+	trie.Insert("abc", 1)
+	trie.Delete("abc")
+	assert.Empty(t, trie.root.children)
+}
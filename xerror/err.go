@@ -64,3 +64,8 @@ func (ea errorArray) Error() string {
 
 	return buf.String()
 }
+
+// Unwrap returns the inside errors, so that errors.Is and errors.As look into all of them.
+func (ea errorArray) Unwrap() []error {
+	return ea
+}
@@ -61,3 +61,11 @@ func TestBatchErrorWithErrors(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf("%s\n%s", err1, err2), batch.Err().Error())
 	assert.True(t, batch.NotNil())
 }
+
+func TestBatchErrorUnwrap(t *testing.T) {
+	target := errors.New(err2)
+	var batch BatchError
+	batch.Add(errors.New(err1))
+	batch.Add(fmt.Errorf("wrapped: %w", target))
+	assert.ErrorIs(t, batch.Err(), target)
+}
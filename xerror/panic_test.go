@@ -0,0 +1,47 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xerror
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPanicError(t *testing.T) {
+	recovered := func(fn func()) (err *PanicError) {
+		defer func() {
+			if v := recover(); v != nil {
+				err = NewPanicError(v)
+			}
+		}()
+		fn()
+
+		return nil
+	}
+
+	err := recovered(func() { panic("boom") })
+	assert.EqualError(t, err, "panic: boom")
+	assert.Equal(t, "boom", err.Value)
+	assert.Contains(t, string(err.Stack), "TestPanicError")
+	assert.Nil(t, err.Unwrap())
+
+	cause := errors.New("cause")
+	err = recovered(func() { panic(cause) })
+	assert.ErrorIs(t, err, cause)
+	assert.Nil(t, recovered(func() {}))
+}
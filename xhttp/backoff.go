@@ -0,0 +1,51 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xhttp
+
+import (
+	"math/rand"
+	"time"
+)
+
+// A Backoff returns how long to wait before the given retry, counting from 1.
+type Backoff func(retry int) time.Duration
+
+// ConstantBackoff waits d before every retry.
+func ConstantBackoff(d time.Duration) Backoff {
+	return func(int) time.Duration {
+		return d
+	}
+}
+
+// ExponentialBackoff doubles the wait from base up to limit, with full jitter
+// so that many clients don't retry in lockstep.
+func ExponentialBackoff(base, limit time.Duration) Backoff {
+	return func(retry int) time.Duration {
+		d := base
+		for i := 1; i < retry && d < limit; i++ {
+			d <<= 1
+		}
+		if d > limit || d <= 0 {
+			d = limit
+		}
+		if d <= 0 {
+			return 0
+		}
+
+		return time.Duration(rand.Int63n(int64(d) + 1))
+	}
+}
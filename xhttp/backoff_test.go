@@ -0,0 +1,41 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xhttp
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestConstantBackoff(t *testing.T) {
+	backoff := ConstantBackoff(time.Second)
+	assert.Equal(t, time.Second, backoff(1))
+	assert.Equal(t, time.Second, backoff(10))
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(10*time.Millisecond, 100*time.Millisecond)
+	for i := 0; i < 100; i++ {
+		assert.LessOrEqual(t, backoff(1), 10*time.Millisecond)
+		assert.LessOrEqual(t, backoff(3), 40*time.Millisecond)
+		assert.LessOrEqual(t, backoff(100), 100*time.Millisecond)
+		assert.GreaterOrEqual(t, backoff(100), time.Duration(0))
+	}
+
+	assert.Equal(t, time.Duration(0), ExponentialBackoff(0, 0)(3))
+}
@@ -0,0 +1,372 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xhttp
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/chenquan/go-pkg/xvalidate"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	sourceQuery = "query"
+	sourceForm  = "form"
+
+	defaultMaxMemory = 32 << 20
+)
+
+var (
+	// ErrBindTarget is returned when binding into something that isn't a pointer to a struct.
+	ErrBindTarget = errors.New("xhttp: bind target must be a non-nil pointer to a struct")
+
+	bindPlans sync.Map // reflect.Type -> []bindField
+
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+)
+
+type (
+	// BindOption defines the method to customize Bind.
+	BindOption func(*bindOptions)
+
+	bindOptions struct {
+		validator *xvalidate.Validator
+		custom    bool
+		maxMemory int64
+	}
+
+	// A BindError is returned when the request can't be decoded into the target.
+	BindError struct {
+		// Source is where the value comes from: "query", "form" or "json".
+		Source string
+		// Field is the name of the parameter, empty for a malformed JSON body.
+		Field string
+		// Value is the raw value that failed.
+		Value string
+		Err   error
+	}
+
+	bindField struct {
+		index  []int
+		source string
+		name   string
+	}
+)
+
+// WithBindValidator customizes the Validator used after decoding, default to the xvalidate defaults.
+// A nil Validator disables validation.
+func WithBindValidator(v *xvalidate.Validator) BindOption {
+	return func(o *bindOptions) {
+		o.validator = v
+		o.custom = true
+	}
+}
+
+// WithMaxMemory customizes the memory used to parse multipart forms, default to 32MiB.
+func WithMaxMemory(n int64) BindOption {
+	return func(o *bindOptions) {
+		o.maxMemory = n
+	}
+}
+
+// Bind decodes r into dst, a pointer to a struct, then validates it with xvalidate.
+//
+// A JSON body is decoded with encoding/json. Fields tagged `form:"name"` are filled from
+// urlencoded or multipart forms, and fields tagged `query:"name"` from the URL query.
+// Strings are coerced into the type of the field: numbers, bools, time.Duration,
+// time.Time in RFC 3339, encoding.TextUnmarshaler, pointers and slices of those.
+// Untagged struct fields are bound recursively.
+//
+// Decoding failures are returned as a *BindError, and validation failures as xvalidate.Errors.
+func Bind(r *http.Request, dst interface{}, opts ...BindOption) error {
+	o := bindOptions{maxMemory: defaultMaxMemory}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrBindTarget
+	}
+	rv = rv.Elem()
+	fields := bindPlan(rv.Type())
+
+	if err := bindBody(r, dst, rv, fields, o.maxMemory); err != nil {
+		return err
+	}
+	if len(r.URL.RawQuery) > 0 {
+		query := r.URL.Query()
+		if err := bindValues(rv, fields, sourceQuery, query); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case !o.custom:
+		return xvalidate.Struct(dst)
+	case o.validator != nil:
+		return o.validator.Struct(dst)
+	default:
+		return nil
+	}
+}
+
+// Error returns a string that represents the failure.
+func (e *BindError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("xhttp: invalid %s body: %v", e.Source, e.Err)
+	}
+
+	return fmt.Sprintf("xhttp: invalid %s parameter %q: %v", e.Source, e.Field, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *BindError) Unwrap() error {
+	return e.Err
+}
+
+// StatusCode returns http.StatusBadRequest.
+func (e *BindError) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+func bindBody(r *http.Request, dst interface{}, rv reflect.Value, fields []bindField, maxMemory int64) error {
+	if r.Body == nil || r.Body == http.NoBody || r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/json":
+		if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+			var tooLarge *BodyTooLargeError
+			if errors.As(err, &tooLarge) {
+				return tooLarge
+			}
+
+			be := &BindError{Source: "json", Err: err}
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &typeErr) {
+				be.Field = typeErr.Field
+				be.Value = typeErr.Value
+			}
+			return be
+		}
+	case "application/x-www-form-urlencoded", "multipart/form-data":
+		var err error
+		if mediaType == "multipart/form-data" {
+			err = r.ParseMultipartForm(maxMemory)
+		} else {
+			err = r.ParseForm()
+		}
+		if err != nil {
+			var tooLarge *BodyTooLargeError
+			if errors.As(err, &tooLarge) {
+				return tooLarge
+			}
+			return &BindError{Source: sourceForm, Err: err}
+		}
+
+		return bindValues(rv, fields, sourceForm, r.PostForm)
+	}
+
+	return nil
+}
+
+func bindValues(rv reflect.Value, fields []bindField, source string, values map[string][]string) error {
+	for _, f := range fields {
+		if f.source != source {
+			continue
+		}
+
+		raw, ok := values[f.name]
+		if !ok || len(raw) == 0 {
+			continue
+		}
+
+		if value, err := setField(fieldByIndex(rv, f.index), raw); err != nil {
+			return &BindError{Source: source, Field: f.name, Value: value, Err: err}
+		}
+	}
+
+	return nil
+}
+
+// setField sets fv from raw, and returns the value that failed if any.
+func setField(fv reflect.Value, raw []string) (string, error) {
+	if fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() != reflect.Uint8 && !isScalar(fv.Type()) {
+		slice := reflect.MakeSlice(fv.Type(), len(raw), len(raw))
+		for i, s := range raw {
+			if err := setValue(slice.Index(i), s); err != nil {
+				return s, err
+			}
+		}
+		fv.Set(slice)
+		return "", nil
+	}
+
+	return raw[0], setValue(fv, raw[0])
+}
+
+func setValue(fv reflect.Value, s string) error {
+	if fv.Kind() == reflect.Ptr {
+		v := reflect.New(fv.Type().Elem())
+		if err := setValue(v.Elem(), s); err != nil {
+			return err
+		}
+		fv.Set(v)
+		return nil
+	}
+
+	if fv.CanAddr() && fv.Addr().Type().Implements(textUnmarshalerType) {
+		return fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch fv.Type() {
+	case durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(int64(d))
+		return nil
+	case timeType:
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(f)
+	case reflect.Slice:
+		// []byte
+		fv.SetBytes([]byte(s))
+	default:
+		return fmt.Errorf("unsupported type %s", fv.Type())
+	}
+
+	return nil
+}
+
+// isScalar reports whether a slice type is decoded from a single value.
+func isScalar(t reflect.Type) bool {
+	return reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
+
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	return v
+}
+
+func bindPlan(t reflect.Type) []bindField {
+	if fields, ok := bindPlans.Load(t); ok {
+		return fields.([]bindField)
+	}
+
+	fields := collectBindFields(t, nil, map[reflect.Type]bool{})
+	bindPlans.Store(t, fields)
+
+	return fields
+}
+
+func collectBindFields(t reflect.Type, index []int, visiting map[reflect.Type]bool) []bindField {
+	if visiting[t] {
+		return nil
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	var fields []bindField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		// the exported fields of an unexported embedded struct are still settable.
+		if !sf.IsExported() && !(sf.Anonymous && sf.Type.Kind() == reflect.Struct) {
+			continue
+		}
+
+		fieldIndex := append(append([]int(nil), index...), i)
+		tagged := false
+		for _, source := range []string{sourceQuery, sourceForm} {
+			if name, ok := sf.Tag.Lookup(source); ok && name != "-" {
+				if name == "" {
+					name = sf.Name
+				}
+				fields = append(fields, bindField{index: fieldIndex, source: source, name: name})
+				tagged = true
+			}
+		}
+		if tagged {
+			continue
+		}
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && ft != timeType && !reflect.PtrTo(ft).Implements(textUnmarshalerType) {
+			fields = append(fields, collectBindFields(ft, fieldIndex, visiting)...)
+		}
+	}
+
+	return fields
+}
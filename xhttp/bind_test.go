@@ -0,0 +1,170 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xhttp
+
+import (
+	"bytes"
+	"errors"
+	"github.com/chenquan/go-pkg/xvalidate"
+	"github.com/stretchr/testify/assert"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+type (
+	BindPage struct {
+		Page int  `query:"page" validate:"min=1"`
+		Size *int `query:"size"`
+	}
+
+	bindRequest struct {
+		BindPage
+		Name    string        `json:"name" form:"name" validate:"required"`
+		Age     uint8         `json:"age" form:"age"`
+		Tags    []string      `json:"tags" form:"tag"`
+		IDs     []int64       `query:"id"`
+		Debug   bool          `query:"debug"`
+		Ratio   float64       `query:"ratio"`
+		Timeout time.Duration `query:"timeout"`
+		Since   time.Time     `query:"since"`
+		IP      net.IP        `query:"ip"`
+		Filter  *bindFilter
+		Ignored string `query:"-"`
+	}
+
+	bindFilter struct {
+		Status string `query:"status"`
+	}
+)
+
+func TestBind_JSON(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost,
+		"/?page=2&size=10&id=1&id=2&debug=true&ratio=0.5&timeout=1m&since=2021-01-02T03:04:05Z&ip=10.0.0.1&status=open&Ignored=x",
+		strings.NewReader(`{"name":"go-pkg","age":3,"tags":["a","b"]}`))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	var dst bindRequest
+	assert.NoError(t, Bind(req, &dst))
+	assert.Equal(t, "go-pkg", dst.Name)
+	assert.Equal(t, uint8(3), dst.Age)
+	assert.Equal(t, []string{"a", "b"}, dst.Tags)
+	assert.Equal(t, 2, dst.Page)
+	assert.Equal(t, 10, *dst.Size)
+	assert.Equal(t, []int64{1, 2}, dst.IDs)
+	assert.True(t, dst.Debug)
+	assert.Equal(t, 0.5, dst.Ratio)
+	assert.Equal(t, time.Minute, dst.Timeout)
+	assert.Equal(t, time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC), dst.Since)
+	assert.Equal(t, "10.0.0.1", dst.IP.String())
+	assert.Equal(t, "open", dst.Filter.Status)
+	assert.Empty(t, dst.Ignored)
+}
+
+func TestBind_Form(t *testing.T) {
+	form := url.Values{"name": {"go-pkg"}, "age": {"7"}, "tag": {"x", "y"}}
+	req := httptest.NewRequest(http.MethodPost, "/?page=1", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var dst bindRequest
+	assert.NoError(t, Bind(req, &dst))
+	assert.Equal(t, "go-pkg", dst.Name)
+	assert.Equal(t, uint8(7), dst.Age)
+	assert.Equal(t, []string{"x", "y"}, dst.Tags)
+	assert.Nil(t, dst.Filter)
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	assert.NoError(t, mw.WriteField("name", "multipart"))
+	assert.NoError(t, mw.Close())
+	req = httptest.NewRequest(http.MethodPost, "/?page=1", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	dst = bindRequest{}
+	assert.NoError(t, Bind(req, &dst, WithMaxMemory(1<<10)))
+	assert.Equal(t, "multipart", dst.Name)
+}
+
+func TestBind_Errors(t *testing.T) {
+	var dst bindRequest
+
+	err := Bind(httptest.NewRequest(http.MethodGet, "/?page=x", nil), &dst)
+	var be *BindError
+	assert.True(t, errors.As(err, &be))
+	assert.Equal(t, "query", be.Source)
+	assert.Equal(t, "page", be.Field)
+	assert.Equal(t, "x", be.Value)
+	assert.Equal(t, http.StatusBadRequest, be.StatusCode())
+	assert.EqualError(t, err, `xhttp: invalid query parameter "page": strconv.ParseInt: parsing "x": invalid syntax`)
+
+	err = Bind(httptest.NewRequest(http.MethodGet, "/?id=1&id=b", nil), &dst)
+	assert.True(t, errors.As(err, &be))
+	assert.Equal(t, "b", be.Value)
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"age":300}`))
+	req.Header.Set("Content-Type", "application/json")
+	err = Bind(req, &dst)
+	assert.True(t, errors.As(err, &be))
+	assert.Equal(t, "json", be.Source)
+	assert.Equal(t, "age", be.Field)
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{`))
+	req.Header.Set("Content-Type", "application/json")
+	err = Bind(req, &dst)
+	assert.True(t, errors.As(err, &be))
+	assert.Contains(t, err.Error(), "xhttp: invalid json body")
+
+	assert.ErrorIs(t, Bind(httptest.NewRequest(http.MethodGet, "/", nil), dst), ErrBindTarget)
+	assert.ErrorIs(t, Bind(httptest.NewRequest(http.MethodGet, "/", nil), (*bindRequest)(nil)), ErrBindTarget)
+	var n int
+	assert.ErrorIs(t, Bind(httptest.NewRequest(http.MethodGet, "/", nil), &n), ErrBindTarget)
+}
+
+func TestBind_Validate(t *testing.T) {
+	var dst bindRequest
+	err := Bind(httptest.NewRequest(http.MethodGet, "/?page=0", nil), &dst)
+	var errs xvalidate.Errors
+	assert.True(t, errors.As(err, &errs))
+	assert.Len(t, errs.ByPath("Name"), 1)
+	assert.Len(t, errs.ByPath("BindPage.Page"), 1)
+
+	assert.NoError(t, Bind(httptest.NewRequest(http.MethodGet, "/?page=0", nil), &dst, WithBindValidator(nil)))
+
+	v := xvalidate.NewValidator(xvalidate.WithTagName("check"))
+	assert.NoError(t, Bind(httptest.NewRequest(http.MethodGet, "/?page=0", nil), &dst, WithBindValidator(v)))
+}
+
+func TestBind_BodyTooLarge(t *testing.T) {
+	handler := MaxBytesBody(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var dst bindRequest
+		err := Bind(r, &dst)
+		var tooLarge *BodyTooLargeError
+		assert.True(t, errors.As(err, &tooLarge))
+	}), 4)
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"go-pkg"}`))
+	req.ContentLength = -1
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
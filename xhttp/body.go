@@ -0,0 +1,114 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xhttp
+
+import (
+	"errors"
+	"fmt"
+	"github.com/chenquan/go-pkg/xio"
+	"io"
+	"net/http"
+)
+
+type (
+	// A BodyTooLargeError is returned when reading a request body over the limit of MaxBytesBody.
+	BodyTooLargeError struct {
+		Limit int64
+	}
+
+	limitedBody struct {
+		io.ReadCloser
+		limit int64
+		hit   bool
+	}
+
+	peekedBody struct {
+		io.Reader
+		io.Closer
+	}
+)
+
+// Error returns a string that represents the limit.
+func (e *BodyTooLargeError) Error() string {
+	return fmt.Sprintf("xhttp: request body larger than %d bytes", e.Limit)
+}
+
+// StatusCode returns http.StatusRequestEntityTooLarge.
+func (e *BodyTooLargeError) StatusCode() int {
+	return http.StatusRequestEntityTooLarge
+}
+
+// MaxBytesBody returns an http.Handler that limits the request bodies of h to limit bytes.
+// A request declaring a larger Content-Length is rejected with 413 without calling h.
+// Reading over the limit returns a *BodyTooLargeError, and a 413 response is sent
+// if h has not written anything.
+func MaxBytesBody(h http.Handler, limit int64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > limit {
+			writeBodyTooLarge(w, limit)
+			return
+		}
+		if r.Body == nil || r.Body == http.NoBody {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		sw := wrapStatusWriter(w)
+		body := &limitedBody{ReadCloser: http.MaxBytesReader(sw, r.Body, limit), limit: limit}
+		r2 := r.Clone(r.Context())
+		r2.Body = body
+		h.ServeHTTP(sw, r2)
+		if body.hit && sw.status == 0 {
+			writeBodyTooLarge(sw, limit)
+		}
+	})
+}
+
+// PeekBody returns up to the first n bytes of the body of r, and leaves the whole body readable.
+// A body shorter than n is not an error.
+func PeekBody(r *http.Request, n int) ([]byte, error) {
+	if r.Body == nil || r.Body == http.NoBody || n <= 0 {
+		return nil, nil
+	}
+
+	replay := xio.NewReplayReader(r.Body)
+	_, err := io.CopyN(io.Discard, replay, int64(n))
+	if errors.Is(err, io.EOF) {
+		err = nil
+	}
+	peeked := append([]byte(nil), replay.Recorded()...)
+	r.Body = peekedBody{Reader: replay.Replay(), Closer: r.Body}
+
+	return peeked, err
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		b.hit = true
+		err = &BodyTooLargeError{Limit: b.limit}
+	}
+
+	return n, err
+}
+
+func writeBodyTooLarge(w http.ResponseWriter, limit int64) {
+	// the rest of the body is not read, so the connection can't be reused.
+	w.Header().Set("Connection", "close")
+	http.Error(w, (&BodyTooLargeError{Limit: limit}).Error(), http.StatusRequestEntityTooLarge)
+}
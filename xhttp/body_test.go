@@ -0,0 +1,113 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xhttp
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMaxBytesBody(t *testing.T) {
+	var readErr error
+	handler := MaxBytesBody(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data []byte
+		data, readErr = io.ReadAll(r.Body)
+		if r.URL.Path == "/custom" && readErr != nil {
+			http.Error(w, "custom", http.StatusBadRequest)
+			return
+		}
+		if readErr == nil {
+			_, _ = w.Write(data)
+		}
+	}), 5)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("hello")))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "hello", rec.Body.String())
+
+	// declared too large.
+	rec = httptest.NewRecorder()
+	readErr = nil
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("hello world")))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.NoError(t, readErr)
+
+	// found too large while reading.
+	req := httptest.NewRequest(http.MethodPost, "/", io.NopCloser(strings.NewReader("hello world")))
+	req.ContentLength = -1
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	var tooLarge *BodyTooLargeError
+	assert.True(t, errors.As(readErr, &tooLarge))
+	assert.Equal(t, int64(5), tooLarge.Limit)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, tooLarge.StatusCode())
+	assert.EqualError(t, tooLarge, "xhttp: request body larger than 5 bytes")
+
+	// the handler answers itself.
+	req = httptest.NewRequest(http.MethodPost, "/custom", io.NopCloser(strings.NewReader("hello world")))
+	req.ContentLength = -1
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestPeekBody(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"go-pkg"}`))
+	peeked, err := PeekBody(req, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "{", string(peeked))
+
+	peeked, err = PeekBody(req, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name":"go-pkg"}`, string(peeked))
+
+	data, err := io.ReadAll(req.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name":"go-pkg"}`, string(data))
+	assert.NoError(t, req.Body.Close())
+
+	peeked, err = PeekBody(httptest.NewRequest(http.MethodGet, "/", nil), 10)
+	assert.NoError(t, err)
+	assert.Nil(t, peeked)
+}
+
+func TestPeekBody_WithinLimit(t *testing.T) {
+	handler := MaxBytesBody(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		peeked, err := PeekBody(r, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, "he", string(peeked))
+		_, err = io.ReadAll(r.Body)
+		assert.Error(t, err)
+	}), 5)
+
+	req := httptest.NewRequest(http.MethodPost, "/", io.NopCloser(strings.NewReader("hello world")))
+	req.ContentLength = -1
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
@@ -0,0 +1,340 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xhttp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetries       = 2
+	defaultMaxRetryAfter = time.Minute
+	maxDrainBytes        = 4 << 10
+)
+
+var defaultRetryStatus = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+type (
+	// ClientOption defines the method to customize a Client.
+	ClientOption func(*clientOptions)
+
+	// A RequestHook is called before every attempt, an error aborts the request.
+	RequestHook func(req *http.Request, attempt int) error
+
+	// A ResponseHook is called after every attempt with its response or error.
+	ResponseHook func(req *http.Request, resp *http.Response, err error, attempt int)
+
+	clientOptions struct {
+		client         *http.Client
+		retries        int
+		retryStatus    map[int]bool
+		backoff        Backoff
+		maxRetryAfter  time.Duration
+		attemptTimeout time.Duration
+		timeout        time.Duration
+		requestHooks   []RequestHook
+		responseHooks  []ResponseHook
+	}
+
+	// A Client is an http.Client that retries idempotent requests.
+	//
+	// A request is retried on a network error or a retryable status code, if its method is idempotent
+	// or it has an Idempotency-Key header, and its body can be replayed with GetBody.
+	// The Retry-After header of the response is honored.
+	Client struct {
+		options clientOptions
+	}
+
+	cancelBody struct {
+		io.ReadCloser
+		cancel context.CancelFunc
+	}
+)
+
+// WithHTTPClient customizes the underlying http.Client, default to http.DefaultClient.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(o *clientOptions) {
+		o.client = client
+	}
+}
+
+// WithRetries customizes the maximum number of retries, default to 2.
+func WithRetries(n int) ClientOption {
+	return func(o *clientOptions) {
+		o.retries = n
+	}
+}
+
+// WithRetryStatus customizes the status codes to retry on, default to 429, 502, 503 and 504.
+func WithRetryStatus(codes ...int) ClientOption {
+	return func(o *clientOptions) {
+		o.retryStatus = make(map[int]bool, len(codes))
+		for _, code := range codes {
+			o.retryStatus[code] = true
+		}
+	}
+}
+
+// WithBackoff customizes the wait between attempts, default to an ExponentialBackoff from 100ms to 5s.
+func WithBackoff(backoff Backoff) ClientOption {
+	return func(o *clientOptions) {
+		o.backoff = backoff
+	}
+}
+
+// WithMaxRetryAfter caps the wait asked by a Retry-After header, default to one minute.
+// A response asking for longer is returned as is.
+func WithMaxRetryAfter(d time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.maxRetryAfter = d
+	}
+}
+
+// WithAttemptTimeout bounds every attempt, until its response body is closed.
+func WithAttemptTimeout(d time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.attemptTimeout = d
+	}
+}
+
+// WithTimeout bounds the whole request including the retries, until the response body is closed.
+func WithTimeout(d time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.timeout = d
+	}
+}
+
+// WithRequestHook adds a hook called before every attempt.
+func WithRequestHook(hook RequestHook) ClientOption {
+	return func(o *clientOptions) {
+		o.requestHooks = append(o.requestHooks, hook)
+	}
+}
+
+// WithResponseHook adds a hook called after every attempt.
+func WithResponseHook(hook ResponseHook) ClientOption {
+	return func(o *clientOptions) {
+		o.responseHooks = append(o.responseHooks, hook)
+	}
+}
+
+// NewClient returns a Client.
+func NewClient(opts ...ClientOption) *Client {
+	options := clientOptions{
+		client:        http.DefaultClient,
+		retries:       defaultRetries,
+		backoff:       ExponentialBackoff(100*time.Millisecond, 5*time.Second),
+		maxRetryAfter: defaultMaxRetryAfter,
+	}
+	WithRetryStatus(defaultRetryStatus...)(&options)
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &Client{options: options}
+}
+
+// Get issues a GET to url.
+func (c *Client) Get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Do(req)
+}
+
+// Do sends req with retries, see http.Client.Do.
+// The response body must be closed to release the timeouts.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	cancel := context.CancelFunc(func() {})
+	if c.options.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.options.timeout)
+	}
+
+	retryable := c.retryable(req)
+	for attempt := 0; ; attempt++ {
+		resp, sent, err := c.attempt(ctx, req, attempt)
+		if !sent {
+			// a failed hook or body replay isn't a transient error, it's returned as is.
+			cancel()
+			return nil, err
+		}
+		if attempt >= c.options.retries || !retryable || ctx.Err() != nil {
+			return c.finish(resp, err, cancel)
+		}
+
+		var wait time.Duration
+		switch {
+		case err != nil:
+			wait = c.options.backoff(attempt + 1)
+		case c.options.retryStatus[resp.StatusCode]:
+			var ok bool
+			if wait, ok = c.retryAfter(resp, attempt+1); !ok {
+				return c.finish(resp, err, cancel)
+			}
+			drain(resp.Body)
+		default:
+			return c.finish(resp, err, cancel)
+		}
+
+		if err := sleep(ctx, wait); err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+}
+
+// attempt sends req once, it reports whether req has been sent so that the errors
+// of the request hooks and of GetBody aren't retried.
+func (c *Client) attempt(ctx context.Context, req *http.Request, attempt int) (*http.Response, bool, error) {
+	cancel := context.CancelFunc(func() {})
+	if c.options.attemptTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.options.attemptTimeout)
+	}
+
+	r := req.Clone(ctx)
+	if attempt > 0 && req.Body != nil && req.Body != http.NoBody {
+		body, err := req.GetBody()
+		if err != nil {
+			cancel()
+			return nil, false, err
+		}
+		r.Body = body
+	}
+
+	for _, hook := range c.options.requestHooks {
+		if err := hook(r, attempt); err != nil {
+			cancel()
+			if r.Body != nil {
+				_ = r.Body.Close()
+			}
+			return nil, false, err
+		}
+	}
+
+	resp, err := c.options.client.Do(r)
+	for _, hook := range c.options.responseHooks {
+		hook(r, resp, err, attempt)
+	}
+	if err != nil {
+		cancel()
+		return nil, true, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+
+	return resp, true, nil
+}
+
+func (c *Client) finish(resp *http.Response, err error, cancel context.CancelFunc) (*http.Response, error) {
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+
+	return resp, nil
+}
+
+func (c *Client) retryable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return req.Header.Get("Idempotency-Key") != ""
+	}
+}
+
+// retryAfter returns the wait before the retry, and false if Retry-After asks for too long.
+func (c *Client) retryAfter(resp *http.Response, retry int) (time.Duration, bool) {
+	wait, ok := ParseRetryAfter(resp.Header.Get("Retry-After"))
+	if !ok {
+		return c.options.backoff(retry), true
+	}
+
+	return wait, wait <= c.options.maxRetryAfter
+}
+
+// ParseRetryAfter parses the value of a Retry-After header, either delay seconds or an HTTP date.
+func ParseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	wait := time.Until(date)
+	if wait < 0 {
+		wait = 0
+	}
+
+	return wait, true
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+
+	return err
+}
+
+// drain reads a bit of body before closing it, so that the connection can be reused.
+func drain(body io.ReadCloser) {
+	_, _ = io.CopyN(io.Discard, body, maxDrainBytes)
+	_ = body.Close()
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
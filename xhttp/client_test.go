@@ -0,0 +1,243 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xhttp
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newFlakyServer(t *testing.T, failures int32, status int, header http.Header) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		body, _ := io.ReadAll(r.Body)
+		if n <= failures {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(status)
+			return
+		}
+		_, _ = w.Write(append([]byte("ok:"), body...))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &calls
+}
+
+func TestClient_Retry(t *testing.T) {
+	server, calls := newFlakyServer(t, 2, http.StatusServiceUnavailable, nil)
+	var attempts []int
+	client := NewClient(
+		WithBackoff(ConstantBackoff(time.Millisecond)),
+		WithRequestHook(func(req *http.Request, attempt int) error {
+			attempts = append(attempts, attempt)
+			return nil
+		}),
+		WithResponseHook(func(req *http.Request, resp *http.Response, err error, attempt int) {
+			assert.NoError(t, err)
+		}),
+	)
+
+	resp, err := client.Get(context.Background(), server.URL)
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, "ok:", string(body))
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+	assert.Equal(t, []int{0, 1, 2}, attempts)
+}
+
+func TestClient_GiveUp(t *testing.T) {
+	server, calls := newFlakyServer(t, 10, http.StatusBadGateway, nil)
+	client := NewClient(WithRetries(1), WithBackoff(ConstantBackoff(0)))
+
+	resp, err := client.Get(context.Background(), server.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	resp.Body.Close()
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+func TestClient_NotRetryable(t *testing.T) {
+	server, calls := newFlakyServer(t, 1, http.StatusServiceUnavailable, nil)
+	client := NewClient(WithBackoff(ConstantBackoff(0)))
+
+	// POST is not idempotent.
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("x"))
+	resp, err := client.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	resp.Body.Close()
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+
+	// unless it has an idempotency key, and the body is replayed.
+	req, _ = http.NewRequest(http.MethodPost, server.URL, strings.NewReader("x"))
+	req.Header.Set("Idempotency-Key", "k")
+	atomic.StoreInt32(calls, 0)
+	resp, err = client.Do(req)
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "ok:x", string(body))
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+
+	// a body that can't be replayed.
+	req, _ = http.NewRequest(http.MethodPut, server.URL, io.NopCloser(strings.NewReader("x")))
+	atomic.StoreInt32(calls, 0)
+	resp, err = client.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+
+	// a status that is not retried.
+	server, calls = newFlakyServer(t, 1, http.StatusInternalServerError, nil)
+	resp, err = client.Get(context.Background(), server.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+
+	resp, err = NewClient(WithRetryStatus(http.StatusInternalServerError), WithBackoff(ConstantBackoff(0))).
+		Get(context.Background(), server.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestClient_RetryAfter(t *testing.T) {
+	server, calls := newFlakyServer(t, 1, http.StatusTooManyRequests, http.Header{"Retry-After": {"1"}})
+	client := NewClient(WithBackoff(ConstantBackoff(0)))
+
+	start := time.Now()
+	resp, err := client.Get(context.Background(), server.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+
+	// too long to wait.
+	server, calls = newFlakyServer(t, 1, http.StatusTooManyRequests, http.Header{"Retry-After": {"3600"}})
+	resp, err = client.Get(context.Background(), server.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	d, ok := ParseRetryAfter("120")
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Minute, d)
+
+	d, ok = ParseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.InDelta(t, float64(time.Hour), float64(d), float64(2*time.Second))
+
+	d, ok = ParseRetryAfter("Mon, 02 Jan 2006 15:04:05 GMT")
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), d)
+
+	for _, value := range []string{"", "-1", "soon"} {
+		_, ok = ParseRetryAfter(value)
+		assert.False(t, ok, value)
+	}
+}
+
+func TestClient_Timeouts(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithAttemptTimeout(50*time.Millisecond), WithBackoff(ConstantBackoff(0)))
+	resp, err := client.Get(context.Background(), server.URL)
+	assert.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "ok", string(body))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer slow.Close()
+	client = NewClient(WithTimeout(50*time.Millisecond), WithBackoff(ConstantBackoff(0)))
+	_, err = client.Get(context.Background(), slow.URL)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestClient_Hooks(t *testing.T) {
+	server, calls := newFlakyServer(t, 0, 0, nil)
+	hookErr := errors.New("rejected")
+	var hooks int32
+	client := NewClient(WithBackoff(ConstantBackoff(time.Millisecond)), WithRequestHook(func(req *http.Request, attempt int) error {
+		atomic.AddInt32(&hooks, 1)
+		return hookErr
+	}))
+	_, err := client.Get(context.Background(), server.URL)
+	// an aborted request isn't retried.
+	assert.Equal(t, hookErr, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&hooks))
+	assert.Equal(t, int32(0), atomic.LoadInt32(calls))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = NewClient().Get(ctx, server.URL)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = NewClient().Get(context.Background(), "://bad")
+	assert.Error(t, err)
+}
+
+func TestClient_GetBodyError(t *testing.T) {
+	server, calls := newFlakyServer(t, 5, http.StatusServiceUnavailable, nil)
+	bodyErr := errors.New("body gone")
+
+	req, err := http.NewRequest(http.MethodPut, server.URL, strings.NewReader("payload"))
+	assert.NoError(t, err)
+	var replays int32
+	req.GetBody = func() (io.ReadCloser, error) {
+		atomic.AddInt32(&replays, 1)
+		return nil, bodyErr
+	}
+
+	client := NewClient(WithRetries(5), WithBackoff(ConstantBackoff(time.Millisecond)))
+	_, err = client.Do(req)
+	assert.Equal(t, bodyErr, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&replays))
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
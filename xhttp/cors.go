@@ -0,0 +1,178 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xhttp
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var defaultCORSMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost}
+
+type (
+	// CORSOption defines the method to customize the CORS middleware.
+	CORSOption func(*corsOptions)
+
+	corsOptions struct {
+		origins     map[string]bool
+		anyOrigin   bool
+		methods     string
+		headers     map[string]bool
+		anyHeader   bool
+		exposed     string
+		credentials bool
+		maxAge      time.Duration
+	}
+)
+
+// WithAllowedOrigins customizes the allowed origins, "*" allows any, default to any.
+func WithAllowedOrigins(origins ...string) CORSOption {
+	return func(o *corsOptions) {
+		o.origins = make(map[string]bool, len(origins))
+		o.anyOrigin = false
+		for _, origin := range origins {
+			if origin == "*" {
+				o.anyOrigin = true
+			}
+			o.origins[strings.ToLower(origin)] = true
+		}
+	}
+}
+
+// WithAllowedMethods customizes the allowed methods, default to GET, HEAD and POST.
+func WithAllowedMethods(methods ...string) CORSOption {
+	return func(o *corsOptions) {
+		o.methods = strings.ToUpper(strings.Join(methods, ", "))
+	}
+}
+
+// WithAllowedHeaders customizes the request headers allowed, "*" allows any.
+// By default only the CORS-safelisted headers are allowed.
+func WithAllowedHeaders(headers ...string) CORSOption {
+	return func(o *corsOptions) {
+		o.headers = make(map[string]bool, len(headers))
+		o.anyHeader = false
+		for _, header := range headers {
+			if header == "*" {
+				o.anyHeader = true
+			}
+			o.headers[http.CanonicalHeaderKey(header)] = true
+		}
+	}
+}
+
+// WithExposedHeaders customizes the response headers exposed to the client.
+func WithExposedHeaders(headers ...string) CORSOption {
+	return func(o *corsOptions) {
+		o.exposed = strings.Join(headers, ", ")
+	}
+}
+
+// WithAllowCredentials allows requests with credentials.
+// The allowed origin is then echoed instead of "*", so it requires WithAllowedOrigins:
+// an explicit "*" lets any site make credentialed requests and must be a deliberate choice.
+func WithAllowCredentials() CORSOption {
+	return func(o *corsOptions) {
+		o.credentials = true
+	}
+}
+
+// WithMaxAge customizes how long a preflight response can be cached.
+func WithMaxAge(d time.Duration) CORSOption {
+	return func(o *corsOptions) {
+		o.maxAge = d
+	}
+}
+
+// CORS returns a Middleware that implements Cross-Origin Resource Sharing.
+// Preflight requests are answered with 204 without calling the handler,
+// and disallowed origins get no CORS headers.
+// It panics if WithAllowCredentials is given without WithAllowedOrigins.
+func CORS(opts ...CORSOption) Middleware {
+	o := corsOptions{anyOrigin: true, methods: strings.Join(defaultCORSMethods, ", ")}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.credentials && o.origins == nil {
+		panic("xhttp: CORS with credentials requires explicit allowed origins")
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			h := w.Header()
+			h.Add("Vary", "Origin")
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			if origin == "" || !o.allowOrigin(origin) {
+				if preflight {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if o.anyOrigin && !o.credentials {
+				h.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				h.Set("Access-Control-Allow-Origin", origin)
+			}
+			if o.credentials {
+				h.Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if !preflight {
+				if o.exposed != "" {
+					h.Set("Access-Control-Expose-Headers", o.exposed)
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h.Add("Vary", "Access-Control-Request-Method")
+			h.Add("Vary", "Access-Control-Request-Headers")
+			h.Set("Access-Control-Allow-Methods", o.methods)
+			if requested := r.Header.Get("Access-Control-Request-Headers"); requested != "" && o.allowHeaders(requested) {
+				h.Set("Access-Control-Allow-Headers", requested)
+			}
+			if o.maxAge > 0 {
+				h.Set("Access-Control-Max-Age", strconv.Itoa(int(o.maxAge/time.Second)))
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
+
+func (o *corsOptions) allowOrigin(origin string) bool {
+	return o.anyOrigin || o.origins[strings.ToLower(origin)]
+}
+
+func (o *corsOptions) allowHeaders(requested string) bool {
+	if o.anyHeader {
+		return true
+	}
+
+	for _, header := range strings.Split(requested, ",") {
+		if !o.headers[http.CanonicalHeaderKey(strings.TrimSpace(header))] {
+			return false
+		}
+	}
+
+	return true
+}
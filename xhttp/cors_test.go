@@ -0,0 +1,104 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xhttp
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCORS(t *testing.T) {
+	called := 0
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called++
+	})
+
+	serve := func(handler http.Handler, method, origin string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/", nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	handler := CORS(WithExposedHeaders("X-Total"))(next)
+	rec := serve(handler, http.MethodGet, "https://a.example", nil)
+	assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "X-Total", rec.Header().Get("Access-Control-Expose-Headers"))
+	assert.Equal(t, 1, called)
+
+	rec = serve(handler, http.MethodGet, "", nil)
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, 2, called)
+
+	handler = CORS(
+		WithAllowedOrigins("https://a.example"),
+		WithAllowedMethods("get", "put"),
+		WithAllowedHeaders("Content-Type", "x-request-id"),
+		WithAllowCredentials(),
+		WithMaxAge(time.Hour),
+	)(next)
+
+	called = 0
+	rec = serve(handler, http.MethodOptions, "https://A.example", http.Header{
+		"Access-Control-Request-Method":  {"PUT"},
+		"Access-Control-Request-Headers": {"content-type, X-Request-Id"},
+	})
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, 0, called)
+	assert.Equal(t, "https://A.example", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", rec.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "GET, PUT", rec.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "content-type, X-Request-Id", rec.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "3600", rec.Header().Get("Access-Control-Max-Age"))
+	assert.Contains(t, rec.Header().Values("Vary"), "Origin")
+
+	rec = serve(handler, http.MethodOptions, "https://a.example", http.Header{
+		"Access-Control-Request-Method":  {"PUT"},
+		"Access-Control-Request-Headers": {"X-Other"},
+	})
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Headers"))
+
+	rec = serve(handler, http.MethodOptions, "https://b.example", http.Header{
+		"Access-Control-Request-Method": {"PUT"},
+	})
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+
+	// a plain OPTIONS request is not a preflight.
+	serve(handler, http.MethodOptions, "https://a.example", nil)
+	assert.Equal(t, 1, called)
+
+	rec = serve(CORS(WithAllowedOrigins("*"), WithAllowCredentials(), WithAllowedHeaders("*"))(next),
+		http.MethodOptions, "https://c.example", http.Header{
+			"Access-Control-Request-Method":  {"DELETE"},
+			"Access-Control-Request-Headers": {"X-Anything"},
+		})
+	assert.Equal(t, "https://c.example", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "X-Anything", rec.Header().Get("Access-Control-Allow-Headers"))
+
+	// the default any origin isn't reflected with credentials.
+	assert.Panics(t, func() { CORS(WithAllowCredentials()) })
+}
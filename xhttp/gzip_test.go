@@ -0,0 +1,144 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xhttp
+
+import (
+	"compress/gzip"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func gunzip(t *testing.T, body io.Reader) string {
+	gz, err := gzip.NewReader(body)
+	assert.NoError(t, err)
+	data, err := io.ReadAll(gz)
+	assert.NoError(t, err)
+
+	return string(data)
+}
+
+func TestGzip(t *testing.T) {
+	large := strings.Repeat("hello ", 1000)
+	handler := Gzip()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/small":
+			_, _ = w.Write([]byte("small"))
+		case "/encoded":
+			w.Header().Set("Content-Encoding", "br")
+			_, _ = w.Write([]byte(large))
+		case "/empty":
+			w.WriteHeader(http.StatusNoContent)
+		case "/nothing":
+		case "/html":
+			_, _ = w.Write([]byte("<!DOCTYPE html><html>" + large))
+		case "/json":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(large))
+		default:
+			w.Header().Set("Content-Length", "6000")
+			w.WriteHeader(http.StatusCreated)
+			for i := 0; i < 1000; i++ {
+				_, _ = w.Write([]byte("hello "))
+			}
+		}
+	}))
+
+	serve := func(path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if accept != "" {
+			req.Header.Set("Accept-Encoding", accept)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve("/", "gzip, deflate")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+	assert.Empty(t, rec.Header().Get("Content-Length"))
+	assert.Less(t, rec.Body.Len(), len(large))
+	assert.Equal(t, large, gunzip(t, rec.Body))
+
+	rec = serve("/", "")
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, large, rec.Body.String())
+
+	rec = serve("/", "gzip;q=0")
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+
+	rec = serve("/small", "gzip")
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, "small", rec.Body.String())
+
+	rec = serve("/encoded", "gzip")
+	assert.Equal(t, "br", rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, large, rec.Body.String())
+
+	rec = serve("/empty", "gzip")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+
+	// the type is sniffed from the plain body.
+	rec = serve("/html", "gzip")
+	assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	rec = serve("/json", "gzip")
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	rec = serve("/nothing", "gzip")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Zero(t, rec.Body.Len())
+}
+
+func TestGzip_Flush(t *testing.T) {
+	handler := Gzip(WithGzipLevel(gzip.BestSpeed), WithGzipMinSize(1<<20))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("event"))
+			assert.NoError(t, http.NewResponseController(w).Flush())
+			_, _ = w.Write([]byte(" more"))
+		}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.True(t, rec.Flushed)
+	assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, "event more", gunzip(t, rec.Body))
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	assert.True(t, acceptsGzip("gzip"))
+	assert.True(t, acceptsGzip("deflate, GZIP;q=0.5"))
+	assert.False(t, acceptsGzip(""))
+	assert.False(t, acceptsGzip("br"))
+	assert.False(t, acceptsGzip("gzip; q=0"))
+	assert.False(t, acceptsGzip("gzip;q=0.000"))
+	assert.False(t, acceptsGzip("gzip;q=bad"))
+}
+
+func TestGzip_InvalidLevel(t *testing.T) {
+	assert.Panics(t, func() {
+		Gzip(WithGzipLevel(42))
+	})
+}
@@ -0,0 +1,155 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xhttp
+
+import (
+	"github.com/chenquan/go-pkg/xerror"
+	"github.com/chenquan/go-pkg/xlog"
+	"net/http"
+	"time"
+)
+
+type (
+	// A Middleware wraps an http.Handler with additional behavior.
+	Middleware func(http.Handler) http.Handler
+
+	statusWriter struct {
+		http.ResponseWriter
+		status int
+		bytes  int64
+	}
+)
+
+// Chain returns a Middleware that applies mw in order, the first one being the outermost.
+func Chain(mw ...Middleware) Middleware {
+	return func(next http.Handler) http.Handler {
+		for i := len(mw) - 1; i >= 0; i-- {
+			next = mw[i](next)
+		}
+
+		return next
+	}
+}
+
+// Recover returns a Middleware that recovers from panics in the handler.
+// The panic is logged as an xerror.PanicError with its stack, and a 500 response is sent
+// if nothing has been written yet. http.ErrAbortHandler is panicked again as net/http expects.
+func Recover(logger xlog.Logger) Middleware {
+	logger = xlog.OrNop(logger)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sw := wrapStatusWriter(w)
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+
+				err := xerror.NewPanicError(v)
+				xlog.WithContext(r.Context(), logger).Error("http: panic serving request",
+					"method", r.Method, "path", r.URL.Path, "err", err, "stack", string(err.Stack))
+				if sw.status == 0 {
+					http.Error(sw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				}
+			}()
+
+			next.ServeHTTP(sw, r)
+		})
+	}
+}
+
+// Logging returns a Middleware that logs every request with its status, size and duration.
+// Server errors are logged at error level, client errors at warn level and the others at info level.
+func Logging(logger xlog.Logger) Middleware {
+	logger = xlog.OrNop(logger)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			sw := wrapStatusWriter(w)
+			next.ServeHTTP(sw, r)
+
+			status := sw.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			l := xlog.WithContext(r.Context(), logger)
+			log := l.Info
+			switch {
+			case status >= http.StatusInternalServerError:
+				log = l.Error
+			case status >= http.StatusBadRequest:
+				log = l.Warn
+			}
+			log("http request", "method", r.Method, "path", r.URL.Path, "status", status,
+				"bytes", sw.bytes, "duration", time.Since(start))
+		})
+	}
+}
+
+// Timeout returns a Middleware that bounds the handler with d.
+// The request context is cancelled after d and the client receives a 503 response
+// if the handler has not finished by then.
+func Timeout(d time.Duration) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.TimeoutHandler(next, d, "")
+	}
+}
+
+func wrapStatusWriter(w http.ResponseWriter) *statusWriter {
+	if sw, ok := w.(*statusWriter); ok {
+		return sw
+	}
+
+	return &statusWriter{ResponseWriter: w}
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	// informational responses are followed by the final one.
+	if w.status == 0 && status >= http.StatusOK {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+
+	return n, err
+}
+
+func (w *statusWriter) Flush() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap makes http.ResponseController reach the original http.ResponseWriter.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,184 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xhttp
+
+import (
+	"errors"
+	"github.com/chenquan/go-pkg/xerror"
+	"github.com/chenquan/go-pkg/xlog"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type (
+	logRecord struct {
+		level xlog.Level
+		msg   string
+		kv    map[string]interface{}
+	}
+
+	memorySink struct {
+		mu      sync.Mutex
+		records []logRecord
+	}
+)
+
+func (s *memorySink) Log(level xlog.Level, msg string, kv []interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fields := make(map[string]interface{}, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		fields[kv[i].(string)] = kv[i+1]
+	}
+	s.records = append(s.records, logRecord{level: level, msg: msg, kv: fields})
+}
+
+func TestChain(t *testing.T) {
+	var order []string
+	mw := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	handler := Chain(mw("a"), mw("b"), mw("c"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, "handler")
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, []string{"a", "b", "c", "handler"}, order)
+
+	order = nil
+	Chain()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, "handler")
+	})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, []string{"handler"}, order)
+}
+
+func TestRecover(t *testing.T) {
+	sink := &memorySink{}
+	cause := errors.New("boom")
+	handler := Recover(xlog.New(sink))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(cause)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Len(t, sink.records, 1)
+	record := sink.records[0]
+	assert.Equal(t, xlog.LevelError, record.level)
+	assert.Equal(t, "/panic", record.kv["path"])
+	var perr *xerror.PanicError
+	assert.True(t, errors.As(record.kv["err"].(error), &perr))
+	assert.ErrorIs(t, perr, cause)
+	assert.Contains(t, record.kv["stack"], "TestRecover")
+
+	// the response has already started.
+	handler = Recover(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		panic("late")
+	}))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+
+	handler = Recover(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
+func TestLogging(t *testing.T) {
+	sink := &memorySink{}
+	handler := Chain(Logging(xlog.New(sink)), Recover(nil))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+		case "/panic":
+			panic("boom")
+		default:
+			_, _ = w.Write([]byte("hello"))
+		}
+	}))
+
+	for _, path := range []string{"/", "/missing", "/panic"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req = req.WithContext(xlog.WithFields(req.Context(), "request_id", "42"))
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Len(t, sink.records, 3)
+	assert.Equal(t, xlog.LevelInfo, sink.records[0].level)
+	assert.Equal(t, http.StatusOK, sink.records[0].kv["status"])
+	assert.Equal(t, int64(5), sink.records[0].kv["bytes"])
+	assert.Equal(t, "42", sink.records[0].kv["request_id"])
+	assert.IsType(t, time.Duration(0), sink.records[0].kv["duration"])
+	assert.Equal(t, xlog.LevelWarn, sink.records[1].level)
+	assert.Equal(t, http.StatusNotFound, sink.records[1].kv["status"])
+	assert.Equal(t, xlog.LevelError, sink.records[2].level)
+	assert.Equal(t, http.StatusInternalServerError, sink.records[2].kv["status"])
+
+	// nothing written at all.
+	sink.records = nil
+	Logging(xlog.New(sink))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).
+		ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, sink.records[0].kv["status"])
+}
+
+func TestTimeout(t *testing.T) {
+	handler := Timeout(20 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fast" {
+			_, _ = w.Write([]byte("fast"))
+			return
+		}
+		<-r.Context().Done()
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fast", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "fast", rec.Body.String())
+}
+
+func TestStatusWriter(t *testing.T) {
+	rec := httptest.NewRecorder()
+	sw := wrapStatusWriter(rec)
+	assert.Same(t, sw, wrapStatusWriter(sw))
+
+	sw.Flush()
+	assert.Equal(t, http.StatusOK, sw.status)
+	assert.True(t, rec.Flushed)
+	assert.Same(t, rec, sw.Unwrap())
+	assert.NoError(t, http.NewResponseController(sw).Flush())
+	_, _ = sw.Write([]byte(strings.Repeat("x", 3)))
+	assert.Equal(t, int64(3), sw.bytes)
+}
@@ -0,0 +1,228 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xhttp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/chenquan/go-pkg/xcontext"
+	"github.com/chenquan/go-pkg/xlog"
+	"net/http"
+	"strings"
+)
+
+const (
+	// HeaderRequestID is the default header carrying the request ID.
+	HeaderRequestID = "X-Request-Id"
+	// HeaderTraceparent is the W3C Trace Context header.
+	HeaderTraceparent = "Traceparent"
+
+	// MetaRequestID is the xcontext metadata key of the request ID.
+	MetaRequestID = "request_id"
+	// MetaTraceparent is the xcontext metadata key of the traceparent.
+	MetaTraceparent = "traceparent"
+
+	maxRequestIDLength = 128
+)
+
+type (
+	// RequestIDOption defines the method to customize the RequestID middleware and the PropagationTransport.
+	RequestIDOption func(*requestIDOptions)
+
+	requestIDOptions struct {
+		header      string
+		generate    func() string
+		traceparent bool
+	}
+
+	propagationTransport struct {
+		next    http.RoundTripper
+		options requestIDOptions
+	}
+)
+
+// WithRequestIDHeader customizes the header carrying the request ID, default to X-Request-Id.
+func WithRequestIDHeader(header string) RequestIDOption {
+	return func(o *requestIDOptions) {
+		o.header = header
+	}
+}
+
+// WithRequestIDGenerator customizes how missing request IDs are generated, default to 16 random bytes in hex.
+func WithRequestIDGenerator(generate func() string) RequestIDOption {
+	return func(o *requestIDOptions) {
+		o.generate = generate
+	}
+}
+
+// WithTraceparent enables the W3C traceparent header.
+//
+// The middleware continues the incoming trace with a new span ID, or starts a new trace,
+// and the transport forwards the traceparent of the context.
+func WithTraceparent() RequestIDOption {
+	return func(o *requestIDOptions) {
+		o.traceparent = true
+	}
+}
+
+// RequestID returns a Middleware that extracts the request ID from the request, or generates one.
+// The ID is stored in the xcontext metadata and the xlog fields of the request context,
+// and sent back in the response headers.
+// Incoming IDs that are too long or contain non-printable characters are replaced.
+func RequestID(opts ...RequestIDOption) Middleware {
+	o := newRequestIDOptions(opts)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(o.header)
+			if !validRequestID(id) {
+				id = o.generate()
+			}
+
+			ctx := xcontext.WithMeta(r.Context(), MetaRequestID, id)
+			ctx = xlog.WithFields(ctx, MetaRequestID, id)
+			w.Header().Set(o.header, id)
+
+			if o.traceparent {
+				traceID, flags, ok := parseTraceparent(r.Header.Get(HeaderTraceparent))
+				if !ok {
+					traceID, flags = randomHex(16), "01"
+				}
+				traceparent := "00-" + traceID + "-" + randomHex(8) + "-" + flags
+				ctx = xcontext.WithMeta(ctx, MetaTraceparent, traceparent)
+				ctx = xlog.WithFields(ctx, "trace_id", traceID)
+				w.Header().Set(HeaderTraceparent, traceparent)
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// PropagationTransport returns an http.RoundTripper that forwards the request ID,
+// and the traceparent if enabled, of the request context to outbound calls.
+// A nil next uses http.DefaultTransport.
+func PropagationTransport(next http.RoundTripper, opts ...RequestIDOption) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &propagationTransport{next: next, options: newRequestIDOptions(opts)}
+}
+
+// RequestIDFrom returns the request ID stored in ctx.
+func RequestIDFrom(ctx context.Context) string {
+	id, _ := xcontext.MetaValue[string](ctx, MetaRequestID)
+
+	return id
+}
+
+// TraceparentFrom returns the traceparent stored in ctx.
+func TraceparentFrom(ctx context.Context) string {
+	traceparent, _ := xcontext.MetaValue[string](ctx, MetaTraceparent)
+
+	return traceparent
+}
+
+func (t *propagationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	id := RequestIDFrom(ctx)
+	traceparent := ""
+	if t.options.traceparent {
+		traceparent = TraceparentFrom(ctx)
+	}
+	if id == "" && traceparent == "" {
+		return t.next.RoundTrip(req)
+	}
+
+	// a RoundTripper must not modify the request.
+	req = req.Clone(ctx)
+	if id != "" && req.Header.Get(t.options.header) == "" {
+		req.Header.Set(t.options.header, id)
+	}
+	if traceparent != "" && req.Header.Get(HeaderTraceparent) == "" {
+		req.Header.Set(HeaderTraceparent, traceparent)
+	}
+
+	return t.next.RoundTrip(req)
+}
+
+func newRequestIDOptions(opts []RequestIDOption) requestIDOptions {
+	o := requestIDOptions{header: HeaderRequestID, generate: func() string { return randomHex(16) }}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseTraceparent returns the trace ID and the flags of a valid traceparent.
+func parseTraceparent(s string) (traceID, flags string, ok bool) {
+	// version-traceid-parentid-flags, future versions may append fields.
+	if len(s) < 55 || (len(s) > 55 && s[55] != '-') {
+		return "", "", false
+	}
+
+	parts := strings.SplitN(s[:55], "-", 4)
+	if len(parts) != 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return "", "", false
+	}
+	for _, part := range parts {
+		if !isLowerHex(part) {
+			return "", "", false
+		}
+	}
+	if parts[0] == "ff" || (parts[0] == "00" && len(s) != 55) ||
+		strings.Trim(parts[1], "0") == "" || strings.Trim(parts[2], "0") == "" {
+		return "", "", false
+	}
+
+	return parts[1], parts[3], true
+}
+
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
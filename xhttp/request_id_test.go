@@ -0,0 +1,172 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xhttp
+
+import (
+	"context"
+	"github.com/chenquan/go-pkg/xcontext"
+	"github.com/chenquan/go-pkg/xlog"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestRequestID(t *testing.T) {
+	var ctx context.Context
+	handler := RequestID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx = r.Context()
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	id := RequestIDFrom(ctx)
+	assert.Len(t, id, 32)
+	assert.Equal(t, id, rec.Header().Get(HeaderRequestID))
+	assert.Equal(t, []interface{}{MetaRequestID, id}, xlog.FieldsFrom(ctx))
+	assert.Empty(t, TraceparentFrom(ctx))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(HeaderRequestID, "abc-123")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, "abc-123", RequestIDFrom(ctx))
+	assert.Equal(t, "abc-123", rec.Header().Get(HeaderRequestID))
+
+	for _, bad := range []string{"has space", "new\nline", strings.Repeat("x", 129)} {
+		req = httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header[HeaderRequestID] = []string{bad}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		assert.NotEqual(t, bad, RequestIDFrom(ctx))
+		assert.Len(t, RequestIDFrom(ctx), 32)
+	}
+
+	assert.Empty(t, RequestIDFrom(context.Background()))
+}
+
+func TestRequestID_Options(t *testing.T) {
+	var ctx context.Context
+	handler := RequestID(
+		WithRequestIDHeader("X-Correlation-Id"),
+		WithRequestIDGenerator(func() string { return "generated" }),
+		WithTraceparent(),
+	)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx = r.Context()
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, "generated", rec.Header().Get("X-Correlation-Id"))
+	traceID, flags, ok := parseTraceparent(TraceparentFrom(ctx))
+	assert.True(t, ok)
+	assert.Equal(t, "01", flags)
+	assert.Equal(t, TraceparentFrom(ctx), rec.Header().Get(HeaderTraceparent))
+	assert.Contains(t, xlog.FieldsFrom(ctx), traceID)
+
+	incoming := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00"
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(HeaderTraceparent, incoming)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	traceparent := TraceparentFrom(ctx)
+	assert.True(t, strings.HasPrefix(traceparent, "00-4bf92f3577b34da6a3ce929d0e0e4736-"))
+	assert.True(t, strings.HasSuffix(traceparent, "-00"))
+	assert.NotEqual(t, incoming, traceparent)
+	meta, _ := xcontext.Meta(ctx, MetaTraceparent)
+	assert.Equal(t, traceparent, meta)
+}
+
+func TestParseTraceparent(t *testing.T) {
+	traceID, flags, ok := parseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.True(t, ok)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceID)
+	assert.Equal(t, "01", flags)
+
+	_, _, ok = parseTraceparent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future")
+	assert.True(t, ok)
+
+	for _, bad := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00_4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01x",
+	} {
+		_, _, ok = parseTraceparent(bad)
+		assert.False(t, ok, bad)
+	}
+}
+
+func TestPropagationTransport(t *testing.T) {
+	var got http.Header
+	transport := PropagationTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		got = req.Header
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	}), WithTraceparent())
+
+	ctx := xcontext.WithMeta(context.Background(), MetaRequestID, "abc")
+	ctx = xcontext.WithMeta(ctx, MetaTraceparent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com", nil)
+	_, err := transport.RoundTrip(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", got.Get(HeaderRequestID))
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", got.Get(HeaderTraceparent))
+	assert.Empty(t, req.Header.Get(HeaderRequestID))
+
+	// explicit headers win.
+	req.Header.Set(HeaderRequestID, "explicit")
+	_, err = transport.RoundTrip(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "explicit", got.Get(HeaderRequestID))
+
+	req, _ = http.NewRequest(http.MethodGet, "http://example.com", nil)
+	_, err = transport.RoundTrip(req)
+	assert.NoError(t, err)
+	assert.Empty(t, got.Get(HeaderRequestID))
+
+	assert.Same(t, http.DefaultTransport, PropagationTransport(nil).(*propagationTransport).next)
+}
+
+func TestRequestID_EndToEnd(t *testing.T) {
+	var upstreamID string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamID = r.Header.Get(HeaderRequestID)
+	}))
+	defer upstream.Close()
+
+	client := NewClient(WithHTTPClient(&http.Client{Transport: PropagationTransport(nil)}))
+	handler := RequestID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp, err := client.Get(r.Context(), upstream.URL)
+		if assert.NoError(t, err) {
+			resp.Body.Close()
+		}
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(HeaderRequestID, "end-to-end")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, "end-to-end", upstreamID)
+}
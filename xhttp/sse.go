@@ -0,0 +1,213 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xhttp
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrFlushUnsupported is returned when the http.ResponseWriter can't be flushed, so it can't stream events.
+	ErrFlushUnsupported = errors.New("xhttp: response writer doesn't support flushing")
+	// ErrEventField is returned when the id or the type of an event contains a line break.
+	ErrEventField = errors.New("xhttp: event id and type must be a single line")
+	// ErrSSEClosed is returned when sending on a closed SSEWriter.
+	ErrSSEClosed = errors.New("xhttp: sse writer closed")
+)
+
+type (
+	// An Event is a Server-Sent Event.
+	Event struct {
+		// ID is the event ID, sent back as Last-Event-ID when a client reconnects.
+		ID string
+		// Event is the event type, "message" if empty.
+		Event string
+		// Data is the payload, it can span several lines.
+		Data string
+		// Retry asks the client to wait that long before reconnecting, if positive.
+		Retry time.Duration
+	}
+
+	// SSEOption defines the method to customize an SSEWriter.
+	SSEOption func(*sseOptions)
+
+	sseOptions struct {
+		heartbeat time.Duration
+	}
+
+	// An SSEWriter streams Server-Sent Events to a client, flushing every event.
+	// It is safe for concurrent use.
+	SSEWriter struct {
+		w      http.ResponseWriter
+		rc     *http.ResponseController
+		lock   sync.Mutex
+		buf    []byte
+		err    error
+		closed bool
+		done   chan struct{}
+	}
+)
+
+// WithHeartbeat sends a comment every d so that proxies don't close an idle stream.
+func WithHeartbeat(d time.Duration) SSEOption {
+	return func(o *sseOptions) {
+		o.heartbeat = d
+	}
+}
+
+// NewSSEWriter starts an event stream on w.
+// Call Close when done, the handler must not write to w directly anymore.
+func NewSSEWriter(w http.ResponseWriter, opts ...SSEOption) (*SSEWriter, error) {
+	var o sseOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	rc := http.NewResponseController(w)
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	// disables the buffering of nginx.
+	h.Set("X-Accel-Buffering", "no")
+	h.Del("Content-Length")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		if errors.Is(err, http.ErrNotSupported) {
+			return nil, ErrFlushUnsupported
+		}
+		return nil, err
+	}
+
+	s := &SSEWriter{w: w, rc: rc, done: make(chan struct{})}
+	if o.heartbeat > 0 {
+		go s.heartbeat(o.heartbeat)
+	}
+
+	return s, nil
+}
+
+// Send writes e and flushes it.
+func (s *SSEWriter) Send(e Event) error {
+	if strings.ContainsAny(e.ID, "\r\n\x00") || strings.ContainsAny(e.Event, "\r\n") {
+		return ErrEventField
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	buf := s.buf[:0]
+	if e.ID != "" {
+		buf = append(buf, "id: "...)
+		buf = append(buf, e.ID...)
+		buf = append(buf, '\n')
+	}
+	if e.Event != "" {
+		buf = append(buf, "event: "...)
+		buf = append(buf, e.Event...)
+		buf = append(buf, '\n')
+	}
+	if e.Retry > 0 {
+		buf = append(buf, "retry: "...)
+		buf = strconv.AppendInt(buf, e.Retry.Milliseconds(), 10)
+		buf = append(buf, '\n')
+	}
+	buf = appendLines(buf, "data: ", e.Data)
+	buf = append(buf, '\n')
+	s.buf = buf
+
+	return s.write(buf)
+}
+
+// Comment writes a comment line, which clients ignore.
+func (s *SSEWriter) Comment(text string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.buf = append(appendLines(s.buf[:0], ": ", text), '\n')
+
+	return s.write(s.buf)
+}
+
+// Close stops the heartbeat, further writes return ErrSSEClosed.
+func (s *SSEWriter) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.closed {
+		s.closed = true
+		close(s.done)
+	}
+
+	return nil
+}
+
+func (s *SSEWriter) write(p []byte) error {
+	if s.closed {
+		return ErrSSEClosed
+	}
+	if s.err != nil {
+		return s.err
+	}
+
+	if _, err := s.w.Write(p); err != nil {
+		s.err = err
+		return err
+	}
+	s.err = s.rc.Flush()
+
+	return s.err
+}
+
+func (s *SSEWriter) heartbeat(d time.Duration) {
+	ticker := time.NewTicker(d)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			if err := s.Comment("heartbeat"); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// appendLines appends every line of text with the prefix, an empty text is a single empty line.
+func appendLines(buf []byte, prefix, text string) []byte {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	for {
+		i := strings.IndexAny(text, "\r\n")
+		line := text
+		if i >= 0 {
+			line = text[:i]
+		}
+		buf = append(buf, prefix...)
+		buf = append(buf, line...)
+		buf = append(buf, '\n')
+		if i < 0 {
+			return buf
+		}
+		text = text[i+1:]
+	}
+}
@@ -0,0 +1,286 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xhttp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultReconnectDelay = 3 * time.Second
+	maxEventSize          = 1 << 20
+)
+
+type (
+	// SSEClientOption defines the method to customize an SSEClient.
+	SSEClientOption func(*SSEClient)
+
+	// An SSEClient consumes a Server-Sent Events stream, reconnecting with Last-Event-ID
+	// whenever the connection drops.
+	SSEClient struct {
+		url           string
+		client        *http.Client
+		header        http.Header
+		delay         time.Duration
+		maxReconnects int
+		lastEventID   string
+	}
+
+	// An SSEStatusError is returned when the server answers with an unexpected status or content type.
+	SSEStatusError struct {
+		StatusCode  int
+		ContentType string
+	}
+)
+
+// WithSSEHTTPClient customizes the underlying http.Client, default to http.DefaultClient.
+// Its Timeout must be zero, or it cuts the stream.
+func WithSSEHTTPClient(client *http.Client) SSEClientOption {
+	return func(c *SSEClient) {
+		c.client = client
+	}
+}
+
+// WithSSEHeader adds a header to every connection request.
+func WithSSEHeader(key, value string) SSEClientOption {
+	return func(c *SSEClient) {
+		c.header.Add(key, value)
+	}
+}
+
+// WithReconnectDelay customizes the wait before reconnecting, default to 3s.
+// The server can change it with the retry field.
+func WithReconnectDelay(d time.Duration) SSEClientOption {
+	return func(c *SSEClient) {
+		c.delay = d
+	}
+}
+
+// WithMaxReconnects bounds the number of reconnections in a row without receiving an event,
+// a negative n means unlimited, the default.
+func WithMaxReconnects(n int) SSEClientOption {
+	return func(c *SSEClient) {
+		c.maxReconnects = n
+	}
+}
+
+// WithLastEventID resumes the stream after the event id.
+func WithLastEventID(id string) SSEClientOption {
+	return func(c *SSEClient) {
+		c.lastEventID = id
+	}
+}
+
+// NewSSEClient returns an SSEClient of the stream at url.
+func NewSSEClient(url string, opts ...SSEClientOption) *SSEClient {
+	c := &SSEClient{
+		url:           url,
+		client:        http.DefaultClient,
+		header:        make(http.Header),
+		delay:         defaultReconnectDelay,
+		maxReconnects: -1,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Error returns a string that represents the status.
+func (e *SSEStatusError) Error() string {
+	return fmt.Sprintf("xhttp: unexpected sse response, status %d and content type %q", e.StatusCode, e.ContentType)
+}
+
+// Subscribe calls fn with every event until ctx is done, fn returns an error,
+// or the server closes the stream with 204 No Content.
+// Dropped connections are reopened after the reconnect delay.
+// It returns nil on 204, the error of fn, ctx.Err(), an *SSEStatusError, or the last error
+// once the maximum number of reconnections is reached.
+// Subscribe must not be called concurrently.
+func (c *SSEClient) Subscribe(ctx context.Context, fn func(Event) error) error {
+	failures := 0
+	for {
+		received, err := c.stream(ctx, fn)
+		var fnErr *subscriberError
+		if errors.As(err, &fnErr) {
+			return fnErr.err
+		}
+		if err == errStreamStopped {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var statusErr *SSEStatusError
+		if errors.As(err, &statusErr) {
+			return err
+		}
+
+		if received {
+			failures = 0
+		}
+		failures++
+		if c.maxReconnects >= 0 && failures > c.maxReconnects {
+			if err == nil {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+
+		if err := sleep(ctx, c.delay); err != nil {
+			return err
+		}
+	}
+}
+
+// LastEventID returns the ID of the last event received.
+func (c *SSEClient) LastEventID() string {
+	return c.lastEventID
+}
+
+var errStreamStopped = errors.New("xhttp: sse stream stopped by the server")
+
+// subscriberError carries an error that ends Subscribe as is.
+type subscriberError struct {
+	err error
+}
+
+func (e *subscriberError) Error() string {
+	return e.err.Error()
+}
+
+// stream reads one connection, and reports whether an event has been received.
+// It returns a nil error when the server closed the stream.
+func (c *SSEClient) stream(ctx context.Context, fn func(Event) error) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return false, &subscriberError{err: err}
+	}
+	for k, v := range c.header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+	if c.lastEventID != "" {
+		req.Header.Set("Last-Event-ID", c.lastEventID)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return false, errStreamStopped
+	}
+	contentType := resp.Header.Get("Content-Type")
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if resp.StatusCode != http.StatusOK || mediaType != "text/event-stream" {
+		return false, &SSEStatusError{StatusCode: resp.StatusCode, ContentType: contentType}
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 4096), maxEventSize)
+	scanner.Split(scanEventLines)
+
+	received := false
+	var (
+		data      bytes.Buffer
+		eventType string
+		hasData   bool
+	)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if !hasData {
+				eventType = ""
+				continue
+			}
+
+			e := Event{ID: c.lastEventID, Event: eventType, Data: strings.TrimSuffix(data.String(), "\n")}
+			if e.Event == "" {
+				e.Event = "message"
+			}
+			data.Reset()
+			eventType, hasData = "", false
+			received = true
+			if err := fn(e); err != nil {
+				return received, &subscriberError{err: err}
+			}
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+			hasData = true
+		case "event":
+			eventType = value
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				c.lastEventID = value
+			}
+		case "retry":
+			if ms, err := strconv.ParseUint(value, 10, 32); err == nil {
+				c.delay = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+
+	return received, scanner.Err()
+}
+
+// scanEventLines splits lines ended by "\r\n", "\n" or "\r".
+func scanEventLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\r' {
+			if i+1 == len(data) && !atEOF {
+				// wait for a possible "\n".
+				return 0, nil, nil
+			}
+			if i+1 < len(data) && data[i+1] == '\n' {
+				return i + 2, data[:i], nil
+			}
+		}
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		// an incomplete event is discarded.
+		return len(data), nil, nil
+	}
+
+	return 0, nil, nil
+}
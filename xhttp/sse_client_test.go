@@ -0,0 +1,172 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xhttp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSSEClient_Reconnect(t *testing.T) {
+	var (
+		connections int32
+		lastIDs     = make(chan string, 10)
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "text/event-stream", r.Header.Get("Accept"))
+		assert.Equal(t, "token", r.Header.Get("Authorization"))
+		lastIDs <- r.Header.Get("Last-Event-ID")
+
+		n := atomic.AddInt32(&connections, 1)
+		if n == 3 {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		s, err := NewSSEWriter(w)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer s.Close()
+		_ = s.Send(Event{ID: fmt.Sprint(n), Data: fmt.Sprintf("first %d", n), Retry: time.Millisecond})
+		_ = s.Send(Event{Event: "update", Data: "a\nb"})
+	}))
+	defer server.Close()
+
+	client := NewSSEClient(server.URL, WithSSEHeader("Authorization", "token"), WithReconnectDelay(time.Hour))
+	var events []Event
+	err := client.Subscribe(context.Background(), func(e Event) error {
+		events = append(events, e)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []Event{
+		{ID: "1", Event: "message", Data: "first 1"},
+		{ID: "1", Event: "update", Data: "a\nb"},
+		{ID: "2", Event: "message", Data: "first 2"},
+		{ID: "2", Event: "update", Data: "a\nb"},
+	}, events)
+	assert.Equal(t, "2", client.LastEventID())
+	assert.Equal(t, "", <-lastIDs)
+	assert.Equal(t, "1", <-lastIDs)
+	assert.Equal(t, "2", <-lastIDs)
+}
+
+func TestSSEClient_Stop(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/json":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte("{}"))
+		case "/closed":
+			w.Header().Set("Content-Type", "text/event-stream")
+		default:
+			s, _ := NewSSEWriter(w)
+			for i := 0; ; i++ {
+				if s.Send(Event{Data: fmt.Sprint(i)}) != nil {
+					return
+				}
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}))
+	defer server.Close()
+
+	stop := errors.New("stop")
+	err := NewSSEClient(server.URL).Subscribe(context.Background(), func(e Event) error {
+		if e.Data == "2" {
+			return stop
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, stop)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err = NewSSEClient(server.URL).Subscribe(ctx, func(e Event) error {
+		cancel()
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	err = NewSSEClient(server.URL+"/json").Subscribe(context.Background(), func(Event) error { return nil })
+	var statusErr *SSEStatusError
+	assert.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, http.StatusOK, statusErr.StatusCode)
+	assert.Equal(t, "application/json", statusErr.ContentType)
+
+	err = NewSSEClient(server.URL+"/closed", WithMaxReconnects(2), WithReconnectDelay(time.Millisecond)).
+		Subscribe(context.Background(), func(Event) error { return nil })
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	err = NewSSEClient("http://127.0.0.1:1", WithMaxReconnects(0)).
+		Subscribe(context.Background(), func(Event) error { return nil })
+	assert.Error(t, err)
+
+	err = NewSSEClient("://bad").Subscribe(context.Background(), func(Event) error { return nil })
+	assert.Error(t, err)
+}
+
+func TestSSEClient_Parse(t *testing.T) {
+	stream := ": comment\r\n" +
+		"retry: 5\r" +
+		"retry: bad\n" +
+		"id\n" +
+		"data\n\n" +
+		"event: ignored\n\n" +
+		"data:no space\r\n" +
+		"id: a\x00b\n\n" +
+		"data: incomplete"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+		_, _ = io.WriteString(w, stream)
+	}))
+	defer server.Close()
+
+	client := NewSSEClient(server.URL, WithMaxReconnects(0), WithLastEventID("start"))
+	var events []Event
+	err := client.Subscribe(context.Background(), func(e Event) error {
+		events = append(events, e)
+		return nil
+	})
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Equal(t, []Event{
+		{Event: "message", Data: ""},
+		{Event: "message", Data: "no space"},
+	}, events)
+	assert.Equal(t, 5*time.Millisecond, client.delay)
+}
+
+func TestScanEventLines(t *testing.T) {
+	scanner := bufio.NewScanner(strings.NewReader("a\r\nb\rc\n\rd"))
+	scanner.Split(scanEventLines)
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	assert.NoError(t, scanner.Err())
+	assert.Equal(t, []string{"a", "b", "c", ""}, lines)
+}
@@ -0,0 +1,96 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xhttp
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type (
+	syncRecorder struct {
+		*httptest.ResponseRecorder
+		lock sync.Mutex
+	}
+
+	noFlushWriter struct {
+		header http.Header
+	}
+)
+
+func (r *syncRecorder) Write(p []byte) (int, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.ResponseRecorder.Write(p)
+}
+
+func (r *syncRecorder) body() string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.Body.String()
+}
+
+func (w *noFlushWriter) Header() http.Header         { return w.header }
+func (w *noFlushWriter) Write(p []byte) (int, error) { return len(p), nil }
+func (w *noFlushWriter) WriteHeader(int)             {}
+
+func TestSSEWriter(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Length", "10")
+	s, err := NewSSEWriter(rec)
+	assert.NoError(t, err)
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "no-cache", rec.Header().Get("Cache-Control"))
+	assert.Empty(t, rec.Header().Get("Content-Length"))
+	assert.True(t, rec.Flushed)
+
+	assert.NoError(t, s.Send(Event{ID: "1", Event: "update", Data: "line1\nline2\r\nline3", Retry: 1500 * time.Millisecond}))
+	assert.NoError(t, s.Send(Event{Data: ""}))
+	assert.NoError(t, s.Comment("ping"))
+	assert.Equal(t, "id: 1\nevent: update\nretry: 1500\ndata: line1\ndata: line2\ndata: line3\n\n"+
+		"data: \n\n"+
+		": ping\n\n", rec.Body.String())
+
+	assert.ErrorIs(t, s.Send(Event{ID: "1\n2"}), ErrEventField)
+	assert.ErrorIs(t, s.Send(Event{Event: "a\rb"}), ErrEventField)
+
+	assert.NoError(t, s.Close())
+	assert.NoError(t, s.Close())
+	assert.ErrorIs(t, s.Send(Event{Data: "late"}), ErrSSEClosed)
+}
+
+func TestSSEWriter_Heartbeat(t *testing.T) {
+	rec := &syncRecorder{ResponseRecorder: httptest.NewRecorder()}
+	s, err := NewSSEWriter(rec, WithHeartbeat(5*time.Millisecond))
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return strings.Contains(rec.body(), ": heartbeat\n\n")
+	}, time.Second, time.Millisecond)
+	assert.NoError(t, s.Close())
+}
+
+func TestSSEWriter_FlushUnsupported(t *testing.T) {
+	_, err := NewSSEWriter(&noFlushWriter{header: make(http.Header)})
+	assert.ErrorIs(t, err, ErrFlushUnsupported)
+}
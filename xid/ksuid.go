@@ -0,0 +1,144 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xid
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+const (
+	// KSUIDEpoch is the epoch of KSUID timestamps, in seconds since the Unix epoch.
+	KSUIDEpoch = 1400000000
+
+	ksuidEncoding = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	ksuidLength   = 27
+	ksuidSize     = 20
+)
+
+var (
+	// ErrInvalidKSUID is returned when parsing a malformed KSUID.
+	ErrInvalidKSUID = errors.New("xid: invalid ksuid")
+
+	ksuidDecoding = func() (table [256]byte) {
+		for i := range table {
+			table[i] = 0xff
+		}
+		for i := 0; i < len(ksuidEncoding); i++ {
+			table[ksuidEncoding[i]] = byte(i)
+		}
+		return
+	}()
+)
+
+// A KSUID is a K-Sortable Unique IDentifier: a 32-bit timestamp in seconds since KSUIDEpoch
+// followed by 128 random bits, printed as 27 characters of base62.
+type KSUID [ksuidSize]byte
+
+// NewKSUID returns a new KSUID.
+func NewKSUID() KSUID {
+	return newKSUID(time.Now())
+}
+
+// ParseKSUID parses the string representation of a KSUID.
+func ParseKSUID(s string) (KSUID, error) {
+	var k KSUID
+	if len(s) != ksuidLength {
+		return k, ErrInvalidKSUID
+	}
+
+	for i := 0; i < ksuidLength; i++ {
+		d := ksuidDecoding[s[i]]
+		if d == 0xff {
+			return k, ErrInvalidKSUID
+		}
+
+		// k = k*62 + d
+		carry := uint32(d)
+		for j := ksuidSize - 1; j >= 0; j-- {
+			v := uint32(k[j])*62 + carry
+			k[j] = byte(v)
+			carry = v >> 8
+		}
+		if carry != 0 {
+			return KSUID{}, ErrInvalidKSUID
+		}
+	}
+
+	return k, nil
+}
+
+// String returns the 27 characters representation of k.
+func (k KSUID) String() string {
+	b, _ := k.MarshalText()
+	return string(b)
+}
+
+// Time returns the timestamp embedded in k.
+func (k KSUID) Time() time.Time {
+	return time.Unix(int64(binary.BigEndian.Uint32(k[:4]))+KSUIDEpoch, 0)
+}
+
+// Payload returns the random part of k.
+func (k KSUID) Payload() []byte {
+	return append([]byte(nil), k[4:]...)
+}
+
+// Compare returns -1, 0 or 1 if k is less than, equal to or greater than other.
+func (k KSUID) Compare(other KSUID) int {
+	return bytes.Compare(k[:], other[:])
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (k KSUID) MarshalText() ([]byte, error) {
+	out := make([]byte, ksuidLength)
+	n := k
+	for i := ksuidLength - 1; i >= 0; i-- {
+		// n, r = n/62, n%62
+		var r uint32
+		for j := 0; j < ksuidSize; j++ {
+			v := r<<8 | uint32(n[j])
+			n[j] = byte(v / 62)
+			r = v % 62
+		}
+		out[i] = ksuidEncoding[r]
+	}
+
+	return out, nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (k *KSUID) UnmarshalText(text []byte) error {
+	parsed, err := ParseKSUID(string(text))
+	if err != nil {
+		return err
+	}
+	*k = parsed
+
+	return nil
+}
+
+func newKSUID(t time.Time) KSUID {
+	var k KSUID
+	binary.BigEndian.PutUint32(k[:4], uint32(t.Unix()-KSUIDEpoch))
+	_, _ = rand.Read(k[4:])
+
+	return k
+}
@@ -0,0 +1,86 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xid
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestKSUID(t *testing.T) {
+	k, err := ParseKSUID("0ujtsYcgvSTl8PAuAdqWYSMnLOv")
+	assert.NoError(t, err)
+	assert.Equal(t, "0ujtsYcgvSTl8PAuAdqWYSMnLOv", k.String())
+	assert.Equal(t, int64(107608047+KSUIDEpoch), k.Time().Unix())
+	assert.Equal(t, "b5a1cd34b5f99d1154fb6853345c9735", hex.EncodeToString(k.Payload()))
+
+	zero, err := ParseKSUID("000000000000000000000000000")
+	assert.NoError(t, err)
+	assert.Equal(t, KSUID{}, zero)
+	assert.Equal(t, "000000000000000000000000000", KSUID{}.String())
+
+	maxKSUID, err := ParseKSUID("aWgEPTl1tmebfsQzFP4bxwgy80V")
+	assert.NoError(t, err)
+	for _, b := range maxKSUID {
+		assert.Equal(t, byte(0xff), b)
+	}
+
+	for _, bad := range []string{"", "0ujtsYcgvSTl8PAuAdqWYSMnLO", "aWgEPTl1tmebfsQzFP4bxwgy80W", "0ujtsYcgvSTl8PAuAdqWYSMnLO-"} {
+		_, err = ParseKSUID(bad)
+		assert.ErrorIs(t, err, ErrInvalidKSUID, bad)
+	}
+}
+
+func TestNewKSUID(t *testing.T) {
+	now := time.Now()
+	k := NewKSUID()
+	assert.WithinDuration(t, now, k.Time(), time.Second)
+	assert.NotEqual(t, NewKSUID(), k)
+
+	parsed, err := ParseKSUID(k.String())
+	assert.NoError(t, err)
+	assert.Equal(t, k, parsed)
+
+	ids := []string{
+		newKSUID(now.Add(2 * time.Second)).String(),
+		newKSUID(now).String(),
+		newKSUID(now.Add(time.Second)).String(),
+	}
+	sort.Strings(ids)
+	earlier, _ := ParseKSUID(ids[0])
+	later, _ := ParseKSUID(ids[2])
+	assert.Equal(t, -1, earlier.Compare(later))
+	assert.Equal(t, 1, later.Compare(earlier))
+	assert.Equal(t, now.Unix(), earlier.Time().Unix())
+	assert.Equal(t, now.Unix()+2, later.Time().Unix())
+}
+
+func TestKSUID_Text(t *testing.T) {
+	k := NewKSUID()
+	data, err := json.Marshal(k)
+	assert.NoError(t, err)
+	assert.Equal(t, `"`+k.String()+`"`, string(data))
+
+	var decoded KSUID
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, k, decoded)
+	assert.Error(t, json.Unmarshal([]byte(`"bad"`), &decoded))
+}
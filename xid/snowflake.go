@@ -0,0 +1,254 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xid
+
+import (
+	"errors"
+	"hash/fnv"
+	"net"
+	"os"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// WorkerIDEnv is the environment variable read to derive the worker ID of a Snowflake.
+	WorkerIDEnv = "XID_WORKER_ID"
+
+	defaultWorkerBits   = 10
+	defaultSequenceBits = 12
+	defaultMaxDrift     = 10 * time.Millisecond
+	minTimeBits         = 32
+)
+
+var (
+	// DefaultEpoch is the default epoch of a Snowflake, 2021-01-01 UTC.
+	DefaultEpoch = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// ErrLayout is returned when the bit layout of a Snowflake leaves less than 32 bits for the timestamp.
+	ErrLayout = errors.New("xid: invalid snowflake bit layout")
+	// ErrWorkerID is returned when the worker ID doesn't fit in the worker bits.
+	ErrWorkerID = errors.New("xid: worker id out of range")
+	// ErrClockBackwards is returned when the clock moved backwards by more than the tolerated drift.
+	ErrClockBackwards = errors.New("xid: clock moved backwards")
+	// ErrTimeOverflow is returned when the timestamp doesn't fit in its bits anymore.
+	ErrTimeOverflow = errors.New("xid: snowflake timestamp overflow")
+)
+
+type (
+	// SnowflakeOption defines the method to customize a Snowflake.
+	SnowflakeOption func(*snowflakeOptions)
+
+	snowflakeOptions struct {
+		epoch        time.Time
+		workerID     int64
+		hasWorkerID  bool
+		workerBits   uint
+		sequenceBits uint
+		maxDrift     time.Duration
+	}
+
+	// A Snowflake generates unique, time-ordered int64 IDs made of
+	// a millisecond timestamp, a worker ID and a sequence number.
+	//
+	// It is lock-free and safe for concurrent use.
+	Snowflake struct {
+		epoch        int64
+		workerID     int64
+		workerBits   uint
+		sequenceBits uint
+		maxTime      int64
+		maxDrift     int64
+		// state packs the last timestamp and sequence.
+		state atomic.Uint64
+		now   func() int64
+	}
+
+	// A SnowflakeID is a Snowflake ID split into its parts.
+	SnowflakeID struct {
+		ID       int64
+		Time     time.Time
+		WorkerID int64
+		Sequence int64
+	}
+)
+
+// WithEpoch customizes the epoch of the timestamps, default to DefaultEpoch.
+func WithEpoch(epoch time.Time) SnowflakeOption {
+	return func(o *snowflakeOptions) {
+		o.epoch = epoch
+	}
+}
+
+// WithWorkerID customizes the worker ID.
+// By default, it's read from WorkerIDEnv, or derived from the private IPv4 address, or the hostname.
+func WithWorkerID(id int64) SnowflakeOption {
+	return func(o *snowflakeOptions) {
+		o.workerID = id
+		o.hasWorkerID = true
+	}
+}
+
+// WithLayout customizes the bits of the worker ID and the sequence, default to 10 and 12.
+// The timestamp takes the remaining bits of the 63.
+func WithLayout(workerBits, sequenceBits uint) SnowflakeOption {
+	return func(o *snowflakeOptions) {
+		o.workerBits = workerBits
+		o.sequenceBits = sequenceBits
+	}
+}
+
+// WithMaxDrift customizes how far the clock may move backwards, default to 10ms.
+// Within it, IDs keep being generated from the last timestamp, beyond it Next returns ErrClockBackwards.
+func WithMaxDrift(d time.Duration) SnowflakeOption {
+	return func(o *snowflakeOptions) {
+		o.maxDrift = d
+	}
+}
+
+// NewSnowflake returns a Snowflake.
+func NewSnowflake(opts ...SnowflakeOption) (*Snowflake, error) {
+	o := snowflakeOptions{
+		epoch:        DefaultEpoch,
+		workerBits:   defaultWorkerBits,
+		sequenceBits: defaultSequenceBits,
+		maxDrift:     defaultMaxDrift,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.sequenceBits == 0 || o.workerBits+o.sequenceBits > 63-minTimeBits {
+		return nil, ErrLayout
+	}
+	if !o.hasWorkerID {
+		o.workerID = deriveWorkerID(o.workerBits)
+	}
+	if o.workerID < 0 || o.workerID >= 1<<o.workerBits {
+		return nil, ErrWorkerID
+	}
+
+	return &Snowflake{
+		epoch:        o.epoch.UnixMilli(),
+		workerID:     o.workerID,
+		workerBits:   o.workerBits,
+		sequenceBits: o.sequenceBits,
+		maxTime:      1<<(63-o.workerBits-o.sequenceBits) - 1,
+		maxDrift:     o.maxDrift.Milliseconds(),
+		now:          func() int64 { return time.Now().UnixMilli() },
+	}, nil
+}
+
+// Next returns a new ID.
+// IDs of a Snowflake are strictly increasing.
+func (s *Snowflake) Next() (int64, error) {
+	sequenceMask := uint64(1)<<s.sequenceBits - 1
+	for {
+		old := s.state.Load()
+		last := int64(old >> s.sequenceBits)
+		t := s.now() - s.epoch
+
+		var next uint64
+		switch {
+		case t > last:
+			if t > s.maxTime {
+				return 0, ErrTimeOverflow
+			}
+			next = uint64(t) << s.sequenceBits
+		case last-t > s.maxDrift:
+			return 0, ErrClockBackwards
+		case old&sequenceMask < sequenceMask:
+			next = old + 1
+		default:
+			// the sequence of this millisecond is exhausted.
+			runtime.Gosched()
+			continue
+		}
+
+		if s.state.CompareAndSwap(old, next) {
+			return int64(next>>s.sequenceBits)<<(s.workerBits+s.sequenceBits) |
+				s.workerID<<s.sequenceBits | int64(next&sequenceMask), nil
+		}
+	}
+}
+
+// MustNext is like Next but panics on error.
+func (s *Snowflake) MustNext() int64 {
+	id, err := s.Next()
+	if err != nil {
+		panic(err)
+	}
+
+	return id
+}
+
+// WorkerID returns the worker ID of s.
+func (s *Snowflake) WorkerID() int64 {
+	return s.workerID
+}
+
+// Parse splits id into its parts with the layout and epoch of s.
+func (s *Snowflake) Parse(id int64) SnowflakeID {
+	ms := id >> (s.workerBits + s.sequenceBits)
+
+	return SnowflakeID{
+		ID:       id,
+		Time:     time.UnixMilli(s.epoch + ms),
+		WorkerID: id >> s.sequenceBits & (1<<s.workerBits - 1),
+		Sequence: id & (1<<s.sequenceBits - 1),
+	}
+}
+
+func deriveWorkerID(bits uint) int64 {
+	mask := int64(1)<<bits - 1
+	if v, ok := os.LookupEnv(WorkerIDEnv); ok {
+		if id, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return id
+		}
+	}
+
+	if ip := privateIPv4(); ip != nil {
+		return (int64(ip[2])<<8 | int64(ip[3])) & mask
+	}
+
+	hostname, _ := os.Hostname()
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(hostname))
+
+	return int64(h.Sum64()>>1) & mask
+}
+
+func privateIPv4() net.IP {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil
+	}
+
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		if ip := ipNet.IP.To4(); ip != nil && ip.IsPrivate() {
+			return ip
+		}
+	}
+
+	return nil
+}
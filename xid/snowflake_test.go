@@ -0,0 +1,147 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xid
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSnowflake(t *testing.T) {
+	s, err := NewSnowflake(WithWorkerID(5))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), s.WorkerID())
+
+	before := time.Now().Truncate(time.Millisecond)
+	prev := s.MustNext()
+	for i := 0; i < 10000; i++ {
+		id, err := s.Next()
+		assert.NoError(t, err)
+		assert.Greater(t, id, prev)
+		prev = id
+	}
+
+	parsed := s.Parse(prev)
+	assert.Equal(t, prev, parsed.ID)
+	assert.Equal(t, int64(5), parsed.WorkerID)
+	assert.False(t, parsed.Time.Before(before))
+	assert.False(t, parsed.Time.After(time.Now()))
+	assert.Less(t, parsed.Sequence, int64(1<<12))
+}
+
+func TestSnowflake_Concurrent(t *testing.T) {
+	s, err := NewSnowflake(WithWorkerID(1), WithLayout(4, 4))
+	assert.NoError(t, err)
+
+	var (
+		seen sync.Map
+		dups int32
+		wg   sync.WaitGroup
+	)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				if _, loaded := seen.LoadOrStore(s.MustNext(), struct{}{}); loaded {
+					atomic.AddInt32(&dups, 1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Zero(t, atomic.LoadInt32(&dups))
+}
+
+func TestSnowflake_Clock(t *testing.T) {
+	epoch := time.UnixMilli(1000)
+	s, err := NewSnowflake(WithEpoch(epoch), WithWorkerID(3), WithLayout(2, 2), WithMaxDrift(5*time.Millisecond))
+	assert.NoError(t, err)
+	var now int64 = 1100
+	s.now = func() int64 { return atomic.LoadInt64(&now) }
+
+	id := s.MustNext()
+	assert.Equal(t, SnowflakeID{ID: id, Time: time.UnixMilli(1100), WorkerID: 3, Sequence: 0}, s.Parse(id))
+
+	// within the drift, the last timestamp goes on.
+	atomic.StoreInt64(&now, 1097)
+	id = s.MustNext()
+	assert.Equal(t, int64(1), s.Parse(id).Sequence)
+	assert.Equal(t, time.UnixMilli(1100), s.Parse(id).Time)
+
+	atomic.StoreInt64(&now, 1090)
+	_, err = s.Next()
+	assert.ErrorIs(t, err, ErrClockBackwards)
+	assert.Panics(t, func() { s.MustNext() })
+
+	// the sequence is exhausted, wait for the next millisecond.
+	atomic.StoreInt64(&now, 1100)
+	s.MustNext()
+	s.MustNext()
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		atomic.StoreInt64(&now, 1101)
+	}()
+	id = s.MustNext()
+	assert.Equal(t, time.UnixMilli(1101), s.Parse(id).Time)
+	assert.Equal(t, int64(0), s.Parse(id).Sequence)
+
+	atomic.StoreInt64(&now, 1000+1<<59)
+	_, err = s.Next()
+	assert.ErrorIs(t, err, ErrTimeOverflow)
+}
+
+func TestNewSnowflake_Invalid(t *testing.T) {
+	_, err := NewSnowflake(WithLayout(10, 0))
+	assert.ErrorIs(t, err, ErrLayout)
+	_, err = NewSnowflake(WithLayout(20, 12))
+	assert.ErrorIs(t, err, ErrLayout)
+	_, err = NewSnowflake(WithWorkerID(1024))
+	assert.ErrorIs(t, err, ErrWorkerID)
+	_, err = NewSnowflake(WithWorkerID(-1))
+	assert.ErrorIs(t, err, ErrWorkerID)
+}
+
+func TestDeriveWorkerID(t *testing.T) {
+	t.Setenv(WorkerIDEnv, "42")
+	s, err := NewSnowflake()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), s.WorkerID())
+
+	t.Setenv(WorkerIDEnv, "4096")
+	_, err = NewSnowflake()
+	assert.ErrorIs(t, err, ErrWorkerID)
+
+	t.Setenv(WorkerIDEnv, "bad")
+	id := deriveWorkerID(10)
+	assert.GreaterOrEqual(t, id, int64(0))
+	assert.Less(t, id, int64(1024))
+	assert.Equal(t, id, deriveWorkerID(10))
+}
+
+func BenchmarkSnowflake_Next(b *testing.B) {
+	s, _ := NewSnowflake(WithWorkerID(1))
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = s.Next()
+		}
+	})
+}
@@ -0,0 +1,183 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xid
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	ulidEncoding = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	ulidLength   = 26
+)
+
+var (
+	// ErrInvalidULID is returned when parsing a malformed ULID.
+	ErrInvalidULID = errors.New("xid: invalid ulid")
+
+	ulidDecoding = func() (table [256]byte) {
+		for i := range table {
+			table[i] = 0xff
+		}
+		for i := 0; i < len(ulidEncoding); i++ {
+			table[ulidEncoding[i]] = byte(i)
+			table[ulidEncoding[i]|0x20] = byte(i)
+		}
+		// Crockford's base32 reads the ambiguous letters as digits.
+		for _, c := range "Oo" {
+			table[c] = 0
+		}
+		for _, c := range "IiLl" {
+			table[c] = 1
+		}
+		return
+	}()
+
+	ulidGenerator = &monotonicULID{}
+)
+
+type (
+	// A ULID is a Universally Unique Lexicographically Sortable Identifier:
+	// a 48-bit millisecond timestamp followed by 80 random bits,
+	// printed as 26 characters of Crockford's base32.
+	ULID [16]byte
+
+	monotonicULID struct {
+		lock sync.Mutex
+		last ULID
+	}
+)
+
+// NewULID returns a new ULID.
+// ULIDs generated by the process are strictly increasing, even within the same millisecond.
+func NewULID() ULID {
+	return ulidGenerator.next(time.Now().UnixMilli())
+}
+
+// ParseULID parses the string representation of a ULID, case insensitively.
+func ParseULID(s string) (ULID, error) {
+	var u ULID
+	if len(s) != ulidLength {
+		return u, ErrInvalidULID
+	}
+
+	var v [ulidLength]byte
+	for i := 0; i < ulidLength; i++ {
+		v[i] = ulidDecoding[s[i]]
+		if v[i] == 0xff {
+			return u, ErrInvalidULID
+		}
+	}
+	// 26 characters hold 130 bits, the 2 high bits must be zero.
+	if v[0] > 7 {
+		return u, ErrInvalidULID
+	}
+
+	u[0] = v[0]<<5 | v[1]
+	u[1] = v[2]<<3 | v[3]>>2
+	u[2] = v[3]<<6 | v[4]<<1 | v[5]>>4
+	u[3] = v[5]<<4 | v[6]>>1
+	u[4] = v[6]<<7 | v[7]<<2 | v[8]>>3
+	u[5] = v[8]<<5 | v[9]
+	u[6] = v[10]<<3 | v[11]>>2
+	u[7] = v[11]<<6 | v[12]<<1 | v[13]>>4
+	u[8] = v[13]<<4 | v[14]>>1
+	u[9] = v[14]<<7 | v[15]<<2 | v[16]>>3
+	u[10] = v[16]<<5 | v[17]
+	u[11] = v[18]<<3 | v[19]>>2
+	u[12] = v[19]<<6 | v[20]<<1 | v[21]>>4
+	u[13] = v[21]<<4 | v[22]>>1
+	u[14] = v[22]<<7 | v[23]<<2 | v[24]>>3
+	u[15] = v[24]<<5 | v[25]
+
+	return u, nil
+}
+
+// String returns the 26 characters representation of u.
+func (u ULID) String() string {
+	b, _ := u.MarshalText()
+	return string(b)
+}
+
+// Time returns the timestamp embedded in u.
+func (u ULID) Time() time.Time {
+	return time.UnixMilli(u.timestamp())
+}
+
+// Compare returns -1, 0 or 1 if u is less than, equal to or greater than other.
+func (u ULID) Compare(other ULID) int {
+	return bytes.Compare(u[:], other[:])
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (u ULID) MarshalText() ([]byte, error) {
+	const e = ulidEncoding
+	return []byte{
+		e[u[0]>>5], e[u[0]&31],
+		e[u[1]>>3], e[(u[1]&7)<<2|u[2]>>6], e[u[2]>>1&31], e[(u[2]&1)<<4|u[3]>>4],
+		e[(u[3]&15)<<1|u[4]>>7], e[u[4]>>2&31], e[(u[4]&3)<<3|u[5]>>5], e[u[5]&31],
+		e[u[6]>>3], e[(u[6]&7)<<2|u[7]>>6], e[u[7]>>1&31], e[(u[7]&1)<<4|u[8]>>4],
+		e[(u[8]&15)<<1|u[9]>>7], e[u[9]>>2&31], e[(u[9]&3)<<3|u[10]>>5], e[u[10]&31],
+		e[u[11]>>3], e[(u[11]&7)<<2|u[12]>>6], e[u[12]>>1&31], e[(u[12]&1)<<4|u[13]>>4],
+		e[(u[13]&15)<<1|u[14]>>7], e[u[14]>>2&31], e[(u[14]&3)<<3|u[15]>>5], e[u[15]&31],
+	}, nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (u *ULID) UnmarshalText(text []byte) error {
+	parsed, err := ParseULID(string(text))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+
+	return nil
+}
+
+func (u ULID) timestamp() int64 {
+	return int64(u[0])<<40 | int64(u[1])<<32 | int64(binary.BigEndian.Uint32(u[2:6]))
+}
+
+func (g *monotonicULID) next(ms int64) ULID {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	var u ULID
+	if last := g.last.timestamp(); ms <= last {
+		// same millisecond or clock moved backwards: increment the last one,
+		// carrying into the timestamp on overflow.
+		u = g.last
+		for i := len(u) - 1; i >= 0; i-- {
+			u[i]++
+			if u[i] != 0 {
+				break
+			}
+		}
+	} else {
+		u[0], u[1] = byte(ms>>40), byte(ms>>32)
+		binary.BigEndian.PutUint32(u[2:6], uint32(ms))
+		_, _ = rand.Read(u[6:])
+	}
+	g.last = u
+
+	return u
+}
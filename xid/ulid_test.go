@@ -0,0 +1,110 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xid
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestULID(t *testing.T) {
+	u, err := ParseULID("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	assert.NoError(t, err)
+	assert.Equal(t, "01ARZ3NDEKTSV4RRFFQ69G5FAV", u.String())
+	assert.Equal(t, int64(1469922850259), u.Time().UnixMilli())
+
+	u2, err := ParseULID("01ARYZ6S41TSV4RRFFQ69G5FAV")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1469918176385), u2.Time().UnixMilli())
+
+	lower, err := ParseULID("01arz3ndektsv4rrffq69g5fav")
+	assert.NoError(t, err)
+	assert.Equal(t, u, lower)
+	ambiguous, err := ParseULID("OLARZ3NDEKTSV4RRFFQ69G5FAV")
+	assert.NoError(t, err)
+	assert.Equal(t, u, ambiguous)
+
+	maxULID, err := ParseULID("7ZZZZZZZZZZZZZZZZZZZZZZZZZ")
+	assert.NoError(t, err)
+	assert.Equal(t, ULID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, maxULID)
+
+	for _, bad := range []string{"", "01ARZ3NDEKTSV4RRFFQ69G5FA", "8ZZZZZZZZZZZZZZZZZZZZZZZZZ", "01ARZ3NDEKTSV4RRFFQ69G5FAU"} {
+		_, err = ParseULID(bad)
+		assert.ErrorIs(t, err, ErrInvalidULID, bad)
+	}
+}
+
+func TestNewULID(t *testing.T) {
+	start := time.Now().Truncate(time.Millisecond)
+	ids := make([]string, 1000)
+	prev := NewULID()
+	for i := range ids {
+		u := NewULID()
+		assert.Equal(t, 1, u.Compare(prev))
+		assert.Equal(t, -1, prev.Compare(u))
+		assert.Equal(t, 0, u.Compare(u))
+		prev = u
+
+		ids[i] = u.String()
+		parsed, err := ParseULID(ids[i])
+		assert.NoError(t, err)
+		assert.Equal(t, u, parsed)
+	}
+	assert.True(t, sort.StringsAreSorted(ids))
+	assert.False(t, prev.Time().Before(start))
+}
+
+func TestMonotonicULID(t *testing.T) {
+	var g monotonicULID
+	a := g.next(100)
+	b := g.next(100)
+	assert.Equal(t, 1, b.Compare(a))
+	assert.Equal(t, a.Time(), b.Time())
+
+	// the clock moved backwards.
+	c := g.next(99)
+	assert.Equal(t, 1, c.Compare(b))
+
+	// overflow of the random part.
+	g.last = ULID{0, 0, 0, 0, 0, 100, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	d := g.next(100)
+	assert.Equal(t, int64(101), d.Time().UnixMilli())
+}
+
+func TestULID_Text(t *testing.T) {
+	u := NewULID()
+	data, err := json.Marshal(map[string]ULID{"id": u})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":"`+u.String()+`"}`, string(data))
+
+	var decoded map[string]ULID
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, u, decoded["id"])
+
+	assert.Error(t, json.Unmarshal([]byte(`{"id":"`+strings.Repeat("U", 26)+`"}`), &decoded))
+}
+
+func BenchmarkNewULID(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = NewULID()
+	}
+}
@@ -0,0 +1,57 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xio
+
+import (
+	"bytes"
+	"io"
+)
+
+// A ReplayReader records what is read from an io.Reader so that it can be read again.
+type ReplayReader struct {
+	r   io.Reader
+	buf bytes.Buffer
+}
+
+// NewReplayReader returns a ReplayReader reading from r.
+func NewReplayReader(r io.Reader) *ReplayReader {
+	return &ReplayReader{r: r}
+}
+
+// Read reads from the underlying reader and records the data.
+func (r *ReplayReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.buf.Write(p[:n])
+
+	return n, err
+}
+
+// Recorded returns the data read so far.
+// The returned slice is only valid until the next Read.
+func (r *ReplayReader) Recorded() []byte {
+	return r.buf.Bytes()
+}
+
+// Replay returns a reader of the recorded data followed by the remaining data of the underlying reader.
+// The ReplayReader must not be used anymore afterwards.
+func (r *ReplayReader) Replay() io.Reader {
+	if r.buf.Len() == 0 {
+		return r.r
+	}
+
+	return io.MultiReader(bytes.NewReader(r.buf.Bytes()), r.r)
+}
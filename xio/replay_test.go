@@ -0,0 +1,57 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xio
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestReplayReader(t *testing.T) {
+	r := NewReplayReader(strings.NewReader("hello world"))
+	p := make([]byte, 5)
+	n, err := io.ReadFull(r, p)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, n)
+	assert.Equal(t, "hello", string(r.Recorded()))
+
+	data, err := io.ReadAll(r.Replay())
+	assert.NoError(t, err)
+	assert.Equal(t, "hello world", string(data))
+}
+
+func TestReplayReader_Empty(t *testing.T) {
+	src := strings.NewReader("abc")
+	r := NewReplayReader(src)
+	assert.Empty(t, r.Recorded())
+	assert.Same(t, src, r.Replay())
+}
+
+func TestReplayReader_Error(t *testing.T) {
+	cause := errors.New("broken")
+	r := NewReplayReader(io.MultiReader(strings.NewReader("ab"), iotest.ErrReader(cause)))
+	_, err := io.ReadAll(r)
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, "ab", string(r.Recorded()))
+
+	_, err = io.ReadAll(r.Replay())
+	assert.ErrorIs(t, err, cause)
+}
@@ -0,0 +1,50 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xiter
+
+import "iter"
+
+// ToSlice collects the elements of seq into a slice.
+func ToSlice[T any](seq iter.Seq[T]) []T {
+	var s []T
+	for v := range seq {
+		s = append(s, v)
+	}
+
+	return s
+}
+
+// ToMap collects the key-value pairs of seq into a map, the last value of a key wins.
+func ToMap[K comparable, V any](seq iter.Seq2[K, V]) map[K]V {
+	m := make(map[K]V)
+	for k, v := range seq {
+		m[k] = v
+	}
+
+	return m
+}
+
+// GroupBy collects the elements of seq into slices by the key returned by fn, keeping their order.
+func GroupBy[T any, K comparable](seq iter.Seq[T], fn func(T) K) map[K][]T {
+	m := make(map[K][]T)
+	for v := range seq {
+		k := fn(v)
+		m[k] = append(m[k], v)
+	}
+
+	return m
+}
@@ -0,0 +1,46 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xiter
+
+import (
+	"github.com/stretchr/testify/assert"
+	"iter"
+	"testing"
+)
+
+func TestToSlice(t *testing.T) {
+	assert.Equal(t, []int{0, 1, 2}, ToSlice(Take(count(), 3)))
+	assert.Nil(t, ToSlice(Take(count(), 0)))
+}
+
+func TestToMap(t *testing.T) {
+	var seq iter.Seq2[string, int] = func(yield func(string, int) bool) {
+		_ = yield("a", 1) && yield("b", 2) && yield("a", 3)
+	}
+	assert.Equal(t, map[string]int{"a": 3, "b": 2}, ToMap(seq))
+}
+
+func TestGroupBy(t *testing.T) {
+	groups := GroupBy(Take(count(), 7), func(i int) string {
+		if i%2 == 0 {
+			return "even"
+		}
+		return "odd"
+	})
+	assert.Equal(t, map[string][]int{"even": {0, 2, 4, 6}, "odd": {1, 3, 5}}, groups)
+	assert.Empty(t, GroupBy(Take(count(), 0), func(i int) int { return i }))
+}
@@ -0,0 +1,125 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xiter
+
+import (
+	"github.com/stretchr/testify/assert"
+	"iter"
+	"strconv"
+	"testing"
+)
+
+// count returns an infinite sequence from 0, to check the adapters are lazy.
+func count() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func TestFromSlice(t *testing.T) {
+	assert.Equal(t, []int{1, 2, 3}, ToSlice(FromSlice([]int{1, 2, 3})))
+	assert.Nil(t, ToSlice(FromSlice[int](nil)))
+	assert.Equal(t, []int{1}, ToSlice(Take(FromSlice([]int{1, 2, 3}), 1)))
+}
+
+func TestFromMap(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2}
+	assert.Equal(t, m, ToMap(FromMap(m)))
+
+	n := 0
+	for range FromMap(m) {
+		n++
+		break
+	}
+	assert.Equal(t, 1, n)
+}
+
+func TestFromChan(t *testing.T) {
+	ch := make(chan int, 3)
+	ch <- 1
+	ch <- 2
+	ch <- 3
+	close(ch)
+	assert.Equal(t, []int{1, 2}, ToSlice(Take(FromChan(ch), 2)))
+	assert.Equal(t, 3, <-ch)
+}
+
+func TestMapFilter(t *testing.T) {
+	seq := Map(Filter(count(), func(i int) bool { return i%2 == 0 }), strconv.Itoa)
+	assert.Equal(t, []string{"0", "2", "4"}, ToSlice(Take(seq, 3)))
+}
+
+func TestTakeSkip(t *testing.T) {
+	assert.Equal(t, []int{3, 4, 5}, ToSlice(Take(Skip(count(), 3), 3)))
+	assert.Nil(t, ToSlice(Take(count(), 0)))
+	assert.Nil(t, ToSlice(Skip(FromSlice([]int{1, 2}), 5)))
+	assert.Equal(t, []int{1, 2}, ToSlice(Skip(FromSlice([]int{1, 2}), -1)))
+
+	var got []int
+	for v := range Skip(FromSlice([]int{1, 2, 3, 4}), 1) {
+		if v == 3 {
+			break
+		}
+		got = append(got, v)
+	}
+	assert.Equal(t, []int{2}, got)
+}
+
+func TestConcat(t *testing.T) {
+	assert.Equal(t, []int{1, 2, 3, 0, 1}, ToSlice(Take(Concat(FromSlice([]int{1, 2, 3}), count()), 5)))
+	assert.Equal(t, []int{1}, ToSlice(Take(Concat(FromSlice([]int{1, 2})), 1)))
+	assert.Nil(t, ToSlice(Concat[int]()))
+}
+
+func TestZip(t *testing.T) {
+	pairs := map[string]int{}
+	for s, i := range Zip(FromSlice([]string{"a", "b", "c"}), count()) {
+		pairs[s] = i
+	}
+	assert.Equal(t, map[string]int{"a": 0, "b": 1, "c": 2}, pairs)
+
+	assert.Equal(t, map[int]string{0: "a"}, ToMap(Zip(count(), FromSlice([]string{"a"}))))
+
+	n := 0
+	for range Zip(count(), count()) {
+		n++
+		if n == 2 {
+			break
+		}
+	}
+	assert.Equal(t, 2, n)
+}
+
+func BenchmarkPipeline(b *testing.B) {
+	data := make([]int, 1000)
+	for i := range data {
+		data[i] = i
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sum := 0
+		for v := range Map(Filter(FromSlice(data), func(i int) bool { return i%3 == 0 }), func(i int) int { return i * 2 }) {
+			sum += v
+		}
+		_ = sum
+	}
+}
@@ -0,0 +1,122 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xlog
+
+import (
+	"context"
+	"log/slog"
+)
+
+type (
+	// ZapSugaredLogger is the subset of *zap.SugaredLogger used by FromZap.
+	ZapSugaredLogger interface {
+		Debugw(msg string, keysAndValues ...interface{})
+		Infow(msg string, keysAndValues ...interface{})
+		Warnw(msg string, keysAndValues ...interface{})
+		Errorw(msg string, keysAndValues ...interface{})
+	}
+
+	// LogrusEntry is the subset of *logrus.Entry used by FromLogrus.
+	LogrusEntry interface {
+		Debug(args ...interface{})
+		Info(args ...interface{})
+		Warn(args ...interface{})
+		Error(args ...interface{})
+	}
+
+	// LogrusLogger is the subset of *logrus.Logger and *logrus.Entry used by FromLogrus,
+	// F is logrus.Fields and E is *logrus.Entry.
+	LogrusLogger[F ~map[string]interface{}, E LogrusEntry] interface {
+		WithFields(fields F) E
+	}
+
+	slogSink struct {
+		l *slog.Logger
+	}
+
+	zapSink struct {
+		l ZapSugaredLogger
+	}
+
+	logrusSink[F ~map[string]interface{}, E LogrusEntry] struct {
+		l LogrusLogger[F, E]
+	}
+)
+
+// FromSlog returns a Logger writing into l.
+func FromSlog(l *slog.Logger) Logger {
+	return New(slogSink{l: l})
+}
+
+// FromZap returns a Logger writing into a *zap.SugaredLogger, e.g. FromZap(zapLogger.Sugar()).
+func FromZap(l ZapSugaredLogger) Logger {
+	return New(zapSink{l: l})
+}
+
+// FromLogrus returns a Logger writing into a *logrus.Logger or *logrus.Entry,
+// e.g. FromLogrus[logrus.Fields, *logrus.Entry](logrus.StandardLogger()).
+func FromLogrus[F ~map[string]interface{}, E LogrusEntry](l LogrusLogger[F, E]) Logger {
+	return New(logrusSink[F, E]{l: l})
+}
+
+func (s slogSink) Log(level Level, msg string, kv []interface{}) {
+	var sl slog.Level
+	switch level {
+	case LevelDebug:
+		sl = slog.LevelDebug
+	case LevelInfo:
+		sl = slog.LevelInfo
+	case LevelWarn:
+		sl = slog.LevelWarn
+	default:
+		sl = slog.LevelError
+	}
+
+	s.l.Log(context.Background(), sl, msg, kv...)
+}
+
+func (s zapSink) Log(level Level, msg string, kv []interface{}) {
+	switch level {
+	case LevelDebug:
+		s.l.Debugw(msg, kv...)
+	case LevelInfo:
+		s.l.Infow(msg, kv...)
+	case LevelWarn:
+		s.l.Warnw(msg, kv...)
+	default:
+		s.l.Errorw(msg, kv...)
+	}
+}
+
+func (s logrusSink[F, E]) Log(level Level, msg string, kv []interface{}) {
+	fields := F{}
+	pairs(kv, func(key string, value interface{}) {
+		fields[key] = value
+	})
+
+	entry := s.l.WithFields(fields)
+	switch level {
+	case LevelDebug:
+		entry.Debug(msg)
+	case LevelInfo:
+		entry.Info(msg)
+	case LevelWarn:
+		entry.Warn(msg)
+	default:
+		entry.Error(msg)
+	}
+}
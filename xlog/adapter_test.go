@@ -0,0 +1,110 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xlog
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+)
+
+type (
+	fakeZap struct {
+		lines []string
+		kv    [][]interface{}
+	}
+
+	fakeLogrusFields map[string]interface{}
+
+	fakeLogrusEntry struct {
+		logger *fakeLogrus
+		fields fakeLogrusFields
+	}
+
+	fakeLogrus struct {
+		lines  []string
+		fields []fakeLogrusFields
+	}
+)
+
+func (z *fakeZap) Debugw(msg string, kv ...interface{}) { z.add("debug "+msg, kv) }
+func (z *fakeZap) Infow(msg string, kv ...interface{})  { z.add("info "+msg, kv) }
+func (z *fakeZap) Warnw(msg string, kv ...interface{})  { z.add("warn "+msg, kv) }
+func (z *fakeZap) Errorw(msg string, kv ...interface{}) { z.add("error "+msg, kv) }
+func (z *fakeZap) add(line string, kv []interface{}) {
+	z.lines = append(z.lines, line)
+	z.kv = append(z.kv, kv)
+}
+
+func (l *fakeLogrus) WithFields(fields fakeLogrusFields) *fakeLogrusEntry {
+	return &fakeLogrusEntry{logger: l, fields: fields}
+}
+
+func (e *fakeLogrusEntry) Debug(args ...interface{}) { e.add("debug", args) }
+func (e *fakeLogrusEntry) Info(args ...interface{})  { e.add("info", args) }
+func (e *fakeLogrusEntry) Warn(args ...interface{})  { e.add("warn", args) }
+func (e *fakeLogrusEntry) Error(args ...interface{}) { e.add("error", args) }
+func (e *fakeLogrusEntry) add(level string, args []interface{}) {
+	e.logger.lines = append(e.logger.lines, level+" "+args[0].(string))
+	e.logger.fields = append(e.logger.fields, e.fields)
+}
+
+func TestFromSlog(t *testing.T) {
+	var buf bytes.Buffer
+	l := FromSlog(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})))
+	l.With("a", 1).Debug("d", "b", 2)
+	l.Info("i")
+	l.Warn("w")
+	l.Error("e")
+	assert.Equal(t, ""+
+		"level=DEBUG msg=d a=1 b=2\n"+
+		"level=INFO msg=i\n"+
+		"level=WARN msg=w\n"+
+		"level=ERROR msg=e\n", buf.String())
+}
+
+func TestFromZap(t *testing.T) {
+	z := &fakeZap{}
+	l := FromZap(z)
+	l.With("a", 1).Debug("d", "b", 2)
+	l.Info("i")
+	l.Warn("w")
+	l.Error("e")
+	assert.Equal(t, []string{"debug d", "info i", "warn w", "error e"}, z.lines)
+	assert.Equal(t, []interface{}{"a", 1, "b", 2}, z.kv[0])
+}
+
+func TestFromLogrus(t *testing.T) {
+	lr := &fakeLogrus{}
+	l := FromLogrus[fakeLogrusFields, *fakeLogrusEntry](lr)
+	l.With("a", 1).Debug("d", "b", 2)
+	l.Info("i")
+	l.Warn("w")
+	l.Error("e", 3)
+	assert.Equal(t, []string{"debug d", "info i", "warn w", "error e"}, lr.lines)
+	assert.Equal(t, fakeLogrusFields{"a": 1, "b": 2}, lr.fields[0])
+	assert.Equal(t, fakeLogrusFields{badKey: 3}, lr.fields[3])
+}
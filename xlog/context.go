@@ -0,0 +1,53 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xlog
+
+import "context"
+
+type fieldsKey struct{}
+
+// WithFields returns a copy of ctx that carries kv in addition to the fields already in ctx.
+// Use Logger.WithContext to add them into every message of a Logger.
+func WithFields(ctx context.Context, kv ...interface{}) context.Context {
+	if len(kv) == 0 {
+		return ctx
+	}
+
+	parent := FieldsFrom(ctx)
+	fields := make([]interface{}, 0, len(parent)+len(kv))
+	fields = append(fields, parent...)
+	fields = append(fields, kv...)
+
+	return context.WithValue(ctx, fieldsKey{}, fields)
+}
+
+// FieldsFrom returns the fields carried by ctx.
+// The returned slice must not be modified.
+func FieldsFrom(ctx context.Context) []interface{} {
+	if ctx == nil {
+		return nil
+	}
+
+	fields, _ := ctx.Value(fieldsKey{}).([]interface{})
+
+	return fields
+}
+
+// WithContext is like l.WithContext(ctx) but accepts a nil l, treated as a Nop Logger.
+func WithContext(ctx context.Context, l Logger) Logger {
+	return OrNop(l).WithContext(ctx)
+}
@@ -0,0 +1,57 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xlog
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWithFields(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, FieldsFrom(ctx))
+	assert.Equal(t, ctx, WithFields(ctx))
+
+	ctx1 := WithFields(ctx, "request_id", "r1")
+	ctx2 := WithFields(ctx1, "user_id", 42)
+	ctx3 := WithFields(ctx1, "user_id", 7)
+	assert.Equal(t, []interface{}{"request_id", "r1"}, FieldsFrom(ctx1))
+	assert.Equal(t, []interface{}{"request_id", "r1", "user_id", 42}, FieldsFrom(ctx2))
+	assert.Equal(t, []interface{}{"request_id", "r1", "user_id", 7}, FieldsFrom(ctx3))
+
+	//nolint:staticcheck
+	assert.Nil(t, FieldsFrom(nil))
+}
+
+func TestWithContext(t *testing.T) {
+	sink := &memorySink{}
+	l := New(sink).With("service", "api")
+
+	assert.Equal(t, l, l.WithContext(context.Background()))
+	assert.Equal(t, l, WithContext(context.Background(), l))
+
+	ctx := WithFields(context.Background(), "request_id", "r1")
+	l.WithContext(ctx).Info("hello", "k", "v")
+	assert.Equal(t, []interface{}{"service", "api", "request_id", "r1", "k", "v"}, sink.records[0].kv)
+	WithContext(ctx, l).Info("hello")
+	assert.Equal(t, []interface{}{"service", "api", "request_id", "r1"}, sink.records[1].kv)
+
+	assert.NotPanics(t, func() {
+		WithContext(ctx, nil).Info("discarded")
+	})
+}
@@ -0,0 +1,260 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xlog
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultSampleFirst      = 100
+	defaultSampleThereafter = 100
+	defaultSampleTick       = time.Second
+	maxSampleKeys           = 4096
+)
+
+type (
+	// SampleOption defines the method to customize a Sampler.
+	SampleOption func(*sampleOptions)
+
+	sampleOptions struct {
+		first      uint64
+		thereafter uint64
+		tick       time.Duration
+		rate       float64
+		burst      float64
+	}
+
+	// A Sampler is a Logger that samples and rate-limits identical messages.
+	//
+	// Within each tick, the first N messages with the same level and text are logged,
+	// then one in every M. Optionally a token bucket per message limits the rate.
+	// Loggers derived by With share the same sampling state.
+	Sampler struct {
+		logger Logger
+		state  *sampleState
+	}
+
+	// SampleStats is a snapshot of the Sampler counters.
+	SampleStats struct {
+		Logged     uint64
+		Suppressed uint64
+		// SuppressedByKey counts suppressed messages by "LEVEL message" since they were last tracked.
+		SuppressedByKey map[string]uint64
+	}
+
+	sampleState struct {
+		options    sampleOptions
+		now        func() time.Time
+		lock       sync.Mutex
+		entries    map[string]*sampleEntry
+		logged     uint64
+		suppressed uint64
+	}
+
+	sampleEntry struct {
+		windowStart time.Time
+		count       uint64
+		tokens      float64
+		last        time.Time
+		suppressed  uint64
+	}
+)
+
+// WithSampleFirst logs the first n identical messages of every tick, default to 100.
+func WithSampleFirst(n int) SampleOption {
+	return func(o *sampleOptions) {
+		o.first = uint64(n)
+	}
+}
+
+// WithSampleThereafter logs one in every m identical messages after the first ones, default to 100.
+// A non-positive m drops all of them.
+func WithSampleThereafter(m int) SampleOption {
+	return func(o *sampleOptions) {
+		if m < 0 {
+			m = 0
+		}
+		o.thereafter = uint64(m)
+	}
+}
+
+// WithSampleTick customizes the window the counters reset after, default to one second.
+func WithSampleTick(tick time.Duration) SampleOption {
+	return func(o *sampleOptions) {
+		o.tick = tick
+	}
+}
+
+// WithRateLimit limits identical messages to rate per second with the given burst.
+func WithRateLimit(rate float64, burst int) SampleOption {
+	return func(o *sampleOptions) {
+		o.rate = rate
+		o.burst = float64(burst)
+	}
+}
+
+// NewSampler returns a Sampler writing into logger.
+func NewSampler(logger Logger, opts ...SampleOption) *Sampler {
+	options := sampleOptions{
+		first:      defaultSampleFirst,
+		thereafter: defaultSampleThereafter,
+		tick:       defaultSampleTick,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.burst < 1 {
+		options.burst = 1
+	}
+
+	return &Sampler{
+		logger: OrNop(logger),
+		state: &sampleState{
+			options: options,
+			now:     time.Now,
+			entries: map[string]*sampleEntry{},
+		},
+	}
+}
+
+// Debug logs a message at LevelDebug if it's sampled.
+func (s *Sampler) Debug(msg string, kv ...interface{}) {
+	if s.state.allow(LevelDebug, msg) {
+		s.logger.Debug(msg, kv...)
+	}
+}
+
+// Info logs a message at LevelInfo if it's sampled.
+func (s *Sampler) Info(msg string, kv ...interface{}) {
+	if s.state.allow(LevelInfo, msg) {
+		s.logger.Info(msg, kv...)
+	}
+}
+
+// Warn logs a message at LevelWarn if it's sampled.
+func (s *Sampler) Warn(msg string, kv ...interface{}) {
+	if s.state.allow(LevelWarn, msg) {
+		s.logger.Warn(msg, kv...)
+	}
+}
+
+// Error logs a message at LevelError if it's sampled.
+func (s *Sampler) Error(msg string, kv ...interface{}) {
+	if s.state.allow(LevelError, msg) {
+		s.logger.Error(msg, kv...)
+	}
+}
+
+// With returns a Logger that adds kv to every message, sharing the sampling state of s.
+func (s *Sampler) With(kv ...interface{}) Logger {
+	return &Sampler{logger: s.logger.With(kv...), state: s.state}
+}
+
+// WithContext returns a Logger that adds the fields carried by ctx to every message, sharing the sampling state of s.
+func (s *Sampler) WithContext(ctx context.Context) Logger {
+	fields := FieldsFrom(ctx)
+	if len(fields) == 0 {
+		return s
+	}
+
+	return s.With(fields...)
+}
+
+// Stats returns a snapshot of the counters.
+func (s *Sampler) Stats() SampleStats {
+	s.state.lock.Lock()
+	defer s.state.lock.Unlock()
+
+	byKey := make(map[string]uint64)
+	for key, e := range s.state.entries {
+		if e.suppressed > 0 {
+			byKey[key] = e.suppressed
+		}
+	}
+
+	return SampleStats{
+		Logged:          atomic.LoadUint64(&s.state.logged),
+		Suppressed:      atomic.LoadUint64(&s.state.suppressed),
+		SuppressedByKey: byKey,
+	}
+}
+
+func (s *sampleState) allow(level Level, msg string) bool {
+	key := level.String() + " " + msg
+	now := s.now()
+
+	s.lock.Lock()
+	e, ok := s.entries[key]
+	if !ok {
+		if len(s.entries) >= maxSampleKeys {
+			// bound the memory of high-cardinality messages.
+			s.entries = map[string]*sampleEntry{}
+		}
+		e = &sampleEntry{windowStart: now, tokens: s.options.burst, last: now}
+		s.entries[key] = e
+	}
+
+	allowed := e.sample(now, &s.options) && e.limit(now, &s.options)
+	if !allowed {
+		e.suppressed++
+	}
+	s.lock.Unlock()
+
+	if allowed {
+		atomic.AddUint64(&s.logged, 1)
+	} else {
+		atomic.AddUint64(&s.suppressed, 1)
+	}
+
+	return allowed
+}
+
+func (e *sampleEntry) sample(now time.Time, o *sampleOptions) bool {
+	if o.tick > 0 && now.Sub(e.windowStart) >= o.tick {
+		e.windowStart = now
+		e.count = 0
+	}
+
+	e.count++
+	if e.count <= o.first {
+		return true
+	}
+
+	return o.thereafter > 0 && (e.count-o.first)%o.thereafter == 0
+}
+
+func (e *sampleEntry) limit(now time.Time, o *sampleOptions) bool {
+	if o.rate <= 0 {
+		return true
+	}
+
+	e.tokens += now.Sub(e.last).Seconds() * o.rate
+	if e.tokens > o.burst {
+		e.tokens = o.burst
+	}
+	e.last = now
+	if e.tokens < 1 {
+		return false
+	}
+	e.tokens--
+
+	return true
+}
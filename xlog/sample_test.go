@@ -0,0 +1,139 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xlog
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.t
+}
+
+func newTestSampler(sink Sink, opts ...SampleOption) (*Sampler, *fakeClock) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	s := NewSampler(New(sink), opts...)
+	s.state.now = clock.now
+
+	return s, clock
+}
+
+func TestSampler(t *testing.T) {
+	sink := &memorySink{}
+	s, clock := newTestSampler(sink, WithSampleFirst(2), WithSampleThereafter(3))
+
+	for i := 0; i < 10; i++ {
+		s.Error("storm", "i", i)
+	}
+	s.Info("storm")
+	assert.Len(t, sink.records, 2+2+1)
+	assert.Equal(t, []interface{}{"i", 4}, sink.records[2].kv)
+	assert.Equal(t, []interface{}{"i", 7}, sink.records[3].kv)
+
+	stats := s.Stats()
+	assert.Equal(t, uint64(5), stats.Logged)
+	assert.Equal(t, uint64(6), stats.Suppressed)
+	assert.Equal(t, map[string]uint64{"ERROR storm": 6}, stats.SuppressedByKey)
+
+	// the next tick resets the counters.
+	clock.t = clock.t.Add(time.Second)
+	s.Error("storm")
+	s.Error("storm")
+	assert.Len(t, sink.records, 7)
+
+	// With shares the state.
+	s.With("k", "v").Error("storm")
+	assert.Len(t, sink.records, 7)
+	s.With("k", "v").Warn("other")
+	assert.Equal(t, []interface{}{"k", "v"}, sink.records[7].kv)
+
+	// So does WithContext.
+	assert.Equal(t, s, s.WithContext(context.Background()))
+	ctx := WithFields(context.Background(), "request_id", "r1")
+	s.WithContext(ctx).Error("storm")
+	assert.Len(t, sink.records, 8)
+	s.WithContext(ctx).Warn("another")
+	assert.Equal(t, []interface{}{"request_id", "r1"}, sink.records[8].kv)
+}
+
+func TestSampler_DropThereafter(t *testing.T) {
+	sink := &memorySink{}
+	s, _ := newTestSampler(sink, WithSampleFirst(1), WithSampleThereafter(-1))
+	for i := 0; i < 5; i++ {
+		s.Debug("x")
+	}
+	assert.Len(t, sink.records, 1)
+}
+
+func TestSampler_RateLimit(t *testing.T) {
+	sink := &memorySink{}
+	s, clock := newTestSampler(sink, WithSampleFirst(1000), WithRateLimit(2, 2), WithSampleTick(0))
+
+	for i := 0; i < 5; i++ {
+		s.Info("limited")
+	}
+	assert.Len(t, sink.records, 2)
+
+	clock.t = clock.t.Add(500 * time.Millisecond)
+	s.Info("limited")
+	s.Info("limited")
+	assert.Len(t, sink.records, 3)
+
+	clock.t = clock.t.Add(time.Hour)
+	for i := 0; i < 5; i++ {
+		s.Info("limited")
+	}
+	assert.Len(t, sink.records, 5)
+	assert.Equal(t, uint64(7), s.Stats().Suppressed)
+}
+
+func TestSampler_MaxKeys(t *testing.T) {
+	sink := &memorySink{}
+	s, _ := newTestSampler(sink)
+	for i := 0; i < maxSampleKeys+10; i++ {
+		s.Info(time.Duration(i).String())
+	}
+	assert.LessOrEqual(t, len(s.state.entries), maxSampleKeys)
+	assert.Len(t, sink.records, maxSampleKeys+10)
+}
+
+func TestSampler_Concurrent(t *testing.T) {
+	s := NewSampler(nil, WithSampleFirst(10), WithSampleThereafter(0))
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				s.Warn("concurrent")
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := s.Stats()
+	assert.Equal(t, uint64(10), stats.Logged)
+	assert.Equal(t, uint64(990), stats.Suppressed)
+}
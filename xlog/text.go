@@ -0,0 +1,139 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xlog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+const defaultTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+var defaultLogger = NewLogger(os.Stderr)
+
+type (
+	// Option defines the method to customize the text Logger.
+	Option func(*textSink)
+
+	textSink struct {
+		lock       sync.Mutex
+		w          io.Writer
+		level      Level
+		timeFormat string
+		now        func() time.Time
+	}
+)
+
+// WithLevel customizes the minimum level to write, default to LevelInfo.
+func WithLevel(level Level) Option {
+	return func(s *textSink) {
+		s.level = level
+	}
+}
+
+// WithTimeFormat customizes the timestamp layout, an empty layout omits the timestamp.
+func WithTimeFormat(layout string) Option {
+	return func(s *textSink) {
+		s.timeFormat = layout
+	}
+}
+
+// NewLogger returns a Logger writing lines like
+// `2021-01-02T15:04:05.000Z INFO started port=8080` into w.
+func NewLogger(w io.Writer, opts ...Option) Logger {
+	s := &textSink{
+		w:          w,
+		level:      LevelInfo,
+		timeFormat: defaultTimeFormat,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return New(s)
+}
+
+// Default returns the Logger writing into stderr at LevelInfo.
+func Default() Logger {
+	return defaultLogger
+}
+
+func (s *textSink) Log(level Level, msg string, kv []interface{}) {
+	if level < s.level {
+		return
+	}
+
+	var buf bytes.Buffer
+	if s.timeFormat != "" {
+		buf.WriteString(s.now().Format(s.timeFormat))
+		buf.WriteByte(' ')
+	}
+	buf.WriteString(level.String())
+	buf.WriteByte(' ')
+	buf.WriteString(msg)
+	pairs(kv, func(key string, value interface{}) {
+		buf.WriteByte(' ')
+		buf.WriteString(key)
+		buf.WriteByte('=')
+		buf.WriteString(formatValue(value))
+	})
+	buf.WriteByte('\n')
+
+	s.lock.Lock()
+	_, _ = s.w.Write(buf.Bytes())
+	s.lock.Unlock()
+}
+
+func formatValue(v interface{}) string {
+	var s string
+	switch x := v.(type) {
+	case string:
+		s = x
+	case error:
+		s = x.Error()
+	case fmt.Stringer:
+		s = x.String()
+	case nil:
+		return "<nil>"
+	default:
+		s = fmt.Sprint(x)
+	}
+
+	if needsQuote(s) {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
+
+func needsQuote(s string) bool {
+	if s == "" {
+		return true
+	}
+
+	return strings.IndexFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '"' || r == '=' || !unicode.IsPrint(r)
+	}) >= 0
+}
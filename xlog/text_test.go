@@ -0,0 +1,52 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xlog
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNewLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(&buf, WithTimeFormat(""))
+	l.Debug("hidden")
+	l.Info("started", "port", 8080, "name", "my app", "err", errors.New("boom"), "d", time.Second, "nil", nil, "empty", "")
+	l.With("k", "a=b").Warn("warn")
+	assert.Equal(t, ""+
+		"INFO started port=8080 name=\"my app\" err=boom d=1s nil=<nil> empty=\"\"\n"+
+		"WARN warn k=\"a=b\"\n", buf.String())
+
+	buf.Reset()
+	l = NewLogger(&buf, WithLevel(LevelDebug))
+	l.(*logger).sink.(*textSink).now = func() time.Time {
+		return time.Date(2021, 1, 2, 15, 4, 5, 0, time.UTC)
+	}
+	l.Debug("debug")
+	assert.Equal(t, "2021-01-02T15:04:05.000Z DEBUG debug\n", buf.String())
+
+	buf.Reset()
+	NewLogger(&buf, WithTimeFormat(""), WithLevel(LevelError)).Warn("dropped")
+	assert.Equal(t, "", buf.String())
+}
+
+func TestDefault(t *testing.T) {
+	assert.NotNil(t, Default())
+}
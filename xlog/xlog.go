@@ -0,0 +1,183 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xlog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	// LevelDebug is the level of debugging messages.
+	LevelDebug Level = iota - 1
+	// LevelInfo is the level of informational messages.
+	LevelInfo
+	// LevelWarn is the level of warning messages.
+	LevelWarn
+	// LevelError is the level of error messages.
+	LevelError
+)
+
+const badKey = "!BADKEY"
+
+var nop = New(nopSink{})
+
+type (
+	// Level is the importance of a log message.
+	Level int
+
+	// A Logger is a leveled, structured logger.
+	// kv are alternating keys and values, e.g. Info("started", "port", 8080).
+	Logger interface {
+		Debug(msg string, kv ...interface{})
+		Info(msg string, kv ...interface{})
+		Warn(msg string, kv ...interface{})
+		Error(msg string, kv ...interface{})
+		// With returns a Logger that adds kv to every message.
+		With(kv ...interface{}) Logger
+		// WithContext returns a Logger that adds the fields carried by ctx, see WithFields, to every message.
+		WithContext(ctx context.Context) Logger
+	}
+
+	// A Sink writes log messages, it's the extension point for adapters.
+	// kv holds the fields added by With followed by those of the message.
+	Sink interface {
+		Log(level Level, msg string, kv []interface{})
+	}
+
+	logger struct {
+		sink   Sink
+		fields []interface{}
+	}
+
+	nopSink struct{}
+)
+
+// String returns the name of l.
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	default:
+		return fmt.Sprintf("LEVEL(%d)", int(l))
+	}
+}
+
+// ParseLevel parses a level name such as "debug" or "WARN".
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	default:
+		return LevelInfo, fmt.Errorf("xlog: unknown level %q", s)
+	}
+}
+
+// New returns a Logger that writes into sink.
+func New(sink Sink) Logger {
+	return &logger{sink: sink}
+}
+
+// Nop returns a Logger that discards everything.
+func Nop() Logger {
+	return nop
+}
+
+// OrNop returns l, or a Nop Logger if l is nil.
+func OrNop(l Logger) Logger {
+	if l == nil {
+		return nop
+	}
+
+	return l
+}
+
+func (l *logger) Debug(msg string, kv ...interface{}) {
+	l.log(LevelDebug, msg, kv)
+}
+
+func (l *logger) Info(msg string, kv ...interface{}) {
+	l.log(LevelInfo, msg, kv)
+}
+
+func (l *logger) Warn(msg string, kv ...interface{}) {
+	l.log(LevelWarn, msg, kv)
+}
+
+func (l *logger) Error(msg string, kv ...interface{}) {
+	l.log(LevelError, msg, kv)
+}
+
+func (l *logger) With(kv ...interface{}) Logger {
+	if len(kv) == 0 {
+		return l
+	}
+
+	return &logger{sink: l.sink, fields: l.merge(kv)}
+}
+
+func (l *logger) WithContext(ctx context.Context) Logger {
+	return l.With(FieldsFrom(ctx)...)
+}
+
+func (l *logger) log(level Level, msg string, kv []interface{}) {
+	l.sink.Log(level, msg, l.merge(kv))
+}
+
+func (l *logger) merge(kv []interface{}) []interface{} {
+	if len(l.fields) == 0 {
+		return kv
+	}
+	if len(kv) == 0 {
+		return l.fields
+	}
+
+	fields := make([]interface{}, 0, len(l.fields)+len(kv))
+	fields = append(fields, l.fields...)
+
+	return append(fields, kv...)
+}
+
+func (nopSink) Log(Level, string, []interface{}) {}
+
+// pairs calls fn with each key and value of kv.
+// A non-string key or a missing value is reported with the key "!BADKEY".
+func pairs(kv []interface{}, fn func(key string, value interface{})) {
+	for i := 0; i < len(kv); i++ {
+		key, ok := kv[i].(string)
+		if !ok || i+1 == len(kv) {
+			fn(badKey, kv[i])
+			continue
+		}
+
+		fn(key, kv[i+1])
+		i++
+	}
+}
@@ -0,0 +1,101 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xlog
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type (
+	record struct {
+		level Level
+		msg   string
+		kv    []interface{}
+	}
+
+	memorySink struct {
+		records []record
+	}
+)
+
+func (s *memorySink) Log(level Level, msg string, kv []interface{}) {
+	s.records = append(s.records, record{level: level, msg: msg, kv: kv})
+}
+
+func TestLevel(t *testing.T) {
+	assert.Equal(t, "DEBUG", LevelDebug.String())
+	assert.Equal(t, "INFO", LevelInfo.String())
+	assert.Equal(t, "WARN", LevelWarn.String())
+	assert.Equal(t, "ERROR", LevelError.String())
+	assert.Equal(t, "LEVEL(9)", Level(9).String())
+
+	for s, want := range map[string]Level{"debug": LevelDebug, "INFO": LevelInfo, "warning": LevelWarn, "Error": LevelError} {
+		l, err := ParseLevel(s)
+		assert.NoError(t, err)
+		assert.Equal(t, want, l)
+	}
+	_, err := ParseLevel("fatal")
+	assert.Error(t, err)
+}
+
+func TestLogger(t *testing.T) {
+	sink := &memorySink{}
+	l := New(sink)
+	l.Debug("d", "a", 1)
+	l.Info("i")
+	l.Warn("w")
+	l.Error("e", "err", "x")
+
+	child := l.With("request_id", "r1")
+	assert.Equal(t, child, child.With())
+	child.With("user", 2).Info("nested", "k", "v")
+	child.Info("child")
+
+	assert.Equal(t, []record{
+		{LevelDebug, "d", []interface{}{"a", 1}},
+		{LevelInfo, "i", nil},
+		{LevelWarn, "w", nil},
+		{LevelError, "e", []interface{}{"err", "x"}},
+		{LevelInfo, "nested", []interface{}{"request_id", "r1", "user", 2, "k", "v"}},
+		{LevelInfo, "child", []interface{}{"request_id", "r1"}},
+	}, sink.records)
+}
+
+func TestNop(t *testing.T) {
+	assert.NotPanics(t, func() {
+		Nop().With("a", 1).Error("x")
+	})
+	assert.Equal(t, Nop(), OrNop(nil))
+
+	l := New(&memorySink{})
+	assert.Equal(t, l, OrNop(l))
+}
+
+func TestPairs(t *testing.T) {
+	var got []interface{}
+	pairs([]interface{}{"a", 1, 2, "b", "c"}, func(key string, value interface{}) {
+		got = append(got, key, value)
+	})
+	assert.Equal(t, []interface{}{"a", 1, badKey, 2, "b", "c"}, got)
+
+	got = nil
+	pairs([]interface{}{"a", 1, "dangling"}, func(key string, value interface{}) {
+		got = append(got, key, value)
+	})
+	assert.Equal(t, []interface{}{"a", 1, badKey, "dangling"}, got)
+}
@@ -0,0 +1,303 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xmap
+
+import (
+	"sync"
+	"time"
+)
+
+// EvictReason tells why an entry left an ExpiringMap.
+type EvictReason int
+
+const (
+	// EvictExpired means the entry outlived its TTL.
+	EvictExpired EvictReason = iota
+	// EvictDeleted means the entry was removed by Delete.
+	EvictDeleted
+)
+
+// String returns the name of r.
+func (r EvictReason) String() string {
+	switch r {
+	case EvictExpired:
+		return "expired"
+	case EvictDeleted:
+		return "deleted"
+	default:
+		return "unknown"
+	}
+}
+
+type (
+	// An ExpiringMap is a map whose entries expire after a TTL, safe for concurrent use.
+	// Expired entries are evicted lazily when they are read and by DeleteExpired,
+	// which an optional janitor goroutine calls periodically, see WithExpiringJanitor.
+	ExpiringMap[K comparable, V any] struct {
+		lock    sync.RWMutex
+		entries map[K]expiringEntry[V]
+		ttl     time.Duration
+		now     func() time.Time
+		onEvict func(key K, value V, reason EvictReason)
+
+		closeOnce sync.Once
+		done      chan struct{}
+	}
+
+	expiringEntry[V any] struct {
+		value V
+		// expireAt is in unix nanoseconds, zero means never.
+		expireAt int64
+	}
+
+	// ExpiringMapOption defines the method to customize an ExpiringMap.
+	ExpiringMapOption func(o *expiringOptions)
+
+	expiringOptions struct {
+		janitor time.Duration
+		now     func() time.Time
+	}
+)
+
+// WithExpiringJanitor runs a goroutine removing expired entries every interval until Close is called.
+// By default expired entries are only removed when accessed or by DeleteExpired.
+func WithExpiringJanitor(interval time.Duration) ExpiringMapOption {
+	if interval <= 0 {
+		panic("xmap: janitor interval must be positive")
+	}
+
+	return func(o *expiringOptions) {
+		o.janitor = interval
+	}
+}
+
+// WithExpiringClock customizes the clock used to compute expiration, default to time.Now.
+func WithExpiringClock(now func() time.Time) ExpiringMapOption {
+	return func(o *expiringOptions) {
+		o.now = now
+	}
+}
+
+// NewExpiringMap returns an empty ExpiringMap whose entries expire after ttl by default.
+// A non-positive ttl means entries never expire unless set with SetWithTTL.
+func NewExpiringMap[K comparable, V any](ttl time.Duration, opts ...ExpiringMapOption) *ExpiringMap[K, V] {
+	o := expiringOptions{now: time.Now}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	m := &ExpiringMap[K, V]{
+		entries: make(map[K]expiringEntry[V]),
+		ttl:     ttl,
+		now:     o.now,
+		done:    make(chan struct{}),
+	}
+	if o.janitor > 0 {
+		go m.janitor(o.janitor)
+	}
+
+	return m
+}
+
+// OnEvict registers fn to be called after an entry is evicted or deleted, replacing any previous one.
+// fn is called without holding the lock, so it may use the map.
+func (m *ExpiringMap[K, V]) OnEvict(fn func(key K, value V, reason EvictReason)) {
+	m.lock.Lock()
+	m.onEvict = fn
+	m.lock.Unlock()
+}
+
+// Len returns the number of entries, including expired ones not evicted yet.
+func (m *ExpiringMap[K, V]) Len() int {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return len(m.entries)
+}
+
+// Get returns the value of key if it is present and not expired.
+func (m *ExpiringMap[K, V]) Get(key K) (V, bool) {
+	now := m.now().UnixNano()
+	m.lock.RLock()
+	e, ok := m.entries[key]
+	m.lock.RUnlock()
+	if ok && !e.expired(now) {
+		return e.value, true
+	}
+	if ok {
+		m.evictExpired(key, now)
+	}
+
+	var zero V
+	return zero, false
+}
+
+// TTL returns the time left before key expires, zero for an entry that never expires.
+func (m *ExpiringMap[K, V]) TTL(key K) (time.Duration, bool) {
+	now := m.now().UnixNano()
+	m.lock.RLock()
+	e, ok := m.entries[key]
+	m.lock.RUnlock()
+	if !ok || e.expired(now) {
+		return 0, false
+	}
+	if e.expireAt == 0 {
+		return 0, true
+	}
+
+	return time.Duration(e.expireAt - now), true
+}
+
+// Set stores value under key with the default TTL, replacing any previous value and TTL.
+func (m *ExpiringMap[K, V]) Set(key K, value V) {
+	m.SetWithTTL(key, value, m.ttl)
+}
+
+// SetWithTTL stores value under key expiring after ttl, a non-positive ttl means never.
+func (m *ExpiringMap[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	e := m.newEntry(value, ttl)
+	m.lock.Lock()
+	m.entries[key] = e
+	m.lock.Unlock()
+}
+
+// GetOrSet returns the existing value of key if it is present and not expired.
+// Otherwise, it stores value with the default TTL and returns it.
+// The loaded result is true if the value was loaded, false if stored.
+func (m *ExpiringMap[K, V]) GetOrSet(key K, value V) (actual V, loaded bool) {
+	now := m.now().UnixNano()
+	m.lock.Lock()
+	e, ok := m.entries[key]
+	if ok && !e.expired(now) {
+		m.lock.Unlock()
+		return e.value, true
+	}
+	m.entries[key] = m.newEntry(value, m.ttl)
+	onEvict := m.onEvict
+	m.lock.Unlock()
+
+	if ok && onEvict != nil {
+		onEvict(key, e.value, EvictExpired)
+	}
+
+	return value, false
+}
+
+// Delete removes key and reports whether it was present and not expired.
+func (m *ExpiringMap[K, V]) Delete(key K) bool {
+	now := m.now().UnixNano()
+	m.lock.Lock()
+	e, ok := m.entries[key]
+	if ok {
+		delete(m.entries, key)
+	}
+	onEvict := m.onEvict
+	m.lock.Unlock()
+
+	if !ok {
+		return false
+	}
+	expired := e.expired(now)
+	if onEvict != nil {
+		reason := EvictDeleted
+		if expired {
+			reason = EvictExpired
+		}
+		onEvict(key, e.value, reason)
+	}
+
+	return !expired
+}
+
+// DeleteExpired removes all expired entries and returns how many were removed.
+func (m *ExpiringMap[K, V]) DeleteExpired() int {
+	now := m.now().UnixNano()
+	type evicted struct {
+		key   K
+		value V
+	}
+
+	var list []evicted
+	m.lock.Lock()
+	for k, e := range m.entries {
+		if e.expired(now) {
+			delete(m.entries, k)
+			list = append(list, evicted{key: k, value: e.value})
+		}
+	}
+	onEvict := m.onEvict
+	m.lock.Unlock()
+
+	if onEvict != nil {
+		for _, e := range list {
+			onEvict(e.key, e.value, EvictExpired)
+		}
+	}
+
+	return len(list)
+}
+
+// Close stops the janitor goroutine if any. The map stays usable with lazy eviction only.
+func (m *ExpiringMap[K, V]) Close() {
+	m.closeOnce.Do(func() {
+		close(m.done)
+	})
+}
+
+func (m *ExpiringMap[K, V]) newEntry(value V, ttl time.Duration) expiringEntry[V] {
+	e := expiringEntry[V]{value: value}
+	if ttl > 0 {
+		e.expireAt = m.now().Add(ttl).UnixNano()
+	}
+
+	return e
+}
+
+func (m *ExpiringMap[K, V]) evictExpired(key K, now int64) {
+	m.lock.Lock()
+	// the entry may have been refreshed since it was read.
+	e, ok := m.entries[key]
+	if !ok || !e.expired(now) {
+		m.lock.Unlock()
+		return
+	}
+	delete(m.entries, key)
+	onEvict := m.onEvict
+	m.lock.Unlock()
+
+	if onEvict != nil {
+		onEvict(key, e.value, EvictExpired)
+	}
+}
+
+func (m *ExpiringMap[K, V]) janitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.DeleteExpired()
+		case <-m.done:
+			return
+		}
+	}
+}
+
+func (e expiringEntry[V]) expired(now int64) bool {
+	return e.expireAt != 0 && now >= e.expireAt
+}
@@ -0,0 +1,227 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xmap
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	lock sync.Mutex
+	now  time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(1700000000, 0)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	c.now = c.now.Add(d)
+	c.lock.Unlock()
+}
+
+type evictEvent struct {
+	key    string
+	value  int
+	reason EvictReason
+}
+
+func TestExpiringMap(t *testing.T) {
+	clock := newFakeClock()
+	m := NewExpiringMap[string, int](time.Minute, WithExpiringClock(clock.Now))
+	defer m.Close()
+
+	var events []evictEvent
+	m.OnEvict(func(key string, value int, reason EvictReason) {
+		events = append(events, evictEvent{key, value, reason})
+	})
+
+	m.Set("a", 1)
+	m.SetWithTTL("b", 2, 2*time.Minute)
+	m.SetWithTTL("forever", 3, 0)
+
+	v, ok := m.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	ttl, ok := m.TTL("a")
+	assert.True(t, ok)
+	assert.Equal(t, time.Minute, ttl)
+	ttl, ok = m.TTL("forever")
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), ttl)
+
+	clock.Advance(time.Minute)
+	_, ok = m.TTL("a")
+	assert.False(t, ok)
+	assert.Equal(t, 3, m.Len(), "eviction is lazy")
+	_, ok = m.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 2, m.Len())
+	assert.Equal(t, []evictEvent{{"a", 1, EvictExpired}}, events)
+
+	v, ok = m.Get("b")
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+
+	// Set refreshes the TTL.
+	m.Set("b", 20)
+	clock.Advance(59 * time.Second)
+	v, ok = m.Get("b")
+	assert.True(t, ok)
+	assert.Equal(t, 20, v)
+
+	assert.True(t, m.Delete("b"))
+	assert.False(t, m.Delete("b"))
+	assert.Equal(t, evictEvent{"b", 20, EvictDeleted}, events[1])
+
+	clock.Advance(time.Hour)
+	v, ok = m.Get("forever")
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+	assert.Len(t, events, 2)
+}
+
+func TestExpiringMap_GetOrSet(t *testing.T) {
+	clock := newFakeClock()
+	m := NewExpiringMap[string, int](time.Second, WithExpiringClock(clock.Now))
+
+	var events []evictEvent
+	m.OnEvict(func(key string, value int, reason EvictReason) {
+		events = append(events, evictEvent{key, value, reason})
+	})
+
+	v, loaded := m.GetOrSet("k", 1)
+	assert.False(t, loaded)
+	assert.Equal(t, 1, v)
+	v, loaded = m.GetOrSet("k", 2)
+	assert.True(t, loaded)
+	assert.Equal(t, 1, v)
+
+	clock.Advance(time.Second)
+	v, loaded = m.GetOrSet("k", 3)
+	assert.False(t, loaded)
+	assert.Equal(t, 3, v)
+	assert.Equal(t, []evictEvent{{"k", 1, EvictExpired}}, events)
+}
+
+func TestExpiringMap_DeleteExpired(t *testing.T) {
+	clock := newFakeClock()
+	m := NewExpiringMap[string, int](0, WithExpiringClock(clock.Now))
+
+	var events []evictEvent
+	m.OnEvict(func(key string, value int, reason EvictReason) {
+		events = append(events, evictEvent{key, value, reason})
+	})
+
+	m.SetWithTTL("a", 1, time.Second)
+	m.SetWithTTL("b", 2, time.Second)
+	m.SetWithTTL("c", 3, time.Hour)
+	m.Set("d", 4)
+
+	assert.Equal(t, 0, m.DeleteExpired())
+	clock.Advance(time.Second)
+	assert.Equal(t, 2, m.DeleteExpired())
+	assert.Equal(t, 2, m.Len())
+	assert.ElementsMatch(t, []evictEvent{{"a", 1, EvictExpired}, {"b", 2, EvictExpired}}, events)
+
+	// deleting an expired entry reports it as expired.
+	clock.Advance(time.Hour)
+	assert.False(t, m.Delete("c"))
+	assert.Equal(t, evictEvent{"c", 3, EvictExpired}, events[2])
+}
+
+func TestExpiringMap_Janitor(t *testing.T) {
+	var evicted atomic.Int32
+	m := NewExpiringMap[int, int](time.Millisecond, WithExpiringJanitor(5*time.Millisecond))
+	m.OnEvict(func(int, int, EvictReason) {
+		evicted.Add(1)
+	})
+	for i := 0; i < 10; i++ {
+		m.Set(i, i)
+	}
+
+	assert.Eventually(t, func() bool {
+		return m.Len() == 0
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, int32(10), evicted.Load())
+
+	m.Close()
+	m.Close()
+
+	assert.Panics(t, func() {
+		WithExpiringJanitor(0)
+	})
+}
+
+func TestExpiringMap_CallbackReentrant(t *testing.T) {
+	clock := newFakeClock()
+	m := NewExpiringMap[string, int](time.Second, WithExpiringClock(clock.Now))
+	m.OnEvict(func(key string, value int, reason EvictReason) {
+		if reason == EvictExpired {
+			m.Set(key, value+1)
+		}
+	})
+
+	m.Set("k", 1)
+	clock.Advance(time.Second)
+	_, ok := m.Get("k")
+	assert.False(t, ok)
+	v, ok := m.Get("k")
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+}
+
+func TestExpiringMap_Concurrent(t *testing.T) {
+	m := NewExpiringMap[int, int](time.Millisecond, WithExpiringJanitor(time.Millisecond))
+	defer m.Close()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				key := i % 32
+				m.Set(key, i)
+				m.Get(key)
+				m.GetOrSet(key+g, i)
+				if i%7 == 0 {
+					m.Delete(key)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func TestEvictReason_String(t *testing.T) {
+	assert.Equal(t, "expired", EvictExpired.String())
+	assert.Equal(t, "deleted", EvictDeleted.String())
+	assert.Equal(t, "unknown", EvictReason(-1).String())
+}
@@ -0,0 +1,104 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xmap
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDuplicateValue is returned by Invert when several keys have the same value.
+var ErrDuplicateValue = errors.New("xmap: duplicate value")
+
+// Invert returns a map from the values of m to their keys.
+// It is meant for lookup tables where values are unique and
+// returns ErrDuplicateValue if several keys share a value;
+// see InvertFunc and InvertGroup to handle duplicates.
+func Invert[M ~map[K]V, K, V comparable](m M) (map[V]K, error) {
+	out := make(map[V]K, len(m))
+	for k, v := range m {
+		if _, ok := out[v]; ok {
+			return nil, fmt.Errorf("%w: %v", ErrDuplicateValue, v)
+		}
+		out[v] = k
+	}
+
+	return out, nil
+}
+
+// InvertFunc is like Invert, but stores resolve(value, existing, k) when a value is shared by several keys.
+// Keys are visited in map order, so resolve should not depend on the order of its arguments
+// for a deterministic result, e.g. keep the smaller key.
+func InvertFunc[M ~map[K]V, K, V comparable](m M, resolve func(value V, existing, k K) K) map[V]K {
+	out := make(map[V]K, len(m))
+	for k, v := range m {
+		if existing, ok := out[v]; ok {
+			k = resolve(v, existing, k)
+		}
+		out[v] = k
+	}
+
+	return out
+}
+
+// InvertGroup returns a map from the values of m to all keys having that value, in no particular order.
+func InvertGroup[M ~map[K]V, K, V comparable](m M) map[V][]K {
+	out := make(map[V][]K, len(m))
+	for k, v := range m {
+		out[v] = append(out[v], k)
+	}
+
+	return out
+}
+
+// MapKeys returns a new map with fn applied to the keys of m.
+// If fn maps several keys to the same key, one of their values is kept arbitrarily;
+// see MapKeysFunc to merge them.
+func MapKeys[M ~map[K]V, K, K2 comparable, V any](m M, fn func(K) K2) map[K2]V {
+	out := make(map[K2]V, len(m))
+	for k, v := range m {
+		out[fn(k)] = v
+	}
+
+	return out
+}
+
+// MapKeysFunc is like MapKeys, but stores merge(key, existing, v) when several keys map to the same key.
+func MapKeysFunc[M ~map[K]V, K, K2 comparable, V any](m M, fn func(K) K2, merge func(key K2, existing, v V) V) map[K2]V {
+	out := make(map[K2]V, len(m))
+	for k, v := range m {
+		k2 := fn(k)
+		if existing, ok := out[k2]; ok {
+			v = merge(k2, existing, v)
+		}
+		out[k2] = v
+	}
+
+	return out
+}
+
+// FilterKeys returns a new map with the entries of m whose key satisfies fn.
+func FilterKeys[M ~map[K]V, K comparable, V any](m M, fn func(K) bool) M {
+	out := make(M)
+	for k, v := range m {
+		if fn(k) {
+			out[k] = v
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,87 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xmap
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"strings"
+	"testing"
+)
+
+type color int
+
+const (
+	red color = iota
+	green
+	blue
+)
+
+func TestInvert(t *testing.T) {
+	names := map[color]string{red: "red", green: "green", blue: "blue"}
+	inverted, err := Invert(names)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]color{"red": red, "green": green, "blue": blue}, inverted)
+
+	empty, err := Invert(map[int]int(nil))
+	assert.NoError(t, err)
+	assert.Empty(t, empty)
+
+	dup, err := Invert(map[int]string{1: "x", 2: "x"})
+	assert.ErrorIs(t, err, ErrDuplicateValue)
+	assert.EqualError(t, err, "xmap: duplicate value: x")
+	assert.Nil(t, dup)
+}
+
+func TestInvertFunc(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2, "c": 1, "d": 1}
+	smallest := InvertFunc(m, func(_ int, existing, k string) string { return min(existing, k) })
+	assert.Equal(t, map[int]string{1: "a", 2: "b"}, smallest)
+
+	var dups []int
+	InvertFunc(m, func(v int, existing, k string) string {
+		dups = append(dups, v)
+		return k
+	})
+	assert.Equal(t, []int{1, 1}, dups)
+}
+
+func TestInvertGroup(t *testing.T) {
+	groups := InvertGroup(map[string]int{"a": 1, "b": 2, "c": 1})
+	for _, keys := range groups {
+		sort.Strings(keys)
+	}
+	assert.Equal(t, map[int][]string{1: {"a", "c"}, 2: {"b"}}, groups)
+}
+
+func TestMapKeys(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2}
+	assert.Equal(t, map[string]int{"A": 1, "B": 2}, MapKeys(m, strings.ToUpper))
+	assert.Len(t, MapKeys(m, func(string) int { return 1 }), 1)
+
+	mixed := map[string]int{"a": 1, "A": 2, "b": 3}
+	merged := MapKeysFunc(mixed, strings.ToLower, func(_ string, existing, v int) int { return existing + v })
+	assert.Equal(t, map[string]int{"a": 3, "b": 3}, merged)
+}
+
+func TestFilterKeys(t *testing.T) {
+	m := map[string]int{"apple": 1, "avocado": 2, "banana": 3}
+	got := FilterKeys(m, func(k string) bool { return strings.HasPrefix(k, "a") })
+	assert.Equal(t, map[string]int{"apple": 1, "avocado": 2}, got)
+	assert.Len(t, m, 3)
+	assert.Empty(t, FilterKeys(m, func(string) bool { return false }))
+}
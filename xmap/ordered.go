@@ -0,0 +1,331 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xmap
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"reflect"
+	"strconv"
+)
+
+// ErrUnsupportedKey is returned when an OrderedMap key cannot be encoded as a JSON object key.
+var ErrUnsupportedKey = errors.New("xmap: unsupported key type")
+
+type (
+	// An OrderedMap is a map that remembers the order in which keys were first inserted.
+	// Setting an existing key updates its value in place without moving it.
+	// The zero value is an empty map ready to use. An OrderedMap is not safe for concurrent use.
+	OrderedMap[K comparable, V any] struct {
+		index map[K]*orderedNode[K, V]
+		// root is the sentinel of a circular doubly linked list: root.next is the oldest entry.
+		root orderedNode[K, V]
+	}
+
+	orderedNode[K comparable, V any] struct {
+		key        K
+		value      V
+		prev, next *orderedNode[K, V]
+	}
+)
+
+// NewOrderedMap returns an empty OrderedMap with room for size entries.
+func NewOrderedMap[K comparable, V any](size ...int) *OrderedMap[K, V] {
+	m := &OrderedMap[K, V]{}
+	n := 0
+	if len(size) > 0 {
+		n = size[0]
+	}
+	m.init(n)
+
+	return m
+}
+
+func (m *OrderedMap[K, V]) init(size int) {
+	m.index = make(map[K]*orderedNode[K, V], size)
+	m.root.prev = &m.root
+	m.root.next = &m.root
+}
+
+func (m *OrderedMap[K, V]) lazyInit() {
+	if m.index == nil {
+		m.init(0)
+	}
+}
+
+// Len returns the number of entries.
+func (m *OrderedMap[K, V]) Len() int {
+	return len(m.index)
+}
+
+// Get returns the value of key.
+func (m *OrderedMap[K, V]) Get(key K) (V, bool) {
+	if n, ok := m.index[key]; ok {
+		return n.value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Has reports whether key is present.
+func (m *OrderedMap[K, V]) Has(key K) bool {
+	_, ok := m.index[key]
+	return ok
+}
+
+// Set stores value under key. A new key is appended at the end, an existing one keeps its position.
+func (m *OrderedMap[K, V]) Set(key K, value V) {
+	m.lazyInit()
+	if n, ok := m.index[key]; ok {
+		n.value = value
+		return
+	}
+
+	n := &orderedNode[K, V]{key: key, value: value, prev: m.root.prev, next: &m.root}
+	m.root.prev.next = n
+	m.root.prev = n
+	m.index[key] = n
+}
+
+// Delete removes key and reports whether it was present.
+func (m *OrderedMap[K, V]) Delete(key K) bool {
+	n, ok := m.index[key]
+	if !ok {
+		return false
+	}
+
+	delete(m.index, key)
+	// n keeps its own links so that an iterator standing on it can move on.
+	n.prev.next = n.next
+	n.next.prev = n.prev
+
+	return true
+}
+
+// Clear removes all entries.
+func (m *OrderedMap[K, V]) Clear() {
+	m.init(0)
+}
+
+// Oldest returns the first inserted entry still present.
+func (m *OrderedMap[K, V]) Oldest() (K, V, bool) {
+	return m.edge(m.root.next)
+}
+
+// Newest returns the last inserted entry still present.
+func (m *OrderedMap[K, V]) Newest() (K, V, bool) {
+	return m.edge(m.root.prev)
+}
+
+func (m *OrderedMap[K, V]) edge(n *orderedNode[K, V]) (K, V, bool) {
+	if m.Len() == 0 {
+		var (
+			key   K
+			value V
+		)
+		return key, value, false
+	}
+
+	return n.key, n.value, true
+}
+
+// Keys returns the keys in insertion order.
+func (m *OrderedMap[K, V]) Keys() []K {
+	keys := make([]K, 0, m.Len())
+	for k := range m.All() {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
+// Values returns the values in insertion order of their keys.
+func (m *OrderedMap[K, V]) Values() []V {
+	values := make([]V, 0, m.Len())
+	for _, v := range m.All() {
+		values = append(values, v)
+	}
+
+	return values
+}
+
+// All returns an iterator over the entries in insertion order.
+// Entries may be deleted during iteration; entries set during iteration may or may not be visited.
+func (m *OrderedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for n := m.root.next; n != nil && n != &m.root; n = n.next {
+			if m.index[n.key] != n {
+				// deleted while iterating.
+				continue
+			}
+			if !yield(n.key, n.value) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the entries in reverse insertion order.
+// Entries may be deleted during iteration; entries set during iteration may or may not be visited.
+func (m *OrderedMap[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for n := m.root.prev; n != nil && n != &m.root; n = n.prev {
+			if m.index[n.key] != n {
+				// deleted while iterating.
+				continue
+			}
+			if !yield(n.key, n.value) {
+				return
+			}
+		}
+	}
+}
+
+// MarshalJSON implements json.Marshaler. The map is encoded as a JSON object with keys in insertion order.
+// Keys follow the rules of encoding/json: they must be strings, integers or implement encoding.TextMarshaler.
+func (m *OrderedMap[K, V]) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	i := 0
+	for k, v := range m.All() {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		i++
+
+		key, err := marshalKey(k)
+		if err != nil {
+			return nil, err
+		}
+		b, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+		buf.WriteByte(':')
+
+		b, err = json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It replaces the content of m with the entries
+// of a JSON object in the order they appear. A JSON null leaves m unchanged.
+func (m *OrderedMap[K, V]) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("xmap: cannot unmarshal %v into OrderedMap", tok)
+	}
+
+	m.Clear()
+	for dec.More() {
+		tok, err = dec.Token()
+		if err != nil {
+			return err
+		}
+		key, err := unmarshalKey[K](tok.(string))
+		if err != nil {
+			return err
+		}
+
+		var value V
+		if err = dec.Decode(&value); err != nil {
+			return err
+		}
+		m.Set(key, value)
+	}
+	// consume the closing brace.
+	_, err = dec.Token()
+
+	return err
+}
+
+func marshalKey[K comparable](key K) (string, error) {
+	v := reflect.ValueOf(&key).Elem()
+	if v.Kind() == reflect.String {
+		return v.String(), nil
+	}
+	if tm, ok := any(key).(encoding.TextMarshaler); ok {
+		if v.Kind() == reflect.Pointer && v.IsNil() {
+			return "", nil
+		}
+		b, err := tm.MarshalText()
+		return string(b), err
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedKey, v.Type())
+	}
+}
+
+func unmarshalKey[K comparable](s string) (K, error) {
+	var key K
+	v := reflect.ValueOf(&key).Elem()
+	if v.Kind() == reflect.String {
+		v.SetString(s)
+		return key, nil
+	}
+	if tu, ok := any(&key).(encoding.TextUnmarshaler); ok {
+		return key, tu.UnmarshalText([]byte(s))
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return key, fmt.Errorf("xmap: invalid key %q: %w", s, err)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return key, fmt.Errorf("xmap: invalid key %q: %w", s, err)
+		}
+		v.SetUint(n)
+	default:
+		return key, fmt.Errorf("%w: %s", ErrUnsupportedKey, v.Type())
+	}
+
+	return key, nil
+}
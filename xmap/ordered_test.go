@@ -0,0 +1,214 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xmap
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/netip"
+	"testing"
+)
+
+func TestOrderedMap(t *testing.T) {
+	m := NewOrderedMap[string, int]()
+	m.Set("c", 3)
+	m.Set("a", 1)
+	m.Set("b", 2)
+	m.Set("a", 10)
+
+	assert.Equal(t, 3, m.Len())
+	assert.Equal(t, []string{"c", "a", "b"}, m.Keys())
+	assert.Equal(t, []int{3, 10, 2}, m.Values())
+
+	v, ok := m.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 10, v)
+	_, ok = m.Get("x")
+	assert.False(t, ok)
+	assert.True(t, m.Has("b"))
+
+	k, v, ok := m.Oldest()
+	assert.True(t, ok)
+	assert.Equal(t, "c", k)
+	assert.Equal(t, 3, v)
+	k, v, ok = m.Newest()
+	assert.True(t, ok)
+	assert.Equal(t, "b", k)
+	assert.Equal(t, 2, v)
+
+	assert.True(t, m.Delete("c"))
+	assert.False(t, m.Delete("c"))
+	m.Set("c", 30)
+	assert.Equal(t, []string{"a", "b", "c"}, m.Keys())
+
+	var backward []string
+	for k := range m.Backward() {
+		backward = append(backward, k)
+	}
+	assert.Equal(t, []string{"c", "b", "a"}, backward)
+
+	m.Clear()
+	assert.Equal(t, 0, m.Len())
+	assert.Empty(t, m.Keys())
+	_, _, ok = m.Oldest()
+	assert.False(t, ok)
+}
+
+func TestOrderedMap_ZeroValue(t *testing.T) {
+	var m OrderedMap[int, string]
+	assert.Equal(t, 0, m.Len())
+	assert.Empty(t, m.Keys())
+	assert.False(t, m.Delete(1))
+	_, ok := m.Get(1)
+	assert.False(t, ok)
+
+	m.Set(2, "b")
+	m.Set(1, "a")
+	assert.Equal(t, []int{2, 1}, m.Keys())
+}
+
+func TestOrderedMap_DeleteWhileIterating(t *testing.T) {
+	m := NewOrderedMap[int, int](8)
+	for i := 0; i < 8; i++ {
+		m.Set(i, i)
+	}
+
+	var visited []int
+	for k := range m.All() {
+		visited = append(visited, k)
+		// delete the current and the next entry.
+		m.Delete(k)
+		m.Delete(k + 1)
+	}
+	assert.Equal(t, []int{0, 2, 4, 6}, visited)
+	assert.Equal(t, 0, m.Len())
+
+	for i := 0; i < 4; i++ {
+		m.Set(i, i)
+	}
+	visited = visited[:0]
+	for k := range m.Backward() {
+		visited = append(visited, k)
+		m.Delete(k - 1)
+	}
+	assert.Equal(t, []int{3, 1}, visited)
+	assert.Equal(t, []int{1, 3}, m.Keys())
+
+	visited = visited[:0]
+	for k := range m.All() {
+		visited = append(visited, k)
+		break
+	}
+	assert.Equal(t, []int{1}, visited)
+}
+
+func TestOrderedMap_JSON(t *testing.T) {
+	m := NewOrderedMap[string, any]()
+	m.Set("zebra", 1)
+	m.Set("apple", []int{1, 2})
+	m.Set("mango", map[string]string{"k": "v"})
+	m.Set("kiwi", nil)
+
+	b, err := json.Marshal(m)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"zebra":1,"apple":[1,2],"mango":{"k":"v"},"kiwi":null}`, string(b))
+
+	var got OrderedMap[string, json.RawMessage]
+	assert.Nil(t, json.Unmarshal([]byte(` { "b" : 1, "a" : {"x": [1]}, "c": "s", "b": 2 } `), &got))
+	assert.Equal(t, []string{"b", "a", "c"}, got.Keys())
+	v, _ := got.Get("b")
+	assert.Equal(t, "2", string(v))
+	v, _ = got.Get("a")
+	assert.Equal(t, `{"x": [1]}`, string(v))
+
+	b, err = json.Marshal(NewOrderedMap[string, int]())
+	assert.Nil(t, err)
+	assert.Equal(t, `{}`, string(b))
+
+	var nilMap *OrderedMap[string, int]
+	b, err = json.Marshal(nilMap)
+	assert.Nil(t, err)
+	assert.Equal(t, `null`, string(b))
+
+	// embedded in a struct, round trip keeps the order.
+	type config struct {
+		Name    string                       `json:"name"`
+		Servers *OrderedMap[string, float64] `json:"servers"`
+	}
+	in := `{"name":"x","servers":{"s2":2,"s1":1.5,"s3":3}}`
+	var c config
+	assert.Nil(t, json.Unmarshal([]byte(in), &c))
+	assert.Equal(t, []string{"s2", "s1", "s3"}, c.Servers.Keys())
+	b, err = json.Marshal(c)
+	assert.Nil(t, err)
+	assert.Equal(t, in, string(b))
+}
+
+func TestOrderedMap_JSONKeys(t *testing.T) {
+	ints := NewOrderedMap[int8, bool]()
+	ints.Set(-3, true)
+	ints.Set(1, false)
+	b, err := json.Marshal(ints)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"-3":true,"1":false}`, string(b))
+
+	var gotInts OrderedMap[int8, bool]
+	assert.Nil(t, json.Unmarshal(b, &gotInts))
+	assert.Equal(t, []int8{-3, 1}, gotInts.Keys())
+	assert.NotNil(t, json.Unmarshal([]byte(`{"300":true}`), &gotInts))
+
+	var gotUints OrderedMap[uint, int]
+	assert.Nil(t, json.Unmarshal([]byte(`{"7":1,"2":2}`), &gotUints))
+	assert.Equal(t, []uint{7, 2}, gotUints.Keys())
+	assert.NotNil(t, json.Unmarshal([]byte(`{"-1":1}`), &gotUints))
+
+	addrs := NewOrderedMap[netip.Addr, int]()
+	addrs.Set(netip.MustParseAddr("10.0.0.2"), 2)
+	addrs.Set(netip.MustParseAddr("10.0.0.1"), 1)
+	b, err = json.Marshal(addrs)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"10.0.0.2":2,"10.0.0.1":1}`, string(b))
+	var gotAddrs OrderedMap[netip.Addr, int]
+	assert.Nil(t, json.Unmarshal(b, &gotAddrs))
+	assert.Equal(t, addrs.Keys(), gotAddrs.Keys())
+
+	type point struct{ X, Y int }
+	points := NewOrderedMap[point, int]()
+	points.Set(point{1, 2}, 1)
+	_, err = json.Marshal(points)
+	assert.ErrorIs(t, err, ErrUnsupportedKey)
+	var gotPoints OrderedMap[point, int]
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"a":1}`), &gotPoints), ErrUnsupportedKey)
+}
+
+func TestOrderedMap_UnmarshalInvalid(t *testing.T) {
+	m := NewOrderedMap[string, int]()
+	m.Set("keep", 1)
+	assert.Nil(t, m.UnmarshalJSON([]byte(`null`)))
+	assert.Equal(t, []string{"keep"}, m.Keys())
+
+	assert.NotNil(t, json.Unmarshal([]byte(`[1,2]`), m))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"a":"x"}`), m))
+	assert.NotNil(t, m.UnmarshalJSON([]byte(`{"a":1`)))
+}
+
+func BenchmarkOrderedMap_Set(b *testing.B) {
+	m := NewOrderedMap[int, int]()
+	for i := 0; i < b.N; i++ {
+		m.Set(i, i)
+	}
+}
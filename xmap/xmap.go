@@ -0,0 +1,126 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xmap
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+)
+
+// Entry is a key-value pair of a map.
+type Entry[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// Keys returns the keys of m in no particular order.
+func Keys[M ~map[K]V, K comparable, V any](m M) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
+// SortedKeys returns the keys of m in increasing order.
+func SortedKeys[M ~map[K]V, K cmp.Ordered, V any](m M) []K {
+	keys := Keys(m)
+	slices.Sort(keys)
+
+	return keys
+}
+
+// Values returns the values of m in no particular order.
+func Values[M ~map[K]V, K comparable, V any](m M) []V {
+	values := make([]V, 0, len(m))
+	for _, v := range m {
+		values = append(values, v)
+	}
+
+	return values
+}
+
+// Entries returns the key-value pairs of m in no particular order.
+func Entries[M ~map[K]V, K comparable, V any](m M) []Entry[K, V] {
+	entries := make([]Entry[K, V], 0, len(m))
+	for k, v := range m {
+		entries = append(entries, Entry[K, V]{Key: k, Value: v})
+	}
+
+	return entries
+}
+
+// Merge returns a new map with the entries of all maps. For a key present in several
+// maps, the value of the last one wins; see MergeFunc to resolve conflicts otherwise.
+func Merge[M ~map[K]V, K comparable, V any](ms ...M) M {
+	return MergeFunc(nil, ms...)
+}
+
+// MergeFunc is like Merge, but stores resolve(key, existing, v) when a key of a later
+// map is already present. A nil resolve lets the later value win.
+func MergeFunc[M ~map[K]V, K comparable, V any](resolve func(key K, existing, v V) V, ms ...M) M {
+	n := 0
+	for _, m := range ms {
+		n = max(n, len(m))
+	}
+	out := make(M, n)
+	for _, m := range ms {
+		for k, v := range m {
+			if existing, ok := out[k]; ok && resolve != nil {
+				v = resolve(k, existing, v)
+			}
+			out[k] = v
+		}
+	}
+
+	return out
+}
+
+// Filter returns a new map with the entries of m that satisfy fn.
+func Filter[M ~map[K]V, K comparable, V any](m M, fn func(k K, v V) bool) M {
+	out := make(M)
+	for k, v := range m {
+		if fn(k, v) {
+			out[k] = v
+		}
+	}
+
+	return out
+}
+
+// MapValues returns a new map with the keys of m and fn applied to their values.
+func MapValues[M ~map[K]V, K comparable, V, U any](m M, fn func(V) U) map[K]U {
+	out := make(map[K]U, len(m))
+	for k, v := range m {
+		out[k] = fn(v)
+	}
+
+	return out
+}
+
+// Equal reports whether a and b have the same keys with equal values.
+// A nil and an empty map are equal.
+func Equal[M1 ~map[K]V, M2 ~map[K]V, K, V comparable](a M1, b M2) bool {
+	return maps.Equal(a, b)
+}
+
+// EqualFunc is like Equal, but compares values with eq.
+func EqualFunc[M1 ~map[K]V1, M2 ~map[K]V2, K comparable, V1, V2 any](a M1, b M2, eq func(V1, V2) bool) bool {
+	return maps.EqualFunc(a, b, eq)
+}
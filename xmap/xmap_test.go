@@ -0,0 +1,96 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xmap
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestKeysValues(t *testing.T) {
+	m := map[string]int{"b": 2, "a": 1, "c": 3}
+
+	keys := Keys(m)
+	sort.Strings(keys)
+	assert.Equal(t, []string{"a", "b", "c"}, keys)
+	assert.Equal(t, []string{"a", "b", "c"}, SortedKeys(m))
+
+	values := Values(m)
+	sort.Ints(values)
+	assert.Equal(t, []int{1, 2, 3}, values)
+
+	entries := Entries(m)
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	assert.Equal(t, []Entry[string, int]{{"a", 1}, {"b", 2}, {"c", 3}}, entries)
+
+	assert.Empty(t, Keys(map[string]int(nil)))
+	assert.Empty(t, Values(map[string]int(nil)))
+	assert.Empty(t, Entries(map[string]int(nil)))
+}
+
+func TestMerge(t *testing.T) {
+	a := map[string]int{"x": 1, "y": 2}
+	b := map[string]int{"y": 20, "z": 30}
+
+	assert.Equal(t, map[string]int{"x": 1, "y": 20, "z": 30}, Merge(a, b))
+	assert.Equal(t, map[string]int{"x": 1, "y": 2, "z": 30}, Merge(b, a))
+	assert.Equal(t, map[string]int{}, Merge[map[string]int]())
+	assert.Equal(t, map[string]int{"x": 1, "y": 2}, a, "inputs must not be modified")
+
+	sum := MergeFunc(func(_ string, existing, v int) int { return existing + v }, a, b, a)
+	assert.Equal(t, map[string]int{"x": 2, "y": 24, "z": 30}, sum)
+
+	first := MergeFunc(func(_ string, existing, _ int) int { return existing }, a, b)
+	assert.Equal(t, map[string]int{"x": 1, "y": 2, "z": 30}, first)
+
+	var keys []string
+	MergeFunc(func(k string, existing, v int) int {
+		keys = append(keys, k)
+		return v
+	}, a, b)
+	assert.Equal(t, []string{"y"}, keys)
+}
+
+func TestFilter(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2, "c": 3, "d": 4}
+	even := Filter(m, func(_ string, v int) bool { return v%2 == 0 })
+	assert.Equal(t, map[string]int{"b": 2, "d": 4}, even)
+	assert.Len(t, m, 4)
+
+	assert.Empty(t, Filter(m, func(string, int) bool { return false }))
+	assert.NotNil(t, Filter(map[string]int(nil), func(string, int) bool { return true }))
+}
+
+func TestMapValues(t *testing.T) {
+	m := map[int]string{1: "a", 2: "bb"}
+	assert.Equal(t, map[int]string{1: "A", 2: "BB"}, MapValues(m, strings.ToUpper))
+	assert.Equal(t, map[int]int{1: 1, 2: 2}, MapValues(m, func(s string) int { return len(s) }))
+}
+
+func TestEqual(t *testing.T) {
+	assert.True(t, Equal(map[string]int{"a": 1}, map[string]int{"a": 1}))
+	assert.True(t, Equal(map[string]int(nil), map[string]int{}))
+	assert.False(t, Equal(map[string]int{"a": 1}, map[string]int{"a": 2}))
+	assert.False(t, Equal(map[string]int{"a": 1}, map[string]int{"b": 1}))
+	assert.False(t, Equal(map[string]int{"a": 0}, map[string]int{}))
+
+	eq := func(a []int, b []int) bool { return len(a) == len(b) }
+	assert.True(t, EqualFunc(map[string][]int{"a": {1}}, map[string][]int{"a": {2}}, eq))
+	assert.False(t, EqualFunc(map[string][]int{"a": {1}}, map[string][]int{"a": {}}, eq))
+}
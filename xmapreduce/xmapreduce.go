@@ -19,6 +19,7 @@ package xmapreduce
 import (
 	"context"
 	"github.com/chenquan/go-pkg/xbarrier"
+	"github.com/chenquan/go-pkg/xlog"
 	"github.com/chenquan/go-pkg/xstream"
 	"github.com/chenquan/go-pkg/xworker"
 	"sync"
@@ -37,6 +38,7 @@ type (
 
 	options struct {
 		workerSize int
+		logger     xlog.Logger
 	}
 
 	// Option defines the method to customize the mapreduce.
@@ -50,8 +52,15 @@ func WithWorkerSize(workerSize int) Option {
 	}
 }
 
+// WithLogger customizes a mapreduce processing with the Logger of its workers.
+func WithLogger(logger xlog.Logger) Option {
+	return func(opts *options) {
+		opts.logger = logger
+	}
+}
+
 func loadOption(opts ...Option) *options {
-	opt := &options{workerSize: 16}
+	opt := &options{workerSize: 16, logger: xlog.Nop()}
 
 	for _, option := range opts {
 		option(opt)
@@ -94,7 +103,7 @@ func doMap(ctx context.Context, mapFunc MapFunc, source <-chan interface{}, coll
 		waitGroup.Wait()
 		close(collector)
 	}()
-	worker := xworker.NewWorker(option.workerSize)
+	worker := xworker.NewWorker(option.workerSize, xworker.WithLogger(option.logger))
 	writer := xbarrier.NewWriteBarrier(ctx, collector)
 
 	for {
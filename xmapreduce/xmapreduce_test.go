@@ -19,7 +19,10 @@ package xmapreduce
 import (
 	"context"
 	"github.com/chenquan/go-pkg/xbarrier"
+	"github.com/chenquan/go-pkg/xlog"
 	"github.com/stretchr/testify/assert"
+	"os"
+	"os/exec"
 	"testing"
 	"time"
 )
@@ -85,3 +88,23 @@ func TestMapStream(t *testing.T) {
 
 	assert.Equal(t, 4, count)
 }
+
+func TestWithLogger(t *testing.T) {
+	// a panicking map function crashes the process, so it runs in a child process logging to stderr.
+	if os.Getenv("XMAPREDUCE_PANIC") == "1" {
+		c := Map(context.Background(), func(source chan<- interface{}) {
+			source <- 1
+		}, func(item interface{}, writer xbarrier.Writer) {
+			panic("boom")
+		}, WithLogger(xlog.NewLogger(os.Stderr, xlog.WithTimeFormat(""))))
+		for range c {
+		}
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestWithLogger$")
+	cmd.Env = append(os.Environ(), "XMAPREDUCE_PANIC=1")
+	out, err := cmd.CombinedOutput()
+	assert.Error(t, err)
+	assert.Contains(t, string(out), "ERROR xworker: task panicked panic=")
+}
@@ -0,0 +1,558 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xmath
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// MaxDecimalScale is the maximum number of digits after the decimal point.
+const MaxDecimalScale = 18
+
+const (
+	// RoundHalfUp rounds towards the nearest neighbor, ties away from zero.
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfDown rounds towards the nearest neighbor, ties towards zero.
+	RoundHalfDown
+	// RoundHalfEven rounds towards the nearest neighbor, ties to the even neighbor (banker's rounding).
+	RoundHalfEven
+	// RoundDown rounds towards zero (truncation).
+	RoundDown
+	// RoundUp rounds away from zero.
+	RoundUp
+	// RoundFloor rounds towards negative infinity.
+	RoundFloor
+	// RoundCeiling rounds towards positive infinity.
+	RoundCeiling
+)
+
+var (
+	// ErrDecimalOverflow is returned (or panicked with) when a result doesn't fit into a Decimal.
+	ErrDecimalOverflow = errors.New("xmath: decimal overflow")
+	// ErrDecimalDivisionByZero is returned when dividing by zero.
+	ErrDecimalDivisionByZero = errors.New("xmath: decimal division by zero")
+	// ErrDecimalSyntax is returned when a string isn't a valid decimal.
+	ErrDecimalSyntax = errors.New("xmath: invalid decimal syntax")
+	// ErrDecimalScale is returned when a scale is out of [0, MaxDecimalScale].
+	ErrDecimalScale = errors.New("xmath: decimal scale out of range")
+
+	pow10Table = func() [MaxDecimalScale + 1]int64 {
+		var t [MaxDecimalScale + 1]int64
+		t[0] = 1
+		for i := 1; i < len(t); i++ {
+			t[i] = t[i-1] * 10
+		}
+		return t
+	}()
+)
+
+type (
+	// RoundingMode defines how a Decimal is rounded when digits are dropped.
+	RoundingMode int
+
+	// Decimal is a fixed-point decimal number represented as value * 10^-scale.
+	// The zero value is 0 and ready to use.
+	//
+	// Add, Sub and Mul are exact and panic with ErrDecimalOverflow when the
+	// result doesn't fit into an int64 mantissa.
+	Decimal struct {
+		value int64
+		scale int32
+	}
+)
+
+// NewDecimal returns a Decimal that equals value * 10^-scale.
+// NewDecimal panics if scale is out of [0, MaxDecimalScale].
+func NewDecimal(value int64, scale int32) Decimal {
+	checkScale(scale)
+	return Decimal{value: value, scale: scale}
+}
+
+// NewDecimalFromInt returns a Decimal that equals v.
+func NewDecimalFromInt(v int64) Decimal {
+	return Decimal{value: v}
+}
+
+// ParseDecimal parses s as a Decimal.
+// The accepted syntax is an optional sign, digits with an optional decimal point,
+// and an optional exponent, e.g. "-12.340" or "1.5e3".
+func ParseDecimal(s string) (Decimal, error) {
+	d, err := parseDecimal(s)
+	if err != nil {
+		return Decimal{}, fmt.Errorf("%w: %q", err, s)
+	}
+
+	return d, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics when error happens.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+func parseDecimal(s string) (Decimal, error) {
+	if s == "" {
+		return Decimal{}, ErrDecimalSyntax
+	}
+
+	mantissa, exp, hasExp := s, "", false
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		mantissa, exp, hasExp = s[:i], s[i+1:], true
+	}
+
+	neg := false
+	switch {
+	case strings.HasPrefix(mantissa, "-"):
+		neg = true
+		mantissa = mantissa[1:]
+	case strings.HasPrefix(mantissa, "+"):
+		mantissa = mantissa[1:]
+	}
+
+	intPart, fracPart := mantissa, ""
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		intPart, fracPart = mantissa[:i], mantissa[i+1:]
+	}
+	if intPart == "" && fracPart == "" || !isDigits(intPart) || !isDigits(fracPart) {
+		return Decimal{}, ErrDecimalSyntax
+	}
+
+	scale := int64(len(fracPart))
+	if hasExp {
+		e, err := strconv.ParseInt(exp, 10, 32)
+		if err != nil {
+			return Decimal{}, ErrDecimalSyntax
+		}
+		scale -= e
+	}
+
+	digits := strings.TrimLeft(intPart+fracPart, "0")
+	// drop trailing zeros that exceed the maximum scale.
+	for scale > MaxDecimalScale && strings.HasSuffix(digits, "0") {
+		digits = digits[:len(digits)-1]
+		scale--
+	}
+	if digits == "" {
+		return Decimal{}, nil
+	}
+	if scale > MaxDecimalScale {
+		return Decimal{}, ErrDecimalScale
+	}
+
+	value, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return Decimal{}, ErrDecimalOverflow
+	}
+	for ; scale < 0; scale++ {
+		if value, err = mulInt64(value, 10); err != nil {
+			return Decimal{}, err
+		}
+	}
+	if neg {
+		value = -value
+	}
+
+	return Decimal{value: value, scale: int32(scale)}, nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Scale returns the number of digits after the decimal point.
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Mantissa returns the unscaled value of d.
+func (d Decimal) Mantissa() int64 {
+	return d.value
+}
+
+// Sign returns -1, 0 or 1 according to the sign of d.
+func (d Decimal) Sign() int {
+	switch {
+	case d.value < 0:
+		return -1
+	case d.value > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// IsZero returns true if d equals 0.
+func (d Decimal) IsZero() bool {
+	return d.value == 0
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	if d.value == math.MinInt64 {
+		panic(ErrDecimalOverflow)
+	}
+
+	return Decimal{value: -d.value, scale: d.scale}
+}
+
+// Abs returns the absolute value of d.
+func (d Decimal) Abs() Decimal {
+	if d.value < 0 {
+		return d.Neg()
+	}
+
+	return d
+}
+
+// Add returns d + other.
+func (d Decimal) Add(other Decimal) Decimal {
+	a, b, scale := align(d, other)
+	v := a + b
+	if (v > a) != (b > 0) {
+		panic(ErrDecimalOverflow)
+	}
+
+	return Decimal{value: v, scale: scale}
+}
+
+// Sub returns d - other.
+func (d Decimal) Sub(other Decimal) Decimal {
+	a, b, scale := align(d, other)
+	v := a - b
+	if (v < a) != (b > 0) {
+		panic(ErrDecimalOverflow)
+	}
+
+	return Decimal{value: v, scale: scale}
+}
+
+// Mul returns d * other.
+// If the resulting scale exceeds MaxDecimalScale, the result is rounded with RoundHalfEven.
+func (d Decimal) Mul(other Decimal) Decimal {
+	scale := d.scale + other.scale
+	if scale <= MaxDecimalScale {
+		v, err := mulInt64(d.value, other.value)
+		if err != nil {
+			panic(err)
+		}
+		return Decimal{value: v, scale: scale}
+	}
+
+	v := new(big.Int).Mul(big.NewInt(d.value), big.NewInt(other.value))
+	r, err := roundBig(v, big.NewInt(pow10Table[scale-MaxDecimalScale]), RoundHalfEven)
+	if err != nil {
+		panic(err)
+	}
+
+	return Decimal{value: r, scale: MaxDecimalScale}
+}
+
+// Div returns d / other rounded to scale digits after the decimal point using mode.
+func (d Decimal) Div(other Decimal, scale int32, mode RoundingMode) (Decimal, error) {
+	if scale < 0 || scale > MaxDecimalScale {
+		return Decimal{}, ErrDecimalScale
+	}
+	if other.value == 0 {
+		return Decimal{}, ErrDecimalDivisionByZero
+	}
+
+	// d / other = (d.value * 10^(scale + other.scale - d.scale)) / other.value * 10^-scale
+	num := big.NewInt(d.value)
+	den := big.NewInt(other.value)
+	shift := int64(scale) + int64(other.scale) - int64(d.scale)
+	if shift >= 0 {
+		num.Mul(num, new(big.Int).Exp(big.NewInt(10), big.NewInt(shift), nil))
+	} else {
+		den.Mul(den, new(big.Int).Exp(big.NewInt(10), big.NewInt(-shift), nil))
+	}
+
+	v, err := roundBig(num, den, mode)
+	if err != nil {
+		return Decimal{}, err
+	}
+
+	return Decimal{value: v, scale: scale}, nil
+}
+
+// Round returns d rounded to scale digits after the decimal point using mode.
+// If d already has no more than scale digits, it's rescaled without rounding.
+func (d Decimal) Round(scale int32, mode RoundingMode) Decimal {
+	checkScale(scale)
+	if scale >= d.scale {
+		v, err := rescale(d.value, scale-d.scale)
+		if err != nil {
+			panic(err)
+		}
+		return Decimal{value: v, scale: scale}
+	}
+
+	v, err := roundBig(big.NewInt(d.value), big.NewInt(pow10Table[d.scale-scale]), mode)
+	if err != nil {
+		panic(err)
+	}
+
+	return Decimal{value: v, scale: scale}
+}
+
+// Cmp compares d and other and returns -1 if d < other, 0 if d == other and 1 if d > other.
+func (d Decimal) Cmp(other Decimal) int {
+	if d.scale == other.scale {
+		return compareInt64(d.value, other.value)
+	}
+
+	a := new(big.Int).Mul(big.NewInt(d.value), big.NewInt(pow10Table[maxInt32(d.scale, other.scale)-d.scale]))
+	b := new(big.Int).Mul(big.NewInt(other.value), big.NewInt(pow10Table[maxInt32(d.scale, other.scale)-other.scale]))
+
+	return a.Cmp(b)
+}
+
+// Equal returns true if d and other represent the same number, e.g. 1.0 equals 1.00.
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+// Float64 returns the nearest float64 value of d.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String returns d with exactly Scale digits after the decimal point.
+func (d Decimal) String() string {
+	var digits string
+	if d.value < 0 {
+		digits = strconv.FormatUint(uint64(-(d.value+1))+1, 10)
+	} else {
+		digits = strconv.FormatInt(d.value, 10)
+	}
+
+	if d.scale > 0 {
+		if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+			digits = strings.Repeat("0", pad) + digits
+		}
+		digits = digits[:len(digits)-int(d.scale)] + "." + digits[len(digits)-int(d.scale):]
+	}
+	if d.value < 0 {
+		digits = "-" + digits
+	}
+
+	return digits
+}
+
+// StringFixed returns d rounded half up to scale digits after the decimal point.
+func (d Decimal) StringFixed(scale int32) string {
+	return d.Round(scale, RoundHalfUp).String()
+}
+
+// MarshalJSON implements json.Marshaler. The decimal is encoded as a string to avoid precision loss.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts both JSON strings and numbers.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+
+	v, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = v
+
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Decimal) UnmarshalText(text []byte) error {
+	v, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = v
+
+	return nil
+}
+
+// Value implements driver.Valuer. The decimal is stored as a string.
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// Scan implements sql.Scanner.
+func (d *Decimal) Scan(src interface{}) error {
+	var (
+		v   Decimal
+		err error
+	)
+	switch s := src.(type) {
+	case nil:
+		v = Decimal{}
+	case string:
+		v, err = ParseDecimal(s)
+	case []byte:
+		v, err = ParseDecimal(string(s))
+	case int64:
+		v = NewDecimalFromInt(s)
+	case float64:
+		v, err = ParseDecimal(strconv.FormatFloat(s, 'f', -1, 64))
+	default:
+		err = fmt.Errorf("xmath: can't scan %T into Decimal", src)
+	}
+	if err != nil {
+		return err
+	}
+	*d = v
+
+	return nil
+}
+
+func checkScale(scale int32) {
+	if scale < 0 || scale > MaxDecimalScale {
+		panic(ErrDecimalScale)
+	}
+}
+
+// align rescales a and b to the same scale.
+func align(a, b Decimal) (int64, int64, int32) {
+	switch {
+	case a.scale == b.scale:
+		return a.value, b.value, a.scale
+	case a.scale > b.scale:
+		v, err := rescale(b.value, a.scale-b.scale)
+		if err != nil {
+			panic(err)
+		}
+		return a.value, v, a.scale
+	default:
+		v, err := rescale(a.value, b.scale-a.scale)
+		if err != nil {
+			panic(err)
+		}
+		return v, b.value, b.scale
+	}
+}
+
+func rescale(v int64, up int32) (int64, error) {
+	return mulInt64(v, pow10Table[up])
+}
+
+func mulInt64(a, b int64) (int64, error) {
+	if a == 0 || b == 0 {
+		return 0, nil
+	}
+
+	c := a * b
+	if c/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, ErrDecimalOverflow
+	}
+
+	return c, nil
+}
+
+// roundBig returns num / den rounded using mode.
+func roundBig(num, den *big.Int, mode RoundingMode) (int64, error) {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() != 0 {
+		// sign of the exact quotient.
+		neg := num.Sign()*den.Sign() < 0
+		// compare 2*|r| with |den|.
+		half := new(big.Int).Abs(r)
+		half.Lsh(half, 1)
+		cmpHalf := half.Cmp(new(big.Int).Abs(den))
+
+		var away bool
+		switch mode {
+		case RoundHalfUp:
+			away = cmpHalf >= 0
+		case RoundHalfDown:
+			away = cmpHalf > 0
+		case RoundHalfEven:
+			away = cmpHalf > 0 || cmpHalf == 0 && q.Bit(0) == 1
+		case RoundDown:
+			away = false
+		case RoundUp:
+			away = true
+		case RoundFloor:
+			away = neg
+		case RoundCeiling:
+			away = !neg
+		default:
+			return 0, fmt.Errorf("xmath: unknown rounding mode %d", mode)
+		}
+
+		if away {
+			if neg {
+				q.Sub(q, big.NewInt(1))
+			} else {
+				q.Add(q, big.NewInt(1))
+			}
+		}
+	}
+
+	if !q.IsInt64() {
+		return 0, ErrDecimalOverflow
+	}
+
+	return q.Int64(), nil
+}
+
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func maxInt32(a, b int32) int32 {
+	if a > b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,211 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xmath
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"0", "0"},
+		{"1", "1"},
+		{"-1.50", "-1.50"},
+		{"+3.14", "3.14"},
+		{".5", "0.5"},
+		{"5.", "5"},
+		{"000123.4500", "123.4500"},
+		{"1.5e3", "1500"},
+		{"15e-3", "0.015"},
+		{"-0.000", "0"},
+		{"1.0000000000000000000000", "1.000000000000000000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			d, err := ParseDecimal(tt.in)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, d.String())
+		})
+	}
+
+	for _, in := range []string{"", "-", ".", "1.2.3", "abc", "1e", "1ex", "--1"} {
+		_, err := ParseDecimal(in)
+		assert.True(t, errors.Is(err, ErrDecimalSyntax), in)
+	}
+
+	_, err := ParseDecimal("99999999999999999999")
+	assert.True(t, errors.Is(err, ErrDecimalOverflow))
+	_, err = ParseDecimal("0.0000000000000000001")
+	assert.True(t, errors.Is(err, ErrDecimalScale))
+
+	assert.Panics(t, func() {
+		MustParseDecimal("x")
+	})
+}
+
+func TestDecimal_Arithmetic(t *testing.T) {
+	a := MustParseDecimal("1.10")
+	b := MustParseDecimal("2.205")
+
+	assert.Equal(t, "3.305", a.Add(b).String())
+	assert.Equal(t, "-1.105", a.Sub(b).String())
+	assert.Equal(t, "2.42550", a.Mul(b).String())
+	assert.Equal(t, "0.3", MustParseDecimal("0.1").Add(MustParseDecimal("0.2")).String())
+
+	assert.Panics(t, func() {
+		NewDecimalFromInt(math.MaxInt64).Add(NewDecimalFromInt(1))
+	})
+	assert.Panics(t, func() {
+		NewDecimalFromInt(math.MaxInt64).Mul(NewDecimalFromInt(2))
+	})
+	assert.Panics(t, func() {
+		NewDecimalFromInt(math.MinInt64).Neg()
+	})
+	assert.Equal(t, NewDecimalFromInt(math.MaxInt64), NewDecimalFromInt(-1).Sub(NewDecimalFromInt(math.MinInt64)))
+	assert.Equal(t, NewDecimalFromInt(math.MinInt64), NewDecimalFromInt(0).Sub(NewDecimalFromInt(math.MaxInt64)).Sub(NewDecimalFromInt(1)))
+	assert.Panics(t, func() {
+		NewDecimalFromInt(0).Sub(NewDecimalFromInt(math.MinInt64))
+	})
+	assert.Panics(t, func() {
+		NewDecimalFromInt(math.MinInt64).Sub(NewDecimalFromInt(1))
+	})
+
+	// scale overflow is rounded.
+	c := NewDecimal(15, 10).Mul(NewDecimal(1, 9))
+	assert.Equal(t, int32(MaxDecimalScale), c.Scale())
+	assert.Equal(t, "0.000000000000000002", c.String())
+}
+
+func TestDecimal_Div(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		scale int32
+		mode  RoundingMode
+		want  string
+	}{
+		{"10", "3", 2, RoundHalfUp, "3.33"},
+		{"20", "3", 2, RoundHalfUp, "6.67"},
+		{"-20", "3", 2, RoundHalfUp, "-6.67"},
+		{"1", "8", 2, RoundHalfUp, "0.13"},
+		{"1", "8", 2, RoundHalfDown, "0.12"},
+		{"1", "8", 2, RoundHalfEven, "0.12"},
+		{"3", "8", 2, RoundHalfEven, "0.38"},
+		{"20", "3", 2, RoundDown, "6.66"},
+		{"10", "3", 2, RoundUp, "3.34"},
+		{"-10", "3", 2, RoundFloor, "-3.34"},
+		{"-10", "3", 2, RoundCeiling, "-3.33"},
+		{"10", "3", 2, RoundCeiling, "3.34"},
+		{"1.5", "0.05", 0, RoundHalfUp, "30"},
+		{"100", "0.003", 1, RoundHalfUp, "33333.3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			got, err := MustParseDecimal(tt.a).Div(MustParseDecimal(tt.b), tt.scale, tt.mode)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+
+	_, err := NewDecimalFromInt(1).Div(Decimal{}, 2, RoundHalfUp)
+	assert.Equal(t, ErrDecimalDivisionByZero, err)
+	_, err = NewDecimalFromInt(1).Div(NewDecimalFromInt(1), MaxDecimalScale+1, RoundHalfUp)
+	assert.Equal(t, ErrDecimalScale, err)
+	_, err = NewDecimalFromInt(1).Div(NewDecimalFromInt(1), 1, RoundingMode(100))
+	assert.NoError(t, err)
+	_, err = NewDecimalFromInt(1).Div(NewDecimalFromInt(3), 1, RoundingMode(100))
+	assert.Error(t, err)
+}
+
+func TestDecimal_Round(t *testing.T) {
+	d := MustParseDecimal("2.345")
+	assert.Equal(t, "2.35", d.Round(2, RoundHalfUp).String())
+	assert.Equal(t, "2.34", d.Round(2, RoundHalfEven).String())
+	assert.Equal(t, "2.34", d.Round(2, RoundDown).String())
+	assert.Equal(t, "2.34500", d.Round(5, RoundDown).String())
+	assert.Equal(t, "-2.35", d.Neg().Round(2, RoundHalfUp).String())
+	assert.Equal(t, "2.3", d.StringFixed(1))
+	assert.Panics(t, func() {
+		d.Round(-1, RoundHalfUp)
+	})
+}
+
+func TestDecimal_Cmp(t *testing.T) {
+	assert.True(t, MustParseDecimal("1.0").Equal(MustParseDecimal("1.000")))
+	assert.Equal(t, -1, MustParseDecimal("0.99").Cmp(MustParseDecimal("1")))
+	assert.Equal(t, 1, MustParseDecimal("-0.5").Cmp(MustParseDecimal("-0.51")))
+	assert.Equal(t, 0, NewDecimal(5, 1).Cmp(NewDecimal(5, 1)))
+	assert.Equal(t, -1, MustParseDecimal("-2").Sign())
+	assert.Equal(t, 0, Decimal{}.Sign())
+	assert.True(t, Decimal{}.IsZero())
+	assert.Equal(t, "2.5", MustParseDecimal("-2.5").Abs().String())
+	assert.Equal(t, 2.5, MustParseDecimal("2.5").Float64())
+	assert.Equal(t, int64(-25), MustParseDecimal("-2.5").Mantissa())
+	assert.Equal(t, "-9223372036854775808", NewDecimalFromInt(math.MinInt64).String())
+	assert.Equal(t, "-0.05", NewDecimal(-5, 2).String())
+}
+
+func TestDecimal_JSON(t *testing.T) {
+	type order struct {
+		Amount Decimal  `json:"amount"`
+		Fee    *Decimal `json:"fee"`
+	}
+
+	b, err := json.Marshal(order{Amount: MustParseDecimal("12.30")})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"amount":"12.30","fee":null}`, string(b))
+
+	var o order
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount":12.5,"fee":"0.01"}`), &o))
+	assert.Equal(t, "12.5", o.Amount.String())
+	assert.Equal(t, "0.01", o.Fee.String())
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount":null}`), &o))
+	assert.Error(t, json.Unmarshal([]byte(`{"amount":"x"}`), &o))
+
+	text, err := MustParseDecimal("1.5").MarshalText()
+	assert.NoError(t, err)
+	var d Decimal
+	assert.NoError(t, d.UnmarshalText(text))
+	assert.Equal(t, "1.5", d.String())
+	assert.Error(t, d.UnmarshalText([]byte("?")))
+}
+
+func TestDecimal_SQL(t *testing.T) {
+	v, err := MustParseDecimal("3.14").Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "3.14", v)
+
+	var d Decimal
+	assert.NoError(t, d.Scan("1.25"))
+	assert.Equal(t, "1.25", d.String())
+	assert.NoError(t, d.Scan([]byte("2.5")))
+	assert.Equal(t, "2.5", d.String())
+	assert.NoError(t, d.Scan(int64(7)))
+	assert.Equal(t, "7", d.String())
+	assert.NoError(t, d.Scan(0.125))
+	assert.Equal(t, "0.125", d.String())
+	assert.NoError(t, d.Scan(nil))
+	assert.True(t, d.IsZero())
+	assert.Error(t, d.Scan(true))
+	assert.Error(t, d.Scan("bad"))
+}
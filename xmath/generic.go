@@ -0,0 +1,89 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xmath
+
+import (
+	"cmp"
+	"math"
+)
+
+type (
+	// Integer is a constraint that permits any integer type.
+	Integer interface {
+		~int | ~int8 | ~int16 | ~int32 | ~int64 |
+			~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+	}
+	// Float is a constraint that permits any floating-point type.
+	Float interface {
+		~float32 | ~float64
+	}
+	// Number is a constraint that permits any integer or floating-point type.
+	Number interface {
+		Integer | Float
+	}
+)
+
+// Clamp returns v limited to the range [lo, hi].
+// If lo > hi, Clamp panics.
+func Clamp[T cmp.Ordered](v, lo, hi T) T {
+	if lo > hi {
+		panic("lo should not be greater than hi")
+	}
+
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+
+	return v
+}
+
+// Lerp linearly interpolates between a and b by t, t = 0 returns a and t = 1 returns b.
+// t isn't clamped, so values outside [0, 1] extrapolate.
+func Lerp[T Float](a, b, t T) T {
+	return a + (b-a)*t
+}
+
+// InverseLerp returns the t that Lerp(a, b, t) == v.
+// If a == b, InverseLerp returns 0.
+func InverseLerp[T Float](a, b, v T) T {
+	if a == b {
+		return 0
+	}
+
+	return (v - a) / (b - a)
+}
+
+// Remap maps value from the range [inLo, inHi] to the range [outLo, outHi].
+// If inLo == inHi, Remap returns outLo.
+func Remap[T Float](value, inLo, inHi, outLo, outHi T) T {
+	return Lerp(outLo, outHi, InverseLerp(inLo, inHi, value))
+}
+
+// RoundTo rounds v to the nearest multiple of step, ties away from zero.
+// If step <= 0, v is returned.
+// NOTE: the calculation is done in float64, so integers beyond 2^53 may lose precision.
+func RoundTo[T Number](v, step T) T {
+	if step <= 0 {
+		return v
+	}
+
+	s := float64(step)
+	return T(math.Round(float64(v)/s) * s)
+}
@@ -0,0 +1,64 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xmath
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestClamp(t *testing.T) {
+	assert.Equal(t, 5, Clamp(5, 0, 10))
+	assert.Equal(t, 0, Clamp(-5, 0, 10))
+	assert.Equal(t, 10, Clamp(15, 0, 10))
+	assert.Equal(t, 0.5, Clamp(0.5, 0.0, 1.0))
+	assert.Equal(t, "b", Clamp("z", "a", "b"))
+	assert.Equal(t, time.Second, Clamp(time.Minute, time.Millisecond, time.Second))
+	assert.Panics(t, func() {
+		Clamp(1, 10, 0)
+	})
+}
+
+func TestLerp(t *testing.T) {
+	assert.Equal(t, 0.0, Lerp(0.0, 10.0, 0))
+	assert.Equal(t, 10.0, Lerp(0.0, 10.0, 1))
+	assert.Equal(t, 5.0, Lerp(0.0, 10.0, 0.5))
+	assert.Equal(t, 15.0, Lerp(0.0, 10.0, 1.5))
+	assert.Equal(t, float32(2.5), Lerp(float32(0), 10, 0.25))
+
+	assert.Equal(t, 0.25, InverseLerp(0.0, 10.0, 2.5))
+	assert.Equal(t, 0.0, InverseLerp(3.0, 3.0, 4))
+}
+
+func TestRemap(t *testing.T) {
+	assert.Equal(t, 50.0, Remap(0.5, 0, 1, 0, 100))
+	assert.Equal(t, 0.0, Remap(-1.0, -1, 1, 0, 100))
+	assert.Equal(t, 100.0, Remap(20.0, 10, 20, 200, 100))
+	assert.Equal(t, 7.0, Remap(5.0, 5, 5, 7, 9))
+}
+
+func TestRoundTo(t *testing.T) {
+	assert.Equal(t, 10, RoundTo(12, 5))
+	assert.Equal(t, 15, RoundTo(13, 5))
+	assert.Equal(t, -15, RoundTo(-13, 5))
+	assert.Equal(t, uint(100), RoundTo(uint(149), 100))
+	assert.InDelta(t, 0.25, RoundTo(0.3, 0.25), 1e-9)
+	assert.InDelta(t, 1.5, RoundTo(1.4, 0.5), 1e-9)
+	assert.Equal(t, 7, RoundTo(7, 0))
+	assert.Equal(t, 7, RoundTo(7, -2))
+}
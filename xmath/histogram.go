@@ -0,0 +1,369 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xmath
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const defaultBarWidth = 40
+
+var (
+	// ErrHistogramLayout is returned when merging histograms with different bucket layouts.
+	ErrHistogramLayout = errors.New("xmath: histogram bucket layouts differ")
+
+	sparks = []rune("▁▂▃▄▅▆▇█")
+)
+
+type (
+	// A Histogram counts observations into buckets bounded by ascending upper bounds.
+	// Bucket i counts values in (bounds[i-1], bounds[i]], and an implicit last bucket
+	// counts values greater than the largest bound.
+	// A Histogram is safe for concurrent use.
+	Histogram struct {
+		mu     sync.Mutex
+		bounds []float64
+		counts []uint64
+		count  uint64
+		sum    float64
+		min    float64
+		max    float64
+	}
+
+	// Bucket is a snapshot of a Histogram bucket.
+	Bucket struct {
+		// UpperBound is the inclusive upper bound, +Inf for the last bucket.
+		UpperBound float64
+		Count      uint64
+	}
+)
+
+// LinearBuckets returns count bounds, the first is start and each next is width greater.
+func LinearBuckets(start, width float64, count int) []float64 {
+	if count < 1 || width <= 0 {
+		panic("count should be greater than 0 and width should be positive")
+	}
+
+	bounds := make([]float64, count)
+	for i := range bounds {
+		bounds[i] = start + width*float64(i)
+	}
+
+	return bounds
+}
+
+// ExponentialBuckets returns count bounds, the first is start and each next is factor times greater.
+func ExponentialBuckets(start, factor float64, count int) []float64 {
+	if count < 1 || start <= 0 || factor <= 1 {
+		panic("count should be greater than 0, start should be positive and factor should be greater than 1")
+	}
+
+	bounds := make([]float64, count)
+	bounds[0] = start
+	for i := 1; i < count; i++ {
+		bounds[i] = bounds[i-1] * factor
+	}
+
+	return bounds
+}
+
+// NewHistogram returns a Histogram with the given custom bounds.
+// The bounds must be non-empty and strictly ascending, otherwise NewHistogram panics.
+func NewHistogram(bounds []float64) *Histogram {
+	if len(bounds) == 0 {
+		panic("bounds should not be empty")
+	}
+	for i := 1; i < len(bounds); i++ {
+		if bounds[i] <= bounds[i-1] {
+			panic("bounds should be strictly ascending")
+		}
+	}
+
+	h := &Histogram{
+		bounds: append([]float64(nil), bounds...),
+		counts: make([]uint64, len(bounds)+1),
+	}
+	h.resetLocked()
+
+	return h
+}
+
+// Observe adds v into h.
+func (h *Histogram) Observe(v float64) {
+	if math.IsNaN(v) {
+		return
+	}
+
+	i := sort.SearchFloat64s(h.bounds, v)
+
+	h.mu.Lock()
+	h.counts[i]++
+	h.count++
+	h.sum += v
+	if v < h.min {
+		h.min = v
+	}
+	if v > h.max {
+		h.max = v
+	}
+	h.mu.Unlock()
+}
+
+// Count returns the number of observations.
+func (h *Histogram) Count() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.count
+}
+
+// Sum returns the sum of observations.
+func (h *Histogram) Sum() float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.sum
+}
+
+// Mean returns the mean of observations, or NaN if there is no observation.
+func (h *Histogram) Mean() float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.count == 0 {
+		return math.NaN()
+	}
+
+	return h.sum / float64(h.count)
+}
+
+// Min returns the minimum observation, or NaN if there is no observation.
+func (h *Histogram) Min() float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.count == 0 {
+		return math.NaN()
+	}
+
+	return h.min
+}
+
+// Max returns the maximum observation, or NaN if there is no observation.
+func (h *Histogram) Max() float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.count == 0 {
+		return math.NaN()
+	}
+
+	return h.max
+}
+
+// Buckets returns a snapshot of all buckets.
+func (h *Histogram) Buckets() []Bucket {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	buckets := make([]Bucket, len(h.counts))
+	for i, c := range h.counts {
+		buckets[i] = Bucket{UpperBound: h.upperBound(i), Count: c}
+	}
+
+	return buckets
+}
+
+// Quantile estimates the q-quantile (0 <= q <= 1) of observations
+// by linear interpolation inside the bucket containing it.
+// It returns NaN if there is no observation or q is out of range.
+func (h *Histogram) Quantile(q float64) float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.count == 0 || q < 0 || q > 1 || math.IsNaN(q) {
+		return math.NaN()
+	}
+
+	rank := q * float64(h.count)
+	var cumulative float64
+	for i, c := range h.counts {
+		if c == 0 {
+			continue
+		}
+
+		next := cumulative + float64(c)
+		if rank <= next {
+			lower := h.min
+			if i > 0 {
+				lower = math.Max(h.bounds[i-1], h.min)
+			}
+			upper := math.Min(h.upperBound(i), h.max)
+
+			return lower + (upper-lower)*(rank-cumulative)/float64(c)
+		}
+		cumulative = next
+	}
+
+	return h.max
+}
+
+// Merge adds all observations of other into h.
+// It returns ErrHistogramLayout if the bucket layouts differ.
+func (h *Histogram) Merge(other *Histogram) error {
+	if h == other {
+		return errors.New("xmath: can't merge a histogram into itself")
+	}
+
+	other.mu.Lock()
+	bounds, counts := other.bounds, append([]uint64(nil), other.counts...)
+	count, sum, lo, hi := other.count, other.sum, other.min, other.max
+	other.mu.Unlock()
+
+	if len(bounds) != len(h.bounds) {
+		return ErrHistogramLayout
+	}
+	for i := range bounds {
+		if bounds[i] != h.bounds[i] {
+			return ErrHistogramLayout
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i, c := range counts {
+		h.counts[i] += c
+	}
+	h.count += count
+	h.sum += sum
+	h.min = math.Min(h.min, lo)
+	h.max = math.Max(h.max, hi)
+
+	return nil
+}
+
+// Reset clears all observations.
+func (h *Histogram) Reset() {
+	h.mu.Lock()
+	h.resetLocked()
+	h.mu.Unlock()
+}
+
+func (h *Histogram) resetLocked() {
+	for i := range h.counts {
+		h.counts[i] = 0
+	}
+	h.count = 0
+	h.sum = 0
+	h.min = math.Inf(1)
+	h.max = math.Inf(-1)
+}
+
+func (h *Histogram) upperBound(i int) float64 {
+	if i == len(h.bounds) {
+		return math.Inf(1)
+	}
+
+	return h.bounds[i]
+}
+
+// Sparkline renders bucket counts as a single line of block characters, e.g. "▁▃█▅▁".
+func (h *Histogram) Sparkline() string {
+	buckets := h.Buckets()
+
+	var peak uint64
+	for _, b := range buckets {
+		if b.Count > peak {
+			peak = b.Count
+		}
+	}
+
+	sb := strings.Builder{}
+	for _, b := range buckets {
+		i := 0
+		if peak > 0 {
+			i = int(float64(b.Count) / float64(peak) * float64(len(sparks)-1))
+		}
+		sb.WriteRune(sparks[i])
+	}
+
+	return sb.String()
+}
+
+// Render writes one ASCII bar per bucket into w, the longest bar is width characters.
+func (h *Histogram) Render(w io.Writer, width int) error {
+	if width <= 0 {
+		width = defaultBarWidth
+	}
+
+	buckets := h.Buckets()
+	labels := make([]string, len(buckets))
+	var (
+		peak     uint64
+		labelLen int
+	)
+	for i, b := range buckets {
+		lower := "-inf"
+		if i > 0 {
+			lower = formatBound(buckets[i-1].UpperBound)
+		}
+		labels[i] = "(" + lower + ", " + formatBound(b.UpperBound) + "]"
+		if len(labels[i]) > labelLen {
+			labelLen = len(labels[i])
+		}
+		if b.Count > peak {
+			peak = b.Count
+		}
+	}
+
+	for i, b := range buckets {
+		n := 0
+		if peak > 0 {
+			n = int(float64(b.Count) / float64(peak) * float64(width))
+		}
+		_, err := fmt.Fprintf(w, "%-*s |%-*s %d\n", labelLen, labels[i], width, strings.Repeat("#", n), b.Count)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// String returns the ASCII rendering of h.
+func (h *Histogram) String() string {
+	sb := &strings.Builder{}
+	_ = h.Render(sb, defaultBarWidth)
+
+	return sb.String()
+}
+
+func formatBound(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+inf"
+	}
+
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,138 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xmath
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestBuckets(t *testing.T) {
+	assert.Equal(t, []float64{0, 10, 20}, LinearBuckets(0, 10, 3))
+	assert.Equal(t, []float64{1, 2, 4, 8}, ExponentialBuckets(1, 2, 4))
+	assert.Panics(t, func() {
+		LinearBuckets(0, 0, 3)
+	})
+	assert.Panics(t, func() {
+		ExponentialBuckets(1, 1, 3)
+	})
+	assert.Panics(t, func() {
+		NewHistogram(nil)
+	})
+	assert.Panics(t, func() {
+		NewHistogram([]float64{1, 1})
+	})
+}
+
+func TestHistogram_Observe(t *testing.T) {
+	h := NewHistogram([]float64{1, 2, 5})
+	assert.True(t, math.IsNaN(h.Mean()))
+	assert.True(t, math.IsNaN(h.Min()))
+	assert.True(t, math.IsNaN(h.Max()))
+	assert.True(t, math.IsNaN(h.Quantile(0.5)))
+
+	for _, v := range []float64{0.5, 1, 1.5, 3, 4, 10, math.NaN()} {
+		h.Observe(v)
+	}
+	assert.Equal(t, uint64(6), h.Count())
+	assert.Equal(t, 20.0, h.Sum())
+	assert.InDelta(t, 20.0/6, h.Mean(), 1e-9)
+	assert.Equal(t, 0.5, h.Min())
+	assert.Equal(t, 10.0, h.Max())
+	assert.Equal(t, []Bucket{
+		{UpperBound: 1, Count: 2},
+		{UpperBound: 2, Count: 1},
+		{UpperBound: 5, Count: 2},
+		{UpperBound: math.Inf(1), Count: 1},
+	}, h.Buckets())
+
+	h.Reset()
+	assert.Equal(t, uint64(0), h.Count())
+}
+
+func TestHistogram_Quantile(t *testing.T) {
+	h := NewHistogram(LinearBuckets(10, 10, 10))
+	for i := 1; i <= 100; i++ {
+		h.Observe(float64(i))
+	}
+
+	assert.InDelta(t, 50, h.Quantile(0.5), 1)
+	assert.InDelta(t, 90, h.Quantile(0.9), 1)
+	assert.InDelta(t, 99, h.Quantile(0.99), 1)
+	assert.Equal(t, 1.0, h.Quantile(0))
+	assert.Equal(t, 100.0, h.Quantile(1))
+	assert.True(t, math.IsNaN(h.Quantile(1.1)))
+	assert.True(t, math.IsNaN(h.Quantile(-0.1)))
+}
+
+func TestHistogram_Merge(t *testing.T) {
+	a := NewHistogram([]float64{1, 2})
+	b := NewHistogram([]float64{1, 2})
+	a.Observe(0.5)
+	b.Observe(1.5)
+	b.Observe(5)
+
+	assert.NoError(t, a.Merge(b))
+	assert.Equal(t, uint64(3), a.Count())
+	assert.Equal(t, 5.0, a.Max())
+	assert.Equal(t, 0.5, a.Min())
+	assert.Equal(t, ErrHistogramLayout, a.Merge(NewHistogram([]float64{1, 3})))
+	assert.Equal(t, ErrHistogramLayout, a.Merge(NewHistogram([]float64{1})))
+	assert.Error(t, a.Merge(a))
+}
+
+func TestHistogram_Concurrent(t *testing.T) {
+	h := NewHistogram(LinearBuckets(0, 1, 10))
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				h.Observe(float64(j % 10))
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, uint64(1000), h.Count())
+}
+
+func TestHistogram_Render(t *testing.T) {
+	h := NewHistogram([]float64{1, 2, 3})
+	assert.Equal(t, "▁▁▁▁", h.Sparkline())
+
+	h.Observe(0)
+	h.Observe(1.5)
+	h.Observe(1.5)
+	h.Observe(2.5)
+	h.Observe(2.5)
+	h.Observe(2.5)
+	h.Observe(2.5)
+	assert.Equal(t, "▂▄█▁", h.Sparkline())
+
+	sb := &strings.Builder{}
+	assert.NoError(t, h.Render(sb, 4))
+	assert.Equal(t, ""+
+		"(-inf, 1] |#    1\n"+
+		"(1, 2]    |##   2\n"+
+		"(2, 3]    |#### 4\n"+
+		"(3, +inf] |     0\n", sb.String())
+	assert.Equal(t, 4, strings.Count(h.String(), "\n"))
+}
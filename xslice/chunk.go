@@ -0,0 +1,82 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xslice
+
+import "iter"
+
+// Chunk splits s into consecutive sub-slices of size elements, the last one holding
+// the remainder. The chunks share the memory of s but have their capacity clipped,
+// so appending to one never overwrites the next. It panics if size < 1.
+func Chunk[T any](s []T, size int) [][]T {
+	if size < 1 {
+		panic("xslice: chunk size must be positive")
+	}
+
+	chunks := make([][]T, 0, (len(s)+size-1)/size)
+	for c := range ChunkSeq(s, size) {
+		chunks = append(chunks, c)
+	}
+
+	return chunks
+}
+
+// ChunkSeq is like Chunk, but yields the chunks one at a time instead of materializing them.
+func ChunkSeq[T any](s []T, size int) iter.Seq[[]T] {
+	if size < 1 {
+		panic("xslice: chunk size must be positive")
+	}
+
+	return func(yield func([]T) bool) {
+		for i := 0; i < len(s); i += size {
+			end := min(i+size, len(s))
+			if !yield(s[i:end:end]) {
+				return
+			}
+		}
+	}
+}
+
+// Windows returns the sliding windows of size elements over s, the first starting
+// at index 0 and each following one step elements later. Only full windows are
+// returned, so there are none if s is shorter than size. The windows share the memory
+// of s, with their capacity clipped. It panics if size or step is less than 1.
+func Windows[T any](s []T, size, step int) [][]T {
+	var windows [][]T
+	for w := range WindowSeq(s, size, step) {
+		windows = append(windows, w)
+	}
+
+	return windows
+}
+
+// WindowSeq is like Windows, but yields the windows one at a time instead of materializing them.
+func WindowSeq[T any](s []T, size, step int) iter.Seq[[]T] {
+	if size < 1 {
+		panic("xslice: window size must be positive")
+	}
+	if step < 1 {
+		panic("xslice: window step must be positive")
+	}
+
+	return func(yield func([]T) bool) {
+		for i := 0; i+size <= len(s); i += step {
+			if !yield(s[i : i+size : i+size]) {
+				return
+			}
+		}
+	}
+}
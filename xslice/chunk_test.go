@@ -0,0 +1,73 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xslice
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestChunk(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5, 6, 7}
+	assert.Equal(t, [][]int{{1, 2, 3}, {4, 5, 6}, {7}}, Chunk(s, 3))
+	assert.Equal(t, [][]int{{1, 2, 3, 4, 5, 6, 7}}, Chunk(s, 10))
+	assert.Equal(t, [][]int{{1}, {2}, {3}, {4}, {5}, {6}, {7}}, Chunk(s, 1))
+	assert.Equal(t, [][]int{}, Chunk([]int{}, 3))
+	assert.Panics(t, func() { Chunk(s, 0) })
+
+	chunks := Chunk(s, 3)
+	chunks[0] = append(chunks[0], 100)
+	assert.Equal(t, 4, s[3], "appending to a chunk must not overwrite the next one")
+}
+
+func TestChunkSeq(t *testing.T) {
+	var got [][]int
+	for c := range ChunkSeq([]int{1, 2, 3, 4, 5}, 2) {
+		got = append(got, c)
+		if len(got) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}}, got)
+	assert.Panics(t, func() { ChunkSeq([]int{1}, -1) })
+}
+
+func TestWindows(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5}
+	assert.Equal(t, [][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}, Windows(s, 3, 1))
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}}, Windows(s, 2, 2))
+	assert.Equal(t, [][]int{{1, 2}, {4, 5}}, Windows(s, 2, 3))
+	assert.Equal(t, [][]int{{1, 2, 3, 4, 5}}, Windows(s, 5, 1))
+	assert.Nil(t, Windows(s, 6, 1))
+	assert.Panics(t, func() { Windows(s, 0, 1) })
+	assert.Panics(t, func() { Windows(s, 1, 0) })
+
+	w := Windows(s, 2, 1)
+	w[0] = append(w[0], 100)
+	assert.Equal(t, 3, s[2])
+}
+
+func TestWindowSeq(t *testing.T) {
+	var sums []int
+	for w := range WindowSeq([]int{1, 2, 3, 4, 5}, 2, 1) {
+		sums = append(sums, w[0]+w[1])
+		if len(sums) == 3 {
+			break
+		}
+	}
+	assert.Equal(t, []int{3, 5, 7}, sums)
+}
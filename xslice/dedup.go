@@ -0,0 +1,62 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xslice
+
+// Dedup returns a new slice with the elements of s without duplicates,
+// keeping the first occurrence of each in order.
+func Dedup[T comparable](s []T) []T {
+	return DedupBy(s, identity[T])
+}
+
+// DedupBy is like Dedup, but considers two elements duplicates if key returns the same value for them.
+func DedupBy[T any, K comparable](s []T, key func(T) K) []T {
+	if s == nil {
+		return nil
+	}
+
+	return dedupInto(make([]T, 0, len(s)), s, key)
+}
+
+// DedupInPlace is like Dedup, but reuses the memory of s instead of allocating a
+// new slice: s is modified, and the elements past the returned length are zeroed
+// so that they can be garbage collected.
+func DedupInPlace[T comparable](s []T) []T {
+	return DedupByInPlace(s, identity[T])
+}
+
+// DedupByInPlace is like DedupBy, but reuses the memory of s, see DedupInPlace.
+func DedupByInPlace[T any, K comparable](s []T, key func(T) K) []T {
+	out := dedupInto(s[:0], s, key)
+	clear(s[len(out):])
+
+	return out
+}
+
+// dedupInto appends the first occurrence of every key in s to out, where out may
+// share the memory of s as it never grows faster than s is read.
+func dedupInto[T any, K comparable](out, s []T, key func(T) K) []T {
+	seen := make(map[K]struct{}, len(s))
+	for _, v := range s {
+		k := key(v)
+		if _, ok := seen[k]; !ok {
+			seen[k] = struct{}{}
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,76 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xslice
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestDedup(t *testing.T) {
+	s := []int{3, 1, 3, 2, 1, 3}
+	assert.Equal(t, []int{3, 1, 2}, Dedup(s))
+	assert.Equal(t, []int{3, 1, 3, 2, 1, 3}, s, "Dedup must not modify its argument")
+	assert.Equal(t, []int{}, Dedup([]int{}))
+	assert.Nil(t, Dedup([]int(nil)))
+}
+
+func TestDedupBy(t *testing.T) {
+	s := []string{"Go", "rust", "GO", "Rust", "zig"}
+	assert.Equal(t, []string{"Go", "rust", "zig"}, DedupBy(s, strings.ToLower))
+
+	users := []user{{ID: 1, Tags: []string{"a"}}, {ID: 2}, {ID: 1, Tags: []string{"b"}}}
+	assert.Equal(t, []user{{ID: 1, Tags: []string{"a"}}, {ID: 2}}, DedupBy(users, userID))
+}
+
+func TestDedupInPlace(t *testing.T) {
+	s := []int{3, 1, 3, 2, 1, 3}
+	got := DedupInPlace(s)
+	assert.Equal(t, []int{3, 1, 2}, got)
+	assert.Equal(t, &s[0], &got[0], "DedupInPlace must reuse the memory of its argument")
+	assert.Equal(t, []int{3, 1, 2, 0, 0, 0}, s)
+
+	ptrs := []*int{new(int), nil, nil}
+	ptrs[2] = ptrs[0]
+	got2 := DedupByInPlace(ptrs, func(p *int) *int { return p })
+	assert.Len(t, got2, 2)
+	assert.Nil(t, ptrs[2])
+
+	assert.Nil(t, DedupInPlace([]int(nil)))
+}
+
+func BenchmarkDedup(b *testing.B) {
+	s := make([]int, 1000)
+	for i := range s {
+		s[i] = i % 100
+	}
+	b.Run("copy", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			Dedup(s)
+		}
+	})
+	b.Run("in-place", func(b *testing.B) {
+		buf := make([]int, len(s))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			copy(buf, s)
+			DedupInPlace(buf)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xslice
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDuplicateKey is returned by ToMapStrict when two elements have the same key.
+var ErrDuplicateKey = errors.New("xslice: duplicate key")
+
+// GroupBy groups the elements of s by the key returned by key,
+// keeping their order within every group.
+func GroupBy[T any, K comparable](s []T, key func(T) K) map[K][]T {
+	groups := make(map[K][]T)
+	for _, v := range s {
+		k := key(v)
+		groups[k] = append(groups[k], v)
+	}
+
+	return groups
+}
+
+// ToMap returns a map of the elements of s by the key returned by key.
+// When several elements have the same key the last one wins; see ToMapMerge
+// and ToMapStrict for other strategies.
+func ToMap[T any, K comparable](s []T, key func(T) K) map[K]T {
+	m := make(map[K]T, len(s))
+	for _, v := range s {
+		m[key(v)] = v
+	}
+
+	return m
+}
+
+// ToMapMerge is like ToMap, but resolves duplicate keys by storing merge(existing, v);
+// returning existing keeps the first element.
+func ToMapMerge[T any, K comparable](s []T, key func(T) K, merge func(existing, v T) T) map[K]T {
+	m := make(map[K]T, len(s))
+	for _, v := range s {
+		k := key(v)
+		if existing, ok := m[k]; ok {
+			v = merge(existing, v)
+		}
+		m[k] = v
+	}
+
+	return m
+}
+
+// ToMapStrict is like ToMap, but fails with ErrDuplicateKey if several elements have the same key.
+func ToMapStrict[T any, K comparable](s []T, key func(T) K) (map[K]T, error) {
+	m := make(map[K]T, len(s))
+	for _, v := range s {
+		k := key(v)
+		if _, ok := m[k]; ok {
+			return nil, fmt.Errorf("%w: %v", ErrDuplicateKey, k)
+		}
+		m[k] = v
+	}
+
+	return m, nil
+}
+
+// Partition splits s into the elements that satisfy fn and the rest, keeping their order.
+func Partition[T any](s []T, fn func(T) bool) (matched, rest []T) {
+	for _, v := range s {
+		if fn(v) {
+			matched = append(matched, v)
+		} else {
+			rest = append(rest, v)
+		}
+	}
+
+	return matched, rest
+}
@@ -0,0 +1,64 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xslice
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGroupBy(t *testing.T) {
+	groups := GroupBy([]string{"apple", "avocado", "banana", "blueberry", "cherry"}, func(s string) byte { return s[0] })
+	assert.Equal(t, map[byte][]string{
+		'a': {"apple", "avocado"},
+		'b': {"banana", "blueberry"},
+		'c': {"cherry"},
+	}, groups)
+	assert.Empty(t, GroupBy(nil, userID))
+}
+
+func TestToMap(t *testing.T) {
+	users := []user{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}, {ID: 1, Name: "c"}}
+	assert.Equal(t, map[int]user{1: {ID: 1, Name: "c"}, 2: {ID: 2, Name: "b"}}, ToMap(users, userID))
+
+	first := ToMapMerge(users, userID, func(existing, _ user) user { return existing })
+	assert.Equal(t, map[int]user{1: {ID: 1, Name: "a"}, 2: {ID: 2, Name: "b"}}, first)
+
+	merged := ToMapMerge(users, userID, func(existing, v user) user {
+		existing.Name += v.Name
+		return existing
+	})
+	assert.Equal(t, "ac", merged[1].Name)
+
+	_, err := ToMapStrict(users, userID)
+	assert.ErrorIs(t, err, ErrDuplicateKey)
+	assert.Contains(t, err.Error(), "1")
+
+	m, err := ToMapStrict(users[:2], userID)
+	assert.NoError(t, err)
+	assert.Len(t, m, 2)
+}
+
+func TestPartition(t *testing.T) {
+	even, odd := Partition([]int{1, 2, 3, 4, 5}, isEven)
+	assert.Equal(t, []int{2, 4}, even)
+	assert.Equal(t, []int{1, 3, 5}, odd)
+
+	even, odd = Partition(nil, isEven)
+	assert.Nil(t, even)
+	assert.Nil(t, odd)
+}
@@ -0,0 +1,63 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xslice
+
+import "iter"
+
+// Paginate returns the items of the 1-based page of s that holds perPage items per page.
+// A page < 1 is treated as the first page, and nil is returned for a page past the
+// last one or a perPage < 1. The page shares the memory of s, with its capacity clipped.
+func Paginate[T any](s []T, page, perPage int) []T {
+	if perPage < 1 {
+		return nil
+	}
+	page = max(page, 1)
+	if page > TotalPages(len(s), perPage) {
+		return nil
+	}
+
+	start := (page - 1) * perPage
+	end := min(start+perPage, len(s))
+
+	return s[start:end:end]
+}
+
+// Pages yields the 1-based number and the items of every page of s that holds perPage
+// items per page. Nothing is yielded for an empty s or a perPage < 1.
+func Pages[T any](s []T, perPage int) iter.Seq2[int, []T] {
+	return func(yield func(int, []T) bool) {
+		if perPage < 1 {
+			return
+		}
+		for page, start := 1, 0; start < len(s); page, start = page+1, start+perPage {
+			end := min(start+perPage, len(s))
+			if !yield(page, s[start:end:end]) {
+				return
+			}
+		}
+	}
+}
+
+// TotalPages returns the number of pages needed for total items with perPage items per page.
+// It returns 0 if total or perPage is less than 1.
+func TotalPages(total, perPage int) int {
+	if total < 1 || perPage < 1 {
+		return 0
+	}
+
+	return (total-1)/perPage + 1
+}
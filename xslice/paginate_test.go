@@ -0,0 +1,75 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xslice
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5, 6, 7}
+	assert.Equal(t, []int{1, 2, 3}, Paginate(s, 1, 3))
+	assert.Equal(t, []int{4, 5, 6}, Paginate(s, 2, 3))
+	assert.Equal(t, []int{7}, Paginate(s, 3, 3))
+	assert.Nil(t, Paginate(s, 4, 3))
+	assert.Equal(t, []int{1, 2, 3}, Paginate(s, 0, 3))
+	assert.Equal(t, []int{1, 2, 3}, Paginate(s, -5, 3))
+	assert.Nil(t, Paginate(s, 1, 0))
+	assert.Equal(t, s, Paginate(s, 1, 100))
+	assert.Nil(t, Paginate([]int(nil), 1, 10))
+	assert.Nil(t, Paginate(s, math.MaxInt, 3))
+
+	page := Paginate(s, 1, 3)
+	_ = append(page, 100)
+	assert.Equal(t, 4, s[3])
+}
+
+func TestPages(t *testing.T) {
+	var numbers []int
+	var pages [][]int
+	for n, p := range Pages([]int{1, 2, 3, 4, 5}, 2) {
+		numbers = append(numbers, n)
+		pages = append(pages, p)
+	}
+	assert.Equal(t, []int{1, 2, 3}, numbers)
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, pages)
+
+	for range Pages([]int{1, 2, 3}, 0) {
+		t.Fatal("no page expected")
+	}
+	for range Pages([]int(nil), 10) {
+		t.Fatal("no page expected")
+	}
+	for n := range Pages([]int{1, 2, 3}, 1) {
+		if n == 2 {
+			break
+		}
+		assert.Equal(t, 1, n)
+	}
+}
+
+func TestTotalPages(t *testing.T) {
+	assert.Equal(t, 0, TotalPages(0, 10))
+	assert.Equal(t, 1, TotalPages(1, 10))
+	assert.Equal(t, 1, TotalPages(10, 10))
+	assert.Equal(t, 2, TotalPages(11, 10))
+	assert.Equal(t, 0, TotalPages(11, 0))
+	assert.Equal(t, 0, TotalPages(-1, 10))
+	assert.Equal(t, math.MaxInt, TotalPages(math.MaxInt, 1))
+}
@@ -0,0 +1,75 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xslice
+
+import (
+	"context"
+	"github.com/chenquan/go-pkg/xerror"
+	"sync"
+	"sync/atomic"
+)
+
+// ParallelMap calls fn for every element of items, running at most concurrency calls at
+// a time, and returns the results in the order of items.
+//
+// Every element is processed even if some calls fail; the errors are aggregated in the
+// order of items into the returned error, which works with errors.Is and errors.As.
+// Once ctx is done no further calls are started, and ctx.Err() is added to the error.
+// The results of failed or skipped elements are zero values; the others are returned
+// even if the error is not nil. It panics if concurrency < 1.
+func ParallelMap[T, R any](ctx context.Context, items []T, concurrency int,
+	fn func(ctx context.Context, item T) (R, error)) ([]R, error) {
+	if concurrency < 1 {
+		panic("xslice: concurrency must be positive")
+	}
+
+	results := make([]R, len(items))
+	errs := make([]error, len(items))
+	var (
+		next    atomic.Int64
+		skipped atomic.Bool
+		wg      sync.WaitGroup
+	)
+	for w := min(concurrency, len(items)); w > 0; w-- {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= len(items) {
+					return
+				}
+				if ctx.Err() != nil {
+					skipped.Store(true)
+					return
+				}
+				results[i], errs[i] = fn(ctx, items[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	var be xerror.BatchError
+	for _, err := range errs {
+		be.Add(err)
+	}
+	if skipped.Load() {
+		be.Add(ctx.Err())
+	}
+
+	return results, be.Err()
+}
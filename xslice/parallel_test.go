@@ -0,0 +1,102 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xslice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParallelMap(t *testing.T) {
+	items := make([]int, 100)
+	for i := range items {
+		items[i] = i
+	}
+
+	var running, peak atomic.Int32
+	results, err := ParallelMap(context.Background(), items, 4, func(ctx context.Context, v int) (string, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		return fmt.Sprint(v * 2), nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, results, 100)
+	for i, r := range results {
+		assert.Equal(t, fmt.Sprint(i*2), r)
+	}
+	assert.LessOrEqual(t, peak.Load(), int32(4))
+
+	empty, err := ParallelMap(context.Background(), []int(nil), 4, func(ctx context.Context, v int) (int, error) {
+		return v, nil
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, empty)
+
+	assert.Panics(t, func() {
+		_, _ = ParallelMap(context.Background(), items, 0, func(ctx context.Context, v int) (int, error) { return v, nil })
+	})
+}
+
+func TestParallelMapErrors(t *testing.T) {
+	errOdd := errors.New("odd")
+	var calls atomic.Int32
+	results, err := ParallelMap(context.Background(), []int{1, 2, 3, 4}, 2, func(ctx context.Context, v int) (int, error) {
+		calls.Add(1)
+		if v%2 == 1 {
+			return 0, fmt.Errorf("item %d: %w", v, errOdd)
+		}
+		return v * 10, nil
+	})
+	assert.Equal(t, int32(4), calls.Load(), "a failure must not stop the other items")
+	assert.Equal(t, []int{0, 20, 0, 40}, results)
+	assert.ErrorIs(t, err, errOdd)
+	assert.Equal(t, "item 1: odd\nitem 3: odd", err.Error())
+}
+
+func TestParallelMapCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls atomic.Int32
+	results, err := ParallelMap(ctx, make([]int, 50), 2, func(ctx context.Context, v int) (int, error) {
+		if calls.Add(1) == 3 {
+			cancel()
+		}
+		return 1, nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, calls.Load(), int32(50))
+	assert.Len(t, results, 50)
+
+	done, cancel2 := context.WithCancel(context.Background())
+	cancel2()
+	_, err = ParallelMap(done, []int{1}, 1, func(ctx context.Context, v int) (int, error) {
+		t.Fatal("must not be called")
+		return 0, nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+}
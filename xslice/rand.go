@@ -0,0 +1,93 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xslice
+
+import "math/rand"
+
+type (
+	// RandOption customizes the random source of Shuffle, Sample and SampleN.
+	RandOption func(o *randOptions)
+
+	randOptions struct {
+		intn func(n int) int
+	}
+)
+
+// WithRand makes the random functions draw from r instead of the global math/rand
+// source, e.g. a seeded one for reproducible results. Like r itself, the call is
+// then not safe for concurrent use.
+func WithRand(r *rand.Rand) RandOption {
+	return func(o *randOptions) {
+		o.intn = r.Intn
+	}
+}
+
+func loadRandOptions(opts []RandOption) randOptions {
+	o := randOptions{intn: rand.Intn}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// Shuffle randomizes the order of the elements of s in place, with the Fisher-Yates shuffle.
+func Shuffle[T any](s []T, opts ...RandOption) {
+	o := loadRandOptions(opts)
+	for i := len(s) - 1; i > 0; i-- {
+		j := o.intn(i + 1)
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+// Sample returns a random element of s, and false if s is empty.
+func Sample[T any](s []T, opts ...RandOption) (T, bool) {
+	if len(s) == 0 {
+		var zero T
+		return zero, false
+	}
+
+	o := loadRandOptions(opts)
+	return s[o.intn(len(s))], true
+}
+
+// SampleN returns n distinct random elements of s (by position, without replacement)
+// in random order, using reservoir sampling. All of s is returned, shuffled, if n >= len(s).
+// s is not modified.
+func SampleN[T any](s []T, n int, opts ...RandOption) []T {
+	if n <= 0 {
+		return nil
+	}
+
+	o := loadRandOptions(opts)
+	n = min(n, len(s))
+	reservoir := make([]T, n)
+	copy(reservoir, s[:n])
+	for i := n; i < len(s); i++ {
+		if j := o.intn(i + 1); j < n {
+			reservoir[j] = s[i]
+		}
+	}
+	// The reservoir keeps the first elements in their original order; shuffle it
+	// so that the order of the result is random too.
+	for i := n - 1; i > 0; i-- {
+		j := o.intn(i + 1)
+		reservoir[i], reservoir[j] = reservoir[j], reservoir[i]
+	}
+
+	return reservoir
+}
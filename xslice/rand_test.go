@@ -0,0 +1,96 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xslice
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestShuffle(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	Shuffle(s)
+	sorted := append([]int(nil), s...)
+	sort.Ints(sorted)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, sorted)
+
+	a := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	b := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	Shuffle(a, WithRand(rand.New(rand.NewSource(42))))
+	Shuffle(b, WithRand(rand.New(rand.NewSource(42))))
+	assert.Equal(t, a, b)
+
+	Shuffle([]int(nil))
+}
+
+func TestShuffleUniform(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	counts := make(map[[3]int]int)
+	for i := 0; i < 60000; i++ {
+		s := []int{1, 2, 3}
+		Shuffle(s, WithRand(r))
+		counts[[3]int{s[0], s[1], s[2]}]++
+	}
+	assert.Len(t, counts, 6)
+	for perm, c := range counts {
+		assert.InDelta(t, 10000, c, 500, "%v", perm)
+	}
+}
+
+func TestSample(t *testing.T) {
+	_, ok := Sample([]int{})
+	assert.False(t, ok)
+
+	s := []string{"a", "b", "c"}
+	seen := make(map[string]bool)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		v, ok := Sample(s, WithRand(r))
+		assert.True(t, ok)
+		seen[v] = true
+	}
+	assert.Len(t, seen, 3)
+}
+
+func TestSampleN(t *testing.T) {
+	s := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	assert.Nil(t, SampleN(s, 0))
+	assert.Nil(t, SampleN(s, -1))
+
+	got := SampleN(s, 4)
+	assert.Len(t, got, 4)
+	assert.Len(t, Dedup(got), 4)
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, s)
+
+	all := SampleN(s, 20)
+	sort.Ints(all)
+	assert.Equal(t, s, all)
+
+	// Every element is picked with the same probability.
+	r := rand.New(rand.NewSource(1))
+	counts := make([]int, len(s))
+	for i := 0; i < 20000; i++ {
+		for _, v := range SampleN(s, 3, WithRand(r)) {
+			counts[v]++
+		}
+	}
+	for v, c := range counts {
+		assert.InDelta(t, 6000, c, 300, "%d", v)
+	}
+}
@@ -0,0 +1,114 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xslice
+
+// The set operations treat slices as sets: the results contain every element at most
+// once, in the order of its first occurrence, elements of a before elements of b.
+
+// Intersect returns the elements of a that are also in b.
+func Intersect[T comparable](a, b []T) []T {
+	return IntersectFunc(a, b, identity[T])
+}
+
+// IntersectFunc is like Intersect, but compares elements by the key returned by key.
+func IntersectFunc[T any, K comparable](a, b []T, key func(T) K) []T {
+	inB := keySet(b, key)
+	seen := make(map[K]struct{}, min(len(a), len(inB)))
+	var out []T
+	for _, v := range a {
+		k := key(v)
+		if _, ok := inB[k]; !ok {
+			continue
+		}
+		if _, ok := seen[k]; !ok {
+			seen[k] = struct{}{}
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
+
+// Union returns the elements that are in a or b.
+func Union[T comparable](a, b []T) []T {
+	return UnionFunc(a, b, identity[T])
+}
+
+// UnionFunc is like Union, but compares elements by the key returned by key.
+func UnionFunc[T any, K comparable](a, b []T, key func(T) K) []T {
+	seen := make(map[K]struct{}, len(a)+len(b))
+	var out []T
+	for _, s := range [2][]T{a, b} {
+		for _, v := range s {
+			k := key(v)
+			if _, ok := seen[k]; !ok {
+				seen[k] = struct{}{}
+				out = append(out, v)
+			}
+		}
+	}
+
+	return out
+}
+
+// Difference returns the elements of a that are not in b.
+func Difference[T comparable](a, b []T) []T {
+	return DifferenceFunc(a, b, identity[T])
+}
+
+// DifferenceFunc is like Difference, but compares elements by the key returned by key.
+func DifferenceFunc[T any, K comparable](a, b []T, key func(T) K) []T {
+	return appendDifference(nil, a, keySet(b, key), key)
+}
+
+// SymmetricDifference returns the elements that are in exactly one of a and b.
+func SymmetricDifference[T comparable](a, b []T) []T {
+	return SymmetricDifferenceFunc(a, b, identity[T])
+}
+
+// SymmetricDifferenceFunc is like SymmetricDifference, but compares elements by the key returned by key.
+func SymmetricDifferenceFunc[T any, K comparable](a, b []T, key func(T) K) []T {
+	out := appendDifference(nil, a, keySet(b, key), key)
+	return appendDifference(out, b, keySet(a, key), key)
+}
+
+// appendDifference appends the elements of s whose key is not in exclude, once each.
+func appendDifference[T any, K comparable](out, s []T, exclude map[K]struct{}, key func(T) K) []T {
+	for _, v := range s {
+		k := key(v)
+		if _, ok := exclude[k]; !ok {
+			// Adding the key to exclude drops later duplicates.
+			exclude[k] = struct{}{}
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
+
+func keySet[T any, K comparable](s []T, key func(T) K) map[K]struct{} {
+	set := make(map[K]struct{}, len(s))
+	for _, v := range s {
+		set[key(v)] = struct{}{}
+	}
+
+	return set
+}
+
+func identity[T any](v T) T {
+	return v
+}
@@ -0,0 +1,57 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xslice
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type user struct {
+	ID   int
+	Name string
+	Tags []string
+}
+
+func userID(u user) int { return u.ID }
+
+func TestSetOps(t *testing.T) {
+	a := []int{5, 1, 3, 1, 2, 5}
+	b := []int{4, 2, 2, 6, 5}
+
+	assert.Equal(t, []int{5, 2}, Intersect(a, b))
+	assert.Equal(t, []int{5, 1, 3, 2, 4, 6}, Union(a, b))
+	assert.Equal(t, []int{1, 3}, Difference(a, b))
+	assert.Equal(t, []int{4, 6}, Difference(b, a))
+	assert.Equal(t, []int{1, 3, 4, 6}, SymmetricDifference(a, b))
+
+	assert.Nil(t, Intersect(a, nil))
+	assert.Equal(t, []int{5, 1, 3, 2}, Union(a, nil))
+	assert.Equal(t, []int{5, 1, 3, 2}, Difference(a, nil))
+	assert.Nil(t, Difference(nil, b))
+	assert.Nil(t, SymmetricDifference([]int(nil), nil))
+}
+
+func TestSetOpsFunc(t *testing.T) {
+	a := []user{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}, {ID: 1, Name: "a2"}}
+	b := []user{{ID: 2, Name: "B"}, {ID: 3, Name: "C", Tags: []string{"x"}}}
+
+	assert.Equal(t, []user{{ID: 2, Name: "b"}}, IntersectFunc(a, b, userID))
+	assert.Equal(t, []user{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}, {ID: 3, Name: "C", Tags: []string{"x"}}}, UnionFunc(a, b, userID))
+	assert.Equal(t, []user{{ID: 1, Name: "a"}}, DifferenceFunc(a, b, userID))
+	assert.Equal(t, []user{{ID: 1, Name: "a"}, {ID: 3, Name: "C", Tags: []string{"x"}}}, SymmetricDifferenceFunc(a, b, userID))
+}
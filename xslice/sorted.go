@@ -0,0 +1,50 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xslice
+
+import "slices"
+
+// BinarySearchFunc searches target in s, which must be sorted in increasing order by cmp.
+// It returns the position of target, or where it would be inserted if it is not in s,
+// and whether it was found. If s holds several elements equal to target, the position
+// of the first one is returned.
+func BinarySearchFunc[T any](s []T, target T, cmp func(a, b T) int) (int, bool) {
+	return slices.BinarySearchFunc(s, target, cmp)
+}
+
+// InsertSorted inserts v into s, which must be sorted in increasing order by cmp, so that
+// it stays sorted, and returns the modified slice. v is inserted after the elements equal
+// to it, so inserting in order of arrival keeps equal elements in that order.
+func InsertSorted[T any](s []T, v T, cmp func(a, b T) int) []T {
+	// Find the first element greater than v.
+	i, j := 0, len(s)
+	for i < j {
+		h := int(uint(i+j) >> 1)
+		if cmp(s[h], v) <= 0 {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+
+	return slices.Insert(s, i, v)
+}
+
+// IsSortedFunc reports whether s is sorted in increasing order by cmp.
+func IsSortedFunc[T any](s []T, cmp func(a, b T) int) bool {
+	return slices.IsSortedFunc(s, cmp)
+}
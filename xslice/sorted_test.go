@@ -0,0 +1,75 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xslice
+
+import (
+	"cmp"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func byID(a, b user) int { return cmp.Compare(a.ID, b.ID) }
+
+func TestBinarySearchFunc(t *testing.T) {
+	s := []int{1, 3, 3, 3, 7, 9}
+	tests := []struct {
+		target int
+		pos    int
+		found  bool
+	}{
+		{0, 0, false},
+		{1, 0, true},
+		{2, 1, false},
+		{3, 1, true},
+		{7, 4, true},
+		{8, 5, false},
+		{10, 6, false},
+	}
+	for _, tt := range tests {
+		pos, found := BinarySearchFunc(s, tt.target, cmp.Compare[int])
+		assert.Equal(t, tt.pos, pos, "%d", tt.target)
+		assert.Equal(t, tt.found, found, "%d", tt.target)
+	}
+
+	pos, found := BinarySearchFunc(nil, 1, cmp.Compare[int])
+	assert.Equal(t, 0, pos)
+	assert.False(t, found)
+}
+
+func TestInsertSorted(t *testing.T) {
+	var s []int
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		s = InsertSorted(s, r.Intn(50), cmp.Compare[int])
+	}
+	assert.Len(t, s, 200)
+	assert.True(t, IsSortedFunc(s, cmp.Compare[int]))
+
+	users := []user{{ID: 1, Name: "a"}, {ID: 3, Name: "c"}}
+	users = InsertSorted(users, user{ID: 3, Name: "c2"}, byID)
+	users = InsertSorted(users, user{ID: 2, Name: "b"}, byID)
+	users = InsertSorted(users, user{ID: 0, Name: "z"}, byID)
+	assert.Equal(t, []user{{ID: 0, Name: "z"}, {ID: 1, Name: "a"}, {ID: 2, Name: "b"}, {ID: 3, Name: "c"}, {ID: 3, Name: "c2"}}, users)
+}
+
+func TestIsSortedFunc(t *testing.T) {
+	assert.True(t, IsSortedFunc([]int(nil), cmp.Compare[int]))
+	assert.True(t, IsSortedFunc([]int{1, 1, 2}, cmp.Compare[int]))
+	assert.False(t, IsSortedFunc([]int{2, 1}, cmp.Compare[int]))
+	assert.True(t, IsSortedFunc([]user{{ID: 2}, {ID: 1}}, func(a, b user) int { return byID(b, a) }))
+}
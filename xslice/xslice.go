@@ -0,0 +1,114 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xslice
+
+// Map returns a new slice with fn applied to every element of s.
+// It returns nil if s is nil.
+func Map[T, U any](s []T, fn func(T) U) []U {
+	if s == nil {
+		return nil
+	}
+
+	out := make([]U, len(s))
+	for i, v := range s {
+		out[i] = fn(v)
+	}
+
+	return out
+}
+
+// Filter returns a new slice with the elements of s that satisfy fn, in order.
+func Filter[T any](s []T, fn func(T) bool) []T {
+	var out []T
+	for _, v := range s {
+		if fn(v) {
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
+
+// Reduce folds s into a single value, calling fn with the accumulated value
+// (starting at init) and every element in order.
+func Reduce[T, U any](s []T, init U, fn func(acc U, v T) U) U {
+	acc := init
+	for _, v := range s {
+		acc = fn(acc, v)
+	}
+
+	return acc
+}
+
+// ForEach calls fn with the index and value of every element of s in order.
+func ForEach[T any](s []T, fn func(i int, v T)) {
+	for i, v := range s {
+		fn(i, v)
+	}
+}
+
+// Any reports whether at least one element of s satisfies fn.
+// It returns false for an empty slice.
+func Any[T any](s []T, fn func(T) bool) bool {
+	for _, v := range s {
+		if fn(v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// All reports whether every element of s satisfies fn.
+// It returns true for an empty slice.
+func All[T any](s []T, fn func(T) bool) bool {
+	for _, v := range s {
+		if !fn(v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Find returns the first element of s that satisfies fn, and whether there is one.
+func Find[T any](s []T, fn func(T) bool) (T, bool) {
+	for _, v := range s {
+		if fn(v) {
+			return v, true
+		}
+	}
+
+	var zero T
+	return zero, false
+}
+
+// Contains reports whether v is in s.
+func Contains[T comparable](s []T, v T) bool {
+	return IndexOf(s, v) >= 0
+}
+
+// IndexOf returns the index of the first occurrence of v in s, or -1 if v is not in s.
+func IndexOf[T comparable](s []T, v T) int {
+	for i, e := range s {
+		if e == v {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,85 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xslice
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+)
+
+func isEven(v int) bool { return v%2 == 0 }
+
+func TestMap(t *testing.T) {
+	assert.Equal(t, []string{"1", "2", "3"}, Map([]int{1, 2, 3}, strconv.Itoa))
+	assert.Equal(t, []string{}, Map([]int{}, strconv.Itoa))
+	assert.Nil(t, Map([]int(nil), strconv.Itoa))
+}
+
+func TestFilter(t *testing.T) {
+	assert.Equal(t, []int{2, 4}, Filter([]int{1, 2, 3, 4, 5}, isEven))
+	assert.Nil(t, Filter([]int{1, 3}, isEven))
+	assert.Nil(t, Filter(nil, isEven))
+}
+
+func TestReduce(t *testing.T) {
+	sum := Reduce([]int{1, 2, 3, 4}, 0, func(acc, v int) int { return acc + v })
+	assert.Equal(t, 10, sum)
+
+	joined := Reduce([]int{1, 2, 3}, "", func(acc string, v int) string { return acc + strconv.Itoa(v) })
+	assert.Equal(t, "123", joined)
+	assert.Equal(t, 7, Reduce(nil, 7, func(acc, v int) int { return acc + v }))
+}
+
+func TestForEach(t *testing.T) {
+	var indexes, values []int
+	ForEach([]int{10, 20, 30}, func(i, v int) {
+		indexes = append(indexes, i)
+		values = append(values, v)
+	})
+	assert.Equal(t, []int{0, 1, 2}, indexes)
+	assert.Equal(t, []int{10, 20, 30}, values)
+}
+
+func TestAnyAll(t *testing.T) {
+	assert.True(t, Any([]int{1, 2, 3}, isEven))
+	assert.False(t, Any([]int{1, 3}, isEven))
+	assert.False(t, Any(nil, isEven))
+
+	assert.True(t, All([]int{2, 4}, isEven))
+	assert.False(t, All([]int{2, 3}, isEven))
+	assert.True(t, All(nil, isEven))
+}
+
+func TestFind(t *testing.T) {
+	v, ok := Find([]int{1, 3, 4, 6}, isEven)
+	assert.True(t, ok)
+	assert.Equal(t, 4, v)
+
+	v, ok = Find([]int{1, 3}, isEven)
+	assert.False(t, ok)
+	assert.Zero(t, v)
+}
+
+func TestContainsIndexOf(t *testing.T) {
+	s := []string{"a", "b", "c", "b"}
+	assert.True(t, Contains(s, "c"))
+	assert.False(t, Contains(s, "d"))
+	assert.Equal(t, 1, IndexOf(s, "b"))
+	assert.Equal(t, -1, IndexOf(s, "d"))
+	assert.Equal(t, -1, IndexOf(nil, "d"))
+}
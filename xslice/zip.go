@@ -0,0 +1,55 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xslice
+
+// Pair holds two values of possibly different types.
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// Zip pairs the elements of as and bs by index. If the slices differ in length,
+// the extra elements of the longer one are ignored, so the result is as long as
+// the shorter slice.
+func Zip[A, B any](as []A, bs []B) []Pair[A, B] {
+	return ZipWith(as, bs, func(a A, b B) Pair[A, B] {
+		return Pair[A, B]{First: a, Second: b}
+	})
+}
+
+// ZipWith combines the elements of as and bs by index with fn. Like Zip,
+// it stops at the end of the shorter slice.
+func ZipWith[A, B, C any](as []A, bs []B, fn func(a A, b B) C) []C {
+	n := min(len(as), len(bs))
+	out := make([]C, n)
+	for i := 0; i < n; i++ {
+		out[i] = fn(as[i], bs[i])
+	}
+
+	return out
+}
+
+// Unzip splits pairs into the slice of their first and the slice of their second values.
+func Unzip[A, B any](pairs []Pair[A, B]) ([]A, []B) {
+	as := make([]A, len(pairs))
+	bs := make([]B, len(pairs))
+	for i, p := range pairs {
+		as[i], bs[i] = p.First, p.Second
+	}
+
+	return as, bs
+}
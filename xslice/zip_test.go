@@ -0,0 +1,47 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xslice
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestZip(t *testing.T) {
+	ids := []int{1, 2, 3}
+	names := []string{"a", "b", "c"}
+	assert.Equal(t, []Pair[int, string]{{1, "a"}, {2, "b"}, {3, "c"}}, Zip(ids, names))
+	assert.Equal(t, []Pair[int, string]{{1, "a"}, {2, "b"}}, Zip(ids, names[:2]))
+	assert.Equal(t, []Pair[int, string]{{1, "a"}}, Zip(ids[:1], names))
+	assert.Empty(t, Zip([]int(nil), names))
+}
+
+func TestZipWith(t *testing.T) {
+	got := ZipWith([]string{"a", "b", "c"}, []int{1, 2}, strings.Repeat)
+	assert.Equal(t, []string{"a", "bb"}, got)
+}
+
+func TestUnzip(t *testing.T) {
+	ids, names := Unzip([]Pair[int, string]{{1, "a"}, {2, "b"}})
+	assert.Equal(t, []int{1, 2}, ids)
+	assert.Equal(t, []string{"a", "b"}, names)
+
+	ids, names = Unzip(Zip([]int{1, 2, 3}, []string{"x"}))
+	assert.Equal(t, []int{1}, ids)
+	assert.Equal(t, []string{"x"}, names)
+}
@@ -0,0 +1,115 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ToSnake converts s to snake_case, e.g. "HTTPServerID" becomes "http_server_id".
+func ToSnake(s string) string {
+	return joinWords(s, '_', unicode.ToLower)
+}
+
+// ToScreamingSnake converts s to SCREAMING_SNAKE_CASE, e.g. "HTTPServerID" becomes "HTTP_SERVER_ID".
+func ToScreamingSnake(s string) string {
+	return joinWords(s, '_', unicode.ToUpper)
+}
+
+// ToKebab converts s to kebab-case, e.g. "HTTPServerID" becomes "http-server-id".
+func ToKebab(s string) string {
+	return joinWords(s, '-', unicode.ToLower)
+}
+
+// ToCamel converts s to camelCase, e.g. "http_server_id" becomes "httpServerId".
+func ToCamel(s string) string {
+	return titleWords(s, false)
+}
+
+// ToPascal converts s to PascalCase, e.g. "http_server_id" becomes "HttpServerId".
+func ToPascal(s string) string {
+	return titleWords(s, true)
+}
+
+// splitWords splits s into words. Any rune that is neither a letter nor a digit
+// separates words, and a word also ends before an upper case letter that follows a
+// lower case letter, a digit or an uncased letter ("fooBar", "v2Beta"), or that ends
+// a run of upper case letters and starts a new word ("HTTPServer" splits into "HTTP"
+// and "Server").
+func splitWords(s string) [][]rune {
+	runes := []rune(s)
+	var words [][]rune
+	start := -1
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				words = append(words, runes[start:i])
+				start = -1
+			}
+			continue
+		}
+
+		if start >= 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			if !unicode.IsUpper(prev) || i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+				words = append(words, runes[start:i])
+				start = i
+			}
+		}
+
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, runes[start:])
+	}
+
+	return words
+}
+
+func joinWords(s string, sep byte, mapping func(rune) rune) string {
+	var sb strings.Builder
+	sb.Grow(len(s) + len(s)/2)
+	for i, word := range splitWords(s) {
+		if i > 0 {
+			sb.WriteByte(sep)
+		}
+		for _, r := range word {
+			sb.WriteRune(mapping(r))
+		}
+	}
+
+	return sb.String()
+}
+
+func titleWords(s string, upperFirst bool) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for i, word := range splitWords(s) {
+		for j, r := range word {
+			if j == 0 && (i > 0 || upperFirst) {
+				sb.WriteRune(unicode.ToUpper(r))
+			} else {
+				sb.WriteRune(unicode.ToLower(r))
+			}
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,56 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCaseConversion(t *testing.T) {
+	tests := []struct {
+		in        string
+		snake     string
+		screaming string
+		kebab     string
+		camel     string
+		pascal    string
+	}{
+		{"", "", "", "", "", ""},
+		{"HTTPServerID", "http_server_id", "HTTP_SERVER_ID", "http-server-id", "httpServerId", "HttpServerId"},
+		{"http_server_id", "http_server_id", "HTTP_SERVER_ID", "http-server-id", "httpServerId", "HttpServerId"},
+		{"fooBar", "foo_bar", "FOO_BAR", "foo-bar", "fooBar", "FooBar"},
+		{"FooBar", "foo_bar", "FOO_BAR", "foo-bar", "fooBar", "FooBar"},
+		{"  foo--bar.baz  qux ", "foo_bar_baz_qux", "FOO_BAR_BAZ_QUX", "foo-bar-baz-qux", "fooBarBazQux", "FooBarBazQux"},
+		{"ID", "id", "ID", "id", "id", "Id"},
+		{"userID2", "user_id2", "USER_ID2", "user-id2", "userId2", "UserId2"},
+		{"v2Beta", "v2_beta", "V2_BETA", "v2-beta", "v2Beta", "V2Beta"},
+		{"HTTP2Server", "http2_server", "HTTP2_SERVER", "http2-server", "http2Server", "Http2Server"},
+		{"base64Encode", "base64_encode", "BASE64_ENCODE", "base64-encode", "base64Encode", "Base64Encode"},
+		{"ÄpfelÜberStraße", "äpfel_über_straße", "ÄPFEL_ÜBER_STRAßE", "äpfel-über-straße", "äpfelÜberStraße", "ÄpfelÜberStraße"},
+		{"用户ID", "用户_id", "用户_ID", "用户-id", "用户Id", "用户Id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			assert.Equal(t, tt.snake, ToSnake(tt.in))
+			assert.Equal(t, tt.screaming, ToScreamingSnake(tt.in))
+			assert.Equal(t, tt.kebab, ToKebab(tt.in))
+			assert.Equal(t, tt.camel, ToCamel(tt.in))
+			assert.Equal(t, tt.pascal, ToPascal(tt.in))
+		})
+	}
+}
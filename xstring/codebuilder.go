@@ -0,0 +1,177 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+type (
+	// CodeBuilder builds indented text such as generated source code or configuration files.
+	// The zero value is ready to use and indents with tabs.
+	CodeBuilder struct {
+		b           strings.Builder
+		opts        codeBuilderOptions
+		depth       int
+		midLine     bool
+		initialized bool
+	}
+
+	// CodeBuilderOption customizes a CodeBuilder.
+	CodeBuilderOption func(o *codeBuilderOptions)
+
+	codeBuilderOptions struct {
+		indent string
+	}
+)
+
+// WithCodeIndent sets the string written once per indentation level, "\t" by default.
+func WithCodeIndent(indent string) CodeBuilderOption {
+	return func(o *codeBuilderOptions) {
+		o.indent = indent
+	}
+}
+
+// NewCodeBuilder returns a CodeBuilder.
+func NewCodeBuilder(opts ...CodeBuilderOption) *CodeBuilder {
+	b := &CodeBuilder{}
+	b.init(opts...)
+
+	return b
+}
+
+func (b *CodeBuilder) init(opts ...CodeBuilderOption) {
+	b.opts = codeBuilderOptions{indent: "\t"}
+	for _, opt := range opts {
+		opt(&b.opts)
+	}
+	b.initialized = true
+}
+
+// Indent increases the indentation of the following lines by one level.
+func (b *CodeBuilder) Indent() {
+	b.depth++
+}
+
+// Dedent decreases the indentation of the following lines by one level.
+// It does nothing at the outermost level.
+func (b *CodeBuilder) Dedent() {
+	if b.depth > 0 {
+		b.depth--
+	}
+}
+
+// Line writes a line formatted with fmt.Sprintf at the current indentation.
+// Without args, format is written as is. Every line of a multi-line result is indented,
+// and empty lines are written without indentation.
+func (b *CodeBuilder) Line(format string, args ...interface{}) {
+	if len(args) > 0 {
+		format = fmt.Sprintf(format, args...)
+	}
+	_, _ = b.WriteString(format)
+	b.newline()
+}
+
+// Block calls fn with the indentation increased by one level, e.g.
+//
+//	b.Line("func main() {")
+//	b.Block(func() {
+//		b.Line("fmt.Println(%q)", "hello")
+//	})
+//	b.Line("}")
+func (b *CodeBuilder) Block(fn func()) {
+	b.Indent()
+	defer b.Dedent()
+	fn()
+}
+
+// WriteString writes s, indenting the beginning of every non-empty line.
+// It returns the length of s and a nil error.
+func (b *CodeBuilder) WriteString(s string) (int, error) {
+	if !b.initialized {
+		b.init()
+	}
+
+	n := len(s)
+	for s != empty {
+		line := s
+		i := strings.IndexByte(s, '\n')
+		if i >= 0 {
+			line = s[:i]
+		}
+		if line != empty {
+			if !b.midLine {
+				for d := 0; d < b.depth; d++ {
+					b.b.WriteString(b.opts.indent)
+				}
+			}
+			b.b.WriteString(line)
+			b.midLine = true
+		}
+		if i < 0 {
+			break
+		}
+		b.newline()
+		s = s[i+1:]
+	}
+
+	return n, nil
+}
+
+// Write is like WriteString, it implements io.Writer.
+func (b *CodeBuilder) Write(p []byte) (int, error) {
+	return b.WriteString(string(p))
+}
+
+func (b *CodeBuilder) newline() {
+	b.b.WriteByte('\n')
+	b.midLine = false
+}
+
+// String returns the accumulated text.
+func (b *CodeBuilder) String() string {
+	return b.b.String()
+}
+
+// WriteTo writes the accumulated text to w with a single write, so that writing to
+// a Joiner appends it as one element. It implements io.WriterTo.
+func (b *CodeBuilder) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, b.b.String())
+
+	return int64(n), err
+}
+
+// Len returns the number of accumulated bytes.
+func (b *CodeBuilder) Len() int {
+	return b.b.Len()
+}
+
+// Reset discards the accumulated text and resets the indentation, keeping the options.
+func (b *CodeBuilder) Reset() {
+	b.b.Reset()
+	b.depth = 0
+	b.midLine = false
+}
+
+// AddBlock builds code with fn and appends it to j as a single element.
+func (j *Joiner) AddBlock(fn func(b *CodeBuilder), opts ...CodeBuilderOption) {
+	b := NewCodeBuilder(opts...)
+	fn(b)
+	_, _ = j.WriteString(b.String())
+}
@@ -0,0 +1,90 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCodeBuilder(t *testing.T) {
+	b := NewCodeBuilder()
+	b.Line("package main")
+	b.Line("")
+	b.Line("func main() {")
+	b.Block(func() {
+		b.Line("for i := 0; i < %d; i++ {", 3)
+		b.Block(func() {
+			b.Line(`fmt.Println("100%")`)
+		})
+		b.Line("}")
+	})
+	b.Line("}")
+
+	assert.Equal(t, "package main\n\nfunc main() {\n\tfor i := 0; i < 3; i++ {\n\t\tfmt.Println(\"100%\")\n\t}\n}\n", b.String())
+	assert.Equal(t, len(b.String()), b.Len())
+
+	b.Reset()
+	assert.Equal(t, "", b.String())
+	b.Dedent()
+	b.Line("x")
+	assert.Equal(t, "x\n", b.String())
+}
+
+func TestCodeBuilderMultiline(t *testing.T) {
+	b := NewCodeBuilder(WithCodeIndent("  "))
+	b.Line("server:")
+	b.Indent()
+	b.Line("host: %s\nport: %d\n\ntls: %t", "localhost", 8080, false)
+	_, _ = b.WriteString("name: ")
+	_, _ = b.Write([]byte("api\n"))
+	b.Dedent()
+	b.Line("done")
+
+	assert.Equal(t, "server:\n  host: localhost\n  port: 8080\n\n  tls: false\n  name: api\ndone\n", b.String())
+}
+
+func TestCodeBuilderZeroValue(t *testing.T) {
+	var b CodeBuilder
+	b.Block(func() {
+		b.Line("indented")
+	})
+	assert.Equal(t, "\tindented\n", b.String())
+}
+
+func TestCodeBuilderJoiner(t *testing.T) {
+	j := NewJoiner(WithJoinerStep("\n"))
+	for _, name := range []string{"A", "B"} {
+		j.AddBlock(func(b *CodeBuilder) {
+			b.Line("type %s struct {", name)
+			b.Block(func() {
+				b.Line("ID int")
+			})
+			b.Line("}")
+		}, WithCodeIndent("    "))
+	}
+	assert.Equal(t, "type A struct {\n    ID int\n}\n\ntype B struct {\n    ID int\n}\n", j.String())
+
+	b := NewCodeBuilder()
+	b.Line("x := 1")
+	j = NewJoiner(WithJoiner("[", ",", "]"))
+	_, _ = j.WriteString("first")
+	n, err := b.WriteTo(j)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), n)
+	assert.Equal(t, "[first,x := 1\n]", j.String())
+}
@@ -0,0 +1,456 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Diff operations.
+const (
+	DiffEqual DiffOp = iota
+	DiffDelete
+	DiffInsert
+)
+
+type (
+	// DiffOp is the kind of an Edit.
+	DiffOp int
+
+	// Edit is a run of text that is equal in, deleted from or inserted into the compared strings.
+	Edit struct {
+		Op   DiffOp
+		Text string
+	}
+)
+
+// String returns "=", "-" or "+".
+func (op DiffOp) String() string {
+	switch op {
+	case DiffEqual:
+		return "="
+	case DiffDelete:
+		return "-"
+	case DiffInsert:
+		return "+"
+	}
+
+	return fmt.Sprintf("DiffOp(%d)", int(op))
+}
+
+// Diff returns the line-based edits turning a into b, computed with the linear space
+// variant of Myers' algorithm, which produces a minimal diff in O((N+M)·D) time for
+// inputs of N and M lines that differ in D lines. Consecutive lines with the same operation form a single Edit,
+// and each line keeps its trailing newline.
+func Diff(a, b string) []Edit {
+	return diffTokens(splitLines(a), splitLines(b))
+}
+
+// DiffWords is like Diff, but compares words and the whitespace between them,
+// which suits single-line inputs such as log messages and assertion values.
+func DiffWords(a, b string) []Edit {
+	return diffTokens(splitWordTokens(a), splitWordTokens(b))
+}
+
+// UnifiedDiff renders the line diff of a and b in the unified format of diff -u,
+// with context unchanged lines around every change. It returns an empty string
+// if a and b are equal.
+func UnifiedDiff(aName, bName, a, b string, context int) string {
+	edits := Diff(a, b)
+	if len(edits) == 0 || len(edits) == 1 && edits[0].Op == DiffEqual {
+		return empty
+	}
+
+	return "--- " + aName + "\n+++ " + bName + "\n" + Unified(edits, context)
+}
+
+// Unified renders line edits as returned by Diff as unified diff hunks
+// with context unchanged lines around every change, without file headers.
+func Unified(edits []Edit, context int) string {
+	if context < 0 {
+		context = 0
+	}
+
+	type line struct {
+		op   DiffOp
+		text string
+	}
+	var lines []line
+	for _, e := range edits {
+		for _, l := range splitLines(e.Text) {
+			lines = append(lines, line{e.Op, l})
+		}
+	}
+
+	var sb strings.Builder
+	aLine, bLine := 1, 1
+	for i := 0; i < len(lines); {
+		if lines[i].op == DiffEqual {
+			aLine++
+			bLine++
+			i++
+			continue
+		}
+
+		// Extend the hunk while changes are at most 2*context equal lines apart.
+		start := max(0, i-context)
+		end := i
+		for j := i; j < len(lines); j++ {
+			if lines[j].op != DiffEqual {
+				end = j + 1
+			} else if j-end >= 2*context {
+				break
+			}
+		}
+		end = min(len(lines), end+context)
+
+		aStart, bStart := aLine-(i-start), bLine-(i-start)
+		aCount, bCount := 0, 0
+		for _, l := range lines[start:end] {
+			if l.op != DiffInsert {
+				aCount++
+			}
+			if l.op != DiffDelete {
+				bCount++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(aStart, aCount), hunkRange(bStart, bCount))
+		for _, l := range lines[start:end] {
+			switch l.op {
+			case DiffEqual:
+				sb.WriteByte(' ')
+			case DiffDelete:
+				sb.WriteByte('-')
+			case DiffInsert:
+				sb.WriteByte('+')
+			}
+			sb.WriteString(l.text)
+			if !strings.HasSuffix(l.text, "\n") {
+				sb.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		for _, l := range lines[i:end] {
+			if l.op != DiffInsert {
+				aLine++
+			}
+			if l.op != DiffDelete {
+				bLine++
+			}
+		}
+		i = end
+	}
+
+	return sb.String()
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		// An empty range refers to the line before it.
+		start--
+	}
+	if count == 1 {
+		return fmt.Sprint(start)
+	}
+
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// InlineDiff renders edits on a single stream of text, marking deletions as [-text-]
+// and insertions as {+text+} like wdiff, e.g. for DiffWords results.
+func InlineDiff(edits []Edit) string {
+	var sb strings.Builder
+	for _, e := range edits {
+		switch e.Op {
+		case DiffDelete:
+			sb.WriteString("[-" + e.Text + "-]")
+		case DiffInsert:
+			sb.WriteString("{+" + e.Text + "+}")
+		default:
+			sb.WriteString(e.Text)
+		}
+	}
+
+	return sb.String()
+}
+
+func splitLines(s string) []string {
+	if s == empty {
+		return nil
+	}
+
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == empty {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// splitWordTokens splits s into runs of whitespace and runs of other runes.
+func splitWordTokens(s string) []string {
+	var tokens []string
+	start := 0
+	prevSpace := false
+	for i, r := range s {
+		space := unicode.IsSpace(r)
+		if i > start && space != prevSpace {
+			tokens = append(tokens, s[start:i])
+			start = i
+		}
+		prevSpace = space
+	}
+	if start < len(s) {
+		tokens = append(tokens, s[start:])
+	}
+
+	return tokens
+}
+
+func diffTokens(a, b []string) []Edit {
+	ops := diffOps(intern(a, b))
+
+	var edits []Edit
+	var sb strings.Builder
+	x, y := 0, 0
+	for i, op := range ops {
+		switch op {
+		case DiffEqual:
+			sb.WriteString(a[x])
+			x++
+			y++
+		case DiffDelete:
+			sb.WriteString(a[x])
+			x++
+		case DiffInsert:
+			sb.WriteString(b[y])
+			y++
+		}
+		if i == len(ops)-1 || ops[i+1] != op {
+			edits = append(edits, Edit{Op: op, Text: sb.String()})
+			sb.Reset()
+		}
+	}
+
+	return edits
+}
+
+// intern maps the tokens of a and b to integers, so that they compare cheaply.
+func intern(a, b []string) ([]int, []int) {
+	ids := make(map[string]int, len(a))
+	ints := func(tokens []string) []int {
+		out := make([]int, len(tokens))
+		for i, t := range tokens {
+			id, ok := ids[t]
+			if !ok {
+				id = len(ids)
+				ids[t] = id
+			}
+			out[i] = id
+		}
+		return out
+	}
+
+	return ints(a), ints(b)
+}
+
+// diffOps returns the operations of a shortest edit script turning a into b.
+func diffOps(a, b []int) []DiffOp {
+	// Tokens that occur on one side only can never be matched, so they are
+	// set aside before running Myers' algorithm on what remains. This keeps
+	// mostly rewritten inputs fast without affecting the result.
+	inA := make(map[int]bool, len(a))
+	for _, t := range a {
+		inA[t] = true
+	}
+	inB := make(map[int]bool, len(b))
+	for _, t := range b {
+		inB[t] = true
+	}
+	var keptA, keptB, idxA, idxB []int
+	for i, t := range a {
+		if inB[t] {
+			keptA = append(keptA, t)
+			idxA = append(idxA, i)
+		}
+	}
+	for i, t := range b {
+		if inA[t] {
+			keptB = append(keptB, t)
+			idxB = append(idxB, i)
+		}
+	}
+
+	d := differ{ops: make([]DiffOp, 0, len(a)+len(b))}
+	d.compare(keptA, keptB)
+
+	// Merge the discarded tokens back in front of the next kept token on their side.
+	ops := make([]DiffOp, 0, len(a)+len(b))
+	x, y, i, j := 0, 0, 0, 0
+	flush := func(toA, toB int) {
+		for ; x < toA; x++ {
+			ops = append(ops, DiffDelete)
+		}
+		for ; y < toB; y++ {
+			ops = append(ops, DiffInsert)
+		}
+	}
+	for _, op := range d.ops {
+		switch op {
+		case DiffEqual:
+			flush(idxA[i], idxB[j])
+			i++
+			j++
+			x++
+			y++
+		case DiffDelete:
+			flush(idxA[i], y)
+			i++
+			x++
+		case DiffInsert:
+			flush(x, idxB[j])
+			j++
+			y++
+		}
+		ops = append(ops, op)
+	}
+	flush(len(a), len(b))
+
+	return ops
+}
+
+type differ struct {
+	ops []DiffOp
+}
+
+func (d *differ) emit(op DiffOp, n int) {
+	for i := 0; i < n; i++ {
+		d.ops = append(d.ops, op)
+	}
+}
+
+// compare appends the edit script for a and b, splitting the problem at a point
+// of an optimal path found by bisect, which needs linear memory.
+func (d *differ) compare(a, b []int) {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	d.emit(DiffEqual, prefix)
+	a, b = a[prefix:], b[prefix:]
+
+	suffix := 0
+	for suffix < len(a) && suffix < len(b) && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+
+	switch x, y, ok := bisect(a, b); {
+	case !ok || x == 0 && y == 0 || x == len(a) && y == len(b):
+		d.emit(DiffDelete, len(a))
+		d.emit(DiffInsert, len(b))
+	default:
+		d.compare(a[:x], b[:y])
+		d.compare(a[x:], b[y:])
+	}
+	d.emit(DiffEqual, suffix)
+}
+
+// bisect finds the point where the forward and the reverse search of Myers' algorithm
+// meet on a shortest edit path for a and b, which must not share a prefix or suffix.
+func bisect(a, b []int) (int, int, bool) {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		return 0, 0, false
+	}
+
+	maxD := (n + m + 1) / 2
+	offset := maxD
+	size := 2*maxD + 2
+	v1 := make([]int, size)
+	v2 := make([]int, size)
+	for i := range v1 {
+		v1[i], v2[i] = -1, -1
+	}
+	v1[offset+1], v2[offset+1] = 0, 0
+	delta := n - m
+	// An odd delta means the forward search detects the overlap, an even one the reverse search.
+	front := delta%2 != 0
+	// Diagonals beyond the edges of the edit graph are trimmed from the search.
+	k1Start, k1End, k2Start, k2End := 0, 0, 0, 0
+	for d := 0; d < maxD; d++ {
+		for k1 := -d + k1Start; k1 <= d-k1End; k1 += 2 {
+			i := offset + k1
+			var x1 int
+			if k1 == -d || k1 != d && v1[i-1] < v1[i+1] {
+				x1 = v1[i+1]
+			} else {
+				x1 = v1[i-1] + 1
+			}
+			y1 := x1 - k1
+			for x1 < n && y1 < m && a[x1] == b[y1] {
+				x1++
+				y1++
+			}
+			v1[i] = x1
+			switch {
+			case x1 > n:
+				k1End += 2
+			case y1 > m:
+				k1Start += 2
+			case front:
+				if j := offset + delta - k1; j >= 0 && j < size && v2[j] != -1 && x1 >= n-v2[j] {
+					return x1, y1, true
+				}
+			}
+		}
+
+		for k2 := -d + k2Start; k2 <= d-k2End; k2 += 2 {
+			i := offset + k2
+			var x2 int
+			if k2 == -d || k2 != d && v2[i-1] < v2[i+1] {
+				x2 = v2[i+1]
+			} else {
+				x2 = v2[i-1] + 1
+			}
+			y2 := x2 - k2
+			for x2 < n && y2 < m && a[n-x2-1] == b[m-y2-1] {
+				x2++
+				y2++
+			}
+			v2[i] = x2
+			switch {
+			case x2 > n:
+				k2End += 2
+			case y2 > m:
+				k2Start += 2
+			case !front:
+				if j := offset + delta - k2; j >= 0 && j < size && v1[j] != -1 {
+					x1 := v1[j]
+					y1 := x1 - (j - offset)
+					if x1 >= n-x2 {
+						return x1, y1, true
+					}
+				}
+			}
+		}
+	}
+
+	return 0, 0, false
+}
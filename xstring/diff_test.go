@@ -0,0 +1,191 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	assert.Nil(t, Diff("", ""))
+	assert.Equal(t, []Edit{{DiffEqual, "a\nb\n"}}, Diff("a\nb\n", "a\nb\n"))
+	assert.Equal(t, []Edit{{DiffInsert, "a\n"}}, Diff("", "a\n"))
+	assert.Equal(t, []Edit{{DiffDelete, "a\n"}}, Diff("a\n", ""))
+	assert.Equal(t, []Edit{
+		{DiffEqual, "a\n"},
+		{DiffDelete, "b\n"},
+		{DiffInsert, "B\n"},
+		{DiffEqual, "c\n"},
+		{DiffInsert, "d\n"},
+	}, Diff("a\nb\nc\n", "a\nB\nc\nd\n"))
+	assert.Equal(t, []Edit{
+		{DiffEqual, "a\n"},
+		{DiffDelete, "b"},
+		{DiffInsert, "b\n"},
+	}, Diff("a\nb", "a\nb\n"))
+}
+
+func TestDiffMinimal(t *testing.T) {
+	a := "a\nb\nc\na\nb\nb\na\n"
+	b := "c\nb\na\nb\na\nc\n"
+	edits := Diff(a, b)
+	changed := 0
+	for _, e := range edits {
+		if e.Op != DiffEqual {
+			changed += len(splitLines(e.Text))
+		}
+	}
+	assert.Equal(t, 5, changed)
+	assertReconstructs(t, a, b, edits)
+}
+
+func TestDiffRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	gen := func() string {
+		var sb strings.Builder
+		for i := r.Intn(30); i > 0; i-- {
+			sb.WriteByte("abcd"[r.Intn(4)])
+			sb.WriteByte('\n')
+		}
+		return sb.String()
+	}
+	for i := 0; i < 500; i++ {
+		a, b := gen(), gen()
+		edits := Diff(a, b)
+		assertReconstructs(t, a, b, edits)
+
+		equal := 0
+		for _, e := range edits {
+			if e.Op == DiffEqual {
+				equal += len(splitLines(e.Text))
+			}
+		}
+		assert.Equal(t, lcsLen(splitLines(a), splitLines(b)), equal, "%q %q", a, b)
+	}
+}
+
+func lcsLen(a, b []string) int {
+	dp := make([][]int, len(a)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(b)+1)
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				dp[i][j] = dp[i-1][j-1] + 1
+			} else {
+				dp[i][j] = max(dp[i-1][j], dp[i][j-1])
+			}
+		}
+	}
+	return dp[len(a)][len(b)]
+}
+
+func TestDiffWords(t *testing.T) {
+	edits := DiffWords("the quick brown fox", "the slow brown  fox")
+	assertReconstructs(t, "the quick brown fox", "the slow brown  fox", edits)
+	assert.Equal(t, "the [-quick-]{+slow+} brown[- -]{+  +}fox", InlineDiff(edits))
+	assert.Equal(t, "[-你好-]{+您好+} 世界", InlineDiff(DiffWords("你好 世界", "您好 世界")))
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	assert.Equal(t, "", UnifiedDiff("a", "b", "same\n", "same\n", 3))
+
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+	b := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\neleven\n"
+	want := `--- a.txt
++++ b.txt
+@@ -2,3 +2,3 @@
+ 2
+-3
++three
+ 4
+@@ -10 +10,2 @@
+ 10
++eleven
+`
+	assert.Equal(t, want, UnifiedDiff("a.txt", "b.txt", a, b, 1))
+
+	want = `--- a.txt
++++ b.txt
+@@ -1,6 +1,6 @@
+ 1
+ 2
+-3
++three
+ 4
+ 5
+ 6
+@@ -8,3 +8,4 @@
+ 8
+ 9
+ 10
++eleven
+`
+	assert.Equal(t, want, UnifiedDiff("a.txt", "b.txt", a, b, 3))
+
+	want = "--- a.txt\n+++ b.txt\n@@ -1,10 +1,11 @@\n 1\n 2\n-3\n+three\n 4\n 5\n 6\n 7\n 8\n 9\n 10\n+eleven\n"
+	assert.Equal(t, want, UnifiedDiff("a.txt", "b.txt", a, b, 4))
+
+	assert.Equal(t, "--- a\n+++ b\n@@ -0,0 +1 @@\n+new\n", UnifiedDiff("a", "b", "", "new\n", 3))
+	assert.Equal(t, "--- a\n+++ b\n@@ -1 +1 @@\n-x\n\\ No newline at end of file\n+x\n",
+		UnifiedDiff("a", "b", "x", "x\n", 3))
+}
+
+func TestDiffLarge(t *testing.T) {
+	var a, b strings.Builder
+	for i := 0; i < 50000; i++ {
+		line := strings.Repeat("x", i%50) + "\n"
+		a.WriteString(line)
+		if i%1000 == 0 {
+			b.WriteString("changed\n")
+		} else {
+			b.WriteString(line)
+		}
+	}
+	edits := Diff(a.String(), b.String())
+	assertReconstructs(t, a.String(), b.String(), edits)
+
+	// Completely rewritten inputs.
+	a.Reset()
+	b.Reset()
+	for i := 0; i < 50000; i++ {
+		fmt.Fprintf(&a, "a%d\n", i)
+		fmt.Fprintf(&b, "b%d\n", i)
+	}
+	edits = Diff(a.String(), b.String())
+	assert.Equal(t, []Edit{{DiffDelete, a.String()}, {DiffInsert, b.String()}}, edits)
+}
+
+func assertReconstructs(t *testing.T, a, b string, edits []Edit) {
+	t.Helper()
+	var ra, rb strings.Builder
+	for _, e := range edits {
+		if e.Op != DiffInsert {
+			ra.WriteString(e.Text)
+		}
+		if e.Op != DiffDelete {
+			rb.WriteString(e.Text)
+		}
+	}
+	assert.Equal(t, a, ra.String())
+	assert.Equal(t, b, rb.String())
+}
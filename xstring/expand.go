@@ -0,0 +1,136 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var (
+	// ErrExpandMissing is returned by Expand and ExpandFunc in strict mode for a placeholder without a value.
+	ErrExpandMissing = errors.New("xstring: missing template value")
+	// ErrExpandSyntax is returned by Expand and ExpandFunc for a malformed placeholder.
+	ErrExpandSyntax = errors.New("xstring: malformed template placeholder")
+)
+
+type (
+	// ExpandOption customizes Expand and ExpandFunc.
+	ExpandOption func(o *expandOptions)
+
+	expandOptions struct {
+		strict bool
+	}
+)
+
+// WithExpandStrict makes a placeholder without a value an ErrExpandMissing error
+// instead of leaving it in the output unchanged.
+func WithExpandStrict() ExpandOption {
+	return func(o *expandOptions) {
+		o.strict = true
+	}
+}
+
+// Expand replaces the ${name} and {name} placeholders in template with their values in vars.
+// See ExpandFunc for the syntax.
+func Expand(template string, vars map[string]string, opts ...ExpandOption) (string, error) {
+	return ExpandFunc(template, func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}, opts...)
+}
+
+// ExpandFunc replaces the ${name} and {name} placeholders in template with the values
+// returned by mapping. A name consists of letters, digits, '_', '-' and '.'.
+// "$$", "{{" and "}}" are escapes for a literal '$', '{' and '}', and "$$" also copies
+// a following {...} as is, so "$${name}" gives "${name}"; any other '$' or '}' is copied
+// as is, whereas a '{' that does not start a well-formed placeholder is an ErrExpandSyntax error.
+//
+// When mapping reports that a name has no value, the placeholder is kept verbatim,
+// or ErrExpandMissing is returned if WithExpandStrict is given.
+func ExpandFunc(template string, mapping func(name string) (string, bool), opts ...ExpandOption) (string, error) {
+	var o expandOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(template))
+	for i := 0; i < len(template); {
+		c := template[i]
+		switch {
+		case strings.HasPrefix(template[i:], "$${"):
+			end := strings.IndexByte(template[i:], '}')
+			if end < 0 {
+				return empty, fmt.Errorf("%w: unterminated placeholder at offset %d", ErrExpandSyntax, i)
+			}
+			sb.WriteString(template[i+1 : i+end+1])
+			i += end + 1
+			continue
+		case c == '$' && i+1 < len(template) && template[i+1] == '$',
+			c == '{' && i+1 < len(template) && template[i+1] == '{',
+			c == '}' && i+1 < len(template) && template[i+1] == '}':
+			sb.WriteByte(c)
+			i += 2
+			continue
+		case c == '$' && i+1 < len(template) && template[i+1] == '{',
+			c == '{':
+			start := i
+			if c == '$' {
+				i++
+			}
+			end := strings.IndexByte(template[i:], '}')
+			if end < 0 {
+				return empty, fmt.Errorf("%w: unterminated placeholder at offset %d", ErrExpandSyntax, start)
+			}
+			name := template[i+1 : i+end]
+			if !isPlaceholderName(name) {
+				return empty, fmt.Errorf("%w: invalid name %q at offset %d", ErrExpandSyntax, name, start)
+			}
+			i += end + 1
+
+			if v, ok := mapping(name); ok {
+				sb.WriteString(v)
+			} else if o.strict {
+				return empty, fmt.Errorf("%w: %q", ErrExpandMissing, name)
+			} else {
+				sb.WriteString(template[start:i])
+			}
+			continue
+		}
+
+		sb.WriteByte(c)
+		i++
+	}
+
+	return sb.String(), nil
+}
+
+func isPlaceholderName(name string) bool {
+	if name == empty {
+		return false
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-' && r != '.' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,86 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	vars := map[string]string{
+		"name":      "Alice",
+		"count":     "3",
+		"user.id":   "42",
+		"first-day": "Monday",
+		"名字":        "张三",
+		"empty":     "",
+	}
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"", ""},
+		{"plain text", "plain text"},
+		{"Hello ${name}!", "Hello Alice!"},
+		{"Hello {name}!", "Hello Alice!"},
+		{"{name} has {count} new messages", "Alice has 3 new messages"},
+		{"id=${user.id} day={first-day}", "id=42 day=Monday"},
+		{"你好，{名字}", "你好，张三"},
+		{"[{empty}]", "[]"},
+		{"price: $$5, {{literal}} and }}", "price: $5, {literal} and }"},
+		{"escaped $${name} and $$$${name}", "escaped ${name} and $${name}"},
+		{"$${not a name} then {name}", "${not a name} then Alice"},
+		{"$ alone and } alone", "$ alone and } alone"},
+		{"{missing} and ${missing}", "{missing} and ${missing}"},
+		{"trailing $", "trailing $"},
+	}
+	for _, tt := range tests {
+		got, err := Expand(tt.template, vars)
+		assert.NoError(t, err, tt.template)
+		assert.Equal(t, tt.want, got, tt.template)
+	}
+}
+
+func TestExpandStrict(t *testing.T) {
+	got, err := Expand("Hello {name}", map[string]string{"name": "Bob"}, WithExpandStrict())
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello Bob", got)
+
+	_, err = Expand("Hello {name}", nil, WithExpandStrict())
+	assert.ErrorIs(t, err, ErrExpandMissing)
+	assert.Contains(t, err.Error(), `"name"`)
+}
+
+func TestExpandSyntax(t *testing.T) {
+	for _, template := range []string{"{", "${name", "open {name", "{}", "${}", "{a b}", "{a{b}", "$${name"} {
+		_, err := Expand(template, nil)
+		assert.ErrorIs(t, err, ErrExpandSyntax, template)
+	}
+}
+
+func TestExpandFunc(t *testing.T) {
+	got, err := ExpandFunc("${a}-{b}-{c}", func(name string) (string, bool) {
+		if name == "c" {
+			return "", false
+		}
+		return strings.ToUpper(name), true
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "A-B-{c}", got)
+}
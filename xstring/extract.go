@@ -0,0 +1,107 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import "strings"
+
+// Before returns the part of s before the first occurrence of sep.
+// s is returned if sep does not occur in s, and an empty string if sep is empty.
+func Before(s, sep string) string {
+	if sep == empty {
+		return empty
+	}
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i]
+	}
+
+	return s
+}
+
+// After returns the part of s after the first occurrence of sep.
+// An empty string is returned if sep does not occur in s, and s if sep is empty.
+func After(s, sep string) string {
+	if sep == empty {
+		return s
+	}
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[i+len(sep):]
+	}
+
+	return empty
+}
+
+// BeforeLast returns the part of s before the last occurrence of sep.
+// s is returned if sep is empty or does not occur in s.
+func BeforeLast(s, sep string) string {
+	if sep == empty {
+		return s
+	}
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i]
+	}
+
+	return s
+}
+
+// AfterLast returns the part of s after the last occurrence of sep.
+// An empty string is returned if sep is empty or does not occur in s.
+func AfterLast(s, sep string) string {
+	if sep == empty {
+		return empty
+	}
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[i+len(sep):]
+	}
+
+	return empty
+}
+
+// Between returns the part of s between the first occurrence of open and the first
+// occurrence of close after it, e.g. Between("f(x, y)", "(", ")") returns "x, y".
+// ok is false if either is missing.
+func Between(s, open, close string) (between string, ok bool) {
+	start := strings.Index(s, open)
+	if start < 0 {
+		return empty, false
+	}
+	start += len(open)
+	end := strings.Index(s[start:], close)
+	if end < 0 {
+		return empty, false
+	}
+
+	return s[start : start+end], true
+}
+
+// AllBetween returns every part of s between open and close, scanning from left
+// to right without overlaps, e.g. AllBetween("[a][b]", "[", "]") returns ["a" "b"].
+// open and close must not be empty; nil is returned if they are.
+func AllBetween(s, open, close string) []string {
+	if open == empty || close == empty {
+		return nil
+	}
+
+	var all []string
+	for {
+		between, ok := Between(s, open, close)
+		if !ok {
+			return all
+		}
+		all = append(all, between)
+		s = s[strings.Index(s, open)+len(open)+len(between)+len(close):]
+	}
+}
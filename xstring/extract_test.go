@@ -0,0 +1,75 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBeforeAfter(t *testing.T) {
+	tests := []struct {
+		s, sep                               string
+		before, after, beforeLast, afterLast string
+	}{
+		{"user@mail@example.com", "@", "user", "mail@example.com", "user@mail", "example.com"},
+		{"a.b.c", ".", "a", "b.c", "a.b", "c"},
+		{"abc", "x", "abc", "", "abc", ""},
+		{"abc", "", "", "abc", "abc", ""},
+		{"", "x", "", "", "", ""},
+		{"key=", "=", "key", "", "key", ""},
+		{"=value", "=", "", "value", "", "value"},
+		{"a::b::c", "::", "a", "b::c", "a::b", "c"},
+		{"中文：标题：内容", "：", "中文", "标题：内容", "中文：标题", "内容"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.before, Before(tt.s, tt.sep), "Before(%q, %q)", tt.s, tt.sep)
+		assert.Equal(t, tt.after, After(tt.s, tt.sep), "After(%q, %q)", tt.s, tt.sep)
+		assert.Equal(t, tt.beforeLast, BeforeLast(tt.s, tt.sep), "BeforeLast(%q, %q)", tt.s, tt.sep)
+		assert.Equal(t, tt.afterLast, AfterLast(tt.s, tt.sep), "AfterLast(%q, %q)", tt.s, tt.sep)
+	}
+}
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		s, open, close string
+		want           string
+		ok             bool
+	}{
+		{"f(x, y)", "(", ")", "x, y", true},
+		{"f()", "(", ")", "", true},
+		{"<b>bold</b>", "<b>", "</b>", "bold", true},
+		{"a)b(c", "(", ")", "", false},
+		{"no parens", "(", ")", "", false},
+		{"((nested))", "(", ")", "(nested", true},
+		{"'quoted'", "'", "'", "quoted", true},
+		{"abc", "", "", "", true},
+	}
+	for _, tt := range tests {
+		got, ok := Between(tt.s, tt.open, tt.close)
+		assert.Equal(t, tt.ok, ok, "Between(%q, %q, %q)", tt.s, tt.open, tt.close)
+		assert.Equal(t, tt.want, got, "Between(%q, %q, %q)", tt.s, tt.open, tt.close)
+	}
+}
+
+func TestAllBetween(t *testing.T) {
+	assert.Equal(t, []string{"a", "b", ""}, AllBetween("[a][b] [] [c", "[", "]"))
+	assert.Equal(t, []string{"name", "id"}, AllBetween("Hi {{name}}, #{{id}}", "{{", "}}"))
+	assert.Equal(t, []string{"x", "y"}, AllBetween("'x' and 'y'", "'", "'"))
+	assert.Nil(t, AllBetween("abc", "[", "]"))
+	assert.Nil(t, AllBetween("abc", "", "]"))
+}
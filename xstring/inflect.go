@@ -0,0 +1,284 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+var defaultInflector = NewInflector()
+
+type (
+	// Inflector converts English words between their singular and plural forms.
+	// It is safe for concurrent use, rules may be added at any time.
+	Inflector struct {
+		mu           sync.RWMutex
+		plurals      []inflectRule
+		singulars    []inflectRule
+		irregularPl  map[string]string // singular -> plural
+		irregularSg  map[string]string // plural -> singular
+		uncountables map[string]bool
+	}
+
+	inflectRule struct {
+		re          *regexp.Regexp
+		replacement string
+	}
+)
+
+// NewInflector returns an Inflector with the common English rules, irregular words and uncountable words.
+func NewInflector() *Inflector {
+	in := &Inflector{
+		irregularPl:  make(map[string]string),
+		irregularSg:  make(map[string]string),
+		uncountables: make(map[string]bool),
+	}
+
+	for _, r := range [][2]string{
+		{"$", "s"},
+		{"s$", "s"},
+		{"^(ax|test)is$", "${1}es"},
+		{"(octop|vir)us$", "${1}i"},
+		{"(octop|vir)i$", "${1}i"},
+		{"(alias|status|campus)$", "${1}es"},
+		{"(bu)s$", "${1}ses"},
+		{"(buffal|tomat|potat|her|ech)o$", "${1}oes"},
+		{"([ti])um$", "${1}a"},
+		{"([ti])a$", "${1}a"},
+		{"sis$", "ses"},
+		{"(?:([^f])fe|([lr])f)$", "${1}${2}ves"},
+		{"(hive)$", "${1}s"},
+		{"([^aeiouy]|qu)y$", "${1}ies"},
+		{"(x|ch|ss|sh)$", "${1}es"},
+		{"(matr|vert|ind)(?:ix|ex)$", "${1}ices"},
+		{"^(m|l)ouse$", "${1}ice"},
+		{"^(m|l)ice$", "${1}ice"},
+		{"^(ox)$", "${1}en"},
+		{"^(oxen)$", "${1}"},
+		{"(quiz)$", "${1}zes"},
+	} {
+		in.AddPlural(r[0], r[1])
+	}
+
+	for _, r := range [][2]string{
+		{"s$", ""},
+		{"(ss)$", "${1}"},
+		{"(n)ews$", "${1}ews"},
+		{"([ti])a$", "${1}um"},
+		{"((a)naly|(b)a|(d)iagno|(p)arenthe|(p)rogno|(s)ynop|(t)he)(sis|ses)$", "${1}sis"},
+		{"(^analy)(sis|ses)$", "${1}sis"},
+		{"([^f])ves$", "${1}fe"},
+		{"(hive)s$", "${1}"},
+		{"(tive)s$", "${1}"},
+		{"([lr])ves$", "${1}f"},
+		{"([^aeiouy]|qu)ies$", "${1}y"},
+		{"(s)eries$", "${1}eries"},
+		{"(m)ovies$", "${1}ovie"},
+		{"(x|ch|ss|sh)es$", "${1}"},
+		{"^(m|l)ice$", "${1}ouse"},
+		{"(bus)(es)?$", "${1}"},
+		{"(o)es$", "${1}"},
+		{"(shoe)s$", "${1}"},
+		{"(cris|test)(is|es)$", "${1}is"},
+		{"^(a)x[ie]s$", "${1}xis"},
+		{"(octop|vir)(us|i)$", "${1}us"},
+		{"(alias|status|campus)(es)?$", "${1}"},
+		{"^(ox)en", "${1}"},
+		{"(vert|ind)ices$", "${1}ex"},
+		{"(matr)ices$", "${1}ix"},
+		{"(quiz)zes$", "${1}"},
+		{"(database)s$", "${1}"},
+	} {
+		in.AddSingular(r[0], r[1])
+	}
+
+	for _, w := range [][2]string{
+		{"person", "people"},
+		{"man", "men"},
+		{"woman", "women"},
+		{"child", "children"},
+		{"tooth", "teeth"},
+		{"foot", "feet"},
+		{"goose", "geese"},
+		{"sex", "sexes"},
+		{"move", "moves"},
+		{"zombie", "zombies"},
+		{"cactus", "cacti"},
+	} {
+		in.AddIrregular(w[0], w[1])
+	}
+
+	in.AddUncountable("equipment", "information", "rice", "money", "species", "series",
+		"fish", "sheep", "deer", "jeans", "police", "news", "metadata", "feedback")
+
+	return in
+}
+
+// AddPlural adds a rule turning singular words matching the regular expression pattern
+// into their plural by replacing the match with replacement, which may refer to
+// submatches like regexp.Regexp.ReplaceAllString. Matching ignores case, and rules
+// added later take precedence. It panics if pattern does not compile.
+func (in *Inflector) AddPlural(pattern, replacement string) {
+	rule := inflectRule{re: regexp.MustCompile("(?i)" + pattern), replacement: replacement}
+	in.mu.Lock()
+	in.plurals = append(in.plurals, rule)
+	in.mu.Unlock()
+}
+
+// AddSingular is like AddPlural for a rule turning plural words into their singular.
+func (in *Inflector) AddSingular(pattern, replacement string) {
+	rule := inflectRule{re: regexp.MustCompile("(?i)" + pattern), replacement: replacement}
+	in.mu.Lock()
+	in.singulars = append(in.singulars, rule)
+	in.mu.Unlock()
+}
+
+// AddIrregular adds a word whose plural does not follow any rule, e.g. "person" and "people".
+func (in *Inflector) AddIrregular(singular, plural string) {
+	singular, plural = strings.ToLower(singular), strings.ToLower(plural)
+	in.mu.Lock()
+	in.irregularPl[singular] = plural
+	in.irregularSg[plural] = singular
+	in.mu.Unlock()
+}
+
+// AddUncountable adds words that are the same in singular and plural, e.g. "sheep".
+func (in *Inflector) AddUncountable(words ...string) {
+	in.mu.Lock()
+	for _, w := range words {
+		in.uncountables[strings.ToLower(w)] = true
+	}
+	in.mu.Unlock()
+}
+
+// Plural returns the plural form of word.
+// Only the last word of compounds such as "sales_person" or "SalesPerson" is inflected,
+// and the case of word is kept.
+func (in *Inflector) Plural(word string) string {
+	return in.inflect(word, in.irregularPl, func() []inflectRule { return in.plurals })
+}
+
+// Singular returns the singular form of word, see Plural.
+func (in *Inflector) Singular(word string) string {
+	return in.inflect(word, in.irregularSg, func() []inflectRule { return in.singulars })
+}
+
+// Pluralize returns word if n is 1 or -1, and its plural form otherwise.
+func (in *Inflector) Pluralize(word string, n int) string {
+	if n == 1 || n == -1 {
+		return word
+	}
+
+	return in.Plural(word)
+}
+
+func (in *Inflector) inflect(word string, irregular map[string]string, rules func() []inflectRule) string {
+	head, last := splitLastWord(word)
+	if last == empty {
+		return word
+	}
+
+	in.mu.RLock()
+	defer in.mu.RUnlock()
+
+	lower := strings.ToLower(last)
+	if in.uncountables[lower] {
+		return word
+	}
+	if w, ok := irregular[lower]; ok {
+		return head + matchCase(w, last)
+	}
+	rs := rules()
+	for i := len(rs) - 1; i >= 0; i-- {
+		if rs[i].re.MatchString(last) {
+			return head + matchCase(rs[i].re.ReplaceAllString(last, rs[i].replacement), last)
+		}
+	}
+
+	return word
+}
+
+// splitLastWord splits s before its last word, which starts after the last
+// separator ('_', '-' or a space) or at the last upper case letter following a lower case one.
+func splitLastWord(s string) (string, string) {
+	start := 0
+	prevLower := false
+	for i, r := range s {
+		switch {
+		case r == '_' || r == '-' || unicode.IsSpace(r):
+			start = i + utf8.RuneLen(r)
+		case unicode.IsUpper(r) && prevLower:
+			start = i
+		}
+		prevLower = unicode.IsLower(r)
+	}
+
+	return s[:start], s[start:]
+}
+
+// matchCase returns word in upper case if like is in upper case (and longer than one
+// letter), or with its first letter in upper case if like starts with one.
+func matchCase(word, like string) string {
+	if like == empty {
+		return word
+	}
+	if len(like) > 1 && strings.ToUpper(like) == like {
+		return strings.ToUpper(word)
+	}
+	r, _ := utf8.DecodeRuneInString(like)
+	if unicode.IsUpper(r) {
+		first, size := utf8.DecodeRuneInString(word)
+		return string(unicode.ToUpper(first)) + word[size:]
+	}
+
+	return word
+}
+
+// Pluralize returns word if n is 1 or -1 and its plural form otherwise, using the default rules.
+// Rules can be added with AddPluralRule, AddSingularRule, AddIrregular and AddUncountable.
+func Pluralize(word string, n int) string {
+	return defaultInflector.Pluralize(word, n)
+}
+
+// Singularize returns the singular form of word using the default rules.
+func Singularize(word string) string {
+	return defaultInflector.Singular(word)
+}
+
+// AddPluralRule adds a plural rule to the default rules, see Inflector.AddPlural.
+func AddPluralRule(pattern, replacement string) {
+	defaultInflector.AddPlural(pattern, replacement)
+}
+
+// AddSingularRule adds a singular rule to the default rules, see Inflector.AddSingular.
+func AddSingularRule(pattern, replacement string) {
+	defaultInflector.AddSingular(pattern, replacement)
+}
+
+// AddIrregular adds an irregular word to the default rules, see Inflector.AddIrregular.
+func AddIrregular(singular, plural string) {
+	defaultInflector.AddIrregular(singular, plural)
+}
+
+// AddUncountable adds uncountable words to the default rules, see Inflector.AddUncountable.
+func AddUncountable(words ...string) {
+	defaultInflector.AddUncountable(words...)
+}
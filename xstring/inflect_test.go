@@ -0,0 +1,125 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var inflections = [][2]string{
+	{"user", "users"},
+	{"box", "boxes"},
+	{"church", "churches"},
+	{"wish", "wishes"},
+	{"class", "classes"},
+	{"category", "categories"},
+	{"day", "days"},
+	{"query", "queries"},
+	{"knife", "knives"},
+	{"wolf", "wolves"},
+	{"half", "halves"},
+	{"analysis", "analyses"},
+	{"crisis", "crises"},
+	{"axis", "axes"},
+	{"datum", "data"},
+	{"medium", "media"},
+	{"octopus", "octopi"},
+	{"status", "statuses"},
+	{"alias", "aliases"},
+	{"bus", "buses"},
+	{"tomato", "tomatoes"},
+	{"photo", "photos"},
+	{"matrix", "matrices"},
+	{"vertex", "vertices"},
+	{"index", "indices"},
+	{"mouse", "mice"},
+	{"ox", "oxen"},
+	{"quiz", "quizzes"},
+	{"hive", "hives"},
+	{"movie", "movies"},
+	{"shoe", "shoes"},
+	{"database", "databases"},
+	{"person", "people"},
+	{"child", "children"},
+	{"tooth", "teeth"},
+	{"man", "men"},
+	{"human", "humans"},
+	{"sheep", "sheep"},
+	{"news", "news"},
+	{"information", "information"},
+	{"sales_person", "sales_people"},
+	{"SalesPerson", "SalesPeople"},
+	{"user_profile", "user_profiles"},
+	{"Person", "People"},
+	{"PERSON", "PEOPLE"},
+	{"Category", "Categories"},
+}
+
+func TestPluralize(t *testing.T) {
+	for _, tt := range inflections {
+		assert.Equal(t, tt[1], Pluralize(tt[0], 2), tt[0])
+		assert.Equal(t, tt[1], Pluralize(tt[0], 0), tt[0])
+		assert.Equal(t, tt[0], Pluralize(tt[0], 1), tt[0])
+		assert.Equal(t, tt[0], Pluralize(tt[0], -1), tt[0])
+	}
+	assert.Equal(t, "", Pluralize("", 2))
+	assert.Equal(t, "item_", Pluralize("item_", 2))
+}
+
+func TestSingularize(t *testing.T) {
+	for _, tt := range inflections {
+		if tt[0] == "axis" {
+			continue // "axes" is also the plural of "axe"
+		}
+		assert.Equal(t, tt[0], Singularize(tt[1]), tt[1])
+	}
+}
+
+func TestInflectorCustomRules(t *testing.T) {
+	in := NewInflector()
+	assert.Equal(t, "cacti", in.Plural("cactus"))
+
+	in.AddIrregular("foo", "fooz")
+	in.AddUncountable("firmware")
+	in.AddPlural("(c)ode$", "${1}odez")
+	in.AddSingular("(c)odez$", "${1}ode")
+
+	assert.Equal(t, "fooz", in.Plural("foo"))
+	assert.Equal(t, "Foo", in.Singular("Fooz"))
+	assert.Equal(t, "firmware", in.Plural("firmware"))
+	assert.Equal(t, "codez", in.Plural("code"))
+	assert.Equal(t, "code", in.Singular("codez"))
+
+	// The default rules are unaffected.
+	assert.Equal(t, "codes", Pluralize("code", 2))
+	assert.Equal(t, "foos", Pluralize("foo", 2))
+
+	assert.Panics(t, func() { in.AddPlural("(", "") })
+}
+
+func TestDefaultInflectorRules(t *testing.T) {
+	AddUncountable("xstringtest")
+	AddIrregular("xstringfoot", "xstringfeet")
+	AddPluralRule("(xstring)um$", "${1}a")
+	AddSingularRule("(xstring)a$", "${1}um")
+
+	assert.Equal(t, "xstringtest", Pluralize("xstringtest", 2))
+	assert.Equal(t, "xstringfeet", Pluralize("xstringfoot", 2))
+	assert.Equal(t, "xstringa", Pluralize("xstringum", 2))
+	assert.Equal(t, "xstringum", Singularize("xstringa"))
+}
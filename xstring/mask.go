@@ -0,0 +1,135 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// MaskRune is the rune used by MaskEmail, MaskPhone and MaskBankCard.
+const MaskRune = '*'
+
+// Mask replaces every rune of s in [start, end) with maskRune.
+// start and end are rune offsets and are clamped to the bounds of s, so Mask never panics.
+func Mask(s string, start, end int, maskRune Char) string {
+	if start < 0 {
+		start = 0
+	}
+	if start >= end || s == empty {
+		return s
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(s))
+	i := 0
+	for _, r := range s {
+		if i >= start && i < end {
+			sb.WriteRune(maskRune)
+		} else {
+			sb.WriteRune(r)
+		}
+		i++
+	}
+
+	return sb.String()
+}
+
+// MaskEmail masks the local part of an email address, keeping only its first and
+// last rune and the domain: "john.doe@example.com" becomes "j******e@example.com".
+// Local parts of one or two runes keep just the first rune (or none for one).
+// If s has no '@', all but its first rune is masked.
+func MaskEmail(s string) string {
+	at := strings.LastIndexByte(s, '@')
+	if at < 0 {
+		return Mask(s, 1, Len(s), MaskRune)
+	}
+
+	local := s[:at]
+	n := utf8.RuneCountInString(local)
+	switch {
+	case n <= 1:
+		local = Mask(local, 0, n, MaskRune)
+	case n == 2:
+		local = Mask(local, 1, n, MaskRune)
+	default:
+		local = Mask(local, 1, n-1, MaskRune)
+	}
+
+	return local + s[at:]
+}
+
+// MaskPhone masks the digits of a phone number, keeping the first 3 and the last 4
+// ("13812345678" becomes "138****5678"). Numbers with fewer than 8 digits keep only
+// the last 2. Non-digit runes such as '+', '-' and spaces are preserved.
+func MaskPhone(s string) string {
+	n := countDigits(s)
+	if n < 8 {
+		return maskDigits(s, 0, 2, n)
+	}
+
+	return maskDigits(s, 3, 4, n)
+}
+
+// MaskBankCard masks the digits of a bank card number, keeping the first 6 (the issuer
+// identification number) and the last 4 ("6222021234567890" becomes "622202******7890").
+// Numbers with fewer than 12 digits keep only the last 4.
+// Non-digit runes such as spaces and '-' are preserved.
+func MaskBankCard(s string) string {
+	n := countDigits(s)
+	if n < 12 {
+		return maskDigits(s, 0, 4, n)
+	}
+
+	return maskDigits(s, 6, 4, n)
+}
+
+func isASCIIDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func countDigits(s string) int {
+	n := 0
+	for _, r := range s {
+		if isASCIIDigit(r) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// maskDigits masks all of the n digits in s except the first keepFirst and the last keepLast.
+func maskDigits(s string, keepFirst, keepLast, n int) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+	i := 0
+	for _, r := range s {
+		if !isASCIIDigit(r) {
+			sb.WriteRune(r)
+			continue
+		}
+		if i >= keepFirst && i < n-keepLast {
+			sb.WriteRune(MaskRune)
+		} else {
+			sb.WriteRune(r)
+		}
+		i++
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,70 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMask(t *testing.T) {
+	assert.Equal(t, "ab**ef", Mask("abcdef", 2, 4, '*'))
+	assert.Equal(t, "******", Mask("abcdef", -3, 100, '*'))
+	assert.Equal(t, "abcdef", Mask("abcdef", 4, 2, '*'))
+	assert.Equal(t, "abcdef", Mask("abcdef", 6, 10, '*'))
+	assert.Equal(t, "", Mask("", 0, 1, '*'))
+	assert.Equal(t, "张##", Mask("张三丰", 1, 3, '#'))
+}
+
+func TestMaskEmail(t *testing.T) {
+	tests := map[string]string{
+		"john.doe@example.com": "j******e@example.com",
+		"ab@example.com":       "a*@example.com",
+		"a@example.com":        "*@example.com",
+		"@example.com":         "@example.com",
+		"张三丰@example.cn":       "张*丰@example.cn",
+		"a\"b@c\"@example.com": "a****\"@example.com",
+		"not-an-email":         "n***********",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, MaskEmail(in), in)
+	}
+}
+
+func TestMaskPhone(t *testing.T) {
+	tests := map[string]string{
+		"13812345678":       "138****5678",
+		"+86 138-1234-5678": "+86 1**-****-5678",
+		"1234567":           "*****67",
+		"":                  "",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, MaskPhone(in), in)
+	}
+}
+
+func TestMaskBankCard(t *testing.T) {
+	tests := map[string]string{
+		"6222021234567890":    "622202******7890",
+		"6222 0212 3456 7890": "6222 02** **** 7890",
+		"12345678":            "****5678",
+		"abc":                 "abc",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, MaskBankCard(in), in)
+	}
+}
@@ -0,0 +1,129 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"slices"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NaturalCompare compares a and b in natural order, in which runs of ASCII digits
+// compare by their numeric value, so "file2" sorts before "file10".
+// Everything else compares rune by rune, without any locale rules.
+// Numbers that only differ in leading zeros ("01" and "1") compare by the number of
+// zeros, so the result is 0 only if a == b.
+func NaturalCompare(a, b string) int {
+	return naturalCompare(a, b, false)
+}
+
+// NaturalCompareFold is like NaturalCompare, but ignores case with Unicode simple case folding.
+// It returns 0 for strings that only differ in case or leading zeros.
+func NaturalCompareFold(a, b string) int {
+	return naturalCompare(a, b, true)
+}
+
+// SortNatural sorts s in increasing natural order, see NaturalCompare.
+func SortNatural(s []string) {
+	slices.SortFunc(s, NaturalCompare)
+}
+
+// SortNaturalFold sorts s in increasing case-insensitive natural order, see NaturalCompareFold.
+// The sort is stable, so strings that only differ in case keep their order.
+func SortNaturalFold(s []string) {
+	slices.SortStableFunc(s, NaturalCompareFold)
+}
+
+func naturalCompare(a, b string, fold bool) int {
+	zeros := 0 // tie-breaker for numbers differing in leading zeros only
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if isASCIIDigit(rune(a[i])) && isASCIIDigit(rune(b[j])) {
+			si, sj := i, j
+			for i < len(a) && a[i] == '0' {
+				i++
+			}
+			for j < len(b) && b[j] == '0' {
+				j++
+			}
+			zi, zj := i-si, j-sj
+			ni, nj := i, j
+			for i < len(a) && isASCIIDigit(rune(a[i])) {
+				i++
+			}
+			for j < len(b) && isASCIIDigit(rune(b[j])) {
+				j++
+			}
+			// Without leading zeros, a longer number is greater.
+			if c := (i - ni) - (j - nj); c != 0 {
+				return sign(c)
+			}
+			for k := 0; k < i-ni; k++ {
+				if c := int(a[ni+k]) - int(b[nj+k]); c != 0 {
+					return sign(c)
+				}
+			}
+			if zeros == 0 && zi != zj {
+				zeros = sign(zi - zj)
+			}
+			continue
+		}
+
+		ra, sa := utf8.DecodeRuneInString(a[i:])
+		rb, sb := utf8.DecodeRuneInString(b[j:])
+		if fold {
+			ra, rb = foldRune(ra), foldRune(rb)
+		}
+		if ra != rb {
+			return sign(int(ra) - int(rb))
+		}
+		i += sa
+		j += sb
+	}
+
+	switch {
+	case len(a)-i != len(b)-j:
+		return sign((len(a) - i) - (len(b) - j))
+	case fold:
+		return 0
+	}
+
+	return zeros
+}
+
+// foldRune returns the smallest rune equivalent to r under simple case folding.
+func foldRune(r rune) rune {
+	min := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < min {
+			min = f
+		}
+	}
+
+	return min
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,70 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func TestNaturalCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"a", "", 1},
+		{"", "a", -1},
+		{"file2", "file10", -1},
+		{"file10", "file2", 1},
+		{"file10", "file10", 0},
+		{"a1b2", "a1b10", -1},
+		{"x9", "x09", -1},
+		{"x09", "x9", 1},
+		{"x009a", "x9b", -1},
+		{"1.2.10", "1.2.9", 1},
+		{"abc", "abd", -1},
+		{"a", "B", 1},
+		{"img12.png", "img12.jpg", 1},
+		{"99999999999999999999999", "100000000000000000000000", -1},
+		{"文件2", "文件10", -1},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, NaturalCompare(tt.a, tt.b), "%q %q", tt.a, tt.b)
+	}
+}
+
+func TestNaturalCompareFold(t *testing.T) {
+	assert.Equal(t, 0, NaturalCompareFold("File10", "file10"))
+	assert.Equal(t, -1, NaturalCompareFold("a", "B"))
+	assert.Equal(t, -1, NaturalCompareFold("FILE2", "file10"))
+	assert.Equal(t, 0, NaturalCompareFold("Straße7", "STRAßE07"))
+	assert.Equal(t, 1, NaturalCompareFold("b", "A"))
+}
+
+func TestSortNatural(t *testing.T) {
+	want := []string{"", "1", "01", "2", "10", "file1.txt", "file2.txt", "file10.txt", "file10a.txt", "v1.9.0", "v1.10.0"}
+	got := append([]string(nil), want...)
+	rand.New(rand.NewSource(1)).Shuffle(len(got), func(i, j int) { got[i], got[j] = got[j], got[i] })
+	SortNatural(got)
+	assert.Equal(t, want, got)
+
+	got = []string{"b10", "B2", "a", "A", "b1"}
+	SortNaturalFold(got)
+	assert.Equal(t, []string{"a", "A", "b1", "B2", "b10"}, got)
+}
@@ -0,0 +1,89 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+	"io"
+	"unicode"
+)
+
+// Unicode normalization forms, see https://unicode.org/reports/tr15/.
+const (
+	// NFC is the canonical composition, the form most text is already in.
+	NFC NormForm = iota
+	// NFD is the canonical decomposition.
+	NFD
+	// NFKC is the compatibility composition, which also folds e.g. "ﬁ" to "fi" and "①" to "1".
+	NFKC
+	// NFKD is the compatibility decomposition.
+	NFKD
+)
+
+// NormForm is a Unicode normalization form.
+type NormForm int
+
+func (f NormForm) form() norm.Form {
+	switch f {
+	case NFD:
+		return norm.NFD
+	case NFKC:
+		return norm.NFKC
+	case NFKD:
+		return norm.NFKD
+	}
+
+	return norm.NFC
+}
+
+// Normalize returns s in the normalization form f, so that canonically equivalent
+// strings, such as "é" written as one or as two code points, compare equal.
+func Normalize(s string, f NormForm) string {
+	return f.form().String(s)
+}
+
+// NormalizeReader returns a reader that normalizes the text read from r to the form f.
+func NormalizeReader(r io.Reader, f NormForm) io.Reader {
+	return f.form().Reader(r)
+}
+
+// RemoveAccents removes diacritics from s, so that "café" becomes "cafe" and
+// "Ångström" becomes "Angstrom". The result is in NFC form.
+// Letters without a decomposition, such as "ø" and "ß", are kept; see Slugify for
+// an ASCII transliteration.
+func RemoveAccents(s string) string {
+	out, _, err := transform.String(removeAccents(), s)
+	if err != nil {
+		// The transformers never fail on a string.
+		return s
+	}
+
+	return out
+}
+
+// RemoveAccentsReader returns a reader that removes diacritics from the text read from r,
+// like RemoveAccents, without loading it into memory.
+func RemoveAccentsReader(r io.Reader) io.Reader {
+	return transform.NewReader(r, removeAccents())
+}
+
+// removeAccents returns a new transformer as transform.Chain is stateful.
+func removeAccents() transform.Transformer {
+	return transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+}
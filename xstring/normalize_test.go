@@ -0,0 +1,65 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestNormalize(t *testing.T) {
+	composed, decomposed := "café", "cafe\u0301"
+	assert.NotEqual(t, composed, decomposed)
+	assert.Equal(t, composed, Normalize(decomposed, NFC))
+	assert.Equal(t, decomposed, Normalize(composed, NFD))
+	assert.Equal(t, "fi1", Normalize("ﬁ①", NFKC))
+	assert.Equal(t, "ﬁ①", Normalize("ﬁ①", NFC))
+	assert.Equal(t, "e\u0301", Normalize("é", NFKD))
+
+	got, err := io.ReadAll(NormalizeReader(strings.NewReader(decomposed), NFC))
+	assert.NoError(t, err)
+	assert.Equal(t, composed, string(got))
+}
+
+func TestRemoveAccents(t *testing.T) {
+	tests := map[string]string{
+		"":             "",
+		"plain":        "plain",
+		"café":         "cafe",
+		"cafe\u0301":   "cafe",
+		"Ångström":     "Angstrom",
+		"Tiếng Việt":   "Tieng Viet",
+		"naïve façade": "naive facade",
+		"ø ß 中文":       "ø ß 中文",
+		"Ελληνικά":     "Ελληνικα",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, RemoveAccents(in), in)
+	}
+	assert.Equal(t, RemoveAccents("café"), RemoveAccents("cafe\u0301"))
+}
+
+func TestRemoveAccentsReader(t *testing.T) {
+	in := strings.Repeat("Crème brûlée, déjà vu. ", 5000)
+	want := strings.Repeat("Creme brulee, deja vu. ", 5000)
+	got, err := io.ReadAll(RemoveAccentsReader(iotest.OneByteReader(strings.NewReader(in))))
+	assert.NoError(t, err)
+	assert.Equal(t, want, string(got))
+}
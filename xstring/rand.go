@@ -0,0 +1,143 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	crand "crypto/rand"
+	"github.com/chenquan/go-pkg/internal/hack"
+	"math/bits"
+	"math/rand"
+)
+
+// Charsets for use with WithRandCharset.
+const (
+	CharsetNumeric      = "0123456789"
+	CharsetLowerLetters = "abcdefghijklmnopqrstuvwxyz"
+	CharsetUpperLetters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	CharsetLetters      = CharsetLowerLetters + CharsetUpperLetters
+	CharsetAlphanumeric = CharsetNumeric + CharsetLetters
+	CharsetHex          = "0123456789abcdef"
+	// CharsetURLSafe is the alphabet of base64.RawURLEncoding, safe in URLs and file names.
+	CharsetURLSafe = CharsetUpperLetters + CharsetLowerLetters + CharsetNumeric + "-_"
+)
+
+type (
+	// RandOption customizes RandString.
+	RandOption func(o *randOptions)
+
+	randOptions struct {
+		charset string
+		// source is nil for crypto/rand.
+		source *rand.Rand
+		fast   bool
+	}
+)
+
+// WithRandCharset sets the alphabet RandString picks from, CharsetAlphanumeric by default.
+// The charset must be non-empty ASCII; every byte is picked with the same probability.
+func WithRandCharset(charset string) RandOption {
+	if charset == empty {
+		panic("xstring: empty rand charset")
+	}
+	for i := 0; i < len(charset); i++ {
+		if charset[i] >= 0x80 {
+			panic("xstring: rand charset must be ASCII")
+		}
+	}
+
+	return func(o *randOptions) {
+		o.charset = charset
+	}
+}
+
+// WithRandSource makes RandString draw from r instead of crypto/rand.
+// It is much faster and reproducible for a seeded r, but must not be used for secrets.
+// Like r itself, it is not safe for concurrent use.
+func WithRandSource(r *rand.Rand) RandOption {
+	return func(o *randOptions) {
+		o.source, o.fast = r, false
+	}
+}
+
+// WithFastRand makes RandString draw from the global math/rand source instead of crypto/rand.
+// It must not be used for secrets.
+func WithFastRand() RandOption {
+	return withFastRand
+}
+
+func withFastRand(o *randOptions) {
+	o.source, o.fast = nil, true
+}
+
+// RandString returns a random string of n bytes from the charset given by WithRandCharset.
+// By default it reads from crypto/rand, so the result is suitable for tokens and
+// passwords; use WithRandSource or WithFastRand when it does not need to be unpredictable.
+// It panics if crypto/rand fails.
+func RandString(n int, opts ...RandOption) string {
+	if n <= 0 {
+		return empty
+	}
+
+	o := randOptions{charset: CharsetAlphanumeric}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	// Rejection sampling over the smallest bit mask covering the charset keeps the
+	// distribution uniform. Random bytes are read straight into out and accepted
+	// ones are compacted to its front, so out is the only allocation.
+	mask := byte(1)<<bits.Len8(uint8(len(o.charset)-1)) - 1
+	out := make([]byte, n)
+	filled := 0
+	for filled < n {
+		o.read(out[filled:])
+		for _, b := range out[filled:] {
+			if idx := int(b & mask); idx < len(o.charset) {
+				out[filled] = o.charset[idx]
+				filled++
+			}
+		}
+	}
+
+	return hack.BytesToString(out)
+}
+
+func (o *randOptions) read(p []byte) {
+	switch {
+	case o.source != nil:
+		fillInt63(p, o.source.Int63)
+	case o.fast:
+		fillInt63(p, rand.Int63)
+	default:
+		// a failing system random source can't be recovered from, never return predictable bytes.
+		if _, err := crand.Read(p); err != nil {
+			panic("xstring: crypto/rand failed: " + err.Error())
+		}
+	}
+}
+
+// fillInt63 fills p with 7 bytes from every call to int63.
+func fillInt63(p []byte, int63 func() int64) {
+	for len(p) > 0 {
+		v := int63()
+		for i := 0; i < 7 && len(p) > 0; i++ {
+			p[0] = byte(v)
+			p = p[1:]
+			v >>= 8
+		}
+	}
+}
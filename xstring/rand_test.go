@@ -0,0 +1,88 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestRandString(t *testing.T) {
+	assert.Equal(t, "", RandString(0))
+	assert.Equal(t, "", RandString(-1))
+
+	s := RandString(100)
+	assert.Len(t, s, 100)
+	assertCharset(t, CharsetAlphanumeric, s)
+	assert.NotEqual(t, s, RandString(100))
+
+	for _, charset := range []string{CharsetNumeric, CharsetHex, CharsetURLSafe, CharsetLetters, "ab", "x"} {
+		s := RandString(200, WithRandCharset(charset))
+		assert.Len(t, s, 200)
+		assertCharset(t, charset, s)
+	}
+
+	s = RandString(300, WithFastRand(), WithRandCharset(CharsetHex))
+	assert.Len(t, s, 300)
+	assertCharset(t, CharsetHex, s)
+
+	a := RandString(32, WithRandSource(rand.New(rand.NewSource(1))))
+	b := RandString(32, WithRandSource(rand.New(rand.NewSource(1))))
+	assert.Equal(t, a, b)
+	assertCharset(t, CharsetAlphanumeric, a)
+
+	assert.Panics(t, func() { WithRandCharset("") })
+	assert.Panics(t, func() { WithRandCharset("abc中") })
+}
+
+func TestRandStringUniform(t *testing.T) {
+	const n = 62000
+	counts := make(map[rune]int)
+	for _, r := range RandString(n, WithFastRand()) {
+		counts[r]++
+	}
+	assert.Len(t, counts, len(CharsetAlphanumeric))
+	for r, c := range counts {
+		assert.InDelta(t, n/len(CharsetAlphanumeric), c, 300, string(r))
+	}
+}
+
+func assertCharset(t *testing.T, charset, s string) {
+	t.Helper()
+	for _, r := range s {
+		if !strings.ContainsRune(charset, r) {
+			t.Fatalf("%q is not in charset %q", r, charset)
+		}
+	}
+}
+
+func BenchmarkRandString(b *testing.B) {
+	b.Run("crypto", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			RandString(32)
+		}
+	})
+	b.Run("fast", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			RandString(32, WithFastRand())
+		}
+	})
+}
@@ -0,0 +1,207 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+// Levenshtein returns the Levenshtein edit distance between a and b, that is the
+// minimum number of single-rune insertions, deletions and substitutions needed
+// to turn a into b.
+func Levenshtein(a, b string) int {
+	return levenshtein([]rune(a), []rune(b), -1)
+}
+
+// LevenshteinMax is like Levenshtein, but stops as soon as the distance is known
+// to exceed max and returns -1 in that case. It is considerably faster than
+// Levenshtein when only close matches are of interest.
+func LevenshteinMax(a, b string, max int) int {
+	if max < 0 {
+		return indexNotFound
+	}
+
+	return levenshtein([]rune(a), []rune(b), max)
+}
+
+func levenshtein(a, b []rune, max int) int {
+	a, b = trimCommon(a, b)
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	if max >= 0 && len(a)-len(b) > max {
+		return indexNotFound
+	}
+	if len(b) == 0 {
+		return len(a)
+	}
+
+	// Two rows over the shorter string.
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		rowMin := i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, curr[j])
+		}
+		if max >= 0 && rowMin > max {
+			return indexNotFound
+		}
+		prev, curr = curr, prev
+	}
+
+	d := prev[len(b)]
+	if max >= 0 && d > max {
+		return indexNotFound
+	}
+
+	return d
+}
+
+// DamerauLevenshtein returns the optimal string alignment distance between a and b:
+// like Levenshtein, but a transposition of two adjacent runes counts as a single
+// edit, so "teh" is one edit away from "the". A substring is never edited twice.
+func DamerauLevenshtein(a, b string) int {
+	ra, rb := trimCommon([]rune(a), []rune(b))
+	if len(ra) < len(rb) {
+		ra, rb = rb, ra
+	}
+	if len(rb) == 0 {
+		return len(ra)
+	}
+
+	// Three rows: the transposition looks back two rows.
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+
+	return prev[len(rb)]
+}
+
+// JaroWinkler returns the Jaro-Winkler similarity of a and b, between 0 (nothing in
+// common) and 1 (equal). Strings sharing a common prefix of up to four runes score
+// higher, which suits short strings such as names and command words.
+func JaroWinkler(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	sim := jaro(ra, rb)
+	if sim <= 0.7 {
+		return sim
+	}
+
+	prefix := 0
+	for prefix < min(len(ra), len(rb), 4) && ra[prefix] == rb[prefix] {
+		prefix++
+	}
+
+	return sim + float64(prefix)*0.1*(1-sim)
+}
+
+func jaro(a, b []rune) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	window := max(len(a), len(b))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+	matchedA := make([]bool, len(a))
+	matchedB := make([]bool, len(b))
+	matches := 0
+	for i, r := range a {
+		lo, hi := max(0, i-window), min(len(b), i+window+1)
+		for j := lo; j < hi; j++ {
+			if !matchedB[j] && b[j] == r {
+				matchedA[i], matchedB[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions := 0
+	j := 0
+	for i, r := range a {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[j] {
+			j++
+		}
+		if r != b[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	return (m/float64(len(a)) + m/float64(len(b)) + (m-float64(transpositions)/2)/m) / 3
+}
+
+// Similarity returns a normalized similarity of a and b between 0 and 1,
+// computed as 1 - Levenshtein(a, b) / max(len(a), len(b)) in runes.
+// Two empty strings are considered equal.
+func Similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	n := max(len(ra), len(rb))
+	if n == 0 {
+		return 1
+	}
+
+	return 1 - float64(levenshtein(ra, rb, -1))/float64(n)
+}
+
+// trimCommon strips the prefix and suffix shared by a and b, which never
+// contribute to an edit distance.
+func trimCommon(a, b []rune) ([]rune, []rune) {
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		a, b = a[1:], b[1:]
+	}
+	for len(a) > 0 && len(b) > 0 && a[len(a)-1] == b[len(b)-1] {
+		a, b = a[:len(a)-1], b[:len(b)-1]
+	}
+
+	return a, b
+}
@@ -0,0 +1,98 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"gumbo", "gambol", 2},
+		{"same", "same", 0},
+		{"teh", "the", 2},
+		{"中文字符", "中文符号", 2},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Levenshtein(tt.a, tt.b), "%q %q", tt.a, tt.b)
+		assert.Equal(t, tt.want, Levenshtein(tt.b, tt.a), "%q %q", tt.b, tt.a)
+		assert.Equal(t, tt.want, LevenshteinMax(tt.a, tt.b, tt.want))
+		if tt.want > 0 {
+			assert.Equal(t, -1, LevenshteinMax(tt.a, tt.b, tt.want-1))
+		}
+	}
+	assert.Equal(t, -1, LevenshteinMax("a", "b", -1))
+	assert.Equal(t, -1, LevenshteinMax("short", "a much longer string", 3))
+}
+
+func TestDamerauLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"teh", "the", 1},
+		{"ca", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"abcdef", "badcfe", 3},
+		{"你好", "好你", 1},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, DamerauLevenshtein(tt.a, tt.b), "%q %q", tt.a, tt.b)
+		assert.Equal(t, tt.want, DamerauLevenshtein(tt.b, tt.a), "%q %q", tt.b, tt.a)
+	}
+}
+
+func TestJaroWinkler(t *testing.T) {
+	assert.Equal(t, 1.0, JaroWinkler("", ""))
+	assert.Equal(t, 0.0, JaroWinkler("abc", ""))
+	assert.Equal(t, 0.0, JaroWinkler("abc", "xyz"))
+	assert.Equal(t, 1.0, JaroWinkler("martha", "martha"))
+	assert.InDelta(t, 0.961, JaroWinkler("martha", "marhta"), 0.001)
+	assert.InDelta(t, 0.840, JaroWinkler("dwayne", "duane"), 0.001)
+	assert.InDelta(t, 0.813, JaroWinkler("dixon", "dicksonx"), 0.001)
+	assert.Greater(t, JaroWinkler("checkout", "chekcout"), JaroWinkler("checkout", "cherry"))
+}
+
+func TestSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, Similarity("", ""))
+	assert.Equal(t, 1.0, Similarity("abc", "abc"))
+	assert.Equal(t, 0.0, Similarity("abc", ""))
+	assert.InDelta(t, 1-3.0/7, Similarity("kitten", "sitting"), 1e-9)
+}
+
+func BenchmarkLevenshtein(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Levenshtein("kubectl get pods", "kubectl gte pod")
+	}
+}
+
+func BenchmarkLevenshteinMax(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		LevenshteinMax("kubectl get pods", "helm install chart", 2)
+	}
+}
@@ -0,0 +1,138 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"strings"
+	"unicode"
+)
+
+type (
+	// SlugOption customizes Slugify.
+	SlugOption func(o *slugOptions)
+
+	slugOptions struct {
+		separator     string
+		maxLength     int
+		allowUnicode  bool
+		substitutions map[rune]string
+	}
+)
+
+// WithSlugSeparator sets the separator placed between words, "-" by default.
+func WithSlugSeparator(sep string) SlugOption {
+	return func(o *slugOptions) {
+		o.separator = sep
+	}
+}
+
+// WithSlugMaxLength limits the slug to n runes. The slug is cut at the last separator
+// that fits, or in the middle of a word if the first word alone is too long.
+// n <= 0 means no limit, which is the default.
+func WithSlugMaxLength(n int) SlugOption {
+	return func(o *slugOptions) {
+		o.maxLength = n
+	}
+}
+
+// WithSlugAllowUnicode keeps letters and digits that have no ASCII transliteration,
+// such as CJK characters, instead of dropping them.
+func WithSlugAllowUnicode() SlugOption {
+	return func(o *slugOptions) {
+		o.allowUnicode = true
+	}
+}
+
+// WithSlugSubstitutions replaces runes before transliteration,
+// e.g. map[rune]string{'&': "and", '@': "at"}.
+func WithSlugSubstitutions(subs map[rune]string) SlugOption {
+	return func(o *slugOptions) {
+		o.substitutions = subs
+	}
+}
+
+// Slugify turns s into a URL and file name friendly slug: letters are lowercased and
+// transliterated to ASCII ("Crème Brûlée" becomes "creme-brulee"), every run of other
+// characters becomes a single separator, and the slug never starts or ends with one.
+func Slugify(s string, opts ...SlugOption) string {
+	o := slugOptions{separator: "-"}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(s))
+	pendingSep := false
+	// cuts records the byte and rune lengths of the slug before each separator.
+	var cuts [][2]int
+	runes := 0
+	write := func(r rune) {
+		if pendingSep && sb.Len() > 0 {
+			cuts = append(cuts, [2]int{sb.Len(), runes})
+			sb.WriteString(o.separator)
+			runes += Len(o.separator)
+		}
+		pendingSep = false
+		sb.WriteRune(unicode.ToLower(r))
+		runes++
+	}
+	writeString := func(str string) {
+		for _, r := range str {
+			if r < 0x80 && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+				write(r)
+			} else {
+				pendingSep = true
+			}
+		}
+	}
+
+	for _, r := range s {
+		if sub, ok := o.substitutions[r]; ok {
+			// A substitution is a word of its own.
+			pendingSep = true
+			writeString(sub)
+			pendingSep = true
+			continue
+		}
+		switch {
+		case r < 0x80:
+			writeString(string(r))
+		case unicode.Is(unicode.Mn, r):
+			// Combining marks of decomposed letters are dropped.
+		default:
+			if t, ok := transliterate(r); ok {
+				writeString(t)
+			} else if o.allowUnicode && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+				write(r)
+			} else {
+				pendingSep = true
+			}
+		}
+	}
+
+	slug := sb.String()
+	if o.maxLength <= 0 || runes <= o.maxLength {
+		return slug
+	}
+	for i := len(cuts) - 1; i >= 0; i-- {
+		if cuts[i][1] <= o.maxLength {
+			return slug[:cuts[i][0]]
+		}
+	}
+
+	return Left(slug, o.maxLength)
+}
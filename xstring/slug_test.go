@@ -0,0 +1,58 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"":                             "",
+		"Hello World":                  "hello-world",
+		"  Hello,   World!  ":          "hello-world",
+		"Crème Brûlée":                 "creme-brulee",
+		"Straße & Œuvre":               "strasse-oeuvre",
+		"Ærøskøbing":                   "aeroskobing",
+		"Tiếng Việt có dấu":            "tieng-viet-co-dau",
+		"Привет, мир":                  "privet-mir",
+		"Αθήνα":                        "athina",
+		"e\u0301te\u0301":              "ete",
+		"Go 1.23 released -- finally!": "go-1-23-released-finally",
+		"中文标题 Title":                   "title",
+		"---":                          "",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, Slugify(in), in)
+	}
+}
+
+func TestSlugifyOptions(t *testing.T) {
+	assert.Equal(t, "hello_world", Slugify("Hello World", WithSlugSeparator("_")))
+	assert.Equal(t, "helloworld", Slugify("Hello World", WithSlugSeparator("")))
+	assert.Equal(t, "中文标题-title", Slugify("中文标题 Title", WithSlugAllowUnicode()))
+	assert.Equal(t, "tom-and-jerry-at-home", Slugify("Tom&Jerry @ home",
+		WithSlugSubstitutions(map[rune]string{'&': "and", '@': "at"})))
+
+	assert.Equal(t, "the-quick-brown", Slugify("The quick brown fox", WithSlugMaxLength(17)))
+	assert.Equal(t, "the-quick-brown", Slugify("The quick brown fox", WithSlugMaxLength(15)))
+	assert.Equal(t, "the-quick-brown-fox", Slugify("The quick brown fox", WithSlugMaxLength(19)))
+	assert.Equal(t, "the-quick-brown-fox", Slugify("The quick brown fox", WithSlugMaxLength(0)))
+	assert.Equal(t, "supercal", Slugify("Supercalifragilistic word", WithSlugMaxLength(8)))
+	assert.Equal(t, "中文", Slugify("中文标题", WithSlugAllowUnicode(), WithSlugMaxLength(2)))
+}
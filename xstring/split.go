@@ -0,0 +1,153 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// ErrUnterminatedQuote is returned by SplitQuoted and SplitDelimQuoted for a quote without its closing quote.
+	ErrUnterminatedQuote = errors.New("xstring: unterminated quote")
+	// ErrTrailingEscape is returned by SplitQuoted for a backslash at the end of the input.
+	ErrTrailingEscape = errors.New("xstring: trailing backslash")
+)
+
+// SplitQuoted splits s into words like a POSIX shell does, without any expansion:
+// words are separated by whitespace, single quotes preserve everything up to the
+// next single quote, double quotes preserve everything except that a backslash
+// escapes '"', '\\', '$' and '`', and a backslash outside quotes escapes any rune.
+// Quoted and unquoted parts that are not separated by whitespace form one word,
+// so `--name="John Doe"` is a single word and a pair of empty quotes is an empty one.
+func SplitQuoted(s string) ([]string, error) {
+	var (
+		words  []string
+		sb     strings.Builder
+		inWord bool
+	)
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		switch {
+		case unicode.IsSpace(r):
+			if inWord {
+				words = append(words, sb.String())
+				sb.Reset()
+				inWord = false
+			}
+		case r == '\\':
+			if i == len(s) {
+				return nil, ErrTrailingEscape
+			}
+			r, size = utf8.DecodeRuneInString(s[i:])
+			i += size
+			sb.WriteRune(r)
+			inWord = true
+		case r == '\'':
+			end := strings.IndexByte(s[i:], '\'')
+			if end < 0 {
+				return nil, ErrUnterminatedQuote
+			}
+			sb.WriteString(s[i : i+end])
+			i += end + 1
+			inWord = true
+		case r == '"':
+			closed := false
+			for i < len(s) {
+				c := s[i]
+				if c == '"' {
+					i++
+					closed = true
+					break
+				}
+				if c == '\\' && i+1 < len(s) && strings.IndexByte("\"\\$`", s[i+1]) >= 0 {
+					c = s[i+1]
+					i++
+				}
+				sb.WriteByte(c)
+				i++
+			}
+			if !closed {
+				return nil, ErrUnterminatedQuote
+			}
+			inWord = true
+		default:
+			sb.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, sb.String())
+	}
+
+	return words, nil
+}
+
+// SplitDelimQuoted splits s into fields separated by delim, following the quoting rules
+// of CSV: a field starting with '"' extends to the matching closing quote, may contain
+// delim and newlines, and writes a literal quote as "". Anything between the closing
+// quote and the next delim is kept as is. Unquoted fields are not trimmed.
+// An empty s has no fields.
+func SplitDelimQuoted(s string, delim rune) ([]string, error) {
+	if s == empty {
+		return nil, nil
+	}
+
+	var (
+		fields []string
+		sb     strings.Builder
+	)
+	fieldStart := true
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		switch {
+		case r == delim:
+			fields = append(fields, sb.String())
+			sb.Reset()
+			fieldStart = true
+			continue
+		case r == '"' && fieldStart:
+			closed := false
+			for i < len(s) {
+				c := s[i]
+				i++
+				if c == '"' {
+					if i < len(s) && s[i] == '"' {
+						i++
+					} else {
+						closed = true
+						break
+					}
+				}
+				sb.WriteByte(c)
+			}
+			if !closed {
+				return nil, ErrUnterminatedQuote
+			}
+		default:
+			sb.WriteRune(r)
+		}
+		fieldStart = false
+	}
+	fields = append(fields, sb.String())
+
+	return fields, nil
+}
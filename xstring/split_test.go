@@ -0,0 +1,83 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSplitQuoted(t *testing.T) {
+	tests := []struct {
+		s    string
+		want []string
+	}{
+		{"", nil},
+		{"   ", nil},
+		{"a b  c", []string{"a", "b", "c"}},
+		{" \tgit  commit\n-m ", []string{"git", "commit", "-m"}},
+		{`echo 'hello world'`, []string{"echo", "hello world"}},
+		{`echo "hello world"`, []string{"echo", "hello world"}},
+		{`--name="John Doe" -v`, []string{"--name=John Doe", "-v"}},
+		{`'' ""`, []string{"", ""}},
+		{`a'b'"c"d`, []string{"abcd"}},
+		{`"say \"hi\" \\ \$HOME \n"`, []string{`say "hi" \ $HOME \n`}},
+		{`'no \escapes "here"'`, []string{`no \escapes "here"`}},
+		{`a\ b c\"d`, []string{"a b", `c"d`}},
+		{`tag:"中文 标签"`, []string{"tag:中文 标签"}},
+	}
+	for _, tt := range tests {
+		got, err := SplitQuoted(tt.s)
+		assert.NoError(t, err, tt.s)
+		assert.Equal(t, tt.want, got, tt.s)
+	}
+
+	for _, s := range []string{`'open`, `"open`, `"open\"`, `'it\'s'`} {
+		_, err := SplitQuoted(s)
+		assert.ErrorIs(t, err, ErrUnterminatedQuote, s)
+	}
+	_, err := SplitQuoted(`trailing\`)
+	assert.ErrorIs(t, err, ErrTrailingEscape)
+}
+
+func TestSplitDelimQuoted(t *testing.T) {
+	tests := []struct {
+		s     string
+		delim rune
+		want  []string
+	}{
+		{"", ',', nil},
+		{"a", ',', []string{"a"}},
+		{"a,b,,c,", ',', []string{"a", "b", "", "c", ""}},
+		{` a , b `, ',', []string{" a ", " b "}},
+		{`"a,b",c`, ',', []string{"a,b", "c"}},
+		{`"say ""hi""",x`, ',', []string{`say "hi"`, "x"}},
+		{"\"multi\nline\";y", ';', []string{"multi\nline", "y"}},
+		{`"quoted"tail,z`, ',', []string{"quotedtail", "z"}},
+		{`mid"quote,z`, ',', []string{`mid"quote`, "z"}},
+		{`env:prod|"team:a|b"`, '|', []string{"env:prod", "team:a|b"}},
+		{"标签，\"中，文\"", '，', []string{"标签", "中，文"}},
+	}
+	for _, tt := range tests {
+		got, err := SplitDelimQuoted(tt.s, tt.delim)
+		assert.NoError(t, err, tt.s)
+		assert.Equal(t, tt.want, got, tt.s)
+	}
+
+	_, err := SplitDelimQuoted(`a,"open`, ',')
+	assert.ErrorIs(t, err, ErrUnterminatedQuote)
+}
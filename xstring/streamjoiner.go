@@ -0,0 +1,165 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"errors"
+	"io"
+	"unicode/utf8"
+)
+
+// ErrStreamJoinerClosed is returned when writing to a closed StreamJoiner.
+var ErrStreamJoinerClosed = errors.New("xstring: stream joiner closed")
+
+// StreamJoiner is a Joiner that writes the elements straight to an io.Writer instead of buffering them.
+// The prefix is written with the first element, and the suffix by Close.
+// The first write error is kept and returned by every later call.
+type StreamJoiner struct {
+	w       io.Writer
+	opts    *joinerOptions
+	n       int64
+	started bool
+	closed  bool
+	err     error
+}
+
+// NewStreamJoiner returns a StreamJoiner writing to w.
+func NewStreamJoiner(w io.Writer, opts ...JoinerOption) *StreamJoiner {
+	op := new(joinerOptions)
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	return &StreamJoiner{w: w, opts: op}
+}
+
+// WriteString writes the element s.
+func (j *StreamJoiner) WriteString(s string) (int, error) {
+	if s == "" && j.opts.skipEmpty && j.err == nil {
+		return 0, nil
+	}
+	if err := j.writeStep(); err != nil {
+		return 0, err
+	}
+
+	return j.write(s)
+}
+
+// Write writes the element p.
+func (j *StreamJoiner) Write(p []byte) (int, error) {
+	if len(p) == 0 && j.opts.skipEmpty && j.err == nil {
+		return 0, nil
+	}
+	if err := j.writeStep(); err != nil {
+		return 0, err
+	}
+
+	n, err := j.w.Write(p)
+	j.n += int64(n)
+	j.err = err
+
+	return n, err
+}
+
+// WriteByte writes the element b.
+func (j *StreamJoiner) WriteByte(b byte) error {
+	_, err := j.Write([]byte{b})
+
+	return err
+}
+
+// WriteRune writes the UTF-8 encoding of r as an element.
+func (j *StreamJoiner) WriteRune(r rune) (int, error) {
+	var buf [utf8.UTFMax]byte
+	n := utf8.EncodeRune(buf[:], r)
+
+	return j.Write(buf[:n])
+}
+
+// AddAll writes every item as an element.
+func (j *StreamJoiner) AddAll(items ...string) error {
+	for _, item := range items {
+		if _, err := j.WriteString(item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close writes the suffix, and the prefix if nothing has been written,
+// or only the empty value if one is set.
+// It doesn't close the underlying writer.
+func (j *StreamJoiner) Close() error {
+	if j.closed {
+		return j.err
+	}
+	j.closed = true
+	if j.err != nil {
+		return j.err
+	}
+
+	if !j.started {
+		j.started = true
+		if j.opts.hasEmptyValue {
+			_, err := j.write(j.opts.emptyValue)
+			return err
+		}
+		if _, err := j.write(j.opts.prefix); err != nil {
+			return err
+		}
+	}
+	_, err := j.write(j.opts.suffix)
+
+	return err
+}
+
+// Len returns the number of bytes written to the underlying writer.
+func (j *StreamJoiner) Len() int64 {
+	return j.n
+}
+
+func (j *StreamJoiner) writeStep() error {
+	if j.closed {
+		return ErrStreamJoinerClosed
+	}
+	if j.err != nil {
+		return j.err
+	}
+
+	if j.started {
+		_, err := j.write(j.opts.step)
+		return err
+	}
+
+	j.started = true
+	_, err := j.write(j.opts.prefix)
+
+	return err
+}
+
+func (j *StreamJoiner) write(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	n, err := io.WriteString(j.w, s)
+	j.n += int64(n)
+	j.err = err
+
+	return n, err
+}
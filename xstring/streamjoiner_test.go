@@ -0,0 +1,145 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+type failingWriter struct {
+	left int
+	err  error
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.left {
+		n := w.left
+		w.left = 0
+		return n, w.err
+	}
+	w.left -= len(p)
+
+	return len(p), nil
+}
+
+func TestStreamJoiner(t *testing.T) {
+	var buf bytes.Buffer
+	join := NewStreamJoiner(&buf, WithJoiner("IN (", ",", ")"))
+	_, _ = join.WriteString("1")
+	assert.Equal(t, "IN (1", buf.String())
+	_, _ = join.Write([]byte("2"))
+	_ = join.WriteByte('3')
+	_, _ = join.WriteRune('é')
+	assert.NoError(t, join.AddAll("5", "6"))
+	assert.NoError(t, join.Close())
+	assert.NoError(t, join.Close())
+	assert.Equal(t, "IN (1,2,3,é,5,6)", buf.String())
+	assert.Equal(t, int64(buf.Len()), join.Len())
+
+	_, err := join.WriteString("late")
+	assert.ErrorIs(t, err, ErrStreamJoinerClosed)
+
+	buf.Reset()
+	join = NewStreamJoiner(&buf, WithJoiner("[", ",", "]"))
+	assert.NoError(t, join.Close())
+	assert.Equal(t, "[]", buf.String())
+
+	buf.Reset()
+	join = NewStreamJoiner(&buf)
+	assert.NoError(t, join.Close())
+	assert.Equal(t, "", buf.String())
+}
+
+func TestStreamJoiner_Error(t *testing.T) {
+	cause := errors.New("disk full")
+	w := &failingWriter{left: 6, err: cause}
+	join := NewStreamJoiner(w, WithJoiner("(", ",", ")"))
+	assert.NoError(t, join.AddAll("ab", "cd"))
+	_, err := join.WriteString("ef")
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, int64(6), join.Len())
+	_, err = join.WriteString("gh")
+	assert.ErrorIs(t, err, cause)
+	assert.ErrorIs(t, join.AddAll("x"), cause)
+	assert.ErrorIs(t, join.Close(), cause)
+	assert.ErrorIs(t, join.Close(), cause)
+
+	w = &failingWriter{left: 0, err: cause}
+	join = NewStreamJoiner(w, WithJoiner("(", ",", ")"))
+	_, err = join.Write([]byte("a"))
+	assert.ErrorIs(t, err, cause)
+	assert.ErrorIs(t, NewStreamJoiner(w, WithJoinerPrefix("(")).Close(), cause)
+}
+
+func TestJoiner_WriteTo(t *testing.T) {
+	join := NewJoiner(WithJoiner("(", ",", ")"))
+	join.AddAll("a", "b")
+	var buf strings.Builder
+	n, err := join.WriteTo(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+	assert.Equal(t, "(a,b)", buf.String())
+
+	buf.Reset()
+	n, err = NewJoiner().WriteTo(&buf)
+	assert.NoError(t, err)
+	assert.Zero(t, n)
+
+	cause := errors.New("broken")
+	n, err = join.WriteTo(&failingWriter{left: 2, err: cause})
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, int64(2), n)
+}
+
+func BenchmarkStreamJoiner(b *testing.B) {
+	var buf bytes.Buffer
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		join := NewStreamJoiner(&buf, WithJoiner("IN (", ",", ")"))
+		for j := 0; j < 100; j++ {
+			_, _ = join.WriteString("42")
+		}
+		_ = join.Close()
+	}
+}
+
+func TestStreamJoiner_EmptyValue(t *testing.T) {
+	var buf bytes.Buffer
+	join := NewStreamJoiner(&buf, WithJoiner("(", ",", ")"), WithJoinerEmptyValue("NULL"))
+	assert.NoError(t, join.Close())
+	assert.Equal(t, "NULL", buf.String())
+
+	buf.Reset()
+	join = NewStreamJoiner(&buf, WithJoiner("(", ",", ")"), WithJoinerEmptyValue("NULL"))
+	_, _ = join.WriteString("1")
+	assert.NoError(t, join.Close())
+	assert.Equal(t, "(1)", buf.String())
+}
+
+func TestStreamJoiner_SkipEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	join := NewStreamJoiner(&buf, WithJoinerStep(","), WithJoinerSkipEmpty())
+	assert.NoError(t, join.AddAll("", "a", "", "b"))
+	_, _ = join.Write(nil)
+	assert.NoError(t, join.Close())
+	assert.Equal(t, "a,b", buf.String())
+}
@@ -16,20 +16,35 @@
 
 package xstring
 
-import "strings"
+import (
+	"io"
+	"sync"
+	"unicode/utf8"
+	"unsafe"
+)
+
+var joinerPool = sync.Pool{New: func() interface{} {
+	return &Joiner{opts: new(joinerOptions)}
+}}
 
 type (
 	// Joiner is used to construct a sequence of characters separated by a delimiter and optionally starting with a supplied prefix and ending with a supplied suffix.
 	Joiner struct {
-		b    *strings.Builder
-		opts *joinerOptions
-		n    int // n is length of prefix and suffix for
+		buf     []byte
+		opts    *joinerOptions
+		n       int // n is length of prefix and suffix for
+		written bool
+		// pooled Joiners reuse buf after Reset, so String must copy it.
+		pooled bool
 	}
 	// joinerOptions a option.
 	joinerOptions struct {
-		prefix string
-		step   string
-		suffix string
+		prefix        string
+		step          string
+		suffix        string
+		emptyValue    string
+		hasEmptyValue bool
+		skipEmpty     bool
 	}
 
 	JoinerOption func(*joinerOptions)
@@ -65,6 +80,22 @@ func WithJoiner(prefix, step, suffix string) JoinerOption {
 	}
 }
 
+// WithJoinerEmptyValue returns a JoinerOption that makes an empty Joiner return value
+// instead of the prefix and the suffix.
+func WithJoinerEmptyValue(value string) JoinerOption {
+	return func(options *joinerOptions) {
+		options.emptyValue = value
+		options.hasEmptyValue = true
+	}
+}
+
+// WithJoinerSkipEmpty returns a JoinerOption that drops empty elements, so no step is written for them.
+func WithJoinerSkipEmpty() JoinerOption {
+	return func(options *joinerOptions) {
+		options.skipEmpty = true
+	}
+}
+
 // NewJoiner returns a Joiner.
 func NewJoiner(opts ...JoinerOption) *Joiner {
 	j := &Joiner{}
@@ -73,6 +104,43 @@ func NewJoiner(opts ...JoinerOption) *Joiner {
 	return j
 }
 
+// AcquireJoiner returns a Joiner from a pool, configured with opts.
+// Call ReleaseJoiner once done with it to reuse it.
+// Unlike a Joiner from NewJoiner, it keeps its buffer on Reset so it doesn't reallocate,
+// String copies the buffer instead.
+func AcquireJoiner(opts ...JoinerOption) *Joiner {
+	j := joinerPool.Get().(*Joiner)
+	j.pooled = true
+	j.setOpts(opts...)
+
+	return j
+}
+
+// ReleaseJoiner puts j back to the pool, j must not be used afterwards.
+func ReleaseJoiner(j *Joiner) {
+	j.Reset()
+	joinerPool.Put(j)
+}
+
+// ResetWith resets the Joiner to be empty and replaces all its options with opts.
+func (j *Joiner) ResetWith(opts ...JoinerOption) {
+	j.Reset()
+	j.setOpts(opts...)
+}
+
+func (j *Joiner) setOpts(opts ...JoinerOption) {
+	if j.opts == nil {
+		j.loadOpts(opts...)
+		return
+	}
+
+	*j.opts = joinerOptions{}
+	for _, opt := range opts {
+		opt(j.opts)
+	}
+	j.n = len(j.opts.prefix) + len(j.opts.suffix)
+}
+
 func (j *Joiner) loadOpts(opts ...JoinerOption) {
 	op := new(joinerOptions)
 	for _, opt := range opts {
@@ -87,25 +155,30 @@ func (j *Joiner) loadOpts(opts ...JoinerOption) {
 // It returns the length of r and a nil error.
 func (j *Joiner) WriteRune(r rune) (int, error) {
 	j.tryWriteStep()
-	n, _ := j.b.WriteRune(r)
+	n := len(j.buf)
+	j.buf = utf8.AppendRune(j.buf, r)
 
-	return n, nil
+	return len(j.buf) - n, nil
 }
 
 // WriteString appends the contents of s to b's buffer.
 // It returns the length of s and a nil error.
 func (j *Joiner) WriteString(s string) (int, error) {
+	if s == "" && j.opts.skipEmpty {
+		return 0, nil
+	}
+
 	j.tryWriteStep()
-	n, _ := j.b.WriteString(s)
+	j.buf = append(j.buf, s...)
 
-	return n, nil
+	return len(s), nil
 }
 
 // WriteByte appends the byte c to b's buffer.
 // The returned error is always nil.
 func (j *Joiner) WriteByte(b byte) error {
 	j.tryWriteStep()
-	_ = j.b.WriteByte(b)
+	j.buf = append(j.buf, b)
 
 	return nil
 }
@@ -113,64 +186,141 @@ func (j *Joiner) WriteByte(b byte) error {
 // Write appends the contents of p to b's buffer.
 // Write always returns len(p), nil.
 func (j *Joiner) Write(p []byte) (int, error) {
+	if len(p) == 0 && j.opts.skipEmpty {
+		return 0, nil
+	}
+
 	j.tryWriteStep()
-	n, _ := j.b.Write(p)
+	j.buf = append(j.buf, p...)
 
-	return n, nil
+	return len(p), nil
+}
+
+// SetEmptyValue sets the value returned while nothing has been written, like WithJoinerEmptyValue.
+// Writing an empty element makes the Joiner non-empty.
+func (j *Joiner) SetEmptyValue(value string) {
+	j.opts.emptyValue = value
+	j.opts.hasEmptyValue = true
+}
+
+// AddAll appends every item as an element.
+func (j *Joiner) AddAll(items ...string) {
+	for _, item := range items {
+		_, _ = j.WriteString(item)
+	}
+}
+
+// Merge appends the elements of other, without its prefix and suffix, as a single element.
+// Nothing is appended if other is empty.
+func (j *Joiner) Merge(other *Joiner) {
+	if other == nil || !other.written {
+		return
+	}
+
+	// other may be j itself.
+	s := string(other.buf)
+	_, _ = j.WriteString(s)
 }
 
 // String returns the accumulated string.
 func (j *Joiner) String() string {
-	var s string
-	if j.b != nil {
-		s = j.b.String()
+	if j.isEmptyValue() {
+		return j.opts.emptyValue
+	}
+
+	if j.pooled || j.n > 0 {
+		return j.opts.prefix + string(j.buf) + j.opts.suffix
 	}
 
-	return j.opts.prefix + s + j.opts.suffix
+	// like strings.Builder, the bytes written are never modified so they can be shared.
+	return unsafe.String(unsafe.SliceData(j.buf), len(j.buf))
+}
+
+// WriteTo writes the accumulated string to w without concatenating it first.
+// It implements io.WriterTo.
+func (j *Joiner) WriteTo(w io.Writer) (int64, error) {
+	if j.isEmptyValue() {
+		n, err := io.WriteString(w, j.opts.emptyValue)
+		return int64(n), err
+	}
+
+	var total int64
+	for i, part := range [3]string{j.opts.prefix, "", j.opts.suffix} {
+		var n int
+		var err error
+		switch {
+		case i == 1 && len(j.buf) > 0:
+			n, err = w.Write(j.buf)
+		case part != "":
+			n, err = io.WriteString(w, part)
+		}
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+
+	return total, nil
 }
 
 func (j *Joiner) tryWriteStep() {
-	if j.b == nil {
-		j.b = &strings.Builder{}
-	} else {
-		j.b.WriteString(j.opts.step)
+	if j.written {
+		j.buf = append(j.buf, j.opts.step...)
 	}
+	j.written = true
+}
+
+func (j *Joiner) isEmptyValue() bool {
+	return !j.written && j.opts.hasEmptyValue
 }
 
 // Grow grows b's capacity, if necessary, to guarantee space for
 // another n bytes. After Grow(n), at least n bytes can be written to b
 // without another allocation. If n is negative, Grow panics.
 func (j *Joiner) Grow(n int) {
-	if j.b == nil {
-		j.b = &strings.Builder{}
+	if n < 0 {
+		panic("xstring: negative Joiner.Grow count")
 	}
 
-	j.b.Grow(n)
+	if cap(j.buf)-len(j.buf) < n {
+		buf := make([]byte, len(j.buf), 2*cap(j.buf)+n)
+		copy(buf, j.buf)
+		j.buf = buf
+	}
 }
 
 // Cap returns the capacity of the builder's underlying byte slice. It is the
 // total space allocated for the string being built and includes any bytes
 // already written.
 func (j *Joiner) Cap() int {
-	if j.b == nil {
-		return j.n
-	}
-
-	return j.b.Cap() + j.n
+	return cap(j.buf) + j.n
 }
 
-// Reset resets the Builder to be empty.
+// Reset resets the Joiner to be empty, String returns the empty value again if any.
+// The buffer is released, unless j comes from AcquireJoiner.
 func (j *Joiner) Reset() {
-	if j.b != nil {
-		j.b.Reset()
+	if j.pooled {
+		j.buf = j.buf[:0]
+	} else {
+		j.buf = nil
 	}
+	j.written = false
 }
 
 // Len returns the len of accumulated string.
 func (j *Joiner) Len() int {
-	if j.b == nil {
-		return j.n
+	if j.isEmptyValue() {
+		return len(j.opts.emptyValue)
+	}
+	return len(j.buf) + j.n
+}
+
+// JoinSlice joins the items formatted by format, e.g. JoinSlice(ids, strconv.Itoa, WithJoinerStep(",")).
+func JoinSlice[T any](items []T, format func(T) string, opts ...JoinerOption) string {
+	j := NewJoiner(opts...)
+	for _, item := range items {
+		_, _ = j.WriteString(format(item))
 	}
 
-	return j.b.Len() + j.n
+	return j.String()
 }
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"strconv"
 	"testing"
 )
 
@@ -181,3 +182,177 @@ func TestJoiner_Reset(t *testing.T) {
 	join.Reset()
 	assert.Equal(t, 0, join.Cap())
 }
+
+func TestJoiner_AddAll(t *testing.T) {
+	join := NewJoiner(WithJoiner("[", ", ", "]"))
+	join.AddAll("a", "b")
+	join.AddAll()
+	join.AddAll("c")
+	assert.Equal(t, "[a, b, c]", join.String())
+}
+
+func TestJoinSlice(t *testing.T) {
+	assert.Equal(t, "(1,2,3)", JoinSlice([]int{1, 2, 3}, strconv.Itoa, WithJoiner("(", ",", ")")))
+	assert.Equal(t, "()", JoinSlice(nil, strconv.Itoa, WithJoiner("(", ",", ")")))
+
+	type user struct{ name string }
+	users := []user{{"alice"}, {"bob"}}
+	assert.Equal(t, "alice|bob", JoinSlice(users, func(u user) string { return u.name }, WithJoinerStep("|")))
+}
+
+func TestJoiner_EmptyValue(t *testing.T) {
+	join := NewJoiner(WithJoiner("[", ",", "]"), WithJoinerEmptyValue("NULL"))
+	assert.Equal(t, "NULL", join.String())
+	assert.Equal(t, 4, join.Len())
+
+	_, _ = join.WriteString("")
+	assert.Equal(t, "[]", join.String())
+	assert.Equal(t, 2, join.Len())
+	_, _ = join.WriteString("a")
+	assert.Equal(t, "[,a]", join.String())
+
+	join.Reset()
+	assert.Equal(t, "NULL", join.String())
+	assert.Equal(t, 4, join.Len())
+	_, _ = join.WriteString("b")
+	assert.Equal(t, "[b]", join.String())
+
+	join = NewJoiner(WithJoiner("(", ",", ")"))
+	join.SetEmptyValue("")
+	assert.Equal(t, "", join.String())
+	assert.Zero(t, join.Len())
+	var buf bytes.Buffer
+	n, err := join.WriteTo(&buf)
+	assert.NoError(t, err)
+	assert.Zero(t, n)
+
+	join.SetEmptyValue("[]")
+	n, err = join.WriteTo(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	assert.Equal(t, "[]", buf.String())
+}
+
+func TestJoiner_ResetStep(t *testing.T) {
+	join := NewJoiner(WithJoinerStep(","))
+	join.AddAll("a", "b")
+	join.Reset()
+	join.AddAll("c", "d")
+	assert.Equal(t, "c,d", join.String())
+
+	join = NewJoiner(WithJoinerStep(","))
+	join.Grow(10)
+	join.AddAll("a", "b")
+	assert.Equal(t, "a,b", join.String())
+}
+
+func TestJoiner_Merge(t *testing.T) {
+	left := NewJoiner(WithJoiner("(", ",", ")"))
+	left.AddAll("a", "b")
+	right := NewJoiner(WithJoiner("(", ",", ")"))
+	right.AddAll("c", "d")
+
+	where := NewJoiner(WithJoinerStep(") AND ("), WithJoinerPrefix("("), WithJoinerSuffix(")"))
+	where.Merge(left)
+	where.Merge(right)
+	assert.Equal(t, "(a,b) AND (c,d)", where.String())
+
+	join := NewJoiner(WithJoiner("[", "-", "]"))
+	join.AddAll("x")
+	join.Merge(left)
+	join.Merge(NewJoiner(WithJoiner("{", ",", "}")))
+	join.Merge(nil)
+	assert.Equal(t, "[x-a,b]", join.String())
+
+	join.Merge(join)
+	assert.Equal(t, "[x-a,b-x-a,b]", join.String())
+
+	empty := NewJoiner(WithJoinerEmptyValue("NULL"))
+	empty.Merge(left)
+	assert.Equal(t, "a,b", empty.String())
+}
+
+func TestJoiner_SkipEmpty(t *testing.T) {
+	join := NewJoiner(WithJoinerStep(","), WithJoinerSkipEmpty())
+	n, err := join.WriteString("")
+	assert.NoError(t, err)
+	assert.Zero(t, n)
+	join.AddAll("a", "", "b")
+	_, _ = join.Write(nil)
+	_, _ = join.Write([]byte("c"))
+	assert.Equal(t, "a,b,c", join.String())
+	assert.Equal(t, "x,y", JoinSlice([]string{"", "x", "", "y", ""}, func(s string) string { return s },
+		WithJoinerStep(","), WithJoinerSkipEmpty()))
+
+	join = NewJoiner(WithJoiner("[", ",", "]"), WithJoinerSkipEmpty(), WithJoinerEmptyValue("NULL"))
+	join.AddAll("", "")
+	assert.Equal(t, "NULL", join.String())
+	join.Merge(NewJoiner())
+	assert.Equal(t, "NULL", join.String())
+}
+
+func TestJoiner_ResetWith(t *testing.T) {
+	join := NewJoiner(WithJoiner("(", ",", ")"), WithJoinerEmptyValue("NULL"))
+	join.AddAll("a", "b")
+	join.ResetWith(WithJoinerStep("-"))
+	assert.Equal(t, "", join.String())
+	assert.Zero(t, join.Len())
+	join.AddAll("c", "d")
+	assert.Equal(t, "c-d", join.String())
+
+	var zero Joiner
+	zero.ResetWith(WithJoiner("[", ",", "]"))
+	zero.AddAll("x")
+	assert.Equal(t, "[x]", zero.String())
+}
+
+func TestAcquireJoiner(t *testing.T) {
+	join := AcquireJoiner(WithJoiner("(", ",", ")"))
+	join.AddAll("a", "b")
+	assert.Equal(t, "(a,b)", join.String())
+	ReleaseJoiner(join)
+
+	join = AcquireJoiner(WithJoinerStep("|"))
+	assert.Equal(t, "", join.String())
+	join.AddAll("c", "d")
+	assert.Equal(t, "c|d", join.String())
+	ReleaseJoiner(join)
+}
+
+func TestAcquireJoiner_ReuseBuffer(t *testing.T) {
+	join := AcquireJoiner(WithJoinerStep(","))
+	join.AddAll("first", "second")
+	s := join.String()
+	capacity := join.Cap()
+
+	join.Reset()
+	assert.Equal(t, capacity, join.Cap())
+	join.AddAll("overwritten")
+	// the strings returned before don't share the reused buffer.
+	assert.Equal(t, "first,second", s)
+	ReleaseJoiner(join)
+}
+
+var (
+	benchJoinerItems = []string{"alpha", "beta", "gamma", "delta"}
+	benchJoinerOpt   = WithJoiner("(", ",", ")")
+)
+
+func BenchmarkJoiner_New(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		join := NewJoiner(benchJoinerOpt)
+		join.AddAll(benchJoinerItems...)
+		_ = join.String()
+	}
+}
+
+func BenchmarkJoiner_Acquire(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		join := AcquireJoiner(benchJoinerOpt)
+		join.AddAll(benchJoinerItems...)
+		_ = join.String()
+		ReleaseJoiner(join)
+	}
+}
@@ -0,0 +1,65 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+// Truncate shortens s to at most max runes, replacing the removed tail with suffix
+// (for example "..." or "…"). The suffix counts towards max; if it does not fit,
+// s is cut to max runes without a suffix. s is returned unchanged if it already fits.
+func Truncate(s string, max int, suffix string) string {
+	return truncate(s, max, suffix, func(rune) int { return 1 })
+}
+
+// TruncateWidth is like Truncate, but measures s and suffix in terminal cells,
+// so wide CJK characters count as 2 and combining marks as 0.
+// A wide character that would straddle the limit is dropped entirely.
+func TruncateWidth(s string, max int, suffix string) string {
+	return truncate(s, max, suffix, runeWidth)
+}
+
+func truncate(s string, max int, suffix string, width func(rune) int) string {
+	if max <= 0 {
+		return empty
+	}
+
+	// end is where s is cut when the suffix is appended and cut is the first rune
+	// beyond max; both are only meaningful once s turns out to be too long.
+	suffixWidth := 0
+	for _, r := range suffix {
+		suffixWidth += width(r)
+	}
+	end, cut := -1, -1
+	total := 0
+	for i, r := range s {
+		w := width(r)
+		if end < 0 && total+w > max-suffixWidth {
+			end = i
+		}
+		if total+w > max {
+			cut = i
+			break
+		}
+		total += w
+	}
+	if cut < 0 {
+		return s
+	}
+	if suffixWidth > max {
+		return s[:cut]
+	}
+
+	return s[:end] + suffix
+}
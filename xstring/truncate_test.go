@@ -0,0 +1,74 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s      string
+		max    int
+		suffix string
+		want   string
+	}{
+		{"hello world", 20, "...", "hello world"},
+		{"hello world", 11, "...", "hello world"},
+		{"hello world", 8, "...", "hello..."},
+		{"hello world", 8, "…", "hello w…"},
+		{"hello world", 5, "", "hello"},
+		{"hello world", 3, "...", "..."},
+		{"hello world", 2, "...", "he"},
+		{"hello world", 0, "...", ""},
+		{"hello world", -1, "...", ""},
+		{"", 3, "...", ""},
+		{"你好世界欢迎你", 5, "…", "你好世界…"},
+		{"日本語テキスト", 4, "", "日本語テ"},
+	}
+	for _, tt := range tests {
+		got := Truncate(tt.s, tt.max, tt.suffix)
+		assert.Equal(t, tt.want, got, "Truncate(%q, %d, %q)", tt.s, tt.max, tt.suffix)
+		assert.True(t, utf8.ValidString(got))
+	}
+}
+
+func TestTruncateWidth(t *testing.T) {
+	tests := []struct {
+		s      string
+		max    int
+		suffix string
+		want   string
+	}{
+		{"hello world", 8, "...", "hello..."},
+		{"你好世界", 8, "...", "你好世界"},
+		{"你好世界", 7, "...", "你好..."},
+		{"你好世界", 6, "…", "你好…"},
+		{"你好世界", 3, "", "你"},
+		{"a你好", 4, "", "a你"},
+		{"ééé", 2, "", "éé"},
+		{"e\u0301e\u0301", 1, "", "e\u0301"},
+		{"你好", 1, "...", ""},
+	}
+	for _, tt := range tests {
+		got := TruncateWidth(tt.s, tt.max, tt.suffix)
+		assert.Equal(t, tt.want, got, "TruncateWidth(%q, %d, %q)", tt.s, tt.max, tt.suffix)
+		assert.True(t, utf8.ValidString(got))
+	}
+}
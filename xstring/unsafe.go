@@ -0,0 +1,56 @@
+//go:build !purego
+
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import "unsafe"
+
+// zeroCopy reports whether UnsafeBytes and UnsafeString share memory with their argument.
+const zeroCopy = true
+
+// UnsafeBytes returns the bytes of s without copying them.
+//
+// The returned slice aliases the memory of s, which Go assumes is immutable: it must
+// never be modified, not even temporarily, or the program may crash or silently
+// corrupt other strings sharing that memory (string constants are often in read-only
+// memory). Use it only to pass s to code that reads from a []byte.
+// An empty s returns nil.
+//
+// When built with the purego tag, UnsafeBytes copies s and is always safe.
+func UnsafeBytes(s string) []byte {
+	if s == "" {
+		return nil
+	}
+
+	return unsafe.Slice(unsafe.StringData(s), len(s))
+}
+
+// UnsafeString returns the bytes of b as a string without copying them.
+//
+// The returned string aliases b: b must not be modified for as long as the string,
+// or any substring of it, is in use. This includes reusing b as a buffer, e.g. with
+// bufio.Reader or sync.Pool. Keeping the string alive keeps all of b alive too.
+//
+// When built with the purego tag, UnsafeString copies b and is always safe.
+func UnsafeString(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+
+	return unsafe.String(unsafe.SliceData(b), len(b))
+}
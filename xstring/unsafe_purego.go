@@ -0,0 +1,38 @@
+//go:build purego
+
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+// zeroCopy reports whether UnsafeBytes and UnsafeString share memory with their argument.
+const zeroCopy = false
+
+// UnsafeBytes returns the bytes of s. This build uses the purego tag, so it copies them;
+// see the default build for the aliasing rules callers have to follow.
+func UnsafeBytes(s string) []byte {
+	if s == "" {
+		return nil
+	}
+
+	return []byte(s)
+}
+
+// UnsafeString returns the bytes of b as a string. This build uses the purego tag,
+// so it copies them; see the default build for the aliasing rules callers have to follow.
+func UnsafeString(b []byte) string {
+	return string(b)
+}
@@ -0,0 +1,63 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"unsafe"
+)
+
+func TestUnsafeBytes(t *testing.T) {
+	assert.Nil(t, UnsafeBytes(""))
+
+	s := heapString("hello, 世界")
+	b := UnsafeBytes(s)
+	assert.Equal(t, []byte(s), b)
+	assert.Equal(t, zeroCopy, unsafe.SliceData(b) == unsafe.StringData(s))
+}
+
+func TestUnsafeString(t *testing.T) {
+	assert.Equal(t, "", UnsafeString(nil))
+	assert.Equal(t, "", UnsafeString([]byte{}))
+
+	b := []byte("hello")
+	s := UnsafeString(b)
+	assert.Equal(t, "hello", s)
+	assert.Equal(t, zeroCopy, unsafe.StringData(s) == unsafe.SliceData(b))
+}
+
+// heapString returns a heap copy of s so that the test does not depend on constant placement.
+func heapString(s string) string {
+	return string([]byte(s))
+}
+
+func BenchmarkUnsafeString(b *testing.B) {
+	buf := make([]byte, 1<<20)
+	b.Run("copy", func(b *testing.B) {
+		b.SetBytes(int64(len(buf)))
+		for i := 0; i < b.N; i++ {
+			_ = string(buf)
+		}
+	})
+	b.Run("unsafe", func(b *testing.B) {
+		b.SetBytes(int64(len(buf)))
+		for i := 0; i < b.N; i++ {
+			_ = UnsafeString(buf)
+		}
+	})
+}
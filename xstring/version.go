@@ -0,0 +1,129 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import "strings"
+
+// CompareVersion compares the version strings a and b and returns -1, 0 or 1.
+//
+// It accepts semantic versions as well as the looser forms found in the wild: an
+// optional "v" prefix, any number of dot-separated numeric segments, where missing
+// segments count as 0 ("1.2" equals "1.2.0"), and a pre-release suffix separated by
+// '-', '.' or nothing at all ("1.2.0-rc.1", "1.2.0rc1"). A version with a pre-release
+// is lower than the same version without one. Pre-releases are split into identifiers
+// at separators and between digits and letters, numeric identifiers compare by value
+// and are lower than others, which compare case-insensitively, so "rc.2" and "rc2"
+// are both lower than "rc.10". Build metadata after '+' is ignored.
+func CompareVersion(a, b string) int {
+	mainA, preA := splitVersion(a)
+	mainB, preB := splitVersion(b)
+
+	segsA, segsB := strings.Split(mainA, "."), strings.Split(mainB, ".")
+	for i := 0; i < max(len(segsA), len(segsB)); i++ {
+		var sa, sb string
+		if i < len(segsA) {
+			sa = segsA[i]
+		}
+		if i < len(segsB) {
+			sb = segsB[i]
+		}
+		if c := compareNumeric(sa, sb); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case preA == preB:
+		return 0
+	case preA == empty:
+		return 1
+	case preB == empty:
+		return -1
+	}
+
+	idsA, idsB := splitPreRelease(preA), splitPreRelease(preB)
+	for i := 0; i < min(len(idsA), len(idsB)); i++ {
+		ia, ib := idsA[i], idsB[i]
+		numA, numB := IsNumeric(ia), IsNumeric(ib)
+		var c int
+		switch {
+		case numA && numB:
+			c = compareNumeric(ia, ib)
+		case numA:
+			c = -1
+		case numB:
+			c = 1
+		default:
+			c = strings.Compare(strings.ToLower(ia), strings.ToLower(ib))
+		}
+		if c != 0 {
+			return c
+		}
+	}
+
+	return sign(len(idsA) - len(idsB))
+}
+
+// splitVersion splits a version into its numeric part and its pre-release suffix.
+func splitVersion(v string) (string, string) {
+	v = strings.TrimSpace(v)
+	if v != empty && (v[0] == 'v' || v[0] == 'V') {
+		v = v[1:]
+	}
+	if i := strings.IndexByte(v, '+'); i >= 0 {
+		v = v[:i]
+	}
+
+	end := 0
+	for end < len(v) && (isASCIIDigit(rune(v[end])) || v[end] == '.') {
+		end++
+	}
+	main := strings.TrimRight(v[:end], ".")
+	pre := strings.TrimLeft(v[end:], "-._")
+
+	return main, pre
+}
+
+// splitPreRelease splits a pre-release into identifiers at separators and
+// between digits and other runes, so "rc1" and "rc.1" are equivalent.
+func splitPreRelease(pre string) []string {
+	var ids []string
+	for _, field := range strings.FieldsFunc(pre, func(r rune) bool {
+		return r == '.' || r == '-' || r == '_'
+	}) {
+		start := 0
+		for i := 1; i < len(field); i++ {
+			if isASCIIDigit(rune(field[i])) != isASCIIDigit(rune(field[i-1])) {
+				ids = append(ids, field[start:i])
+				start = i
+			}
+		}
+		ids = append(ids, field[start:])
+	}
+
+	return ids
+}
+
+// compareNumeric compares two strings of ASCII digits by value, treating empty strings as 0.
+func compareNumeric(a, b string) int {
+	a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		return sign(len(a) - len(b))
+	}
+
+	return strings.Compare(a, b)
+}
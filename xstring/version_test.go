@@ -0,0 +1,61 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCompareVersion(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"v1.2.3", "1.2.3", 0},
+		{"V1.2", "1.2.0", 0},
+		{"1.2", "1.2.0.0", 0},
+		{" 1.2.3 ", "1.2.3", 0},
+		{"1.2.3", "1.2.4", -1},
+		{"1.10.0", "1.9.9", 1},
+		{"2", "1.99.99", 1},
+		{"1.02", "1.2", 0},
+		{"1.2.0-rc.1", "1.2.0", -1},
+		{"1.2.0", "1.2.0-rc.1", 1},
+		{"1.2.0-rc.1", "1.2.0-rc.2", -1},
+		{"1.2.0-rc.2", "1.2.0-rc.10", -1},
+		{"1.2.0-rc2", "1.2.0-rc10", -1},
+		{"1.2.0rc1", "1.2.0-rc.1", 0},
+		{"1.2.0rc1", "1.2.0", -1},
+		{"1.2.0-alpha", "1.2.0-beta", -1},
+		{"1.2.0-alpha", "1.2.0-alpha.1", -1},
+		{"1.2.0-alpha.1", "1.2.0-alpha.beta", -1},
+		{"1.2.0-1", "1.2.0-alpha", -1},
+		{"1.2.0-RC.1", "1.2.0-rc.1", 0},
+		{"1.2.0+build.5", "1.2.0+build.9", 0},
+		{"1.2.0-rc.1+b1", "1.2.0-rc.1", 0},
+		{"1.2.3.4", "1.2.3", 1},
+		{"18446744073709551616", "18446744073709551615", 1},
+		{"", "0", 0},
+		{"", "0.0.1", -1},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, CompareVersion(tt.a, tt.b), "%q %q", tt.a, tt.b)
+		assert.Equal(t, -tt.want, CompareVersion(tt.b, tt.a), "%q %q", tt.b, tt.a)
+	}
+}
@@ -0,0 +1,84 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWidth(t *testing.T) {
+	tests := map[string]int{
+		"":                   0,
+		"hello":              5,
+		"你好":                 4,
+		"hello世界":            9,
+		"ｆｕｌｌ":               8,
+		"한국어":                6,
+		"こんにちは":              10,
+		"e\u0301":            1,
+		"a\u200bb":           2,
+		"tab\there":          7,
+		"🎉":                  2,
+		"\x1b[31mred\x1b[0m": 10,
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, Width(in), "%q", in)
+	}
+}
+
+func TestWidthANSI(t *testing.T) {
+	assert.Equal(t, 3, WidthANSI("\x1b[31mred\x1b[0m"))
+	assert.Equal(t, 4, WidthANSI("\x1b[1;32m你好\x1b[m"))
+	assert.Equal(t, 6, WidthANSI("\x1b]8;;https://example.com\x07link\x1b]8;;\x07!!"))
+}
+
+func TestStripANSI(t *testing.T) {
+	tests := map[string]string{
+		"plain":                                "plain",
+		"\x1b[31mred\x1b[0m":                   "red",
+		"\x1b[38;5;208morange\x1b[39m text":    "orange text",
+		"\x1b]0;title\x1b\\body":               "body",
+		"\x1b]8;;http://x\x07link\x1b]8;;\x07": "link",
+		"a\x1bcb":                              "ab",
+		"cut\x1b[31":                           "cut",
+		"end\x1b":                              "end",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, StripANSI(in), "%q", in)
+	}
+}
+
+func TestPadToWidth(t *testing.T) {
+	assert.Equal(t, "ab   ", PadToWidth("ab", 5))
+	assert.Equal(t, "你好 ", PadToWidth("你好", 5))
+	assert.Equal(t, "你好世界", PadToWidth("你好世界", 5))
+	assert.Equal(t, "abc", PadToWidth("abc", -1))
+}
+
+func TestPadLeftToWidth(t *testing.T) {
+	assert.Equal(t, "   ab", PadLeftToWidth("ab", 5))
+	assert.Equal(t, " 你好", PadLeftToWidth("你好", 5))
+	assert.Equal(t, "你好世界", PadLeftToWidth("你好世界", 5))
+}
+
+func TestPadCenterToWidth(t *testing.T) {
+	assert.Equal(t, " ab  ", PadCenterToWidth("ab", 5))
+	assert.Equal(t, " 你好 ", PadCenterToWidth("你好", 6))
+	assert.Equal(t, " 你好  ", PadCenterToWidth("你好", 7))
+	assert.Equal(t, "你好世界", PadCenterToWidth("你好世界", 3))
+}
@@ -0,0 +1,141 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"strings"
+	"unicode"
+)
+
+type (
+	// WrapOption customizes Wrap.
+	WrapOption func(o *wrapOptions)
+
+	wrapOptions struct {
+		breakLongWords bool
+	}
+
+	// wrapToken is a word, or a single wide character, that Wrap never breaks.
+	wrapToken struct {
+		text        string
+		width       int
+		spaceBefore bool
+	}
+)
+
+// WithWrapBreakLongWords makes Wrap split words wider than the line width
+// instead of letting them overflow on a line of their own.
+func WithWrapBreakLongWords() WrapOption {
+	return func(o *wrapOptions) {
+		o.breakLongWords = true
+	}
+}
+
+// Wrap wraps s into lines of at most width display cells (see Width), breaking
+// on whitespace. Runs of whitespace between words collapse to a single space,
+// existing line breaks are kept, and wide CJK characters may be broken between
+// as they are not separated by spaces. A width <= 0 returns s unchanged.
+func Wrap(s string, width int, opts ...WrapOption) string {
+	if width <= 0 {
+		return s
+	}
+
+	var o wrapOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(s) + len(s)/width)
+	for i, line := range strings.Split(s, "\n") {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		wrapLine(&sb, line, width, o)
+	}
+
+	return sb.String()
+}
+
+func wrapLine(sb *strings.Builder, line string, width int, o wrapOptions) {
+	lineWidth := 0
+	for _, tok := range wrapTokens(line) {
+		if lineWidth > 0 {
+			need := tok.width
+			if tok.spaceBefore {
+				need++
+			}
+			if lineWidth+need <= width {
+				if tok.spaceBefore {
+					sb.WriteByte(' ')
+				}
+				sb.WriteString(tok.text)
+				lineWidth += need
+				continue
+			}
+			sb.WriteByte('\n')
+			lineWidth = 0
+		}
+
+		if tok.width <= width || !o.breakLongWords {
+			sb.WriteString(tok.text)
+			lineWidth = tok.width
+			continue
+		}
+		for _, r := range tok.text {
+			w := runeWidth(r)
+			if lineWidth > 0 && lineWidth+w > width {
+				sb.WriteByte('\n')
+				lineWidth = 0
+			}
+			sb.WriteRune(r)
+			lineWidth += w
+		}
+	}
+}
+
+func wrapTokens(line string) []wrapToken {
+	var tokens []wrapToken
+	start, wordWidth := -1, 0
+	space := false
+	flush := func(end int) {
+		if start >= 0 {
+			tokens = append(tokens, wrapToken{text: line[start:end], width: wordWidth, spaceBefore: space})
+			start, wordWidth, space = -1, 0, false
+		}
+	}
+
+	for i, r := range line {
+		switch w := runeWidth(r); {
+		case unicode.IsSpace(r):
+			flush(i)
+			space = true
+		case w == 2:
+			flush(i)
+			tokens = append(tokens, wrapToken{text: string(r), width: w, spaceBefore: space})
+			space = false
+		default:
+			if start < 0 {
+				start = i
+			}
+			wordWidth += w
+		}
+	}
+	flush(len(line))
+
+	return tokens
+}
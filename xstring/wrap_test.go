@@ -0,0 +1,59 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xstring
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"", 10, ""},
+		{"hello", 0, "hello"},
+		{"the quick brown fox jumps over the lazy dog", 10, "the quick\nbrown fox\njumps over\nthe lazy\ndog"},
+		{"the quick brown fox", 100, "the quick brown fox"},
+		{"  spaced    out   words ", 8, "spaced\nout\nwords"},
+		{"first paragraph here\n\nsecond one", 9, "first\nparagraph\nhere\n\nsecond\none"},
+		{"a supercalifragilistic word", 8, "a\nsupercalifragilistic\nword"},
+		{"中文文本自动换行", 6, "中文文\n本自动\n换行"},
+		{"Go 语言 is fun", 6, "Go 语\n言 is\nfun"},
+	}
+	for _, tt := range tests {
+		got := Wrap(tt.s, tt.width)
+		assert.Equal(t, tt.want, got, "%q", tt.s)
+		for _, line := range strings.Split(got, "\n") {
+			if !strings.ContainsRune(line, ' ') && Width(line) > tt.width && tt.width > 0 {
+				continue // a long word is allowed to overflow
+			}
+			if tt.width > 0 {
+				assert.LessOrEqual(t, Width(line), tt.width, "%q", line)
+			}
+		}
+	}
+}
+
+func TestWrapBreakLongWords(t *testing.T) {
+	assert.Equal(t, "a\nsupercal\nifragili\nstic\nword",
+		Wrap("a supercalifragilistic word", 8, WithWrapBreakLongWords()))
+	assert.Equal(t, "abc\ndef\ng", Wrap("abcdefg", 3, WithWrapBreakLongWords()))
+}
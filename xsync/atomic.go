@@ -0,0 +1,71 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xsync
+
+import "sync/atomic"
+
+// An Atomic is a value of type T loaded and stored atomically, built on atomic.Pointer.
+// The zero value holds the zero value of T.
+type Atomic[T any] struct {
+	p atomic.Pointer[T]
+}
+
+// NewAtomic returns an Atomic holding v.
+func NewAtomic[T any](v T) *Atomic[T] {
+	a := &Atomic[T]{}
+	a.p.Store(&v)
+
+	return a
+}
+
+// Load returns the current value.
+func (a *Atomic[T]) Load() T {
+	return deref(a.p.Load())
+}
+
+// Store sets the value to v.
+func (a *Atomic[T]) Store(v T) {
+	a.p.Store(&v)
+}
+
+// Swap sets the value to v and returns the previous one.
+func (a *Atomic[T]) Swap(v T) T {
+	return deref(a.p.Swap(&v))
+}
+
+// CompareAndSwap sets the value to new if the current value equals old, and reports whether it did.
+// Values are compared with ==, so it panics if T holds values that aren't comparable.
+func (a *Atomic[T]) CompareAndSwap(old, new T) bool {
+	for {
+		p := a.p.Load()
+		if any(deref(p)) != any(old) {
+			return false
+		}
+		if a.p.CompareAndSwap(p, &new) {
+			return true
+		}
+	}
+}
+
+func deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+
+	return *p
+}
@@ -0,0 +1,73 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xsync
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestAtomic(t *testing.T) {
+	var a Atomic[string]
+	assert.Equal(t, "", a.Load())
+
+	a.Store("a")
+	assert.Equal(t, "a", a.Load())
+	assert.Equal(t, "a", a.Swap("b"))
+	assert.False(t, a.CompareAndSwap("a", "c"))
+	assert.True(t, a.CompareAndSwap("b", "c"))
+	assert.Equal(t, "c", a.Load())
+
+	var zero Atomic[int]
+	assert.True(t, zero.CompareAndSwap(0, 1))
+	assert.Equal(t, 1, NewAtomic(1).Load())
+}
+
+func TestAtomicStruct(t *testing.T) {
+	type config struct {
+		Addr    string
+		Retries int
+	}
+	a := NewAtomic(config{Addr: "a", Retries: 1})
+	assert.True(t, a.CompareAndSwap(config{Addr: "a", Retries: 1}, config{Addr: "b"}))
+	assert.Equal(t, config{Addr: "b"}, a.Load())
+
+	s := NewAtomic([]int{1})
+	assert.Panics(t, func() { s.CompareAndSwap([]int{1}, nil) })
+}
+
+func TestAtomicConcurrentCompareAndSwap(t *testing.T) {
+	var a Atomic[int]
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				for {
+					v := a.Load()
+					if a.CompareAndSwap(v, v+1) {
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 8000, a.Load())
+}
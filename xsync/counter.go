@@ -0,0 +1,101 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xsync
+
+import (
+	"math/bits"
+	"math/rand/v2"
+	"runtime"
+	"sync/atomic"
+)
+
+// cacheLineSize is a common CPU cache line size, used to keep counters on separate lines.
+const cacheLineSize = 64
+
+// A Counter is an int64 counter padded to fill a cache line,
+// so adjacent counters updated by different CPUs don't contend for the same line.
+// The zero value is a counter at zero.
+type Counter struct {
+	v atomic.Int64
+	_ [cacheLineSize - 8]byte
+}
+
+// Add adds delta to c and returns the new value.
+func (c *Counter) Add(delta int64) int64 {
+	return c.v.Add(delta)
+}
+
+// Inc increments c by one.
+func (c *Counter) Inc() {
+	c.v.Add(1)
+}
+
+// Load returns the value of c.
+func (c *Counter) Load() int64 {
+	return c.v.Load()
+}
+
+// Store sets the value of c to v.
+func (c *Counter) Store(v int64) {
+	c.v.Store(v)
+}
+
+// A ShardedCounter spreads updates over several padded counters to scale under write contention,
+// at the cost of Load summing all of them. It suits high-rate metrics that are written far more than read.
+type ShardedCounter struct {
+	shards []Counter
+	mask   uint32
+}
+
+// NewShardedCounter returns a ShardedCounter with the given number of shards rounded up to a power of two,
+// defaulting to GOMAXPROCS.
+func NewShardedCounter(shards ...int) *ShardedCounter {
+	n := runtime.GOMAXPROCS(0)
+	if len(shards) > 0 && shards[0] > 0 {
+		n = shards[0]
+	}
+	n = 1 << bits.Len(uint(n-1))
+
+	return &ShardedCounter{shards: make([]Counter, n), mask: uint32(n - 1)}
+}
+
+// Add adds delta to c.
+func (c *ShardedCounter) Add(delta int64) {
+	c.shards[rand.Uint32()&c.mask].Add(delta)
+}
+
+// Inc increments c by one.
+func (c *ShardedCounter) Inc() {
+	c.Add(1)
+}
+
+// Load returns the sum of all the shards, it isn't a consistent snapshot under concurrent updates.
+func (c *ShardedCounter) Load() int64 {
+	var sum int64
+	for i := range c.shards {
+		sum += c.shards[i].Load()
+	}
+
+	return sum
+}
+
+// Reset sets all the shards to zero.
+func (c *ShardedCounter) Reset() {
+	for i := range c.shards {
+		c.shards[i].Store(0)
+	}
+}
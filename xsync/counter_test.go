@@ -0,0 +1,77 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xsync
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+func TestCounter(t *testing.T) {
+	var c Counter
+	assert.Equal(t, uintptr(cacheLineSize), unsafe.Sizeof(c))
+
+	c.Inc()
+	assert.Equal(t, int64(4), c.Add(3))
+	c.Store(10)
+	assert.Equal(t, int64(10), c.Load())
+}
+
+func TestShardedCounter(t *testing.T) {
+	assert.Len(t, NewShardedCounter(3).shards, 4)
+	assert.Len(t, NewShardedCounter(1).shards, 1)
+	assert.NotEmpty(t, NewShardedCounter().shards)
+
+	c := NewShardedCounter(8)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				c.Inc()
+			}
+			c.Add(-500)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int64(4000), c.Load())
+
+	c.Reset()
+	assert.Equal(t, int64(0), c.Load())
+}
+
+func BenchmarkCounter(b *testing.B) {
+	b.Run("atomic", func(b *testing.B) {
+		var c Counter
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				c.Inc()
+			}
+		})
+	})
+	b.Run("sharded", func(b *testing.B) {
+		c := NewShardedCounter()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				c.Inc()
+			}
+		})
+	})
+}
@@ -0,0 +1,127 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xsync
+
+import (
+	"context"
+	"fmt"
+	"github.com/chenquan/go-pkg/xerror"
+	"sync"
+)
+
+// An ErrGroup runs goroutines working on subtasks of a common task and collects the first error.
+// A panic in a goroutine is recovered and reported as a *xerror.PanicError instead of crashing the process.
+// The zero value has no concurrency limit and doesn't cancel anything on error, see NewErrGroup.
+type ErrGroup struct {
+	cancel func(error)
+	wg     sync.WaitGroup
+	sem    chan struct{}
+
+	errOnce sync.Once
+	err     error
+}
+
+// NewErrGroup returns an ErrGroup and a context derived from ctx,
+// canceled with the first error as its cause, or once Wait returns.
+func NewErrGroup(ctx context.Context) (*ErrGroup, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &ErrGroup{cancel: cancel}, ctx
+}
+
+// SetLimit limits the number of goroutines running at once to n, a negative n means no limit.
+// It panics if called while goroutines of g are running.
+func (g *ErrGroup) SetLimit(n int) {
+	if n < 0 {
+		g.sem = nil
+		return
+	}
+	if len(g.sem) != 0 {
+		panic(fmt.Errorf("xsync: modify limit while %d goroutines in the group are still active", len(g.sem)))
+	}
+
+	g.sem = make(chan struct{}, n)
+}
+
+// Go calls fn in a new goroutine, blocking until the limit of g allows it.
+// The first call to return a non-nil error or to panic cancels the context of g.
+func (g *ErrGroup) Go(fn func() error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+
+	g.start(fn)
+}
+
+// TryGo calls fn in a new goroutine only if the limit of g allows it without waiting,
+// it reports whether fn was started.
+func (g *ErrGroup) TryGo(fn func() error) bool {
+	if g.sem != nil {
+		select {
+		case g.sem <- struct{}{}:
+		default:
+			return false
+		}
+	}
+
+	g.start(fn)
+
+	return true
+}
+
+// Wait blocks until all the goroutines started by Go and TryGo have returned,
+// then returns the first error if any.
+func (g *ErrGroup) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel(g.err)
+	}
+
+	return g.err
+}
+
+func (g *ErrGroup) start(fn func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.done()
+
+		if err := g.run(fn); err != nil {
+			g.errOnce.Do(func() {
+				g.err = err
+				if g.cancel != nil {
+					g.cancel(err)
+				}
+			})
+		}
+	}()
+}
+
+func (g *ErrGroup) run(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = xerror.NewPanicError(r)
+		}
+	}()
+
+	return fn()
+}
+
+func (g *ErrGroup) done() {
+	if g.sem != nil {
+		<-g.sem
+	}
+	g.wg.Done()
+}
@@ -0,0 +1,120 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xsync
+
+import (
+	"context"
+	"errors"
+	"github.com/chenquan/go-pkg/xerror"
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestErrGroupZeroValue(t *testing.T) {
+	var g ErrGroup
+	var n atomic.Int32
+	for i := 0; i < 10; i++ {
+		g.Go(func() error {
+			n.Add(1)
+			return nil
+		})
+	}
+	assert.NoError(t, g.Wait())
+	assert.EqualValues(t, 10, n.Load())
+}
+
+func TestErrGroupFirstError(t *testing.T) {
+	err1, err2 := errors.New("err1"), errors.New("err2")
+	g, ctx := NewErrGroup(context.Background())
+	g.Go(func() error { return err1 })
+	g.Go(func() error {
+		<-ctx.Done()
+		return err2
+	})
+
+	assert.Equal(t, err1, g.Wait())
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	assert.Equal(t, err1, context.Cause(ctx))
+}
+
+func TestErrGroupWaitCancelsContext(t *testing.T) {
+	g, ctx := NewErrGroup(context.Background())
+	g.Go(func() error { return nil })
+	assert.NoError(t, g.Wait())
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
+func TestErrGroupPanic(t *testing.T) {
+	g, ctx := NewErrGroup(context.Background())
+	g.Go(func() error { panic("boom") })
+
+	err := g.Wait()
+	var pe *xerror.PanicError
+	assert.ErrorAs(t, err, &pe)
+	assert.Equal(t, "boom", pe.Value)
+	assert.NotEmpty(t, pe.Stack)
+	assert.Equal(t, err, context.Cause(ctx))
+}
+
+func TestErrGroupSetLimit(t *testing.T) {
+	var g ErrGroup
+	g.SetLimit(2)
+
+	var running, peak atomic.Int32
+	for i := 0; i < 20; i++ {
+		g.Go(func() error {
+			cur := running.Add(1)
+			for {
+				old := peak.Load()
+				if cur <= old || peak.CompareAndSwap(old, cur) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			running.Add(-1)
+			return nil
+		})
+	}
+	assert.NoError(t, g.Wait())
+	assert.LessOrEqual(t, peak.Load(), int32(2))
+}
+
+func TestErrGroupTryGo(t *testing.T) {
+	var g ErrGroup
+	g.SetLimit(1)
+
+	release := make(chan struct{})
+	assert.True(t, g.TryGo(func() error {
+		<-release
+		return nil
+	}))
+	assert.False(t, g.TryGo(func() error { return nil }))
+	assert.Panics(t, func() { g.SetLimit(2) })
+
+	close(release)
+	assert.NoError(t, g.Wait())
+	assert.True(t, g.TryGo(func() error { return nil }))
+	assert.NoError(t, g.Wait())
+
+	g.SetLimit(-1)
+	for i := 0; i < 5; i++ {
+		assert.True(t, g.TryGo(func() error { return nil }))
+	}
+	assert.NoError(t, g.Wait())
+}
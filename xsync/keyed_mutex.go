@@ -0,0 +1,96 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xsync
+
+import "sync"
+
+// A KeyedMutex is a set of mutual exclusion locks indexed by key,
+// locking a key never blocks holders of other keys.
+// A lock is allocated on first use and freed once no goroutine holds or waits for it.
+// The zero value is an unlocked KeyedMutex.
+type KeyedMutex[K comparable] struct {
+	mu    sync.Mutex
+	locks map[K]*keyedLock
+}
+
+type keyedLock struct {
+	mu  sync.Mutex
+	ref int
+}
+
+// Lock locks key, blocking until it is available.
+func (km *KeyedMutex[K]) Lock(key K) {
+	km.mu.Lock()
+	l := km.lockOf(key)
+	l.ref++
+	km.mu.Unlock()
+
+	l.mu.Lock()
+}
+
+// TryLock tries to lock key and reports whether it succeeded.
+func (km *KeyedMutex[K]) TryLock(key K) bool {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+
+	l := km.lockOf(key)
+	if !l.mu.TryLock() {
+		return false
+	}
+	l.ref++
+
+	return true
+}
+
+// Unlock unlocks key, it panics if key isn't locked.
+func (km *KeyedMutex[K]) Unlock(key K) {
+	km.mu.Lock()
+	l := km.locks[key]
+	if l == nil {
+		km.mu.Unlock()
+		panic("xsync: unlock of unlocked key")
+	}
+	l.ref--
+	if l.ref == 0 {
+		delete(km.locks, key)
+	}
+	km.mu.Unlock()
+
+	l.mu.Unlock()
+}
+
+// lockOf returns the lock of key, allocating it if needed, km.mu must be held.
+func (km *KeyedMutex[K]) lockOf(key K) *keyedLock {
+	l := km.locks[key]
+	if l == nil {
+		if km.locks == nil {
+			km.locks = make(map[K]*keyedLock)
+		}
+		l = &keyedLock{}
+		km.locks[key] = l
+	}
+
+	return l
+}
+
+// Len returns the number of keys currently locked or waited for.
+func (km *KeyedMutex[K]) Len() int {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+
+	return len(km.locks)
+}
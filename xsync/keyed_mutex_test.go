@@ -0,0 +1,78 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xsync
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestKeyedMutex(t *testing.T) {
+	var km KeyedMutex[string]
+	km.Lock("a")
+	assert.False(t, km.TryLock("a"))
+	assert.True(t, km.TryLock("b"))
+	assert.Equal(t, 2, km.Len())
+
+	km.Unlock("a")
+	km.Unlock("b")
+	assert.Equal(t, 0, km.Len())
+	assert.Panics(t, func() { km.Unlock("a") })
+
+	assert.True(t, km.TryLock("a"))
+	km.Unlock("a")
+}
+
+func TestKeyedMutexSerializesPerKey(t *testing.T) {
+	var km KeyedMutex[int]
+	counters := make([]int, 4)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(key int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				km.Lock(key)
+				counters[key]++
+				km.Unlock(key)
+			}
+		}(i % len(counters))
+	}
+	wg.Wait()
+
+	for _, c := range counters {
+		assert.Equal(t, 2500, c)
+	}
+	assert.Equal(t, 0, km.Len())
+}
+
+func TestKeyedMutexIndependentKeys(t *testing.T) {
+	var km KeyedMutex[int]
+	km.Lock(1)
+
+	done := make(chan struct{})
+	go func() {
+		km.Lock(2)
+		km.Unlock(2)
+		close(done)
+	}()
+	<-done
+
+	km.Unlock(1)
+}
@@ -0,0 +1,108 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xsync
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// A Lazy memoizes the result of an initializer run at most once at a time, on first use.
+// Unlike sync.OnceValues, a failed initialization is retried on the next Get by default,
+// and the memoized result can be dropped with Reset.
+type Lazy[T any] struct {
+	fn         func() (T, error)
+	cacheError bool
+
+	mu     sync.Mutex
+	result atomic.Pointer[lazyResult[T]]
+}
+
+type lazyResult[T any] struct {
+	value T
+	err   error
+}
+
+// LazyOption customizes a Lazy.
+type LazyOption func(*lazyOptions)
+
+type lazyOptions struct {
+	cacheError bool
+}
+
+// WithLazyCacheError makes a Lazy memoize errors too, so a failed initialization isn't retried until Reset.
+func WithLazyCacheError() LazyOption {
+	return func(o *lazyOptions) {
+		o.cacheError = true
+	}
+}
+
+// NewLazy returns a Lazy initialized by fn, it panics if fn is nil.
+func NewLazy[T any](fn func() (T, error), opts ...LazyOption) *Lazy[T] {
+	if fn == nil {
+		panic("xsync: nil Lazy initializer")
+	}
+
+	var o lazyOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &Lazy[T]{fn: fn, cacheError: o.cacheError}
+}
+
+// OnceValue returns a function calling fn on first use and returning its memoized result afterwards,
+// a call returning an error is retried on the next use.
+func OnceValue[T any](fn func() (T, error)) func() (T, error) {
+	return NewLazy(fn).Get
+}
+
+// Get returns the memoized result, running the initializer if there is none.
+// Concurrent callers wait for a single run of the initializer.
+// If the initializer panics, the panic is propagated and nothing is memoized.
+func (l *Lazy[T]) Get() (T, error) {
+	if r := l.result.Load(); r != nil {
+		return r.value, r.err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if r := l.result.Load(); r != nil {
+		return r.value, r.err
+	}
+
+	v, err := l.fn()
+	if err == nil || l.cacheError {
+		l.result.Store(&lazyResult[T]{value: v, err: err})
+	}
+
+	return v, err
+}
+
+// Done reports whether a result is memoized.
+func (l *Lazy[T]) Done() bool {
+	return l.result.Load() != nil
+}
+
+// Reset drops the memoized result, so the next Get runs the initializer again.
+func (l *Lazy[T]) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.result.Store(nil)
+}
@@ -0,0 +1,125 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xsync
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestLazy(t *testing.T) {
+	var calls int
+	l := NewLazy(func() (int, error) {
+		calls++
+		return calls, nil
+	})
+	assert.False(t, l.Done())
+
+	for i := 0; i < 3; i++ {
+		v, err := l.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, v)
+	}
+	assert.True(t, l.Done())
+
+	l.Reset()
+	assert.False(t, l.Done())
+	v, _ := l.Get()
+	assert.Equal(t, 2, v)
+
+	assert.Panics(t, func() { NewLazy[int](nil) })
+}
+
+func TestLazyRetriesError(t *testing.T) {
+	errInit := errors.New("init")
+	var calls int
+	l := NewLazy(func() (string, error) {
+		calls++
+		if calls < 3 {
+			return "", errInit
+		}
+		return "ok", nil
+	})
+
+	_, err := l.Get()
+	assert.Equal(t, errInit, err)
+	_, err = l.Get()
+	assert.Equal(t, errInit, err)
+	assert.False(t, l.Done())
+
+	v, err := l.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", v)
+	assert.Equal(t, 3, calls)
+}
+
+func TestLazyCacheError(t *testing.T) {
+	errInit := errors.New("init")
+	var calls int
+	l := NewLazy(func() (int, error) {
+		calls++
+		return 0, errInit
+	}, WithLazyCacheError())
+
+	_, _ = l.Get()
+	_, err := l.Get()
+	assert.Equal(t, errInit, err)
+	assert.Equal(t, 1, calls)
+
+	l.Reset()
+	_, _ = l.Get()
+	assert.Equal(t, 2, calls)
+}
+
+func TestLazyPanic(t *testing.T) {
+	var calls int
+	l := NewLazy(func() (int, error) {
+		calls++
+		if calls == 1 {
+			panic("boom")
+		}
+		return 1, nil
+	})
+
+	assert.Panics(t, func() { _, _ = l.Get() })
+	v, err := l.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+}
+
+func TestLazyConcurrent(t *testing.T) {
+	var calls atomic.Int32
+	get := OnceValue(func() (int32, error) {
+		return calls.Add(1), nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := get()
+			assert.NoError(t, err)
+			assert.EqualValues(t, 1, v)
+		}()
+	}
+	wg.Wait()
+	assert.EqualValues(t, 1, calls.Load())
+}
@@ -18,21 +18,40 @@ package xsync
 
 import (
 	"github.com/chenquan/go-pkg/xerror"
+	"github.com/chenquan/go-pkg/xlog"
 	"golang.org/x/sync/singleflight"
 	"io"
 	"sync"
 )
 
-// ResourceManager is a resource manager.
-type ResourceManager struct {
-	rw           sync.RWMutex
-	resources    map[string]io.Closer
-	singleFlight singleflight.Group
+type (
+	// ResourceManager is a resource manager.
+	ResourceManager struct {
+		rw           sync.RWMutex
+		resources    map[string]io.Closer
+		singleFlight singleflight.Group
+		logger       xlog.Logger
+	}
+
+	// ResourceManagerOption defines the method to customize a ResourceManager.
+	ResourceManagerOption func(m *ResourceManager)
+)
+
+// WithResourceLogger customizes the Logger reporting resource failures, default to xlog.Nop.
+func WithResourceLogger(logger xlog.Logger) ResourceManagerOption {
+	return func(m *ResourceManager) {
+		m.logger = xlog.OrNop(logger)
+	}
 }
 
 // NewResourceManager returns a ResourceManager.
-func NewResourceManager() *ResourceManager {
-	return &ResourceManager{resources: map[string]io.Closer{}}
+func NewResourceManager(opts ...ResourceManagerOption) *ResourceManager {
+	m := &ResourceManager{resources: map[string]io.Closer{}, logger: xlog.Nop()}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
 }
 
 // Close the manager.
@@ -41,8 +60,9 @@ func (m *ResourceManager) Close() error {
 	m.rw.Lock()
 
 	var be xerror.BatchError
-	for _, resource := range m.resources {
+	for key, resource := range m.resources {
 		if err := resource.Close(); err != nil {
+			m.logger.Error("xsync: failed to close resource", "key", key, "err", err)
 			be.Add(err)
 		}
 	}
@@ -65,6 +85,7 @@ func (m *ResourceManager) Get(key string, create func() (io.Closer, error)) (io.
 
 		resource, err := create()
 		if err != nil {
+			m.logger.Warn("xsync: failed to create resource", "key", key, "err", err)
 			return nil, err
 		}
 
@@ -17,7 +17,9 @@
 package xsync
 
 import (
+	"bytes"
 	"errors"
+	"github.com/chenquan/go-pkg/xlog"
 	"github.com/stretchr/testify/assert"
 	"io"
 	"testing"
@@ -101,3 +103,22 @@ func TestResourceManager_Remove(t *testing.T) {
 	assert.NotNil(t, closer)
 	assert.True(t, manager.Remove("key"))
 }
+
+func TestResourceManager_WithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	manager := NewResourceManager(WithResourceLogger(xlog.NewLogger(&buf, xlog.WithTimeFormat(""))))
+
+	_, err := manager.Get("bad", func() (io.Closer, error) {
+		return nil, errors.New("fail")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, "WARN xsync: failed to create resource key=bad err=fail\n", buf.String())
+
+	buf.Reset()
+	_, err = manager.Get("key", func() (io.Closer, error) {
+		return &dummyResource{}, nil
+	})
+	assert.NoError(t, err)
+	assert.Error(t, manager.Close())
+	assert.Equal(t, "ERROR xsync: failed to close resource key=key err=close\n", buf.String())
+}
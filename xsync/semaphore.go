@@ -0,0 +1,172 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xsync
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrWeightTooLarge is returned by Semaphore.Acquire when the requested weight exceeds the size of the Semaphore.
+var ErrWeightTooLarge = errors.New("xsync: weight exceeds semaphore size")
+
+// SemaphoreStats is a snapshot of the state of a Semaphore.
+type SemaphoreStats struct {
+	// Size is the maximum combined weight.
+	Size int64
+	// InFlight is the combined weight currently held.
+	InFlight int64
+	// Waiters is the number of blocked Acquire calls.
+	Waiters int
+	// Waiting is the combined weight requested by blocked Acquire calls.
+	Waiting int64
+}
+
+// A Semaphore is a weighted semaphore, limiting the combined weight held at once.
+// Waiters are served in FIFO order, so a large request isn't starved by a stream of small ones.
+type Semaphore struct {
+	size    int64
+	cur     int64
+	waiting int64
+	mu      sync.Mutex
+	waiters list.List
+}
+
+type semaphoreWaiter struct {
+	n     int64
+	ready chan struct{}
+}
+
+// NewSemaphore returns a Semaphore with the given maximum combined weight, it panics if size < 1.
+func NewSemaphore(size int64) *Semaphore {
+	if size < 1 {
+		panic("xsync: semaphore size must be positive")
+	}
+
+	return &Semaphore{size: size}
+}
+
+// Acquire acquires n units of weight, blocking until they are available or ctx is done.
+// On failure it returns ctx.Err() or ErrWeightTooLarge and leaves the Semaphore unchanged.
+func (s *Semaphore) Acquire(ctx context.Context, n int64) error {
+	if n > s.size {
+		return ErrWeightTooLarge
+	}
+
+	s.mu.Lock()
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		s.mu.Unlock()
+		return nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		s.mu.Unlock()
+		return err
+	}
+
+	w := &semaphoreWaiter{n: n, ready: make(chan struct{})}
+	elem := s.waiters.PushBack(w)
+	s.waiting += n
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		select {
+		case <-w.ready:
+			// Acquired right as ctx was done, give the weight back.
+			s.cur -= n
+			s.notifyWaiters()
+		default:
+			isFront := s.waiters.Front() == elem
+			s.waiters.Remove(elem)
+			s.waiting -= n
+			// The waiters behind the removed front one may fit now.
+			if isFront && s.size > s.cur {
+				s.notifyWaiters()
+			}
+		}
+		s.mu.Unlock()
+
+		return ctx.Err()
+	}
+}
+
+// TryAcquire acquires n units of weight without blocking and reports whether it succeeded.
+func (s *Semaphore) TryAcquire(n int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		return true
+	}
+
+	return false
+}
+
+// Release releases n units of weight, it panics if more weight is released than held.
+func (s *Semaphore) Release(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cur -= n
+	if s.cur < 0 {
+		s.cur += n
+		panic("xsync: semaphore released more than held")
+	}
+	s.notifyWaiters()
+}
+
+// Stats returns a snapshot of the state of s.
+func (s *Semaphore) Stats() SemaphoreStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return SemaphoreStats{
+		Size:     s.size,
+		InFlight: s.cur,
+		Waiters:  s.waiters.Len(),
+		Waiting:  s.waiting,
+	}
+}
+
+// notifyWaiters wakes waiters in FIFO order while they fit, s.mu must be held.
+func (s *Semaphore) notifyWaiters() {
+	for {
+		front := s.waiters.Front()
+		if front == nil {
+			return
+		}
+
+		w := front.Value.(*semaphoreWaiter)
+		if s.size-s.cur < w.n {
+			// Stop at the first waiter that doesn't fit to avoid starving it.
+			return
+		}
+
+		s.cur += w.n
+		s.waiting -= w.n
+		s.waiters.Remove(front)
+		close(w.ready)
+	}
+}
@@ -0,0 +1,138 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xsync
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewSemaphore(t *testing.T) {
+	assert.Panics(t, func() { NewSemaphore(0) })
+}
+
+func TestSemaphoreAcquireRelease(t *testing.T) {
+	s := NewSemaphore(10)
+	ctx := context.Background()
+
+	assert.NoError(t, s.Acquire(ctx, 4))
+	assert.NoError(t, s.Acquire(ctx, 6))
+	assert.False(t, s.TryAcquire(1))
+	assert.Equal(t, SemaphoreStats{Size: 10, InFlight: 10}, s.Stats())
+
+	s.Release(6)
+	assert.True(t, s.TryAcquire(6))
+	s.Release(10)
+	assert.Equal(t, int64(0), s.Stats().InFlight)
+
+	assert.Panics(t, func() { s.Release(1) })
+	assert.Equal(t, ErrWeightTooLarge, s.Acquire(ctx, 11))
+}
+
+func TestSemaphoreAcquireContext(t *testing.T) {
+	s := NewSemaphore(2)
+	assert.True(t, s.TryAcquire(2))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, s.Acquire(ctx, 1), context.DeadlineExceeded)
+
+	stats := s.Stats()
+	assert.Equal(t, 0, stats.Waiters)
+	assert.Equal(t, int64(0), stats.Waiting)
+	assert.Equal(t, int64(2), stats.InFlight)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, s.Acquire(canceled, 1), context.Canceled)
+}
+
+func TestSemaphoreFairness(t *testing.T) {
+	s := NewSemaphore(4)
+	ctx := context.Background()
+	assert.True(t, s.TryAcquire(1))
+
+	large := make(chan struct{})
+	go func() {
+		assert.NoError(t, s.Acquire(ctx, 4))
+		close(large)
+	}()
+	assert.Eventually(t, func() bool { return s.Stats().Waiters == 1 }, time.Second, time.Millisecond)
+
+	// A small request doesn't jump ahead of the queued large one.
+	assert.False(t, s.TryAcquire(1))
+	assert.Equal(t, int64(4), s.Stats().Waiting)
+
+	s.Release(1)
+	<-large
+	assert.Equal(t, int64(4), s.Stats().InFlight)
+	s.Release(4)
+}
+
+func TestSemaphoreCanceledFrontWakesOthers(t *testing.T) {
+	s := NewSemaphore(4)
+	assert.True(t, s.TryAcquire(2))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	front := make(chan error)
+	go func() { front <- s.Acquire(ctx, 4) }()
+	assert.Eventually(t, func() bool { return s.Stats().Waiters == 1 }, time.Second, time.Millisecond)
+
+	small := make(chan struct{})
+	go func() {
+		assert.NoError(t, s.Acquire(context.Background(), 2))
+		close(small)
+	}()
+	assert.Eventually(t, func() bool { return s.Stats().Waiters == 2 }, time.Second, time.Millisecond)
+
+	cancel()
+	assert.ErrorIs(t, <-front, context.Canceled)
+	<-small
+	assert.Equal(t, int64(4), s.Stats().InFlight)
+}
+
+func TestSemaphoreConcurrent(t *testing.T) {
+	s := NewSemaphore(5)
+	var inFlight, peak atomic.Int64
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(n int64) {
+			defer wg.Done()
+			assert.NoError(t, s.Acquire(context.Background(), n))
+			cur := inFlight.Add(n)
+			for {
+				old := peak.Load()
+				if cur <= old || peak.CompareAndSwap(old, cur) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			inFlight.Add(-n)
+			s.Release(n)
+		}(int64(i%3 + 1))
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, peak.Load(), int64(5))
+	assert.Equal(t, SemaphoreStats{Size: 5}, s.Stats())
+}
@@ -0,0 +1,135 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xsync
+
+import (
+	"context"
+	"github.com/chenquan/go-pkg/xerror"
+	"sync"
+)
+
+type (
+	// A Group deduplicates concurrent calls for the same key: while a call is in flight,
+	// later callers wait for its result instead of calling fn again.
+	// The zero value is ready to use.
+	Group[K comparable, V any] struct {
+		lock  sync.Mutex
+		calls map[K]*call[V]
+	}
+
+	call[V any] struct {
+		done  chan struct{}
+		value V
+		err   error
+		// dups is the number of callers sharing the result, guarded by the Group lock.
+		dups int
+		// panicked holds the panic of fn, callers panic with it too.
+		panicked *xerror.PanicError
+	}
+)
+
+// Do calls fn and returns its results, making sure only one call for key is in flight at a time.
+// A caller arriving while a call is in flight waits for it and receives the same results,
+// shared reports whether the results were given to several callers.
+// If fn panics, every caller panics with a *xerror.PanicError.
+func (g *Group[K, V]) Do(key K, fn func() (V, error)) (v V, err error, shared bool) {
+	g.lock.Lock()
+	if g.calls == nil {
+		g.calls = make(map[K]*call[V])
+	}
+	if c, ok := g.calls[key]; ok {
+		c.dups++
+		g.lock.Unlock()
+		<-c.done
+
+		return c.result(true)
+	}
+
+	c := &call[V]{done: make(chan struct{})}
+	g.calls[key] = c
+	g.lock.Unlock()
+
+	g.doCall(c, key, fn)
+
+	return c.result(c.dups > 0)
+}
+
+// DoContext is like Do, but a caller stops waiting and returns ctx.Err() once ctx is done.
+// fn runs in its own goroutine and keeps running for the other callers, it should bound its own work.
+func (g *Group[K, V]) DoContext(ctx context.Context, key K, fn func() (V, error)) (v V, err error, shared bool) {
+	g.lock.Lock()
+	if g.calls == nil {
+		g.calls = make(map[K]*call[V])
+	}
+	c, ok := g.calls[key]
+	if ok {
+		c.dups++
+	} else {
+		c = &call[V]{done: make(chan struct{})}
+		g.calls[key] = c
+		go g.doCall(c, key, fn)
+	}
+	g.lock.Unlock()
+
+	select {
+	case <-c.done:
+		if ok {
+			return c.result(true)
+		}
+
+		g.lock.Lock()
+		shared = c.dups > 0
+		g.lock.Unlock()
+
+		return c.result(shared)
+	case <-ctx.Done():
+		return v, ctx.Err(), false
+	}
+}
+
+// Forget makes the next call for key run fn even if a call is in flight,
+// whose callers still receive its results.
+func (g *Group[K, V]) Forget(key K) {
+	g.lock.Lock()
+	delete(g.calls, key)
+	g.lock.Unlock()
+}
+
+func (g *Group[K, V]) doCall(c *call[V], key K, fn func() (V, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.panicked = xerror.NewPanicError(r)
+		}
+
+		g.lock.Lock()
+		if g.calls[key] == c {
+			delete(g.calls, key)
+		}
+		g.lock.Unlock()
+		close(c.done)
+	}()
+
+	c.value, c.err = fn()
+}
+
+func (c *call[V]) result(shared bool) (V, error, bool) {
+	if c.panicked != nil {
+		panic(c.panicked)
+	}
+
+	return c.value, c.err, shared
+}
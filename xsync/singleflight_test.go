@@ -0,0 +1,192 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xsync
+
+import (
+	"context"
+	"errors"
+	"github.com/chenquan/go-pkg/xerror"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroup_Do(t *testing.T) {
+	var g Group[string, int]
+	v, err, shared := g.Do("key", func() (int, error) {
+		return 1, nil
+	})
+	assert.Equal(t, 1, v)
+	assert.Nil(t, err)
+	assert.False(t, shared)
+
+	errFoo := errors.New("foo")
+	_, err, _ = g.Do("key", func() (int, error) {
+		return 0, errFoo
+	})
+	assert.Equal(t, errFoo, err)
+}
+
+func TestGroup_DoDedup(t *testing.T) {
+	var (
+		g       Group[string, int]
+		calls   atomic.Int32
+		release = make(chan struct{})
+		wg      sync.WaitGroup
+	)
+	fn := func() (int, error) {
+		calls.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	const n = 10
+	var sharedCount atomic.Int32
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err, shared := g.Do("key", fn)
+			assert.Equal(t, 42, v)
+			assert.Nil(t, err)
+			if shared {
+				sharedCount.Add(1)
+			}
+		}()
+	}
+	// wait for the callers to join the call in flight.
+	assert.Eventually(t, func() bool {
+		g.lock.Lock()
+		defer g.lock.Unlock()
+		c := g.calls["key"]
+		return c != nil && c.dups == n-1
+	}, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, int32(n), sharedCount.Load())
+}
+
+func TestGroup_DoPanic(t *testing.T) {
+	var g Group[int, int]
+	assert.PanicsWithError(t, "boom", func() {
+		defer func() {
+			r := recover()
+			pe, ok := r.(*xerror.PanicError)
+			assert.True(t, ok)
+			assert.Contains(t, pe.Error(), "panic: boom")
+			assert.NotEmpty(t, pe.Stack)
+			panic(pe.Unwrap())
+		}()
+		g.Do(1, func() (int, error) {
+			panic(errors.New("boom"))
+		})
+	})
+
+	// the key is released after a panic.
+	v, _, _ := g.Do(1, func() (int, error) { return 2, nil })
+	assert.Equal(t, 2, v)
+}
+
+func TestGroup_Forget(t *testing.T) {
+	var (
+		g       Group[string, int]
+		started = make(chan struct{})
+		release = make(chan struct{})
+		first   = make(chan int)
+	)
+	go func() {
+		v, _, _ := g.Do("key", func() (int, error) {
+			close(started)
+			<-release
+			return 1, nil
+		})
+		first <- v
+	}()
+	<-started
+
+	g.Forget("key")
+	v, _, shared := g.Do("key", func() (int, error) {
+		return 2, nil
+	})
+	assert.Equal(t, 2, v)
+	assert.False(t, shared)
+
+	close(release)
+	assert.Equal(t, 1, <-first)
+}
+
+func TestGroup_DoContext(t *testing.T) {
+	var (
+		g       Group[string, string]
+		release = make(chan struct{})
+		calls   atomic.Int32
+	)
+	fn := func() (string, error) {
+		calls.Add(1)
+		<-release
+		return "done", nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err, _ := g.DoContext(ctx, "key", fn)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// the call keeps running for the other callers.
+	result := make(chan string)
+	go func() {
+		v, err, shared := g.DoContext(context.Background(), "key", fn)
+		assert.Nil(t, err)
+		assert.True(t, shared)
+		result <- v
+	}()
+	assert.Eventually(t, func() bool {
+		g.lock.Lock()
+		defer g.lock.Unlock()
+		return g.calls["key"].dups == 1
+	}, time.Second, time.Millisecond)
+	close(release)
+	assert.Equal(t, "done", <-result)
+	assert.Equal(t, int32(1), calls.Load())
+
+	v, err, shared := g.DoContext(context.Background(), "other", func() (string, error) {
+		return "x", nil
+	})
+	assert.Equal(t, "x", v)
+	assert.Nil(t, err)
+	assert.False(t, shared)
+
+	assert.Panics(t, func() {
+		g.DoContext(context.Background(), "panic", func() (string, error) {
+			panic("boom")
+		})
+	})
+}
+
+func BenchmarkGroup_Do(b *testing.B) {
+	var g Group[int, int]
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			g.Do(1, func() (int, error) { return 1, nil })
+		}
+	})
+}
@@ -0,0 +1,109 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xsync
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// A WaitGroupCtx waits for a collection of goroutines to finish like sync.WaitGroup,
+// but its waits can be abandoned on cancellation or timeout.
+// The zero value is ready to use.
+type WaitGroupCtx struct {
+	mu   sync.Mutex
+	n    int
+	done chan struct{}
+}
+
+// Add adds delta, which may be negative, to the counter, it panics if the counter goes negative.
+func (wg *WaitGroupCtx) Add(delta int) {
+	wg.mu.Lock()
+	defer wg.mu.Unlock()
+
+	wg.n += delta
+	if wg.n < 0 {
+		panic("xsync: negative WaitGroupCtx counter")
+	}
+	if wg.n == 0 && wg.done != nil {
+		close(wg.done)
+		wg.done = nil
+	}
+}
+
+// Done decrements the counter by one.
+func (wg *WaitGroupCtx) Done() {
+	wg.Add(-1)
+}
+
+// Go calls fn in a new goroutine, tracked by wg.
+func (wg *WaitGroupCtx) Go(fn func()) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		fn()
+	}()
+}
+
+// Wait blocks until the counter is zero or ctx is done, in which case it returns ctx.Err().
+func (wg *WaitGroupCtx) Wait(ctx context.Context) error {
+	done := wg.doneChan()
+	if done == nil {
+		return nil
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// WaitTimeout blocks until the counter is zero or d elapses, in which case it returns context.DeadlineExceeded.
+func (wg *WaitGroupCtx) WaitTimeout(d time.Duration) error {
+	done := wg.doneChan()
+	if done == nil {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return nil
+	case <-timer.C:
+		return context.DeadlineExceeded
+	}
+}
+
+// doneChan returns a channel closed once the counter reaches zero, or nil if it already is zero.
+func (wg *WaitGroupCtx) doneChan() <-chan struct{} {
+	wg.mu.Lock()
+	defer wg.mu.Unlock()
+
+	if wg.n == 0 {
+		return nil
+	}
+	if wg.done == nil {
+		wg.done = make(chan struct{})
+	}
+
+	return wg.done
+}
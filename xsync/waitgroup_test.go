@@ -0,0 +1,74 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xsync
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWaitGroupCtx(t *testing.T) {
+	var wg WaitGroupCtx
+	assert.NoError(t, wg.Wait(context.Background()))
+
+	var n atomic.Int32
+	for i := 0; i < 10; i++ {
+		wg.Go(func() { n.Add(1) })
+	}
+	assert.NoError(t, wg.Wait(context.Background()))
+	assert.EqualValues(t, 10, n.Load())
+
+	assert.Panics(t, func() { wg.Done() })
+}
+
+func TestWaitGroupCtxCancel(t *testing.T) {
+	var wg WaitGroupCtx
+	wg.Add(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, wg.Wait(ctx), context.DeadlineExceeded)
+
+	// The group stays usable after an abandoned wait.
+	wg.Done()
+	assert.NoError(t, wg.Wait(context.Background()))
+}
+
+func TestWaitGroupCtxWaitTimeout(t *testing.T) {
+	var wg WaitGroupCtx
+	assert.NoError(t, wg.WaitTimeout(time.Millisecond))
+
+	release := make(chan struct{})
+	wg.Go(func() { <-release })
+	assert.ErrorIs(t, wg.WaitTimeout(10*time.Millisecond), context.DeadlineExceeded)
+
+	close(release)
+	assert.NoError(t, wg.WaitTimeout(time.Second))
+}
+
+func TestWaitGroupCtxReuse(t *testing.T) {
+	var wg WaitGroupCtx
+	for i := 0; i < 3; i++ {
+		wg.Add(2)
+		go wg.Done()
+		go wg.Done()
+		assert.NoError(t, wg.WaitTimeout(time.Second))
+	}
+}
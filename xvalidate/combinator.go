@@ -0,0 +1,227 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xvalidate
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// A Check is a composable rule for values of type T, used with Field.
+// It returns the machine-readable code and param of the violation, and false if value is invalid.
+type Check[T any] func(value T) (code, param string, ok bool)
+
+// NewCheck returns a Check reporting code and param when fn returns false.
+func NewCheck[T any](code, param string, fn func(T) bool) Check[T] {
+	return func(value T) (string, string, bool) {
+		if fn(value) {
+			return "", "", true
+		}
+		return code, param, false
+	}
+}
+
+// Field validates value named name against checks in order, stopping at the first failure.
+// It returns a *FieldError on failure, nil otherwise.
+func Field[T any](name string, value T, checks ...Check[T]) error {
+	if code, param, ok := All(checks...)(value); !ok {
+		return &FieldError{Path: name, Code: code, Param: param, Value: value}
+	}
+
+	return nil
+}
+
+// Validate collects the results of Field and Nested calls into Errors.
+// It returns nil if all results are nil, and returns the first error that
+// isn't a violation as is.
+func Validate(results ...error) error {
+	var errs Errors
+	for _, err := range results {
+		if err == nil {
+			continue
+		}
+
+		var fe *FieldError
+		var es Errors
+		switch {
+		case errors.As(err, &es):
+			errs = append(errs, es...)
+		case errors.As(err, &fe):
+			errs = append(errs, fe)
+		default:
+			return err
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// Nested prefixes the paths of the violations in err with name,
+// used to validate nested structures with their own validation functions.
+func Nested(name string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var fe *FieldError
+	var es Errors
+	switch {
+	case errors.As(err, &es):
+		nested := make(Errors, len(es))
+		for i, e := range es {
+			nested[i] = prefixed(name, e)
+		}
+		return nested
+	case errors.As(err, &fe):
+		return prefixed(name, fe)
+	default:
+		return err
+	}
+}
+
+func prefixed(name string, e *FieldError) *FieldError {
+	path := name
+	switch {
+	case e.Path == "":
+	case strings.HasPrefix(e.Path, "["):
+		path += e.Path
+	default:
+		path = joinPath(name, e.Path)
+	}
+
+	return &FieldError{Path: path, Code: e.Code, Param: e.Param, Value: e.Value}
+}
+
+// Required checks that the value isn't the zero value.
+func Required[T comparable]() Check[T] {
+	return NewCheck("required", "", func(v T) bool {
+		var zero T
+		return v != zero
+	})
+}
+
+// Length checks that the rune count of a string is within [min, max].
+func Length(min, max int) Check[string] {
+	return NewCheck("length", strconv.Itoa(min)+" "+strconv.Itoa(max), func(s string) bool {
+		n := utf8.RuneCountInString(s)
+		return n >= min && n <= max
+	})
+}
+
+// Matches checks that a string matches re.
+func Matches(re *regexp.Regexp) Check[string] {
+	return NewCheck("matches", re.String(), re.MatchString)
+}
+
+// Min checks that the value is greater than or equal to min.
+func Min[T cmp.Ordered](min T) Check[T] {
+	return NewCheck("min", fmt.Sprint(min), func(v T) bool {
+		return v >= min
+	})
+}
+
+// Max checks that the value is less than or equal to max.
+func Max[T cmp.Ordered](max T) Check[T] {
+	return NewCheck("max", fmt.Sprint(max), func(v T) bool {
+		return v <= max
+	})
+}
+
+// OneOf checks that the value equals one of values.
+func OneOf[T comparable](values ...T) Check[T] {
+	params := make([]string, len(values))
+	for i, v := range values {
+		params[i] = fmt.Sprint(v)
+	}
+
+	return NewCheck("oneof", strings.Join(params, " "), func(v T) bool {
+		for _, value := range values {
+			if v == value {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Format checks a string with a format function, e.g. Format("email", IsEmail).
+func Format(code string, fn func(string) bool) Check[string] {
+	return NewCheck(code, "", fn)
+}
+
+// All checks that all checks pass, reporting the first failure.
+func All[T any](checks ...Check[T]) Check[T] {
+	return func(v T) (string, string, bool) {
+		for _, c := range checks {
+			if code, param, ok := c(v); !ok {
+				return code, param, false
+			}
+		}
+		return "", "", true
+	}
+}
+
+// Any checks that at least one of checks passes.
+// On failure it reports the code "any" with the codes of all failed checks as param.
+func Any[T any](checks ...Check[T]) Check[T] {
+	return func(v T) (string, string, bool) {
+		if len(checks) == 0 {
+			return "", "", true
+		}
+
+		codes := make([]string, 0, len(checks))
+		for _, c := range checks {
+			code, _, ok := c(v)
+			if ok {
+				return "", "", true
+			}
+			codes = append(codes, code)
+		}
+		return "any", strings.Join(codes, " "), false
+	}
+}
+
+// When applies checks only if cond is true, used for conditional and cross-field rules.
+func When[T any](cond bool, checks ...Check[T]) Check[T] {
+	if !cond {
+		return func(T) (string, string, bool) {
+			return "", "", true
+		}
+	}
+
+	return All(checks...)
+}
+
+// WhenFunc is like When but evaluates cond with the value.
+func WhenFunc[T any](cond func(T) bool, checks ...Check[T]) Check[T] {
+	all := All(checks...)
+
+	return func(v T) (string, string, bool) {
+		if !cond(v) {
+			return "", "", true
+		}
+		return all(v)
+	}
+}
@@ -0,0 +1,134 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xvalidate
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"testing"
+)
+
+type (
+	signup struct {
+		Name        string
+		Kind        string
+		CompanyName string
+		Password    string
+		Confirm     string
+		Age         int
+		Address     address
+	}
+)
+
+func validateAddress(a address) error {
+	return Validate(
+		Field("City", a.City, Required[string]()),
+		Field("Zip", a.Zip, When(a.Zip != "", Length(6, 6))),
+	)
+}
+
+func validateSignup(s signup) error {
+	return Validate(
+		Field("Name", s.Name, Required[string](), Length(3, 50), Matches(regexp.MustCompile(`^[a-z]+$`))),
+		Field("Kind", s.Kind, OneOf("person", "company")),
+		Field("CompanyName", s.CompanyName, When(s.Kind == "company", Required[string]())),
+		Field("Confirm", s.Confirm, NewCheck("eqfield", "Password", func(v string) bool {
+			return v == s.Password
+		})),
+		Field("Age", s.Age, Min(18), Max(150)),
+		Nested("Address", validateAddress(s.Address)),
+	)
+}
+
+func TestCombinator(t *testing.T) {
+	assert.NoError(t, validateSignup(signup{
+		Name:     "bob",
+		Kind:     "person",
+		Password: "pw",
+		Confirm:  "pw",
+		Age:      20,
+		Address:  address{City: "x"},
+	}))
+
+	err := validateSignup(signup{
+		Name:     "Bob",
+		Kind:     "company",
+		Password: "pw",
+		Confirm:  "wp",
+		Age:      10,
+		Address:  address{Zip: "1"},
+	})
+	assert.Equal(t, map[string]string{
+		"Name":         "matches",
+		"CompanyName":  "required",
+		"Confirm":      "eqfield",
+		"Age":          "min",
+		"Address.City": "required",
+		"Address.Zip":  "length",
+	}, codes(err))
+
+	var errs Errors
+	assert.True(t, errors.As(err, &errs))
+	assert.Equal(t, "18", errs.ByPath("Age")[0].Param)
+	assert.Equal(t, 10, errs.ByPath("Age")[0].Value)
+	assert.Equal(t, "6 6", errs.ByPath("Address.Zip")[0].Param)
+}
+
+func TestAnyAll(t *testing.T) {
+	emailOrPhone := Any(Format("email", IsEmail), Format("cnphone", IsCNPhone))
+	assert.NoError(t, Field("contact", "a@b.com", emailOrPhone))
+	assert.NoError(t, Field("contact", "13800138000", emailOrPhone))
+
+	err := Field("contact", "x", emailOrPhone)
+	var fe *FieldError
+	assert.True(t, errors.As(err, &fe))
+	assert.Equal(t, "any", fe.Code)
+	assert.Equal(t, "email cnphone", fe.Param)
+	assert.NoError(t, Field("empty", "x", Any[string]()))
+
+	all := All(Min(1), Max(10))
+	assert.NoError(t, Field("n", 5, all))
+	assert.Equal(t, map[string]string{"n": "max"}, codes(Validate(Field("n", 11, all))))
+	assert.NoError(t, Field("n", 5))
+}
+
+func TestWhenFunc(t *testing.T) {
+	check := WhenFunc(func(s string) bool { return s != "" }, Length(2, 3))
+	assert.NoError(t, Field("s", "", check))
+	assert.NoError(t, Field("s", "ab", check))
+	assert.Error(t, Field("s", "a", check))
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate())
+	assert.NoError(t, Validate(nil, nil))
+
+	other := errors.New("other")
+	assert.Equal(t, other, Validate(Field("a", "", Required[string]()), other))
+	assert.Equal(t, other, Nested("a", other))
+	assert.NoError(t, Nested("a", nil))
+
+	err := Nested("Items", Validate(Field("[0]", "", Required[string]()), Field("", "", Required[string]())))
+	assert.Equal(t, map[string]string{"Items[0]": "required", "Items": "required"}, codes(err))
+	err = Nested("User", Field("Name", "", Required[string]()))
+	assert.Equal(t, map[string]string{"User.Name": "required"}, codes(Validate(err)))
+
+	// tag validation results can be combined too.
+	err = Validate(Struct(address{}), Field("Extra", 0, Min(1)))
+	assert.Equal(t, map[string]string{"City": "required", "Extra": "min"}, codes(err))
+}
@@ -0,0 +1,76 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xvalidate
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrInvalidTarget is returned when validating something that isn't a struct or a pointer to a struct.
+	ErrInvalidTarget = errors.New("xvalidate: target must be a struct or a pointer to a struct")
+)
+
+type (
+	// A FieldError describes a single violation.
+	FieldError struct {
+		// Path is the full path of the field, e.g. "Address.City" or "Items[0].Name".
+		Path string
+		// Code is the machine-readable name of the failed rule, e.g. "required" or "min".
+		Code string
+		// Param is the rule parameter, e.g. "3" for "min=3".
+		Param string
+		// Value is the value that failed validation.
+		Value interface{}
+	}
+
+	// Errors is the list of all violations, it's returned as the error of a failed validation.
+	Errors []*FieldError
+)
+
+// Error returns a string that represents the violation.
+func (e *FieldError) Error() string {
+	rule := e.Code
+	if e.Param != "" {
+		rule += "=" + e.Param
+	}
+
+	return e.Path + ": failed on rule '" + rule + "'"
+}
+
+// Error returns a string that represents all violations.
+func (es Errors) Error() string {
+	msgs := make([]string, len(es))
+	for i, e := range es {
+		msgs[i] = e.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// ByPath returns the violations of the field with the given path.
+func (es Errors) ByPath(path string) Errors {
+	var found Errors
+	for _, e := range es {
+		if e.Path == path {
+			found = append(found, e)
+		}
+	}
+
+	return found
+}
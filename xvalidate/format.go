@@ -0,0 +1,139 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xvalidate
+
+import (
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	emailRegexp   = regexp.MustCompile(`^[a-zA-Z0-9.!#$%&'*+/=?^_` + "`" + `{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)+$`)
+	uuidRegexp    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	cnPhoneRegexp = regexp.MustCompile(`^(?:\+?86)?1[3-9]\d{9}$`)
+
+	idCardWeights = [17]int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+	idCardChecks  = "10X98765432"
+)
+
+// IsEmail returns true if s is an email address like "user@example.com".
+func IsEmail(s string) bool {
+	return len(s) <= 254 && emailRegexp.MatchString(s)
+}
+
+// IsURL returns true if s is an absolute URL with a scheme and a host.
+func IsURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+// IsUUID returns true if s is a UUID in the canonical 8-4-4-4-12 hex form.
+func IsUUID(s string) bool {
+	return uuidRegexp.MatchString(s)
+}
+
+// IsIP returns true if s is an IPv4 or IPv6 address.
+func IsIP(s string) bool {
+	return net.ParseIP(s) != nil
+}
+
+// IsIPv4 returns true if s is an IPv4 address.
+func IsIPv4(s string) bool {
+	ip := net.ParseIP(s)
+	return ip != nil && ip.To4() != nil && !strings.Contains(s, ":")
+}
+
+// IsIPv6 returns true if s is an IPv6 address.
+func IsIPv6(s string) bool {
+	ip := net.ParseIP(s)
+	return ip != nil && strings.Contains(s, ":")
+}
+
+// IsCIDR returns true if s is a CIDR notation IP address and prefix length, like "192.0.2.0/24".
+func IsCIDR(s string) bool {
+	_, _, err := net.ParseCIDR(s)
+	return err == nil
+}
+
+// IsCNPhone returns true if s is a mainland China mobile phone number, optionally prefixed by "86" or "+86".
+func IsCNPhone(s string) bool {
+	return cnPhoneRegexp.MatchString(s)
+}
+
+// IsCNIDCard returns true if s is an 18-digit mainland China resident identity card number
+// with a valid birth date and check digit.
+func IsCNIDCard(s string) bool {
+	if len(s) != 18 {
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < 17; i++ {
+		c := s[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		sum += int(c-'0') * idCardWeights[i]
+	}
+
+	birthday, err := time.Parse("20060102", s[6:14])
+	if err != nil || birthday.After(time.Now()) {
+		return false
+	}
+
+	last := s[17]
+	if last == 'x' {
+		last = 'X'
+	}
+
+	return idCardChecks[sum%11] == last
+}
+
+// IsBankCard returns true if s is a 12 to 19 digit card number passing the Luhn check.
+func IsBankCard(s string) bool {
+	if len(s) < 12 || len(s) > 19 {
+		return false
+	}
+
+	return luhn(s)
+}
+
+func luhn(s string) bool {
+	sum := 0
+	double := false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+
+	return sum%10 == 0
+}
@@ -0,0 +1,75 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xvalidate
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestFormats(t *testing.T) {
+	tests := []struct {
+		name  string
+		fn    func(string) bool
+		valid []string
+		bad   []string
+	}{
+		{"email", IsEmail,
+			[]string{"a@b.co", "first.last+tag@sub.example.com"},
+			[]string{"", "a", "a@", "@b.com", "a@b", "a b@c.com", strings.Repeat("a", 250) + "@b.com"}},
+		{"url", IsURL,
+			[]string{"http://example.com", "https://a.b/c?d=e", "ftp://host:21"},
+			[]string{"", "example.com", "/path", "http://", "://x"}},
+		{"uuid", IsUUID,
+			[]string{"123e4567-e89b-12d3-a456-426614174000", "123E4567-E89B-12D3-A456-426614174000"},
+			[]string{"", "123e4567e89b12d3a456426614174000", "123e4567-e89b-12d3-a456-42661417400g"}},
+		{"ip", IsIP,
+			[]string{"127.0.0.1", "::1", "2001:db8::68"},
+			[]string{"", "256.0.0.1", "1.2.3", "host"}},
+		{"ipv4", IsIPv4,
+			[]string{"10.0.0.1"},
+			[]string{"::1", "::ffff:10.0.0.1", "x"}},
+		{"ipv6", IsIPv6,
+			[]string{"::1", "::ffff:10.0.0.1"},
+			[]string{"10.0.0.1", "x"}},
+		{"cidr", IsCIDR,
+			[]string{"192.0.2.0/24", "2001:db8::/32"},
+			[]string{"192.0.2.0", "192.0.2.0/33"}},
+		{"cnphone", IsCNPhone,
+			[]string{"13800138000", "+8613800138000", "8619912345678"},
+			[]string{"", "12800138000", "1380013800", "138001380001", "+1 13800138000"}},
+		{"cnidcard", IsCNIDCard,
+			[]string{"11010519491231002X", "11010519491231002x", "440308199001010017"},
+			[]string{"", "110105194912310021", "11010519491331002X", "1101051949123100AX", "11010530001231002X"}},
+		{"bankcard", IsBankCard,
+			[]string{"4111111111111111", "6011111111111117", "378282246310005"},
+			[]string{"", "4111111111111112", "41111111111a1111", "12345678901", "41111111111111111111"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, s := range tt.valid {
+				assert.True(t, tt.fn(s), s)
+				assert.NoError(t, Var(s, tt.name), s)
+			}
+			for _, s := range tt.bad {
+				assert.False(t, tt.fn(s), s)
+				assert.Error(t, Var(s, tt.name), s)
+			}
+		})
+	}
+}
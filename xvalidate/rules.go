@@ -0,0 +1,222 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xvalidate
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Rule reports whether v satisfies the rule with the given param.
+// v is never a pointer, nil pointers are handled before rules run.
+type Rule func(v reflect.Value, param string) bool
+
+// paramCheck returns an error if param is malformed for a built-in rule applied to values of type t.
+type paramCheck func(t reflect.Type, param string) error
+
+func builtinRules() map[string]Rule {
+	return map[string]Rule{
+		"required": hasValue,
+		"len":      compareRule(func(a, b float64) bool { return a == b }),
+		"min":      compareRule(func(a, b float64) bool { return a >= b }),
+		"max":      compareRule(func(a, b float64) bool { return a <= b }),
+		"eq":       equalRule(false),
+		"ne":       equalRule(true),
+		"gt":       compareRule(func(a, b float64) bool { return a > b }),
+		"gte":      compareRule(func(a, b float64) bool { return a >= b }),
+		"lt":       compareRule(func(a, b float64) bool { return a < b }),
+		"lte":      compareRule(func(a, b float64) bool { return a <= b }),
+		"oneof":    oneOf,
+		"email":    stringRule(IsEmail),
+		"url":      stringRule(IsURL),
+		"uuid":     stringRule(IsUUID),
+		"ip":       stringRule(IsIP),
+		"ipv4":     stringRule(IsIPv4),
+		"ipv6":     stringRule(IsIPv6),
+		"cidr":     stringRule(IsCIDR),
+		"cnphone":  stringRule(IsCNPhone),
+		"cnidcard": stringRule(IsCNIDCard),
+		"bankcard": stringRule(IsBankCard),
+		"alpha":    stringRule(isAllRunes(unicode.IsLetter)),
+		"alphanum": stringRule(isAllRunes(func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) })),
+		"numeric":  stringRule(isAllRunes(unicode.IsDigit)),
+		"lowercase": stringRule(func(s string) bool {
+			return s == strings.ToLower(s)
+		}),
+		"uppercase": stringRule(func(s string) bool {
+			return s == strings.ToUpper(s)
+		}),
+		"contains": func(v reflect.Value, param string) bool {
+			return v.Kind() == reflect.String && strings.Contains(v.String(), param)
+		},
+		"startswith": func(v reflect.Value, param string) bool {
+			return v.Kind() == reflect.String && strings.HasPrefix(v.String(), param)
+		},
+		"endswith": func(v reflect.Value, param string) bool {
+			return v.Kind() == reflect.String && strings.HasSuffix(v.String(), param)
+		},
+	}
+}
+
+func builtinParamChecks() map[string]paramCheck {
+	return map[string]paramCheck{
+		"len": checkNumber,
+		"min": checkNumber,
+		"max": checkNumber,
+		"eq":  checkEqual,
+		"ne":  checkEqual,
+		"gt":  checkNumber,
+		"gte": checkNumber,
+		"lt":  checkNumber,
+		"lte": checkNumber,
+	}
+}
+
+func checkNumber(_ reflect.Type, param string) error {
+	_, err := strconv.ParseFloat(param, 64)
+	return err
+}
+
+func checkEqual(t reflect.Type, param string) error {
+	switch t.Kind() {
+	case reflect.String:
+		return nil
+	case reflect.Bool:
+		_, err := strconv.ParseBool(param)
+		return err
+	}
+
+	return checkNumber(t, param)
+}
+
+func hasValue(v reflect.Value, _ string) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() > 0
+	case reflect.Invalid:
+		return false
+	default:
+		return !v.IsZero()
+	}
+}
+
+// compareRule compares the size of v with param: the rune count of strings,
+// the length of slices, arrays and maps, and the value of numbers.
+func compareRule(cmp func(a, b float64) bool) Rule {
+	return func(v reflect.Value, param string) bool {
+		size, ok := sizeOf(v)
+		if !ok {
+			return false
+		}
+
+		// params are checked when the tag is compiled.
+		p, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return false
+		}
+
+		return cmp(size, p)
+	}
+}
+
+// equalRule compares strings and bools with param literally, and others by size like compareRule.
+func equalRule(negate bool) Rule {
+	bySize := compareRule(func(a, b float64) bool { return a == b })
+
+	return func(v reflect.Value, param string) bool {
+		var eq bool
+		switch v.Kind() {
+		case reflect.String:
+			eq = v.String() == param
+		case reflect.Bool:
+			b, err := strconv.ParseBool(param)
+			if err != nil {
+				return false
+			}
+			eq = v.Bool() == b
+		default:
+			if _, ok := sizeOf(v); !ok {
+				return false
+			}
+			eq = bySize(v, param)
+		}
+
+		return eq != negate
+	}
+}
+
+func sizeOf(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), true
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+		return float64(v.Len()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+
+	return 0, false
+}
+
+func oneOf(v reflect.Value, param string) bool {
+	var s string
+	switch v.Kind() {
+	case reflect.String:
+		s = v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s = strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		s = strconv.FormatUint(v.Uint(), 10)
+	default:
+		return false
+	}
+
+	for _, option := range strings.Fields(param) {
+		if option == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func stringRule(fn func(s string) bool) Rule {
+	return func(v reflect.Value, _ string) bool {
+		return v.Kind() == reflect.String && fn(v.String())
+	}
+}
+
+func isAllRunes(fn func(r rune) bool) func(s string) bool {
+	return func(s string) bool {
+		if s == "" {
+			return false
+		}
+		for _, r := range s {
+			if !fn(r) {
+				return false
+			}
+		}
+		return true
+	}
+}
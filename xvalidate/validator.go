@@ -0,0 +1,417 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xvalidate
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	defaultTagName = "validate"
+
+	ruleOmitEmpty = "omitempty"
+	ruleDive      = "dive"
+	ruleRequired  = "required"
+)
+
+var defaultValidator = NewValidator()
+
+type (
+	// Option defines the method to customize a Validator.
+	Option func(*Validator)
+
+	// A Validator validates structs with rules declared in struct tags,
+	// e.g. `validate:"required,min=3,max=50,email"`.
+	//
+	// Rules are separated by ",", parameters follow "=".
+	// "omitempty" skips the remaining rules for zero values, and "dive" applies
+	// the rules after it to each element of a slice, array or map.
+	// Nested structs, including those inside slices, arrays and maps, are always validated,
+	// use the tag "-" to skip a field entirely.
+	//
+	// A Validator is safe for concurrent use.
+	Validator struct {
+		tagName  string
+		nameTag  string
+		lock     sync.RWMutex
+		rules    map[string]Rule
+		checks   map[string]paramCheck
+		plans    map[reflect.Type]*structPlan
+		planLock sync.RWMutex
+	}
+
+	structPlan struct {
+		fields []fieldPlan
+	}
+
+	fieldPlan struct {
+		index int
+		name  string
+		rules *ruleSet
+	}
+
+	ruleSet struct {
+		omitEmpty bool
+		rules     []boundRule
+		dive      *ruleSet
+	}
+
+	boundRule struct {
+		name  string
+		param string
+		fn    Rule
+		// check is the param check left to validation time, as the type of interface values isn't known before.
+		check paramCheck
+	}
+)
+
+// WithTagName customizes the struct tag that declares rules, default to "validate".
+func WithTagName(name string) Option {
+	return func(v *Validator) {
+		v.tagName = name
+	}
+}
+
+// WithFieldNameTag uses the name from the given struct tag, e.g. "json", in error paths.
+func WithFieldNameTag(tag string) Option {
+	return func(v *Validator) {
+		v.nameTag = tag
+	}
+}
+
+// NewValidator returns a Validator with the built-in rules.
+func NewValidator(opts ...Option) *Validator {
+	v := &Validator{
+		tagName: defaultTagName,
+		rules:   builtinRules(),
+		checks:  builtinParamChecks(),
+		plans:   map[reflect.Type]*structPlan{},
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v
+}
+
+// RegisterRule registers a custom rule, replacing any rule with the same name.
+func (v *Validator) RegisterRule(name string, rule Rule) {
+	v.lock.Lock()
+	v.rules[name] = rule
+	delete(v.checks, name)
+	v.lock.Unlock()
+
+	// rules are bound into plans.
+	v.planLock.Lock()
+	v.plans = map[reflect.Type]*structPlan{}
+	v.planLock.Unlock()
+}
+
+// Struct validates s, a struct or a pointer to a struct.
+// It returns Errors holding all violations, or another error if s or a tag is invalid,
+// e.g. an unknown rule or a malformed param like "min=abc".
+func (v *Validator) Struct(s interface{}) error {
+	rv := reflect.ValueOf(s)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return ErrInvalidTarget
+	}
+
+	var errs Errors
+	if err := v.validateStruct("", rv, &errs); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// Var validates a single value against the rules in tag, e.g. Var(email, "required,email").
+func (v *Validator) Var(value interface{}, tag string) error {
+	rs, err := v.parseRules(tag, reflect.TypeOf(value))
+	if err != nil {
+		return err
+	}
+
+	var errs Errors
+	if err := v.validateValue("", reflect.ValueOf(value), rs, &errs); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func (v *Validator) validateStruct(path string, sv reflect.Value, errs *Errors) error {
+	plan, err := v.plan(sv.Type())
+	if err != nil {
+		return err
+	}
+
+	for _, f := range plan.fields {
+		if err := v.validateValue(joinPath(path, f.name), sv.Field(f.index), f.rules, errs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v *Validator) validateValue(path string, fv reflect.Value, rs *ruleSet, errs *Errors) error {
+	iv := indirect(fv)
+	if rs != nil {
+		if rs.omitEmpty && !hasValue(fv, "") {
+			return nil
+		}
+
+		for _, r := range rs.rules {
+			target := iv
+			if r.name == ruleRequired {
+				target = fv
+				if fv.Kind() == reflect.Ptr || fv.Kind() == reflect.Interface {
+					target = reflect.ValueOf(!fv.IsNil())
+				}
+			} else if !iv.IsValid() {
+				// nil pointers only fail "required".
+				continue
+			}
+
+			if r.check != nil {
+				if err := r.check(target.Type(), r.param); err != nil {
+					return fmt.Errorf("xvalidate: invalid param %q of rule %q on field %s: %w", r.param, r.name, path, err)
+				}
+			}
+			if !r.fn(target, r.param) {
+				var value interface{}
+				if iv.IsValid() && iv.CanInterface() {
+					value = iv.Interface()
+				}
+				*errs = append(*errs, &FieldError{Path: path, Code: r.name, Param: r.param, Value: value})
+				// report the first violation of the field only, but keep validating its elements.
+				break
+			}
+		}
+	}
+	if !iv.IsValid() {
+		return nil
+	}
+
+	var dive *ruleSet
+	if rs != nil {
+		dive = rs.dive
+	}
+
+	switch iv.Kind() {
+	case reflect.Struct:
+		return v.validateStruct(path, iv, errs)
+	case reflect.Slice, reflect.Array:
+		if dive == nil && !containsStruct(iv.Type().Elem()) {
+			return nil
+		}
+		for i := 0; i < iv.Len(); i++ {
+			if err := v.validateValue(path+"["+strconv.Itoa(i)+"]", iv.Index(i), dive, errs); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if dive == nil && !containsStruct(iv.Type().Elem()) {
+			return nil
+		}
+		iter := iv.MapRange()
+		for iter.Next() {
+			if err := v.validateValue(path+"["+fmt.Sprint(iter.Key().Interface())+"]", iter.Value(), dive, errs); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (v *Validator) plan(t reflect.Type) (*structPlan, error) {
+	v.planLock.RLock()
+	plan, ok := v.plans[t]
+	v.planLock.RUnlock()
+	if ok {
+		return plan, nil
+	}
+
+	plan = &structPlan{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		tag := f.Tag.Get(v.tagName)
+		if tag == "-" {
+			continue
+		}
+		rs, err := v.parseRules(tag, f.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%w on field %s.%s", err, t.Name(), f.Name)
+		}
+		if rs == nil && !containsStruct(f.Type) {
+			continue
+		}
+
+		plan.fields = append(plan.fields, fieldPlan{index: i, name: v.fieldName(f), rules: rs})
+	}
+
+	v.planLock.Lock()
+	v.plans[t] = plan
+	v.planLock.Unlock()
+
+	return plan, nil
+}
+
+// parseRules compiles tag for values of type t, t is nil if unknown.
+func (v *Validator) parseRules(tag string, t reflect.Type) (*ruleSet, error) {
+	if tag == "" {
+		return nil, nil
+	}
+
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+
+	root := &ruleSet{}
+	rs := root
+	t = staticType(t)
+	for _, item := range strings.Split(tag, ",") {
+		item = strings.TrimSpace(item)
+		name, param := item, ""
+		if i := strings.IndexByte(item, '='); i >= 0 {
+			name, param = item[:i], item[i+1:]
+		}
+
+		switch name {
+		case "":
+			continue
+		case ruleOmitEmpty:
+			rs.omitEmpty = true
+		case ruleDive:
+			rs.dive = &ruleSet{}
+			rs = rs.dive
+			if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map) {
+				t = staticType(t.Elem())
+			} else {
+				t = nil
+			}
+		default:
+			fn, ok := v.rules[name]
+			if !ok {
+				return nil, fmt.Errorf("xvalidate: unknown rule %q", name)
+			}
+			r := boundRule{name: name, param: param, fn: fn, check: v.checks[name]}
+			if r.check != nil && t != nil {
+				if err := r.check(t, param); err != nil {
+					return nil, fmt.Errorf("xvalidate: invalid param %q of rule %q: %w", param, name, err)
+				}
+				r.check = nil
+			}
+			rs.rules = append(rs.rules, r)
+		}
+	}
+
+	return root, nil
+}
+
+func (v *Validator) fieldName(f reflect.StructField) string {
+	if v.nameTag == "" {
+		return f.Name
+	}
+
+	name := f.Tag.Get(v.nameTag)
+	if i := strings.IndexByte(name, ','); i >= 0 {
+		name = name[:i]
+	}
+	if name == "" || name == "-" {
+		return f.Name
+	}
+
+	return name
+}
+
+// Struct validates s with the default Validator.
+func Struct(s interface{}) error {
+	return defaultValidator.Struct(s)
+}
+
+// Var validates value against tag with the default Validator.
+func Var(value interface{}, tag string) error {
+	return defaultValidator.Var(value, tag)
+}
+
+// RegisterRule registers a custom rule into the default Validator.
+func RegisterRule(name string, rule Rule) {
+	defaultValidator.RegisterRule(name, rule)
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+
+	return v
+}
+
+// staticType returns the type rules see for values of type t, or nil if it's only known at validation time.
+func staticType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t != nil && t.Kind() == reflect.Interface {
+		return nil
+	}
+
+	return t
+}
+
+// containsStruct reports whether values of t may hold structs to validate recursively.
+func containsStruct(t reflect.Type) bool {
+	for {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		case reflect.Struct, reflect.Interface:
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
@@ -0,0 +1,273 @@
+/*
+ *    Copyright 2021 chenquan
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package xvalidate
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type (
+	address struct {
+		City string `validate:"required"`
+		Zip  string `validate:"omitempty,len=6,numeric"`
+	}
+
+	user struct {
+		Name     string            `validate:"required,min=3,max=50"`
+		Email    string            `validate:"required,email"`
+		Age      int               `validate:"gte=0,lte=150"`
+		Role     string            `validate:"oneof=admin user"`
+		Nickname *string           `validate:"omitempty,min=2"`
+		Address  *address          `validate:"required"`
+		Others   []address         `validate:"max=2"`
+		Tags     []string          `validate:"dive,required,max=5"`
+		Labels   map[string]string `validate:"dive,alphanum"`
+		Matrix   [][]int           `validate:"dive,dive,gt=0"`
+		Ignored  *address          `validate:"-"`
+		internal string            `validate:"required"`
+	}
+)
+
+func validUser() *user {
+	return &user{
+		Name:    "bob",
+		Email:   "bob@example.com",
+		Age:     20,
+		Role:    "admin",
+		Address: &address{City: "Hangzhou", Zip: "310000"},
+		Others:  []address{{City: "Beijing"}},
+		Tags:    []string{"go"},
+		Labels:  map[string]string{"k": "v1"},
+		Matrix:  [][]int{{1, 2}, {3}},
+		Ignored: &address{},
+	}
+}
+
+func codes(err error) map[string]string {
+	var errs Errors
+	if !errors.As(err, &errs) {
+		return nil
+	}
+
+	m := map[string]string{}
+	for _, e := range errs {
+		m[e.Path] = e.Code
+	}
+	return m
+}
+
+func TestStruct(t *testing.T) {
+	assert.NoError(t, Struct(validUser()))
+	assert.NoError(t, Struct(*validUser()))
+
+	nick := "x"
+	u := &user{
+		Name:     "bo",
+		Email:    "bob",
+		Age:      200,
+		Role:     "root",
+		Nickname: &nick,
+		Others:   []address{{City: "a"}, {}, {City: "b", Zip: "12"}},
+		Tags:     []string{"go", "", "toolong"},
+		Labels:   map[string]string{"k": "v-1"},
+		Matrix:   [][]int{{1, 0}},
+	}
+	err := Struct(u)
+	assert.Equal(t, map[string]string{
+		"Name":           "min",
+		"Email":          "email",
+		"Age":            "lte",
+		"Role":           "oneof",
+		"Nickname":       "min",
+		"Address":        "required",
+		"Others":         "max",
+		"Others[1].City": "required",
+		"Others[2].Zip":  "len",
+		"Tags[1]":        "required",
+		"Tags[2]":        "max",
+		"Labels[k]":      "alphanum",
+		"Matrix[0][1]":   "gt",
+	}, codes(err))
+
+	var errs Errors
+	assert.True(t, errors.As(err, &errs))
+	assert.Equal(t, "3", errs.ByPath("Name")[0].Param)
+	assert.Equal(t, "bo", errs.ByPath("Name")[0].Value)
+	assert.Equal(t, "Name: failed on rule 'min=3'", errs.ByPath("Name")[0].Error())
+	assert.True(t, strings.Contains(err.Error(), "Email: failed on rule 'email'"))
+}
+
+func TestStruct_InvalidTarget(t *testing.T) {
+	assert.Equal(t, ErrInvalidTarget, Struct(1))
+	assert.Equal(t, ErrInvalidTarget, Struct((*user)(nil)))
+	assert.Equal(t, ErrInvalidTarget, Struct(nil))
+
+	type bad struct {
+		Name string `validate:"unknown"`
+	}
+	err := Struct(bad{})
+	assert.Error(t, err)
+	var errs Errors
+	assert.False(t, errors.As(err, &errs))
+
+	type badParam struct {
+		Name string `validate:"min=abc"`
+	}
+	err = Struct(badParam{})
+	assert.EqualError(t, err, `xvalidate: invalid param "abc" of rule "min": strconv.ParseFloat: parsing "abc": invalid syntax on field badParam.Name`)
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+	assert.False(t, errors.As(err, &errs))
+
+	assert.ErrorIs(t, Var(true, "eq=yes"), strconv.ErrSyntax)
+	assert.ErrorIs(t, Var([]int{1}, "dive,eq=x"), strconv.ErrSyntax)
+	assert.ErrorIs(t, Var(map[string]bool{}, "dive,ne=maybe"), strconv.ErrSyntax)
+	assert.NoError(t, Var("yes", "eq=yes"))
+
+	// the type of interface values is only known at validation time.
+	type anyParam struct {
+		Value interface{} `validate:"min=abc"`
+	}
+	assert.ErrorIs(t, Struct(anyParam{Value: 1}), strconv.ErrSyntax)
+	assert.NoError(t, Struct(anyParam{}))
+
+	// custom rules parse their own params.
+	v := NewValidator()
+	v.RegisterRule("min", func(v reflect.Value, param string) bool { return param == "abc" })
+	assert.NoError(t, v.Struct(badParam{}))
+}
+
+func TestFieldNameTag(t *testing.T) {
+	type form struct {
+		UserName string `json:"user_name,omitempty" validate:"required"`
+		Other    string `json:"-" validate:"required"`
+	}
+
+	v := NewValidator(WithFieldNameTag("json"))
+	assert.Equal(t, map[string]string{"user_name": "required", "Other": "required"}, codes(v.Struct(form{})))
+}
+
+func TestTagName(t *testing.T) {
+	type form struct {
+		Name string `v:"required"`
+	}
+
+	assert.NoError(t, Struct(form{}))
+	assert.Error(t, NewValidator(WithTagName("v")).Struct(form{}))
+}
+
+func TestRegisterRule(t *testing.T) {
+	type form struct {
+		Code string `validate:"even"`
+	}
+
+	v := NewValidator()
+	assert.Error(t, v.Struct(form{}))
+	v.RegisterRule("even", func(v reflect.Value, _ string) bool {
+		return v.Kind() == reflect.String && v.Len()%2 == 0
+	})
+	assert.NoError(t, v.Struct(form{Code: "ab"}))
+	assert.Equal(t, map[string]string{"Code": "even"}, codes(v.Struct(form{Code: "abc"})))
+
+	RegisterRule("even", func(v reflect.Value, _ string) bool { return true })
+	assert.NoError(t, Struct(form{Code: "abc"}))
+}
+
+func TestVar(t *testing.T) {
+	assert.NoError(t, Var("bob@example.com", "required,email"))
+	assert.Equal(t, map[string]string{"": "email"}, codes(Var("bob", "required,email")))
+	assert.Equal(t, map[string]string{"": "required"}, codes(Var(nil, "required")))
+	assert.NoError(t, Var(nil, "omitempty,email"))
+	assert.NoError(t, Var([]int{1, 2}, "len=2,dive,gt=0"))
+	assert.Equal(t, map[string]string{"[1]": "gt"}, codes(Var([]int{1, 0}, "dive,gt=0")))
+	assert.Error(t, Var(1, "nope"))
+	assert.NoError(t, Var(1, ""))
+}
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		tag   string
+		ok    bool
+	}{
+		{"abc", "len=3", true},
+		{"世界", "len=2", true},
+		{[]int{1}, "len=2", false},
+		{map[int]int{1: 1}, "min=1", true},
+		{3.5, "max=3", false},
+		{uint(3), "gt=2", true},
+		{"a", "eq=a", true},
+		{"a", "ne=a", false},
+		{true, "eq=true", true},
+		{5, "eq=5", true},
+		{5, "ne=5", false},
+		{struct{}{}, "eq=1", false},
+		{struct{}{}, "min=1", false},
+		{2, "oneof=1 2 3", true},
+		{uint(4), "oneof=1 2 3", false},
+		{1.0, "oneof=1", false},
+		{"abc", "alpha", true},
+		{"ab1", "alpha", false},
+		{"", "alpha", false},
+		{"ABC", "uppercase", true},
+		{"abc", "lowercase", true},
+		{"hello", "contains=ell", true},
+		{"hello", "startswith=he", true},
+		{"hello", "endswith=lo", true},
+		{1, "contains=1", false},
+		{0, "required", false},
+		{[]int{}, "required", false},
+	}
+	for _, tt := range tests {
+		err := Var(tt.value, tt.tag)
+		assert.Equal(t, tt.ok, err == nil, "%#v %s", tt.value, tt.tag)
+	}
+}
+
+func TestStruct_Recursive(t *testing.T) {
+	type node struct {
+		Name     string `validate:"required"`
+		Children []*node
+		Parent   interface{}
+	}
+
+	n := &node{Name: "root", Children: []*node{{Name: "a"}, {Children: []*node{{}}}, nil}}
+	n.Parent = node{}
+	assert.Equal(t, map[string]string{
+		"Children[1].Name":             "required",
+		"Children[1].Children[0].Name": "required",
+		"Parent.Name":                  "required",
+	}, codes(Struct(n)))
+}
+
+func TestStruct_Concurrent(t *testing.T) {
+	v := NewValidator()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, v.Struct(validUser()))
+		}()
+	}
+	wg.Wait()
+}
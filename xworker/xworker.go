@@ -18,17 +18,36 @@ package xworker
 
 import (
 	"context"
+	"github.com/chenquan/go-pkg/xlog"
 	"github.com/chenquan/go-pkg/xtask"
 )
 
-// Worker is used to control the concurrency of goroutines.
-type Worker struct {
-	c chan struct{}
+type (
+	// Worker is used to control the concurrency of goroutines.
+	Worker struct {
+		c      chan struct{}
+		logger xlog.Logger
+	}
+
+	// Option defines the method to customize a Worker.
+	Option func(w *Worker)
+)
+
+// WithLogger customizes the Logger reporting panics and unfinished tasks, default to xlog.Nop.
+func WithLogger(logger xlog.Logger) Option {
+	return func(w *Worker) {
+		w.logger = xlog.OrNop(logger)
+	}
 }
 
 // NewWorker returns a Worker.
-func NewWorker(size int) *Worker {
-	return &Worker{c: make(chan struct{}, size)}
+func NewWorker(size int, opts ...Option) *Worker {
+	w := &Worker{c: make(chan struct{}, size), logger: xlog.Nop()}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
 }
 
 // Run executes function with ctx control.
@@ -36,9 +55,16 @@ func (w *Worker) Run(ctx context.Context, run func(), deferFunc func()) {
 	w.c <- struct{}{}
 	defer func() {
 		<-w.c
+		if p := recover(); p != nil {
+			w.logger.Error("xworker: task panicked", "panic", p)
+			panic(p)
+		}
 	}()
-	_ = xtask.Do(ctx, func() error {
+	err := xtask.Do(ctx, func() error {
 		run()
 		return nil
 	}, deferFunc)
+	if err != nil {
+		w.logger.Warn("xworker: task not completed", "err", err)
+	}
 }
@@ -17,8 +17,11 @@
 package xworker
 
 import (
+	"bytes"
 	"context"
+	"github.com/chenquan/go-pkg/xlog"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -77,3 +80,26 @@ func TestWorker(t *testing.T) {
 	assert.Equal(t, uint32(50), atomic.LoadUint32(&j))
 
 }
+
+func TestWorker_WithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	worker := NewWorker(1, WithLogger(xlog.NewLogger(&buf, xlog.WithTimeFormat(""))))
+
+	assert.Panics(t, func() {
+		worker.Run(context.Background(), func() {
+			panic("boom")
+		}, nil)
+	})
+	assert.True(t, strings.HasPrefix(buf.String(), "ERROR xworker: task panicked panic="))
+
+	buf.Reset()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	worker.Run(ctx, func() {}, nil)
+	assert.Equal(t, "WARN xworker: task not completed err=\"context canceled\"\n", buf.String())
+
+	// a nil Logger falls back to xlog.Nop.
+	assert.NotPanics(t, func() {
+		NewWorker(1, WithLogger(nil)).Run(ctx, func() {}, nil)
+	})
+}